@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminListUsers godoc
+// @Summary      List users
+// @Description  Search users by username, email, creation range, role and active state
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit         query     int     false  "Limit"   default(20)
+// @Param        offset        query     int     false  "Offset"  default(0)
+// @Param        username      query     string  false  "Username contains"
+// @Param        email         query     string  false  "Email contains"
+// @Param        created_from  query     string  false  "Created at or after (RFC3339)"
+// @Param        created_to    query     string  false  "Created at or before (RFC3339)"
+// @Param        role          query     int     false  "Role (0=User,1=Admin)"
+// @Param        active        query     bool    false  "Active accounts only / deactivated only"
+// @Success      200           {object}  dto.Response{data=dto.UserListResp}
+// @Failure      400           {object}  dto.Response
+// @Failure      403           {object}  dto.Response
+// @Router       /v1/admin/users [get]
+func AdminListUsers(adminSrv *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		filter := dto.UserListFilter{}
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := adminSrv.ListUsers(c, filter, pagination.Limit, pagination.Offset)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "users retrieved", resp)
+	}
+}
+
+// AdminGetUser godoc
+// @Summary      Get a user
+// @Description  Retrieve a user account with its administrative state
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  dto.Response{data=dto.AdminUserResp}
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/admin/users/{id} [get]
+func AdminGetUser(adminSrv *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := adminSrv.GetUser(c, uint(userID))
+		if err != nil {
+			adminErr(c, err)
+			return
+		}
+		dto.OK(c, "user retrieved", resp)
+	}
+}
+
+// AdminDeactivateUser godoc
+// @Summary      Deactivate a user
+// @Description  Block the account and revoke all of its tokens
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  dto.Response{data=dto.AdminUserResp}
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Router       /v1/admin/users/{id}/deactivate [post]
+func AdminDeactivateUser(adminSrv *services.AdminService) gin.HandlerFunc {
+	return setUserActive(adminSrv, false)
+}
+
+// AdminReactivateUser godoc
+// @Summary      Reactivate a user
+// @Description  Allow a deactivated account to log in again
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  dto.Response{data=dto.AdminUserResp}
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Router       /v1/admin/users/{id}/reactivate [post]
+func AdminReactivateUser(adminSrv *services.AdminService) gin.HandlerFunc {
+	return setUserActive(adminSrv, true)
+}
+
+func setUserActive(adminSrv *services.AdminService, active bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := adminSrv.SetUserActive(c, uint(userID), active, adminID)
+		if err != nil {
+			adminErr(c, err)
+			return
+		}
+		if active {
+			dto.OK(c, "user reactivated", resp)
+			return
+		}
+		dto.OK(c, "user deactivated", resp)
+	}
+}
+
+// AdminForcePasswordReset godoc
+// @Summary      Force a password reset
+// @Description  Clear the user's password and revoke their sessions, emailing them a single-use reset token to choose a new password with
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Router       /v1/admin/users/{id}/password-reset [post]
+func AdminForcePasswordReset(adminSrv *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := adminSrv.ForcePasswordReset(c, uint(userID)); err != nil {
+			adminErr(c, err)
+			return
+		}
+		dto.OK(c, "password reset required", nil)
+	}
+}
+
+// AdminListUserSessions godoc
+// @Summary      List a user's sessions
+// @Description  List current and past login sessions of a user
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  dto.Response{data=[]dto.SessionResp}
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/admin/users/{id}/sessions [get]
+func AdminListUserSessions(adminSrv *services.AdminService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := adminSrv.ListUserSessions(c, uint(userID))
+		if err != nil {
+			adminErr(c, err)
+			return
+		}
+		dto.OK(c, "sessions retrieved", resp)
+	}
+}
+
+func adminErr(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api_error.ErrUserNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrCannotModifySelf), errors.Is(err, api_error.ErrNoEmail):
+		dto.ErrStatus(c, http.StatusConflict, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupAdminRouter(t *testing.T) (*gin.Engine, *mockRepo.MockUserRepo, *mockRepo.MockSessionRepo) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	userRepo := new(mockRepo.MockUserRepo)
+	sessionRepo := new(mockRepo.MockSessionRepo)
+	authSrv := services.NewAuthService(userRepo, sessionRepo, nil, mockRepo.NoopTransactor{}, rdb, "test-secret")
+	adminSrv := services.NewAdminService(userRepo, sessionRepo, authSrv, discardMailer{})
+
+	r := gin.New()
+	users := r.Group("/admin/users")
+	users.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	users.GET("", AdminListUsers(adminSrv))
+	users.GET("/:id", AdminGetUser(adminSrv))
+	users.POST("/:id/deactivate", AdminDeactivateUser(adminSrv))
+	users.POST("/:id/reactivate", AdminReactivateUser(adminSrv))
+	users.POST("/:id/password-reset", AdminForcePasswordReset(adminSrv))
+	users.GET("/:id/sessions", AdminListUserSessions(adminSrv))
+	return r, userRepo, sessionRepo
+}
+
+func TestAdminListUsersHandler(t *testing.T) {
+	router, userRepo, _ := setupAdminRouter(t)
+
+	userRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.UserListFilter) bool {
+		return f.Username == "jo" && f.Active != nil && *f.Active
+	}), 20, 0).Return([]domain.User{{Username: "john"}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/users?username=jo&active=true", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp dto.Response
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.True(t, resp.Success)
+	userRepo.AssertExpectations(t)
+}
+
+func TestAdminListUsersHandler_InvalidFilter(t *testing.T) {
+	router, _, _ := setupAdminRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/users?created_from=yesterday", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAdminDeactivateUserHandler_Self(t *testing.T) {
+	router, _, _ := setupAdminRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/1/deactivate", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestAdminReactivateUserHandler_NotFound(t *testing.T) {
+	router, userRepo, _ := setupAdminRouter(t)
+
+	userRepo.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/999/reactivate", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAdminListUserSessionsHandler(t *testing.T) {
+	router, userRepo, sessionRepo := setupAdminRouter(t)
+
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.User{}, nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(2), false).
+		Return([]domain.UserSession{{UserID: 2, Valid: true, UserAgent: "curl"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/users/2/sessions", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "curl")
+}
+
+func TestAdminForcePasswordResetHandler(t *testing.T) {
+	router, userRepo, sessionRepo := setupAdminRouter(t)
+
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.User{Email: "john@example.com"}, nil)
+	userRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.User{}, nil)
+	userRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"password", "password_reset_required"}).Return(nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(2), true).Return([]domain.UserSession{}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/users/2/password-reset", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "reset_token")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/admin/users/3/password-reset", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetChecklist godoc
+// @Summary      Get a task's checklist
+// @Description  List the to-do items of a task in order, with how many are checked
+// @Tags         tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.Response{data=dto.ChecklistResp}
+// @Failure      404  {object}  dto.Response
+// @Header       200  {string}  ETag  "Task version"
+// @Router       /v1/tasks/{id}/checklist [get]
+func GetChecklist(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.GetChecklist(c, uint(taskID))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "checklist retrieved", resp)
+	}
+}
+
+// AddChecklistItem godoc
+// @Summary      Add a checklist item
+// @Description  Add an unchecked item to the end of a task's checklist. With If-Match, the item is only added to that version of the task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                   true   "Task ID"
+// @Param        If-Match  header    string                false  "ETag the change is based on"
+// @Param        body      body      dto.ChecklistItemReq  true   "Item text"
+// @Success      201       {object}  dto.Response{data=dto.ChecklistResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Header       201       {string}  ETag  "New task version"
+// @Router       /v1/tasks/{id}/checklist [post]
+func AddChecklistItem(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.ChecklistItemReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.AddChecklistItem(c, uint(taskID), req, userID, matchVersion(c.GetHeader("If-Match")))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.Created(c, "checklist item added", resp)
+	}
+}
+
+// UpdateChecklistItem godoc
+// @Summary      Update a checklist item
+// @Description  Reword a checklist item or check it off; omitted fields are kept. Checking an item records who checked it and when. With If-Match, the change only applies to that version of the task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                         true   "Task ID"
+// @Param        item_id   path      int                         true   "Checklist item ID"
+// @Param        If-Match  header    string                      false  "ETag the change is based on"
+// @Param        body      body      dto.UpdateChecklistItemReq  true   "Fields to change"
+// @Success      200       {object}  dto.Response{data=dto.ChecklistResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Header       200       {string}  ETag  "New task version"
+// @Router       /v1/tasks/{id}/checklist/{item_id} [patch]
+func UpdateChecklistItem(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, itemID, ok := checklistItemParams(c)
+		if !ok {
+			return
+		}
+
+		req := dto.UpdateChecklistItemReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.UpdateChecklistItem(c, taskID, itemID, req, userID, matchVersion(c.GetHeader("If-Match")))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "checklist item updated", resp)
+	}
+}
+
+// MoveChecklistItem godoc
+// @Summary      Reorder a checklist item
+// @Description  Move a checklist item to another position, counting from 0; positions past the end put it last. With If-Match, the move only applies to that version of the task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                       true   "Task ID"
+// @Param        item_id   path      int                       true   "Checklist item ID"
+// @Param        If-Match  header    string                    false  "ETag the move is based on"
+// @Param        body      body      dto.MoveChecklistItemReq  true   "New position"
+// @Success      200       {object}  dto.Response{data=dto.ChecklistResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Header       200       {string}  ETag  "New task version"
+// @Router       /v1/tasks/{id}/checklist/{item_id}/move [post]
+func MoveChecklistItem(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, itemID, ok := checklistItemParams(c)
+		if !ok {
+			return
+		}
+
+		req := dto.MoveChecklistItemReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.MoveChecklistItem(c, taskID, itemID, req, userID, matchVersion(c.GetHeader("If-Match")))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "checklist item moved", resp)
+	}
+}
+
+// DeleteChecklistItem godoc
+// @Summary      Delete a checklist item
+// @Description  Remove an item from a task's checklist. With If-Match, the item is only removed from that version of the task
+// @Tags         tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int     true   "Task ID"
+// @Param        item_id   path      int     true   "Checklist item ID"
+// @Param        If-Match  header    string  false  "ETag the deletion is based on"
+// @Success      200       {object}  dto.Response{data=dto.ChecklistResp}
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Header       200       {string}  ETag  "New task version"
+// @Router       /v1/tasks/{id}/checklist/{item_id} [delete]
+func DeleteChecklistItem(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, itemID, ok := checklistItemParams(c)
+		if !ok {
+			return
+		}
+
+		resp, err := taskSrv.DeleteChecklistItem(c, taskID, itemID, userID, matchVersion(c.GetHeader("If-Match")))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "checklist item deleted", resp)
+	}
+}
+
+// checklistItemParams reads the task and item IDs from the path, answering the request
+// itself when they are malformed.
+func checklistItemParams(c *gin.Context) (uint, uint, bool) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		dto.Err(c, err)
+		return 0, 0, false
+	}
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 64)
+	if err != nil {
+		dto.Err(c, err)
+		return 0, 0, false
+	}
+	return uint(taskID), uint(itemID), true
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupChecklistRouter() (*gin.Engine, *mockRepo.MockTaskRepo) {
+	gin.SetMode(gin.TestMode)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	task := domain.Task{Name: "Release", Version: 3, Checklist: []domain.ChecklistItem{{ID: 1, Text: "Draft"}, {ID: 2, Text: "Review"}}}
+	task.ID = 4
+	taskRepo.On("GetByID", mock.Anything, uint(4)).Return(task, nil)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	r := gin.New()
+	tasks := r.Group("/tasks")
+	tasks.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	tasks.GET("/:id/checklist", GetChecklist(taskSrv))
+	tasks.POST("/:id/checklist", AddChecklistItem(taskSrv))
+	tasks.PATCH("/:id/checklist/:item_id", UpdateChecklistItem(taskSrv))
+	tasks.POST("/:id/checklist/:item_id/move", MoveChecklistItem(taskSrv))
+	tasks.DELETE("/:id/checklist/:item_id", DeleteChecklistItem(taskSrv))
+	return r, taskRepo
+}
+
+func checklistRequest(router *gin.Engine, method, path string, body any, ifMatch string) *httptest.ResponseRecorder {
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetChecklistHandler(t *testing.T) {
+	router, _ := setupChecklistRouter()
+
+	w := checklistRequest(router, "GET", "/tasks/4/checklist", nil, "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `"progress":{"done":0,"total":2}`)
+}
+
+func TestAddChecklistItemHandler(t *testing.T) {
+	router, taskRepo := setupChecklistRouter()
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+	w := checklistRequest(router, "POST", "/tasks/4/checklist", dto.ChecklistItemReq{Text: "Ship"}, `"3"`)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"text":"Ship"`)
+
+	w = checklistRequest(router, "POST", "/tasks/4/checklist", dto.ChecklistItemReq{}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = checklistRequest(router, "POST", "/tasks/4/checklist", dto.ChecklistItemReq{Text: "Ship"}, `"2"`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	taskRepo.AssertNumberOfCalls(t, "UpdateByID", 1)
+}
+
+func TestChecklistItemHandlers(t *testing.T) {
+	router, taskRepo := setupChecklistRouter()
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+	w := checklistRequest(router, "PATCH", "/tasks/4/checklist/2", map[string]any{"checked": true}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"progress":{"done":1,"total":2}`)
+
+	w = checklistRequest(router, "POST", "/tasks/4/checklist/2/move", dto.MoveChecklistItemReq{Position: 0}, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = checklistRequest(router, "POST", "/tasks/4/checklist/2/move", map[string]any{"position": -1}, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = checklistRequest(router, "DELETE", "/tasks/4/checklist/9", nil, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = checklistRequest(router, "DELETE", "/tasks/4/checklist/1", nil, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+}
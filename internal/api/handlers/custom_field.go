@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCustomField godoc
+// @Summary      Add a custom field to a project
+// @Description  Define a custom field (text, number, date, select or user) the project's tasks can hold a value for under its key. Tasks can be filtered and sorted by it as cf.<key>. Project owner or organization owner only
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                 true  "Project ID"
+// @Param        body  body      dto.CustomFieldReq  true  "Field definition"
+// @Success      201   {object}  dto.Response{data=dto.CustomFieldResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Failure      409   {object}  dto.Response
+// @Router       /v1/projects/{id}/custom-fields [post]
+func CreateCustomField(customFieldSrv *services.CustomFieldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.CustomFieldReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := customFieldSrv.CreateCustomField(c, uint(projectID), req, userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.Created(c, "custom field created", resp)
+	}
+}
+
+// ListCustomFields godoc
+// @Summary      List a project's custom fields
+// @Description  List the custom fields of the project in the order they were added
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  dto.Response{data=[]dto.CustomFieldResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/projects/{id}/custom-fields [get]
+func ListCustomFields(customFieldSrv *services.CustomFieldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := customFieldSrv.ListCustomFields(c, uint(projectID), userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "custom fields retrieved", resp)
+	}
+}
+
+// UpdateCustomField godoc
+// @Summary      Update a custom field
+// @Description  Rename a custom field or change its options or whether it is required. Its key and type stay. Tasks keep their values, which are checked against the new definition when next changed. Project owner or organization owner only
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                       true  "Project ID"
+// @Param        field_id  path      int                       true  "Custom field ID"
+// @Param        body      body      dto.UpdateCustomFieldReq  true  "Field changes"
+// @Success      200       {object}  dto.Response{data=dto.CustomFieldResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Router       /v1/projects/{id}/custom-fields/{field_id} [put]
+func UpdateCustomField(customFieldSrv *services.CustomFieldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+		fieldID, err := strconv.ParseUint(c.Param("field_id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.UpdateCustomFieldReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := customFieldSrv.UpdateCustomField(c, uint(projectID), uint(fieldID), req, userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "custom field updated", resp)
+	}
+}
+
+// DeleteCustomField godoc
+// @Summary      Delete a custom field
+// @Description  Delete a custom field along with its values on the project's tasks. Project owner or organization owner only
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int  true  "Project ID"
+// @Param        field_id  path      int  true  "Custom field ID"
+// @Success      200       {object}  dto.Response
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Router       /v1/projects/{id}/custom-fields/{field_id} [delete]
+func DeleteCustomField(customFieldSrv *services.CustomFieldService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+		fieldID, err := strconv.ParseUint(c.Param("field_id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := customFieldSrv.DeleteCustomField(c, uint(projectID), uint(fieldID), userID); err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "custom field deleted", nil)
+	}
+}
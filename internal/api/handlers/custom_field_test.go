@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupCustomFieldRouter() (*gin.Engine, *mockRepo.MockCustomFieldRepo) {
+	gin.SetMode(gin.TestMode)
+	customFieldRepo := new(mockRepo.MockCustomFieldRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	project := domain.Project{Name: "Project", OwnerID: 1}
+	project.ID = 3
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(project, nil)
+	customFieldSrv := services.NewCustomFieldService(customFieldRepo, new(mockRepo.MockTaskRepo), projectRepo, nil, mockRepo.NoopTransactor{})
+
+	r := gin.New()
+	projects := r.Group("/projects")
+	projects.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	projects.POST("/:id/custom-fields", CreateCustomField(customFieldSrv))
+	projects.GET("/:id/custom-fields", ListCustomFields(customFieldSrv))
+	return r, customFieldRepo
+}
+
+func postCustomField(router *gin.Engine, req dto.CustomFieldReq) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("POST", "/projects/3/custom-fields", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+func TestCreateCustomFieldHandler(t *testing.T) {
+	router, customFieldRepo := setupCustomFieldRouter()
+	customFieldRepo.On("ListByProject", mock.Anything, uint(3)).Return([]domain.CustomField{{ProjectID: 3, Key: "points"}}, nil)
+	customFieldRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.CustomField")).Return(uint(2), nil)
+
+	w := postCustomField(router, dto.CustomFieldReq{Key: "customer", Name: "Customer", Type: "text"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	w = postCustomField(router, dto.CustomFieldReq{Key: "points", Name: "Points", Type: "number"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = postCustomField(router, dto.CustomFieldReq{Key: "due", Name: "Due", Type: "datetime"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postCustomField(router, dto.CustomFieldReq{Key: "Due Date", Name: "Due", Type: "date"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	customFieldRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestListCustomFieldsHandler(t *testing.T) {
+	router, customFieldRepo := setupCustomFieldRouter()
+	customFieldRepo.On("ListByProject", mock.Anything, uint(3)).Return([]domain.CustomField{{ProjectID: 3, Key: "points", Name: "Points"}}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/projects/3/custom-fields", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"key":"points"`)
+	assert.Contains(t, w.Body.String(), `"type":"text"`)
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"graph-interview/internal/filter"
 	"graph-interview/internal/repository/enum"
 	"time"
 )
@@ -13,15 +14,22 @@ type LoginUserReq struct {
 }
 
 type JWTResp struct {
-	Access    string    `json:"access"`
-	Refresh   string    `json:"refresh"`
-	ExpiresAt time.Time `json:"expires_at"`
+	Access           string    `json:"access"`
+	Refresh          string    `json:"refresh"`
+	CSRFToken        string    `json:"csrf_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
 
 type RefreshTokenReq struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
+type OIDCCallbackReq struct {
+	Code  string `form:"code" binding:"required"`
+	State string `form:"state" binding:"required"`
+}
+
 // User DTOs
 
 type CreateUserReq struct {
@@ -41,47 +49,642 @@ type UserProfileResp struct {
 	Avatar   string `json:"avatar,omitempty"`
 }
 
+type ResetPasswordReq struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// Admin DTOs
+
+type AdminUserResp struct {
+	ID                    uint      `json:"id"`
+	Username              string    `json:"username"`
+	Email                 string    `json:"email"`
+	Role                  string    `json:"role"`
+	Active                bool      `json:"active"`
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type UserListResp struct {
+	Users  []AdminUserResp `json:"users"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+type SessionResp struct {
+	ID        uint      `json:"id"`
+	Valid     bool      `json:"valid"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type PasswordResetResp struct {
+	ResetToken string    `json:"reset_token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type IdentityResp struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=zip json"`
+}
+
+// UserExport is the personal data archive returned by GET /v1/user/export.
+type UserExport struct {
+	ExportedAt    time.Time          `json:"exported_at"`
+	Profile       UserProfileResp    `json:"profile"`
+	Projects      []ProjectResp      `json:"projects"`
+	TasksCreated  []TaskResp         `json:"tasks_created"`
+	Assignments   []TaskResp         `json:"assignments"`
+	Sessions      []SessionResp      `json:"sessions"`
+	Identities    []IdentityResp     `json:"identities"`
+	Notifications []NotificationResp `json:"notifications"`
+	Reminders     []ReminderResp     `json:"reminders"`
+	Views         []ViewResp         `json:"views"`
+}
+
 // Task DTOs
 
+// CreateTaskReq creates a task. Recurrence is an RRULE such as "FREQ=WEEKLY;BYDAY=MO" and
+// needs a due date.
 type CreateTaskReq struct {
-	Name        string `json:"name" binding:"required,min=1,max=255"`
-	Description string `json:"description"`
+	Name        string     `json:"name" binding:"required,min=1,max=255"`
+	Description string     `json:"description"`
+	ProjectID   *uint      `json:"project_id,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	Recurrence  string     `json:"recurrence,omitempty" example:"FREQ=WEEKLY;BYDAY=MO"`
+	Labels      []string   `json:"labels,omitempty" binding:"max=20,dive,max=50"`
+	// CustomFields sets the project's custom fields by key. A null value leaves a field unset.
+	CustomFields map[string]any `json:"custom_fields,omitempty" binding:"max=50"`
 }
 
+// UpdateTaskReq is the editable state of a task. PUT replaces the task with it, so omitted
+// fields are cleared, and PATCH documents are applied to it. An empty recurrence stops the
+// task from recurring. StatusName picks a status of the task's workflow by name and wins
+// over Status when it changes; otherwise the task moves to the first workflow status that
+// counts as Status.
 type UpdateTaskReq struct {
-	Name        *string          `json:"name,omitempty"`
-	Description *string          `json:"description,omitempty"`
-	Status      *enum.TaskStatus `json:"status,omitempty"`
+	Name        string          `json:"name" binding:"required,min=1,max=255"`
+	Description string          `json:"description"`
+	Status      enum.TaskStatus `json:"status"`
+	StatusName  string          `json:"status_name,omitempty" binding:"max=50" example:"In Review"`
+	DueDate     *time.Time      `json:"due_date"`
+	Recurrence  string          `json:"recurrence" example:"FREQ=WEEKLY;BYDAY=MO"`
+	Labels      []string        `json:"labels" binding:"max=20,dive,max=50"`
+	// CustomFields are the values of the project's custom fields by key. Values left as
+	// they were are not checked again, so tasks keep working when a field changes.
+	CustomFields map[string]any `json:"custom_fields" binding:"max=50"`
 }
 
 type TaskResp struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	StatusName  string     `json:"status_name"`
+	Category    string     `json:"category"`
+	ProjectID   *uint      `json:"project_id"`
+	DueDate     *time.Time `json:"due_date"`
+	Recurrence  string     `json:"recurrence,omitempty"`
+	SeriesID    *uint      `json:"series_id,omitempty"`
+	Occurrence  int        `json:"occurrence,omitempty"`
+	Labels      []string   `json:"labels"`
+	Position    string     `json:"position"`
+	CreatedByID *uint      `json:"created_by_id"`
+	UpdatedByID *uint      `json:"updated_by_id"`
+	Version     int        `json:"version"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// CustomFields are the values of the set custom fields by key.
+	CustomFields map[string]any `json:"custom_fields,omitempty"`
+	// ChecklistProgress counts the task's checklist items, and how many are checked.
+	ChecklistProgress ChecklistProgress `json:"checklist_progress"`
+}
+
+type TaskListResp struct {
+	Tasks  []TaskResp `json:"tasks"`
+	Total  int64      `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+type ChecklistProgress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// ChecklistItemReq adds an item to the end of a task's checklist.
+type ChecklistItemReq struct {
+	Text string `json:"text" binding:"required,max=500"`
+}
+
+// UpdateChecklistItemReq rewords a checklist item or checks it off; omitted fields are kept.
+type UpdateChecklistItemReq struct {
+	Text    *string `json:"text" binding:"omitempty,max=500"`
+	Checked *bool   `json:"checked"`
+}
+
+// MoveChecklistItemReq moves a checklist item to position, counting from 0. Positions past
+// the end put it last.
+type MoveChecklistItemReq struct {
+	Position int `json:"position" binding:"min=0"`
+}
+
+type ChecklistItemResp struct {
+	ID        uint       `json:"id"`
+	Text      string     `json:"text"`
+	Checked   bool       `json:"checked"`
+	CheckedBy *uint      `json:"checked_by"`
+	CheckedAt *time.Time `json:"checked_at"`
+}
+
+// ChecklistResp is a task's checklist in order. Version is the task's version after the
+// change, as in its ETag.
+type ChecklistResp struct {
+	TaskID   uint                `json:"task_id"`
+	Version  int                 `json:"version"`
+	Items    []ChecklistItemResp `json:"items"`
+	Progress ChecklistProgress   `json:"progress"`
+}
+
+// MoveTaskReq moves a task to another project; a null project_id removes it from its project.
+type MoveTaskReq struct {
+	ProjectID *uint `json:"project_id"`
+}
+
+// RepositionTaskReq moves a task on the board into the status column and between two of
+// the tasks there: before_id is the task it will come before and after_id the one it will
+// follow. Give one of them at either end of the column and neither to put it last.
+// StatusName picks the column of a status of the task's workflow by name instead, as on
+// the board of a project with a workflow.
+type RepositionTaskReq struct {
+	Status     enum.TaskStatus `json:"status"`
+	StatusName string          `json:"status_name,omitempty" binding:"max=50" example:"In Review"`
+	BeforeID   *uint           `json:"before_id,omitempty"`
+	AfterID    *uint           `json:"after_id,omitempty"`
+}
+
+// BoardColumn lists the tasks with one status, in board order. On the board of a project
+// the columns are the statuses of its workflow: StatusName names one and Status is the
+// built-in status it counts as.
+type BoardColumn struct {
+	Status     string     `json:"status"`
+	StatusName string     `json:"status_name"`
+	Tasks      []TaskResp `json:"tasks"`
+	Total      int64      `json:"total"`
+}
+
+type BoardResp struct {
+	Columns []BoardColumn `json:"columns"`
+	Limit   int           `json:"limit"`
+}
+
+// AssignTaskReq adds a member of the organization to a task's assignees.
+type AssignTaskReq struct {
+	UserID uint `json:"user_id" binding:"required"`
+}
+
+// BulkTaskOp is one change of a bulk request. Status goes with set_status, UserID with
+// assign and unassign, and Labels with add_labels and remove_labels.
+type BulkTaskOp struct {
+	Op     string           `json:"op" binding:"required,oneof=set_status assign unassign add_labels remove_labels archive delete" example:"set_status"`
+	Status *enum.TaskStatus `json:"status,omitempty"`
+	UserID uint             `json:"user_id,omitempty"`
+	Labels []string         `json:"labels,omitempty" binding:"max=20,dive,max=50"`
+}
+
+// BulkTaskReq applies operations, in order, to the tasks listed in TaskIDs or to those
+// matching Filter. With DryRun nothing is written.
+type BulkTaskReq struct {
+	TaskIDs    []uint          `json:"task_ids,omitempty"`
+	Filter     *TaskListFilter `json:"filter,omitempty"`
+	Operations []BulkTaskOp    `json:"operations" binding:"required,min=1,dive"`
+	DryRun     bool            `json:"dry_run"`
+}
+
+type BulkTaskResult struct {
+	TaskID uint `json:"task_id"`
+	OK     bool `json:"ok"`
+	// Changes describes what the operations change on the task.
+	Changes []string  `json:"changes"`
+	Error   string    `json:"error,omitempty"`
+	Task    *TaskResp `json:"task,omitempty"`
+}
+
+// BulkTaskResp reports the outcome for every task. Changes are only Applied when every
+// task succeeded; otherwise none is.
+type BulkTaskResp struct {
+	DryRun  bool             `json:"dry_run"`
+	Applied bool             `json:"applied"`
+	Results []BulkTaskResult `json:"results"`
+}
+
+// Notification DTOs
+
+type NotificationResp struct {
+	ID             uint       `json:"id"`
+	Kind           string     `json:"kind"`
+	Title          string     `json:"title"`
+	Body           string     `json:"body"`
+	OrganizationID uint       `json:"organization_id"`
+	TaskID         *uint      `json:"task_id,omitempty"`
+	ActorID        *uint      `json:"actor_id,omitempty"`
+	ReadAt         *time.Time `json:"read_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type NotificationListResp struct {
+	Notifications []NotificationResp `json:"notifications"`
+	Total         int64              `json:"total"`
+	Unread        int64              `json:"unread"`
+	Limit         int                `json:"limit"`
+	Offset        int                `json:"offset"`
+}
+
+type NotificationListFilter struct {
+	Unread bool `json:"unread,omitempty" form:"unread"`
+}
+
+// NotificationPrefs says which kinds of notifications the user receives in the inbox.
+type NotificationPrefs struct {
+	Assigned      bool `json:"assigned"`
+	StatusChanged bool `json:"status_changed"`
+	Mentioned     bool `json:"mentioned"`
+	TaskUpdated   bool `json:"task_updated"`
+}
+
+// UpdateNotificationPrefsReq changes the given preferences and leaves the others alone.
+type UpdateNotificationPrefsReq struct {
+	Assigned      *bool `json:"assigned,omitempty"`
+	StatusChanged *bool `json:"status_changed,omitempty"`
+	Mentioned     *bool `json:"mentioned,omitempty"`
+	TaskUpdated   *bool `json:"task_updated,omitempty"`
+}
+
+// Event DTOs
+
+// TaskEvent is one message on the event stream. Task is left out of deletions. A "resync"
+// event tells a reconnecting client that events were missed and it should reload.
+type TaskEvent struct {
+	ID     uint64    `json:"-"`
+	Type   string    `json:"type"`
+	TaskID uint      `json:"task_id,omitempty"`
+	Task   *TaskResp `json:"task,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// Webhook DTOs
+
+// CreateWebhookReq subscribes url, which must be a public address, to task events. A secret
+// is generated when none is given.
+type CreateWebhookReq struct {
+	URL    string   `json:"url" binding:"required,http_url"`
+	Secret string   `json:"secret,omitempty" binding:"omitempty,min=16"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=task.created task.updated task.deleted"`
+}
+
+// UpdateWebhookReq changes the given fields and leaves the others alone.
+type UpdateWebhookReq struct {
+	URL    *string  `json:"url,omitempty" binding:"omitempty,http_url"`
+	Secret *string  `json:"secret,omitempty" binding:"omitempty,min=16"`
+	Events []string `json:"events,omitempty" binding:"omitempty,min=1,dive,oneof=task.created task.updated task.deleted"`
+}
+
+// WebhookResp describes a webhook. The secret is only shown when it is created.
+type WebhookResp struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResp struct {
+	ID             uint       `json:"id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	FailedAt       *time.Time `json:"failed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WebhookDeliveryListResp struct {
+	Deliveries []WebhookDeliveryResp `json:"deliveries"`
+	Total      int64                 `json:"total"`
+	Limit      int                   `json:"limit"`
+	Offset     int                   `json:"offset"`
+}
+
+// View DTOs
+
+// ViewReq saves a filter and sort order under a name. Shared views are visible to the
+// project, or to the whole organization without one.
+type ViewReq struct {
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	Filter    string `json:"filter" binding:"max=1000" example:"assignee = me and status != Done"`
+	Sort      string `json:"sort" binding:"max=100" example:"due,-updated"`
+	Shared    bool   `json:"shared"`
+	ProjectID *uint  `json:"project_id,omitempty"`
+}
+
+// ViewResp describes a saved or built-in view. Built-in views have a key instead of an ID.
+type ViewResp struct {
+	ID        uint       `json:"id,omitempty"`
+	Key       string     `json:"key,omitempty"`
+	Name      string     `json:"name"`
+	Filter    string     `json:"filter"`
+	Sort      string     `json:"sort"`
+	Shared    bool       `json:"shared"`
+	ProjectID *uint      `json:"project_id,omitempty"`
+	OwnerID   uint       `json:"owner_id,omitempty"`
+	Builtin   bool       `json:"builtin"`
+	Pinned    bool       `json:"pinned"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Workflow DTOs
+
+// WorkflowStatusReq is one status of a workflow. CountsAs is the built-in status tasks in it
+// count as, which must belong to the category; it defaults to Created, Started or Done.
+type WorkflowStatusReq struct {
+	Name     string           `json:"name" binding:"required,min=1,max=50" example:"In Review"`
+	Category string           `json:"category" binding:"required,oneof=todo in_progress done" example:"in_progress"`
+	CountsAs *enum.TaskStatus `json:"counts_as,omitempty"`
+}
+
+type WorkflowTransitionReq struct {
+	From string `json:"from" binding:"required" example:"Started"`
+	To   string `json:"to" binding:"required" example:"In Review"`
+}
+
+// WorkflowReq replaces a project's workflow. New tasks start in the first todo status.
+// Without transitions every move is allowed.
+type WorkflowReq struct {
+	Statuses    []WorkflowStatusReq     `json:"statuses" binding:"required,min=1,max=30,dive"`
+	Transitions []WorkflowTransitionReq `json:"transitions" binding:"max=500,dive"`
+}
+
+type WorkflowStatusResp struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	CountsAs string `json:"counts_as"`
+}
+
+type WorkflowTransitionResp struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// WorkflowResp is a project's workflow; Default is set when it has none of its own.
+type WorkflowResp struct {
+	ProjectID   uint                     `json:"project_id"`
+	Default     bool                     `json:"default"`
+	Statuses    []WorkflowStatusResp     `json:"statuses"`
+	Transitions []WorkflowTransitionResp `json:"transitions"`
+}
+
+// CustomFieldReq defines a custom field. Key names its values on tasks and in filters, as
+// in cf.story_points, and cannot change; Options are required for select fields and not
+// allowed otherwise.
+type CustomFieldReq struct {
+	Key      string   `json:"key" binding:"required,max=40" example:"story_points"`
+	Name     string   `json:"name" binding:"required,min=1,max=100" example:"Story points"`
+	Type     string   `json:"type" binding:"required,oneof=text number date select user" example:"number"`
+	Options  []string `json:"options,omitempty" binding:"max=100,dive,min=1,max=100"`
+	Required bool     `json:"required"`
+}
+
+// UpdateCustomFieldReq changes a custom field. Its key and type stay.
+type UpdateCustomFieldReq struct {
+	Name     string   `json:"name" binding:"required,min=1,max=100" example:"Story points"`
+	Options  []string `json:"options,omitempty" binding:"max=100,dive,min=1,max=100"`
+	Required bool     `json:"required"`
+}
+
+type CustomFieldResp struct {
+	ID        uint     `json:"id"`
+	ProjectID uint     `json:"project_id"`
+	Key       string   `json:"key"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Options   []string `json:"options,omitempty"`
+	Required  bool     `json:"required"`
+}
+
+// WebhookPayload is the body posted to webhooks. ID stays the same across retries.
+type WebhookPayload struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	OrganizationID uint      `json:"organization_id"`
+	CreatedAt      time.Time `json:"created_at"`
+	Data           any       `json:"data"`
+}
+
+// Reminder DTOs
+
+// CreateReminderReq sets a reminder at remind_at, or before_due_minutes ahead of the task's
+// due date. Webhook reminders post to target, which must be a public address.
+type CreateReminderReq struct {
+	RemindAt         *time.Time `json:"remind_at,omitempty" binding:"required_without=BeforeDueMinutes,excluded_with=BeforeDueMinutes"`
+	BeforeDueMinutes *int       `json:"before_due_minutes,omitempty" binding:"required_without=RemindAt,omitempty,min=0"`
+	Channel          string     `json:"channel" binding:"required,oneof=email webhook in_app"`
+	Target           string     `json:"target,omitempty" binding:"required_if=Channel webhook,omitempty,http_url"`
+}
+
+type ReminderResp struct {
+	ID               uint       `json:"id"`
+	TaskID           uint       `json:"task_id"`
+	RemindAt         *time.Time `json:"remind_at,omitempty"`
+	BeforeDueMinutes *int       `json:"before_due_minutes,omitempty"`
+	FiresAt          *time.Time `json:"fires_at"`
+	Channel          string     `json:"channel"`
+	Target           string     `json:"target,omitempty"`
+	SentAt           *time.Time `json:"sent_at,omitempty"`
+	FailedAt         *time.Time `json:"failed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// Project DTOs
+
+type CreateProjectReq struct {
+	Name        string `json:"name" binding:"required,min=1,max=255"`
+	Description string `json:"description"`
+}
+
+type UpdateProjectReq struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Description *string `json:"description,omitempty"`
+	Archived    *bool   `json:"archived,omitempty"`
+}
+
+type ProjectResp struct {
 	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	OwnerID     uint      `json:"owner_id"`
+	Archived    bool      `json:"archived"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ProjectListResp struct {
+	Projects []ProjectResp `json:"projects"`
+	Total    int64         `json:"total"`
+	Limit    int           `json:"limit"`
+	Offset   int           `json:"offset"`
+}
+
+// Organization DTOs
+
+type CreateOrgReq struct {
+	Name string `json:"name" binding:"required,min=1,max=255"`
+}
+
+type OrgResp struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Personal bool   `json:"personal"`
+	Role     string `json:"role"`
+	// Active marks the organization the current tokens are scoped to.
+	Active bool `json:"active"`
+}
+
+type MemberResp struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	// Guest members joined through a project invitation and only see their projects.
+	Guest    bool      `json:"guest,omitempty"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type UpdateMemberReq struct {
+	Role enum.MemberRole `json:"role" binding:"min=0,max=2"`
+}
+
+// Invitation DTOs
+
+// InviteReq invites an email address to an organization, or to one of its projects when
+// project_id is set. Roles: 0=Viewer, 1=Editor, 2=Owner.
+type InviteReq struct {
+	Email     string          `json:"email" binding:"required,email"`
+	Role      enum.MemberRole `json:"role" binding:"min=0,max=2"`
+	ProjectID *uint           `json:"project_id,omitempty"`
+}
+
+type InvitationResp struct {
+	ID               uint      `json:"id"`
+	OrganizationID   uint      `json:"organization_id"`
+	OrganizationName string    `json:"organization_name,omitempty"`
+	ProjectID        *uint     `json:"project_id,omitempty"`
+	Email            string    `json:"email"`
+	Role             string    `json:"role"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// InvitationSignupReq creates an account for the invited email address while accepting.
+type InvitationSignupReq struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// Share DTOs
+
+// CreateShareReq shares exactly one of a task or a project. Without expires_at the link
+// stays valid until revoked.
+type CreateShareReq struct {
+	TaskID    *uint      `json:"task_id,omitempty" binding:"required_without=ProjectID,excluded_with=ProjectID"`
+	ProjectID *uint      `json:"project_id,omitempty" binding:"required_without=TaskID"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type ShareResp struct {
+	ID        uint       `json:"id"`
+	Token     string     `json:"token"`
+	TaskID    *uint      `json:"task_id,omitempty"`
+	ProjectID *uint      `json:"project_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// SharedTaskResp is the public view of a task. It leaves out IDs and everything about users.
+type SharedTaskResp struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	Status      string    `json:"status"`
-	CreatedByID uint      `json:"created_by_id"`
-	UpdatedByID uint      `json:"updated_by_id"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
-type TaskListResp struct {
-	Tasks  []TaskResp `json:"tasks"`
-	Total  int64      `json:"total"`
-	Limit  int        `json:"limit"`
-	Offset int        `json:"offset"`
+type SharedProjectResp struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Tasks       []SharedTaskResp `json:"tasks"`
+	Total       int64            `json:"total"`
+	Limit       int              `json:"limit"`
+	Offset      int              `json:"offset"`
+}
+
+// SharedResp is served at GET /v1/shared/{token}; exactly one of task and project is set.
+type SharedResp struct {
+	Task      *SharedTaskResp    `json:"task,omitempty"`
+	Project   *SharedProjectResp `json:"project,omitempty"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
 }
 
 // Filter DTOs
 
 type UserListFilter struct {
+	Username    string         `json:"username,omitempty" form:"username"`
+	Email       string         `json:"email,omitempty" form:"email"`
+	CreatedFrom time.Time      `json:"created_from,omitempty" form:"created_from"`
+	CreatedTo   time.Time      `json:"created_to,omitempty" form:"created_to"`
+	Role        *enum.UserRole `json:"role,omitempty" form:"role"`
+	Active      *bool          `json:"active,omitempty" form:"active"`
 }
 
 type TaskListFilter struct {
 	Status    *enum.TaskStatus `json:"status,omitempty" form:"status"`
 	Assignee  uint             `json:"assignee,omitempty" form:"assignee"`
+	CreatedBy uint             `json:"created_by,omitempty" form:"created_by"`
+	ProjectID uint             `json:"project_id,omitempty" form:"project_id"`
+	SeriesID  uint             `json:"series_id,omitempty" form:"series_id"`
+	Label     string           `json:"label,omitempty" form:"label"`
 	CreatedAt time.Time        `json:"created_at,omitempty" form:"created_at"`
 	UpdatedAt time.Time        `json:"updated_at,omitempty" form:"updated_at"`
+	// Query is an expression in the filter language, such as
+	// "status in (Started, Delayed) and assignee = me". Custom fields are named cf.<key>,
+	// and with a ProjectID statuses can be named as in its workflow. Parsed holds it once
+	// resolved.
+	Query  string      `json:"filter,omitempty" form:"filter" example:"status in (Started, Delayed) and assignee = me and created >= -7d"`
+	Parsed filter.Expr `json:"-" form:"-" swaggerignore:"true"`
+	// Sort lists the fields to sort by, such as "-updated,name". Orders holds it once parsed.
+	Sort   string         `json:"sort,omitempty" form:"sort" example:"-updated,name"`
+	Orders []filter.Order `json:"-" form:"-" swaggerignore:"true"`
+}
+
+type ShareListFilter struct {
+	TaskID    uint `json:"task_id,omitempty" form:"task_id"`
+	ProjectID uint `json:"project_id,omitempty" form:"project_id"`
+}
+
+type ProjectListFilter struct {
+	Owner    uint  `json:"owner,omitempty" form:"owner"`
+	Archived *bool `json:"archived,omitempty" form:"archived"`
 }
 
 // Pagination
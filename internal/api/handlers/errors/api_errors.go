@@ -9,10 +9,54 @@ var (
 	ErrInvalidCredentials = errors.New("invalid username or password")
 	ErrUserNotFound       = errors.New("user not found")
 	ErrTaskNotFound       = errors.New("task not found")
+	ErrProjectNotFound    = errors.New("project not found")
+	ErrProjectArchived    = errors.New("project is archived")
+	ErrOrgNotFound        = errors.New("organization not found")
+	ErrInvitationNotFound = errors.New("invitation not found or no longer valid")
+	ErrShareNotFound      = errors.New("share link not found or no longer valid")
+	ErrExpiryInPast       = errors.New("expires_at must be in the future")
+	ErrInvalidRecurrence  = errors.New("invalid recurrence rule")
+	ErrRecurrenceNoDue    = errors.New("recurring tasks need a due date")
+	ErrTaskModified       = errors.New("task was modified since it was read")
+	ErrUnsupportedPatch   = errors.New("patch must be application/merge-patch+json or application/json-patch+json")
+	ErrBulkTargets        = errors.New("give either task_ids or filter")
+	ErrBulkTooMany        = errors.New("too many tasks for one bulk request")
+	ErrInvalidBulkOp      = errors.New("invalid bulk operation")
+	ErrInvalidStatus      = errors.New("unknown task status")
+	ErrBoardConflict      = errors.New("before_id and after_id must be other tasks in the target column, in board order")
+	ErrReminderNotFound   = errors.New("reminder not found")
+	ErrReminderNoDue      = errors.New("task has no due date to remind relative to")
+	ErrRemindAtInPast     = errors.New("remind_at must be in the future")
 	ErrUnauthorized       = errors.New("unauthorized")
 	ErrTokenExpired       = errors.New("token expired")
 	ErrTokenRevoked       = errors.New("token has been revoked")
 	ErrInvalidToken       = errors.New("invalid token")
+	ErrInvalidCSRFToken   = errors.New("invalid csrf token")
+	ErrUserInactive       = errors.New("user account is deactivated")
+	ErrPasswordReset      = errors.New("password reset required")
+	ErrInvalidResetToken  = errors.New("invalid or expired password reset token")
+	ErrForbidden          = errors.New("forbidden")
+	ErrCannotModifySelf   = errors.New("admins cannot change their own account state")
+	ErrNoEmail            = errors.New("user has no email address")
+	ErrUnknownProvider    = errors.New("unknown identity provider")
+	ErrInvalidOIDCState   = errors.New("invalid or expired login state")
+	ErrOIDCLoginFailed    = errors.New("identity provider login failed")
+
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrForbiddenURL         = errors.New("url must be a public http or https address")
+	ErrViewNotFound         = errors.New("view not found")
+	ErrBuiltinView          = errors.New("built-in views cannot be changed")
+	ErrUnknownStatus        = errors.New("status is not part of the task's workflow")
+	ErrTransitionDenied     = errors.New("the workflow does not allow this status change")
+	ErrInvalidWorkflow      = errors.New("invalid workflow")
+	ErrWorkflowStatusInUse  = errors.New("tasks are still in statuses the workflow would drop")
+	ErrCustomFieldNotFound  = errors.New("custom field not found")
+	ErrCustomFieldExists    = errors.New("the project already has a custom field with this key")
+	ErrInvalidCustomField   = errors.New("invalid custom field")
+	ErrInvalidFieldValue    = errors.New("invalid custom field value")
+	ErrChecklistNotFound    = errors.New("checklist item not found")
+	ErrInvalidChecklist     = errors.New("invalid checklist item")
 )
 
 func UsernameExists(s string) error {
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamEvents godoc
+// @Summary      Stream task events
+// @Description  Server-Sent Events stream of task.created, task.updated and task.deleted events in the active organization. Guests of the organization only receive events of tasks in their projects. Reconnecting clients send the Last-Event-ID header, or the last_event_id query parameter, to receive what they missed; a resync event means too much was missed and tasks should be reloaded
+// @Tags         events
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        Last-Event-ID  header    int  false  "ID of the last event received"
+// @Param        last_event_id  query     int  false  "ID of the last event received, for clients that cannot set headers"
+// @Success      200            {object}  dto.TaskEvent
+// @Failure      401            {object}  dto.Response
+// @Failure      403            {object}  dto.Response
+// @Router       /v1/events [get]
+func StreamEvents(eventSrv *services.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lastEventID := c.GetHeader("Last-Event-ID")
+		if lastEventID == "" {
+			lastEventID = c.Query("last_event_id")
+		}
+		lastID, _ := strconv.ParseUint(lastEventID, 10, 64)
+
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+		visible, err := eventSrv.Visibility(c, getOrgID(c), userID)
+		if err != nil {
+			if errors.Is(err, api_error.ErrOrgNotFound) {
+				dto.ErrStatus(c, http.StatusForbidden, err)
+				return
+			}
+			dto.ErrInternal(c, err)
+			return
+		}
+
+		ctx := c.Request.Context()
+		events, err := eventSrv.Subscribe(ctx, getOrgID(c), lastID, visible)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		heartbeat := time.NewTicker(eventSrv.Heartbeat())
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(c.Writer, ": ping\n\n")
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				if event.ID > 0 {
+					fmt.Fprintf(c.Writer, "id: %d\n", event.ID)
+				}
+				fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data)
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamEvents_ReplaysFromLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	orgRepo.On("GetMembership", mock.Anything, uint(0), uint(1)).Return(domain.Membership{}, nil)
+	eventSrv := services.NewEventService(redis.NewClient(&redis.Options{Addr: mr.Addr()}), cfg.EventCfg{}, orgRepo, new(mockRepo.MockProjectRepo))
+
+	for _, eventType := range []string{domain.EventTaskCreated, domain.EventTaskUpdated} {
+		_, err := eventSrv.Publish(context.Background(), 0, dto.TaskEvent{Type: eventType, TaskID: 5})
+		require.NoError(t, err)
+	}
+
+	r := gin.New()
+	r.GET("/events", func(c *gin.Context) { c.Set("userID", "1") }, StreamEvents(eventSrv))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/events", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "id: 2\nevent: task.updated\ndata: {\"type\":\"task.updated\",\"task_id\":5,")
+	assert.NotContains(t, w.Body.String(), "task.created")
+}
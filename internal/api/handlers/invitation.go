@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateInvitation godoc
+// @Summary      Invite to an organization
+// @Description  Email an invitation to join the organization, or one of its projects when project_id is set. Owners only
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int            true  "Organization ID"
+// @Param        body  body      dto.InviteReq  true  "Invitation"
+// @Success      201   {object}  dto.Response{data=dto.InvitationResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/orgs/{id}/invitations [post]
+func CreateInvitation(invitationSrv *services.InvitationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.InviteReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := invitationSrv.Invite(c, uint(orgID), req, userID)
+		if err != nil {
+			invitationErr(c, err)
+			return
+		}
+		dto.Created(c, "invitation sent", resp)
+	}
+}
+
+// ListInvitations godoc
+// @Summary      List my invitations
+// @Description  List pending invitations sent to the authenticated user's email address
+// @Tags         invitations
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.InvitationResp}
+// @Failure      401  {object}  dto.Response
+// @Router       /v1/invitations [get]
+func ListInvitations(invitationSrv *services.InvitationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := invitationSrv.ListInvitations(c, userID)
+		if err != nil {
+			invitationErr(c, err)
+			return
+		}
+		dto.OK(c, "invitations retrieved", resp)
+	}
+}
+
+// AcceptInvitation godoc
+// @Summary      Accept an invitation
+// @Description  Join the organization or project of the invitation with the authenticated account
+// @Tags         invitations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        token  path      string  true  "Invitation token"
+// @Success      200    {object}  dto.Response{data=dto.OrgResp}
+// @Failure      404    {object}  dto.Response
+// @Router       /v1/invitations/{token}/accept [post]
+func AcceptInvitation(invitationSrv *services.InvitationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := invitationSrv.Accept(c, c.Param("token"), userID)
+		if err != nil {
+			invitationErr(c, err)
+			return
+		}
+		dto.OK(c, "invitation accepted", resp)
+	}
+}
+
+// SignupWithInvitation godoc
+// @Summary      Accept an invitation with a new account
+// @Description  Create an account for the invited email address, accept the invitation and log in
+// @Tags         invitations
+// @Accept       json
+// @Produce      json
+// @Param        token  path      string                   true  "Invitation token"
+// @Param        body   body      dto.InvitationSignupReq  true  "Account data"
+// @Success      201    {object}  dto.Response{data=dto.JWTResp}
+// @Failure      400    {object}  dto.Response
+// @Failure      404    {object}  dto.Response
+// @Router       /v1/auth/invitations/{token}/signup [post]
+func SignupWithInvitation(invitationSrv *services.InvitationService, authSrv *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.InvitationSignupReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := invitationSrv.AcceptWithSignup(c, c.Param("token"), req)
+		if err != nil {
+			if errors.Is(err, api_error.ErrInvitationNotFound) {
+				dto.ErrNotFound(c, err)
+				return
+			}
+			// Like registration, a taken username is reported as a bad request.
+			dto.Err(c, err)
+			return
+		}
+		authSrv.SetAuthCookies(c, resp)
+		dto.Created(c, "invitation accepted", resp)
+	}
+}
+
+// DeclineInvitation godoc
+// @Summary      Decline an invitation
+// @Description  Decline an invitation; the token is enough, no account is needed
+// @Tags         invitations
+// @Produce      json
+// @Param        token  path      string  true  "Invitation token"
+// @Success      200    {object}  dto.Response
+// @Failure      404    {object}  dto.Response
+// @Router       /v1/auth/invitations/{token}/decline [post]
+func DeclineInvitation(invitationSrv *services.InvitationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := invitationSrv.Decline(c, c.Param("token")); err != nil {
+			invitationErr(c, err)
+			return
+		}
+		dto.OK(c, "invitation declined", nil)
+	}
+}
+
+func invitationErr(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api_error.ErrInvitationNotFound), errors.Is(err, api_error.ErrOrgNotFound),
+		errors.Is(err, api_error.ErrProjectNotFound), errors.Is(err, api_error.ErrUserNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrForbidden):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"graph-interview/pkg/mailer"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type discardMailer struct{}
+
+func (discardMailer) Send(context.Context, mailer.Message) error { return nil }
+
+func setupInvitationRouter(t *testing.T) (*gin.Engine, *mockRepo.MockInvitationRepo, *mockRepo.MockOrgRepo) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	invitationRepo := new(mockRepo.MockInvitationRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	userRepo := new(mockRepo.MockUserRepo)
+	authSrv := services.NewAuthService(userRepo, new(mockRepo.MockSessionRepo), orgRepo, mockRepo.NoopTransactor{}, rdb, "test-secret")
+	invitationSrv := services.NewInvitationService(invitationRepo, orgRepo, new(mockRepo.MockProjectRepo), userRepo,
+		mockRepo.NoopTransactor{}, authSrv, discardMailer{}, cfg.InvitationCfg{})
+
+	r := gin.New()
+	r.POST("/auth/invitations/:token/decline", DeclineInvitation(invitationSrv))
+	protected := r.Group("")
+	protected.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	protected.POST("/orgs/:id/invitations", CreateInvitation(invitationSrv))
+	protected.POST("/invitations/:token/accept", AcceptInvitation(invitationSrv))
+	return r, invitationRepo, orgRepo
+}
+
+func TestCreateInvitationHandler(t *testing.T) {
+	router, invitationRepo, orgRepo := setupInvitationRouter(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberOwner}, nil)
+	orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+	invitationRepo.On("Create", mock.Anything, mock.Anything).Return(uint(4), nil)
+
+	body, _ := json.Marshal(dto.InviteReq{Email: "jane@example.com", Role: enum.MemberEditor})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orgs/7/invitations", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.NotContains(t, w.Body.String(), "token")
+}
+
+func TestCreateInvitationHandler_Forbidden(t *testing.T) {
+	router, _, orgRepo := setupInvitationRouter(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orgs/7/invitations", bytes.NewBufferString(`{"email":"jane@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCreateInvitationHandler_InvalidRole(t *testing.T) {
+	router, _, _ := setupInvitationRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orgs/7/invitations", bytes.NewBufferString(`{"email":"jane@example.com","role":5}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAcceptInvitationHandler_Unknown(t *testing.T) {
+	router, invitationRepo, _ := setupInvitationRouter(t)
+	invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(domain.Invitation{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/invitations/nope/accept", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeclineInvitationHandler(t *testing.T) {
+	router, invitationRepo, _ := setupInvitationRouter(t)
+	invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).
+		Return(domain.Invitation{OrganizationID: 7, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+	invitationRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"declined_at"}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/auth/invitations/tok/decline", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	invitationRepo.AssertExpectations(t)
+}
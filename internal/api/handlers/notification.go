@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListNotifications godoc
+// @Summary      List notifications
+// @Description  List the authenticated user's in-app notifications, newest first, with the number of unread ones
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit   query     int   false  "Limit"   default(20)
+// @Param        offset  query     int   false  "Offset"  default(0)
+// @Param        unread  query     bool  false  "Unread notifications only"
+// @Success      200     {object}  dto.Response{data=dto.NotificationListResp}
+// @Failure      400     {object}  dto.Response
+// @Failure      401     {object}  dto.Response
+// @Router       /v1/notifications [get]
+func ListNotifications(notificationSrv *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+		filter := dto.NotificationListFilter{}
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := notificationSrv.ListNotifications(c, userID, filter, pagination.Limit, pagination.Offset)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "notifications retrieved", resp)
+	}
+}
+
+// MarkNotificationRead godoc
+// @Summary      Mark a notification read
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Notification ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/notifications/{id}/read [post]
+func MarkNotificationRead(notificationSrv *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		notificationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := notificationSrv.MarkRead(c, uint(notificationID), userID); err != nil {
+			if errors.Is(err, api_error.ErrNotificationNotFound) {
+				dto.ErrNotFound(c, err)
+				return
+			}
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "notification marked read", nil)
+	}
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary      Mark all notifications read
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Router       /v1/notifications/read-all [post]
+func MarkAllNotificationsRead(notificationSrv *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		if err := notificationSrv.MarkAllRead(c, userID); err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "notifications marked read", nil)
+	}
+}
+
+// GetNotificationPrefs godoc
+// @Summary      Get notification preferences
+// @Description  Which kinds of notifications the authenticated user receives
+// @Tags         notifications
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=dto.NotificationPrefs}
+// @Failure      401  {object}  dto.Response
+// @Router       /v1/notifications/preferences [get]
+func GetNotificationPrefs(notificationSrv *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := notificationSrv.GetPreferences(c, userID)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "notification preferences retrieved", resp)
+	}
+}
+
+// UpdateNotificationPrefs godoc
+// @Summary      Update notification preferences
+// @Description  Turn kinds of notifications on or off; kinds left out keep their setting
+// @Tags         notifications
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.UpdateNotificationPrefsReq  true  "Preferences"
+// @Success      200   {object}  dto.Response{data=dto.NotificationPrefs}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Router       /v1/notifications/preferences [put]
+func UpdateNotificationPrefs(notificationSrv *services.NotificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.UpdateNotificationPrefsReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := notificationSrv.UpdatePreferences(c, userID, req)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "notification preferences updated", resp)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupNotificationRouter() (*gin.Engine, *mockRepo.MockNotificationRepo) {
+	gin.SetMode(gin.TestMode)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	notificationSrv := services.NewNotificationService(notificationRepo, nil, nil, nil, nil)
+
+	r := gin.New()
+	notifications := r.Group("/notifications")
+	notifications.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	notifications.GET("", ListNotifications(notificationSrv))
+	notifications.POST("/:id/read", MarkNotificationRead(notificationSrv))
+	notifications.POST("/read-all", MarkAllNotificationsRead(notificationSrv))
+	return r, notificationRepo
+}
+
+func TestListNotificationsHandler(t *testing.T) {
+	router, notificationRepo := setupNotificationRouter()
+
+	notificationRepo.On("ListByUser", mock.Anything, uint(1), true, 20, 0).
+		Return([]domain.Notification{{Kind: "assigned", Title: "You were assigned"}}, int64(1), nil)
+	notificationRepo.On("CountUnread", mock.Anything, uint(1)).Return(int64(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/notifications?unread=true", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data dto.NotificationListResp `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, int64(1), resp.Data.Unread)
+	assert.Equal(t, "You were assigned", resp.Data.Notifications[0].Title)
+}
+
+func TestMarkNotificationReadHandler_NotFound(t *testing.T) {
+	router, notificationRepo := setupNotificationRouter()
+
+	notificationRepo.On("MarkRead", mock.Anything, uint(9), uint(1)).Return(false, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notifications/9/read", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestMarkAllNotificationsReadHandler(t *testing.T) {
+	router, notificationRepo := setupNotificationRouter()
+
+	notificationRepo.On("MarkAllRead", mock.Anything, uint(1)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/notifications/read-all", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	notificationRepo.AssertExpectations(t)
+}
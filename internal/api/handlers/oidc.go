@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OIDCLogin godoc
+// @Summary      Start OIDC login
+// @Description  Redirect to the identity provider using the authorization code flow with PKCE
+// @Tags         auth
+// @Param        provider  path  string  true  "Configured identity provider name"
+// @Success      302
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/auth/oidc/{provider} [get]
+func OIDCLogin(oidcSrv *services.OIDCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		redirectURL, state, err := oidcSrv.BeginLogin(c, c.Param("provider"))
+		if err != nil {
+			if errors.Is(err, api_error.ErrUnknownProvider) {
+				dto.ErrNotFound(c, err)
+				return
+			}
+			dto.ErrStatus(c, http.StatusBadGateway, err)
+			return
+		}
+		oidcSrv.SetStateCookie(c, state)
+		c.Redirect(http.StatusFound, redirectURL)
+	}
+}
+
+// OIDCCallback godoc
+// @Summary      Complete OIDC login
+// @Description  Exchange the authorization code, link or provision the user and issue JWT tokens. The state must match the cookie set by the login redirect
+// @Tags         auth
+// @Produce      json
+// @Param        provider  path   string  true   "Configured identity provider name"
+// @Param        code      query  string  true   "Authorization code"
+// @Param        state     query  string  true   "Login state"
+// @Success      200  {object}  dto.Response{data=dto.JWTResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Router       /v1/auth/oidc/{provider}/callback [get]
+func OIDCCallback(oidcSrv *services.OIDCService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if idpErr := c.Query("error"); idpErr != "" {
+			dto.ErrUnauthorized(c, errors.New(idpErr))
+			return
+		}
+		req := dto.OIDCCallbackReq{}
+		if err := c.ShouldBindQuery(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+		if !oidcSrv.CheckStateCookie(c, req.State) {
+			dto.Err(c, api_error.ErrInvalidOIDCState)
+			return
+		}
+
+		resp, err := oidcSrv.CompleteLogin(c, c.Param("provider"), req.State, req.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, api_error.ErrUnknownProvider):
+				dto.ErrNotFound(c, err)
+			case errors.Is(err, api_error.ErrInvalidOIDCState):
+				dto.Err(c, err)
+			case errors.Is(err, api_error.ErrOIDCLoginFailed):
+				dto.ErrUnauthorized(c, err)
+			case errors.Is(err, api_error.ErrUserInactive):
+				dto.ErrStatus(c, http.StatusForbidden, err)
+			default:
+				dto.ErrInternal(c, err)
+			}
+			return
+		}
+		oidcSrv.AuthSrv.SetAuthCookies(c, resp)
+		dto.OK(c, "login successful", resp)
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/repository/cache"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupOIDCRouter(t *testing.T) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	userRepo := new(mockRepo.MockUserRepo)
+	authSrv := services.NewAuthService(userRepo, new(mockRepo.MockSessionRepo), memberOrgRepo(1), mockRepo.NoopTransactor{}, rdb, "test-secret")
+	oidcSrv := services.NewOIDCService(userRepo, new(mockRepo.MockIdentityRepo), authSrv, rdb,
+		map[string]cfg.OIDCProviderCfg{}, nil)
+
+	r := gin.New()
+	r.GET("/oidc/:provider", OIDCLogin(oidcSrv))
+	r.GET("/oidc/:provider/callback", OIDCCallback(oidcSrv))
+	return r, mr
+}
+
+func TestOIDCLoginHandler_UnknownProvider(t *testing.T) {
+	router, mr := setupOIDCRouter(t)
+	defer mr.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/oidc/unknown", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestOIDCCallbackHandler_MissingParams(t *testing.T) {
+	router, mr := setupOIDCRouter(t)
+	defer mr.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/oidc/unknown/callback", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOIDCCallbackHandler_ProviderError(t *testing.T) {
+	router, mr := setupOIDCRouter(t)
+	defer mr.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/oidc/company/callback?error=access_denied", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "access_denied")
+}
+
+func TestOIDCCallbackHandler_InvalidState(t *testing.T) {
+	router, mr := setupOIDCRouter(t)
+	defer mr.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/oidc/company/callback?code=abc&state=unknown", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestOIDCCallbackHandler_StateNotPinnedToBrowser(t *testing.T) {
+	router, mr := setupOIDCRouter(t)
+	defer mr.Close()
+	// A state that is live in Redis but was started by another browser.
+	mr.Set(cache.OIDCStateKey("attacker-state"), `{"provider":"company"}`)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/oidc/company/callback?code=abc&state=attacker-state", nil)
+	req.AddCookie(&http.Cookie{Name: services.OIDCStateCookie, Value: "victim-state"})
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.True(t, mr.Exists(cache.OIDCStateKey("attacker-state")))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), services.OIDCStateCookie+"=;")
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateOrg godoc
+// @Summary      Create an organization
+// @Description  Create an organization with the authenticated user as its owner
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateOrgReq  true  "Organization data"
+// @Success      201   {object}  dto.Response{data=dto.OrgResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Router       /v1/orgs [post]
+func CreateOrg(orgSrv *services.OrgService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.CreateOrgReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := orgSrv.CreateOrg(c, req, userID)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.Created(c, "organization created", resp)
+	}
+}
+
+// ListOrgs godoc
+// @Summary      List organizations
+// @Description  List the organizations the authenticated user belongs to
+// @Tags         organizations
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.OrgResp}
+// @Failure      401  {object}  dto.Response
+// @Router       /v1/orgs [get]
+func ListOrgs(orgSrv *services.OrgService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := orgSrv.ListOrgs(c, userID, getOrgID(c))
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "organizations retrieved", resp)
+	}
+}
+
+// ListOrgMembers godoc
+// @Summary      List organization members
+// @Description  List the members of an organization the authenticated user belongs to
+// @Tags         organizations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  dto.Response{data=[]dto.MemberResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/orgs/{id}/members [get]
+func ListOrgMembers(orgSrv *services.OrgService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := orgSrv.ListMembers(c, uint(orgID), userID)
+		if err != nil {
+			orgErr(c, err)
+			return
+		}
+		dto.OK(c, "members retrieved", resp)
+	}
+}
+
+// UpdateOrgMember godoc
+// @Summary      Change a member's role
+// @Description  Change the role of an organization member (0=Viewer, 1=Editor, 2=Owner). Owners only, not for themselves
+// @Tags         organizations
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int                  true  "Organization ID"
+// @Param        user_id  path      int                  true  "Member user ID"
+// @Param        body     body      dto.UpdateMemberReq  true  "New role"
+// @Success      200      {object}  dto.Response{data=dto.MemberResp}
+// @Failure      400      {object}  dto.Response
+// @Failure      403      {object}  dto.Response
+// @Failure      404      {object}  dto.Response
+// @Router       /v1/orgs/{id}/members/{user_id} [put]
+func UpdateOrgMember(orgSrv *services.OrgService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+		memberID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.UpdateMemberReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := orgSrv.UpdateMemberRole(c, uint(orgID), uint(memberID), req.Role, userID)
+		if err != nil {
+			orgErr(c, err)
+			return
+		}
+		dto.OK(c, "member updated", resp)
+	}
+}
+
+// SwitchOrg godoc
+// @Summary      Switch organization
+// @Description  Issue new tokens scoped to another organization the user belongs to
+// @Tags         organizations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Organization ID"
+// @Success      200  {object}  dto.Response{data=dto.JWTResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/orgs/{id}/switch [post]
+func SwitchOrg(orgSrv *services.OrgService, authSrv *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := orgSrv.SwitchOrg(c, uint(orgID), userID)
+		if err != nil {
+			orgErr(c, err)
+			return
+		}
+		authSrv.SetAuthCookies(c, resp)
+		dto.OK(c, "organization switched", resp)
+	}
+}
+
+// getOrgID returns the organization the request is scoped to, or zero when it has none.
+func getOrgID(c *gin.Context) uint {
+	orgID, _ := tenant.FromContext(c)
+	return orgID
+}
+
+func orgErr(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api_error.ErrOrgNotFound), errors.Is(err, api_error.ErrUserNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrForbidden):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	case errors.Is(err, api_error.ErrUserInactive):
+		dto.ErrUnauthorized(c, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupOrgRouter(t *testing.T) (*gin.Engine, *mockRepo.MockOrgRepo, *mockRepo.MockUserRepo, *mockRepo.MockSessionRepo) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	orgRepo := new(mockRepo.MockOrgRepo)
+	userRepo := new(mockRepo.MockUserRepo)
+	sessionRepo := new(mockRepo.MockSessionRepo)
+	authSrv := services.NewAuthService(userRepo, sessionRepo, orgRepo, mockRepo.NoopTransactor{}, rdb, "test-secret")
+	orgSrv := services.NewOrgService(orgRepo, userRepo, mockRepo.NoopTransactor{}, authSrv)
+
+	r := gin.New()
+	orgs := r.Group("/orgs")
+	orgs.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Set(tenant.ContextKey, uint(2))
+		c.Next()
+	})
+	orgs.POST("", CreateOrg(orgSrv))
+	orgs.GET("", ListOrgs(orgSrv))
+	orgs.GET("/:id/members", ListOrgMembers(orgSrv))
+	orgs.POST("/:id/switch", SwitchOrg(orgSrv, authSrv))
+	return r, orgRepo, userRepo, sessionRepo
+}
+
+func TestCreateOrgHandler(t *testing.T) {
+	router, orgRepo, _, _ := setupOrgRouter(t)
+	orgRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Organization")).Return(uint(7), nil)
+	orgRepo.On("AddMember", mock.Anything, mock.AnythingOfType("*domain.Membership")).Return(nil)
+
+	body, _ := json.Marshal(dto.CreateOrgReq{Name: "Acme"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orgs", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	orgRepo.AssertExpectations(t)
+}
+
+func TestListOrgsHandler_MarksActive(t *testing.T) {
+	router, orgRepo, _, _ := setupOrgRouter(t)
+	orgRepo.On("ListMemberships", mock.Anything, uint(1)).Return([]domain.Membership{
+		{OrganizationID: 2, Organization: &domain.Organization{Name: "john", Personal: true}, Role: enum.MemberOwner},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/orgs", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data []dto.OrgResp `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, resp.Data[0].Active)
+}
+
+func TestListOrgMembersHandler_NotMember(t *testing.T) {
+	router, orgRepo, _, _ := setupOrgRouter(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/orgs/7/members", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSwitchOrgHandler_SetsCookies(t *testing.T) {
+	router, orgRepo, userRepo, sessionRepo := setupOrgRouter(t)
+	user := domain.User{Username: "john"}
+	user.ID = 1
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/orgs/7/switch", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Values("Set-Cookie"))
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportUserData godoc
+// @Summary      Export personal data
+// @Description  Download the authenticated user's profile, projects, created and assigned tasks, sessions, linked identities, notifications, reminders and saved views
+// @Tags         user
+// @Produce      application/zip
+// @Produce      json
+// @Security     BearerAuth
+// @Param        format  query     string  false  "Archive format"  Enums(zip, json)  default(zip)
+// @Success      200     {object}  dto.UserExport
+// @Failure      400     {object}  dto.Response
+// @Failure      401     {object}  dto.Response
+// @Failure      404     {object}  dto.Response
+// @Router       /v1/user/export [get]
+func ExportUserData(privacySrv *services.PrivacyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+		query := dto.ExportQuery{}
+		if err := c.ShouldBindQuery(&query); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		name := fmt.Sprintf("user-%d-export-%s", userID, time.Now().UTC().Format("20060102"))
+		if query.Format == "json" {
+			export, err := privacySrv.ExportUser(c, userID)
+			if err != nil {
+				privacyErr(c, err)
+				return
+			}
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, name))
+			c.JSON(http.StatusOK, export)
+			return
+		}
+
+		archive, err := privacySrv.ExportUserArchive(c, userID)
+		if err != nil {
+			privacyErr(c, err)
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, name))
+		c.Data(http.StatusOK, "application/zip", archive)
+	}
+}
+
+// DeleteAccount godoc
+// @Summary      Delete account
+// @Description  Anonymize or delete the authenticated user's personal data and end all sessions
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/user [delete]
+func DeleteAccount(privacySrv *services.PrivacyService, authSrv *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		if err := privacySrv.DeleteAccount(c, userID); err != nil {
+			privacyErr(c, err)
+			return
+		}
+		authSrv.ClearAuthCookies(c)
+		dto.OK(c, "account deleted", nil)
+	}
+}
+
+func privacyErr(c *gin.Context, err error) {
+	if errors.Is(err, api_error.ErrUserNotFound) {
+		dto.ErrNotFound(c, err)
+		return
+	}
+	dto.ErrInternal(c, err)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupPrivacyRouter(t *testing.T) (*gin.Engine, *mockRepo.MockUserRepo, *mockRepo.MockSessionRepo, *mockRepo.MockIdentityRepo, *mockRepo.MockTaskRepo) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	userRepo := new(mockRepo.MockUserRepo)
+	sessionRepo := new(mockRepo.MockSessionRepo)
+	identityRepo := new(mockRepo.MockIdentityRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	projectRepo.On("ListByFilter", mock.Anything, mock.Anything, -1, -1).Return([]domain.Project{}, int64(0), nil).Maybe()
+	projectRepo.On("DeleteMembersByUser", mock.Anything, mock.Anything).Return(nil).Maybe()
+	orgRepo := new(mockRepo.MockOrgRepo)
+	orgRepo.On("DeleteMembershipsByUser", mock.Anything, mock.Anything).Return(nil).Maybe()
+	reminderRepo := new(mockRepo.MockReminderRepo)
+	reminderRepo.On("DeleteByUser", mock.Anything, mock.Anything).Return(nil).Maybe()
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	notificationRepo.On("DeleteByUser", mock.Anything, mock.Anything).Return(nil).Maybe()
+	notificationRepo.On("ListByUser", mock.Anything, mock.Anything, false, -1, -1).Return([]domain.Notification{}, int64(0), nil).Maybe()
+	reminderRepo.On("ListByUser", mock.Anything, mock.Anything).Return([]domain.Reminder{}, nil).Maybe()
+	viewRepo := new(mockRepo.MockViewRepo)
+	viewRepo.On("ListByOwner", mock.Anything, mock.Anything).Return([]domain.View{}, nil).Maybe()
+	viewRepo.On("ListPins", mock.Anything, mock.Anything).Return([]string{}, nil).Maybe()
+	viewRepo.On("DeleteByOwner", mock.Anything, mock.Anything).Return(nil).Maybe()
+	webhookRepo := new(mockRepo.MockWebhookRepo)
+	webhookRepo.On("DeleteByCreator", mock.Anything, mock.Anything).Return(nil).Maybe()
+	invitationRepo := new(mockRepo.MockInvitationRepo)
+	invitationRepo.On("DeletePendingByUser", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	authSrv := services.NewAuthService(userRepo, sessionRepo, orgRepo, mockRepo.NoopTransactor{}, rdb, "test-secret")
+	privacySrv := services.NewPrivacyService(userRepo, sessionRepo, identityRepo, taskRepo, projectRepo, orgRepo, reminderRepo, notificationRepo, viewRepo, webhookRepo, invitationRepo, mockRepo.NoopTransactor{}, authSrv, cfg.PrivacyCfg{})
+
+	r := gin.New()
+	user := r.Group("/user")
+	user.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	user.GET("/export", ExportUserData(privacySrv))
+	user.DELETE("", DeleteAccount(privacySrv, authSrv))
+	return r, userRepo, sessionRepo, identityRepo, taskRepo
+}
+
+func expectExport(userRepo *mockRepo.MockUserRepo, sessionRepo *mockRepo.MockSessionRepo, identityRepo *mockRepo.MockIdentityRepo, taskRepo *mockRepo.MockTaskRepo) {
+	user := domain.User{Username: "john", Email: "john@example.com"}
+	user.ID = 1
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	taskRepo.On("ListByFilter", mock.Anything, mock.Anything, mock.Anything, 0).Return([]domain.Task{{Name: "t"}}, int64(1), nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(1), false).Return([]domain.UserSession{}, nil)
+	identityRepo.On("ListByUser", mock.Anything, uint(1)).Return([]domain.UserIdentity{}, nil)
+}
+
+func TestExportUserDataHandler_JSON(t *testing.T) {
+	router, userRepo, sessionRepo, identityRepo, taskRepo := setupPrivacyRouter(t)
+	expectExport(userRepo, sessionRepo, identityRepo, taskRepo)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user/export?format=json", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Disposition"), ".json")
+	var export dto.UserExport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &export))
+	assert.Equal(t, "john", export.Profile.Username)
+	assert.Len(t, export.TasksCreated, 1)
+}
+
+func TestExportUserDataHandler_ZipByDefault(t *testing.T) {
+	router, userRepo, sessionRepo, identityRepo, taskRepo := setupPrivacyRouter(t)
+	expectExport(userRepo, sessionRepo, identityRepo, taskRepo)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user/export", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	_, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+}
+
+func TestExportUserDataHandler_InvalidFormat(t *testing.T) {
+	router, _, _, _, _ := setupPrivacyRouter(t)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/user/export?format=xml", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteAccountHandler(t *testing.T) {
+	router, userRepo, sessionRepo, identityRepo, taskRepo := setupPrivacyRouter(t)
+
+	user := domain.User{Username: "john"}
+	user.ID = 1
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(1), true).Return([]domain.UserSession{}, nil)
+	sessionRepo.On("DeleteByUser", mock.Anything, uint(1)).Return(nil)
+	identityRepo.On("DeleteByUser", mock.Anything, uint(1)).Return(nil)
+	taskRepo.On("RemoveAssignee", mock.Anything, uint(1)).Return(nil)
+	taskRepo.On("RemoveWatcher", mock.Anything, uint(1)).Return(nil)
+	taskRepo.On("ReassignCreator", mock.Anything, uint(1), (*uint)(nil)).Return(nil)
+	userRepo.On("UpdateByID", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/user", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Values("Set-Cookie"))
+	userRepo.AssertExpectations(t)
+}
+
+func TestDeleteAccountHandler_NotFound(t *testing.T) {
+	router, userRepo, _, _, _ := setupPrivacyRouter(t)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/user", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
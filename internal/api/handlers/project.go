@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateProject godoc
+// @Summary      Create a project
+// @Description  Create a project owned by the authenticated user
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateProjectReq  true  "Project data"
+// @Success      201   {object}  dto.Response{data=dto.ProjectResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Router       /v1/projects [post]
+func CreateProject(projectSrv *services.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.CreateProjectReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := projectSrv.CreateProject(c, req, userID)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.Created(c, "project created", resp)
+	}
+}
+
+// ListProjects godoc
+// @Summary      List projects
+// @Description  List projects with optional owner and archived filters
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit     query     int   false  "Limit"   default(20)
+// @Param        offset    query     int   false  "Offset"  default(0)
+// @Param        owner     query     int   false  "Owner user ID"
+// @Param        archived  query     bool  false  "Archived projects only / active only"
+// @Success      200       {object}  dto.Response{data=dto.ProjectListResp}
+// @Failure      400       {object}  dto.Response
+// @Router       /v1/projects [get]
+func ListProjects(projectSrv *services.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		filter := dto.ProjectListFilter{}
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := projectSrv.ListProjects(c, filter, pagination.Limit, pagination.Offset)
+		if err != nil {
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "projects retrieved", resp)
+	}
+}
+
+// GetProject godoc
+// @Summary      Get a project by ID
+// @Description  Retrieve a single project by its ID
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  dto.Response{data=dto.ProjectResp}
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/projects/{id} [get]
+func GetProject(projectSrv *services.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := projectSrv.GetProject(c, uint(projectID))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "project retrieved", resp)
+	}
+}
+
+// UpdateProject godoc
+// @Summary      Update a project
+// @Description  Update project fields (name, description, archived); owner only
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                   true  "Project ID"
+// @Param        body  body      dto.UpdateProjectReq  true  "Fields to update"
+// @Success      200   {object}  dto.Response{data=dto.ProjectResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/projects/{id} [put]
+func UpdateProject(projectSrv *services.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.UpdateProjectReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := projectSrv.UpdateProject(c, uint(projectID), req, userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "project updated", resp)
+	}
+}
+
+// DeleteProject godoc
+// @Summary      Delete a project
+// @Description  Delete a project; its tasks are kept without a project. Owner only
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/projects/{id} [delete]
+func DeleteProject(projectSrv *services.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := projectSrv.DeleteProject(c, uint(projectID), userID); err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "project deleted", nil)
+	}
+}
+
+// ListProjectTasks godoc
+// @Summary      List project tasks
+// @Description  List the tasks of a project with optional filtering and pagination
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int     true   "Project ID"
+// @Param        limit     query     int     false  "Limit"     default(20)
+// @Param        offset    query     int     false  "Offset"    default(0)
+// @Param        status    query     int     false  "Status filter (0=Created,1=Started,2=Done,3=Failed,4=Delayed,5=Canceled)"
+// @Param        assignee  query     int     false  "Assignee user ID"
+// @Param        filter    query     string  false  "Filter expression, e.g. status = Started and due < today"
+// @Param        sort      query     string  false  "Sort fields, - for descending, e.g. due,-updated"
+// @Success      200       {object}  dto.Response{data=dto.TaskListResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      401       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Router       /v1/projects/{id}/tasks [get]
+func ListProjectTasks(projectSrv *services.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		filter, err := bindTaskFilter(c, userID)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := projectSrv.ListProjectTasks(c, uint(projectID), filter, pagination.Limit, pagination.Offset)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "tasks retrieved", resp)
+	}
+}
+
+func projectErr(c *gin.Context, err error) {
+	var filterErr *filter.Error
+	switch {
+	case errors.Is(err, api_error.ErrProjectNotFound), errors.Is(err, api_error.ErrTaskNotFound), errors.Is(err, api_error.ErrUserNotFound),
+		errors.Is(err, api_error.ErrCustomFieldNotFound), errors.Is(err, api_error.ErrChecklistNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrProjectArchived), errors.Is(err, api_error.ErrBoardConflict),
+		errors.Is(err, api_error.ErrTransitionDenied), errors.Is(err, api_error.ErrWorkflowStatusInUse),
+		errors.Is(err, api_error.ErrCustomFieldExists):
+		dto.ErrStatus(c, http.StatusConflict, err)
+	case errors.Is(err, api_error.ErrTaskModified):
+		dto.ErrStatus(c, http.StatusPreconditionFailed, err)
+	case errors.Is(err, api_error.ErrInvalidRecurrence), errors.Is(err, api_error.ErrRecurrenceNoDue),
+		errors.Is(err, api_error.ErrBulkTargets), errors.Is(err, api_error.ErrBulkTooMany), errors.Is(err, api_error.ErrInvalidBulkOp),
+		errors.Is(err, api_error.ErrInvalidStatus), errors.Is(err, api_error.ErrUnknownStatus), errors.Is(err, api_error.ErrInvalidWorkflow),
+		errors.Is(err, api_error.ErrInvalidCustomField), errors.Is(err, api_error.ErrInvalidFieldValue), errors.As(err, &filterErr),
+		errors.Is(err, api_error.ErrInvalidChecklist):
+		dto.Err(c, err)
+	case errors.Is(err, api_error.ErrForbidden):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
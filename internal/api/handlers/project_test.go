@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupProjectRouter() (*gin.Engine, *mockRepo.MockProjectRepo, *mockRepo.MockTaskRepo) {
+	gin.SetMode(gin.TestMode)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectSrv := services.NewProjectService(projectRepo, taskRepo, new(mockRepo.MockWorkflowRepo), new(mockRepo.MockCustomFieldRepo), mockRepo.NoopTransactor{})
+
+	r := gin.New()
+	projects := r.Group("/projects")
+	projects.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	projects.POST("", CreateProject(projectSrv))
+	projects.GET("", ListProjects(projectSrv))
+	projects.GET("/:id", GetProject(projectSrv))
+	projects.PUT("/:id", UpdateProject(projectSrv))
+	projects.DELETE("/:id", DeleteProject(projectSrv))
+	projects.GET("/:id/tasks", ListProjectTasks(projectSrv))
+	return r, projectRepo, taskRepo
+}
+
+func TestCreateProjectHandler(t *testing.T) {
+	router, projectRepo, _ := setupProjectRouter()
+	projectRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Project")).Return(uint(1), nil)
+
+	body, _ := json.Marshal(dto.CreateProjectReq{Name: "Home"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/projects", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestCreateProjectHandler_MissingName(t *testing.T) {
+	router, _, _ := setupProjectRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/projects", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListProjectsHandler(t *testing.T) {
+	router, projectRepo, _ := setupProjectRouter()
+	projectRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.ProjectListFilter) bool {
+		return f.Owner == 1 && f.Archived != nil && !*f.Archived
+	}), 20, 0).Return([]domain.Project{{Name: "Home"}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/projects?owner=1&archived=false", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestUpdateProjectHandler_Forbidden(t *testing.T) {
+	router, projectRepo, _ := setupProjectRouter()
+	projectRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.Project{OwnerID: 7}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/projects/2", bytes.NewBufferString(`{"archived":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestDeleteProjectHandler_NotFound(t *testing.T) {
+	router, projectRepo, _ := setupProjectRouter()
+	projectRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.Project{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/projects/2", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListProjectTasksHandler(t *testing.T) {
+	router, projectRepo, taskRepo := setupProjectRouter()
+	projectRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.Project{OwnerID: 1}, nil)
+	taskRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.TaskListFilter) bool {
+		return f.ProjectID == 2
+	}), 20, 0).Return([]domain.Task{{Name: "t"}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/projects/2/tasks", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	taskRepo.AssertExpectations(t)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateReminder godoc
+// @Summary      Set a reminder on a task
+// @Description  Remind the authenticated user about a task at a given time or some minutes before it is due, by email, webhook or in-app notification
+// @Tags         reminders
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                    true  "Task ID"
+// @Param        body  body      dto.CreateReminderReq  true  "Reminder"
+// @Success      201   {object}  dto.Response{data=dto.ReminderResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/tasks/{id}/reminders [post]
+func CreateReminder(reminderSrv *services.ReminderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.CreateReminderReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := reminderSrv.CreateReminder(c, uint(taskID), req, userID)
+		if err != nil {
+			reminderErr(c, err)
+			return
+		}
+		dto.Created(c, "reminder created", resp)
+	}
+}
+
+// ListReminders godoc
+// @Summary      List reminders on a task
+// @Description  List the authenticated user's reminders on a task
+// @Tags         reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.Response{data=[]dto.ReminderResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/tasks/{id}/reminders [get]
+func ListReminders(reminderSrv *services.ReminderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := reminderSrv.ListReminders(c, uint(taskID), userID)
+		if err != nil {
+			reminderErr(c, err)
+			return
+		}
+		dto.OK(c, "reminders retrieved", resp)
+	}
+}
+
+// DeleteReminder godoc
+// @Summary      Delete a reminder
+// @Description  Delete one of the authenticated user's reminders
+// @Tags         reminders
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Reminder ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/reminders/{id} [delete]
+func DeleteReminder(reminderSrv *services.ReminderService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		reminderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := reminderSrv.DeleteReminder(c, uint(reminderID), userID); err != nil {
+			reminderErr(c, err)
+			return
+		}
+		dto.OK(c, "reminder deleted", nil)
+	}
+}
+
+func reminderErr(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api_error.ErrTaskNotFound), errors.Is(err, api_error.ErrReminderNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrRemindAtInPast), errors.Is(err, api_error.ErrReminderNoDue),
+		errors.Is(err, api_error.ErrForbiddenURL):
+		dto.Err(c, err)
+	case errors.Is(err, api_error.ErrForbidden):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
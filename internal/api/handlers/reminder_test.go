@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bytes"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupReminderRouter() (*gin.Engine, *mockRepo.MockReminderRepo, *mockRepo.MockTaskRepo) {
+	gin.SetMode(gin.TestMode)
+	reminderRepo := new(mockRepo.MockReminderRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	reminderSrv := services.NewReminderService(reminderRepo, taskRepo, nil, nil, nil, nil, cfg.ReminderCfg{})
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	r.POST("/tasks/:id/reminders", CreateReminder(reminderSrv))
+	r.GET("/tasks/:id/reminders", ListReminders(reminderSrv))
+	r.DELETE("/reminders/:id", DeleteReminder(reminderSrv))
+	return r, reminderRepo, taskRepo
+}
+
+func TestCreateReminderHandler_Success(t *testing.T) {
+	router, reminderRepo, taskRepo := setupReminderRouter()
+
+	taskRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Task{}, nil)
+	reminderRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Reminder")).Return(uint(8), nil)
+
+	at := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks/3/reminders", bytes.NewBufferString(`{"remind_at":"`+at+`","channel":"in_app"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	reminderRepo.AssertExpectations(t)
+}
+
+func TestCreateReminderHandler_WebhookNeedsTarget(t *testing.T) {
+	router, reminderRepo, _ := setupReminderRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks/3/reminders", bytes.NewBufferString(`{"before_due_minutes":30,"channel":"webhook"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	reminderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateReminderHandler_UnknownChannel(t *testing.T) {
+	router, _, _ := setupReminderRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks/3/reminders", bytes.NewBufferString(`{"before_due_minutes":30,"channel":"sms"}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestDeleteReminderHandler_NotFound(t *testing.T) {
+	router, reminderRepo, _ := setupReminderRouter()
+
+	reminderRepo.On("GetByID", mock.Anything, uint(8)).Return(domain.Reminder{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/reminders/8", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
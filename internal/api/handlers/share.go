@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateShare godoc
+// @Summary      Create a share link
+// @Description  Create a public read-only link to a task or a project, optionally expiring. Editors only
+// @Tags         shares
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateShareReq  true  "What to share"
+// @Success      201   {object}  dto.Response{data=dto.ShareResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/shares [post]
+func CreateShare(shareSrv *services.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.CreateShareReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := shareSrv.CreateShare(c, req, userID)
+		if err != nil {
+			shareErr(c, err)
+			return
+		}
+		dto.Created(c, "share link created", resp)
+	}
+}
+
+// ListShares godoc
+// @Summary      List share links
+// @Description  List the active share links, optionally for one task or project. Editors only
+// @Tags         shares
+// @Produce      json
+// @Security     BearerAuth
+// @Param        task_id     query     int  false  "Task ID"
+// @Param        project_id  query     int  false  "Project ID"
+// @Success      200         {object}  dto.Response{data=[]dto.ShareResp}
+// @Failure      400         {object}  dto.Response
+// @Failure      403         {object}  dto.Response
+// @Failure      404         {object}  dto.Response
+// @Router       /v1/shares [get]
+func ListShares(shareSrv *services.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		filter := dto.ShareListFilter{}
+		if err := c.ShouldBindQuery(&filter); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := shareSrv.ListShares(c, filter, userID)
+		if err != nil {
+			shareErr(c, err)
+			return
+		}
+		dto.OK(c, "share links retrieved", resp)
+	}
+}
+
+// RevokeShare godoc
+// @Summary      Revoke a share link
+// @Description  Disable a share link for good. Editors only
+// @Tags         shares
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Share link ID"
+// @Success      200  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/shares/{id} [delete]
+func RevokeShare(shareSrv *services.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		shareID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := shareSrv.RevokeShare(c, uint(shareID), userID); err != nil {
+			shareErr(c, err)
+			return
+		}
+		dto.OK(c, "share link revoked", nil)
+	}
+}
+
+// GetShared godoc
+// @Summary      View a shared task or project
+// @Description  Public read-only view behind a share link; no authentication needed
+// @Tags         shares
+// @Produce      json
+// @Param        token   path      string  true   "Share token"
+// @Param        limit   query     int     false  "Limit"   default(20)
+// @Param        offset  query     int     false  "Offset"  default(0)
+// @Success      200     {object}  dto.Response{data=dto.SharedResp}
+// @Failure      404     {object}  dto.Response
+// @Router       /v1/shared/{token} [get]
+func GetShared(shareSrv *services.ShareService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := shareSrv.Resolve(c, c.Param("token"), pagination.Limit, pagination.Offset)
+		if err != nil {
+			shareErr(c, err)
+			return
+		}
+		dto.OK(c, "shared content retrieved", resp)
+	}
+}
+
+func shareErr(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api_error.ErrShareNotFound), errors.Is(err, api_error.ErrTaskNotFound),
+		errors.Is(err, api_error.ErrProjectNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrForbidden):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	case errors.Is(err, api_error.ErrExpiryInPast):
+		dto.Err(c, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupShareRouter() (*gin.Engine, *mockRepo.MockShareRepo, *mockRepo.MockTaskRepo) {
+	gin.SetMode(gin.TestMode)
+	shareRepo := new(mockRepo.MockShareRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	shareSrv := services.NewShareService(shareRepo, taskRepo, new(mockRepo.MockProjectRepo), new(mockRepo.MockOrgRepo), "test-secret")
+
+	r := gin.New()
+	r.GET("/shared/:token", GetShared(shareSrv))
+	shares := r.Group("/shares")
+	shares.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	shares.POST("", CreateShare(shareSrv))
+	shares.GET("", ListShares(shareSrv))
+	shares.DELETE("/:id", RevokeShare(shareSrv))
+	return r, shareRepo, taskRepo
+}
+
+func TestCreateShareHandler_TaskAndProjectExclusive(t *testing.T) {
+	router, _, _ := setupShareRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/shares", bytes.NewBufferString(`{"task_id":1,"project_id":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCreateShareHandler_NothingShared(t *testing.T) {
+	router, _, _ := setupShareRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/shares", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSharedHandler_WhitelistedFields(t *testing.T) {
+	router, shareRepo, taskRepo := setupShareRouter()
+
+	creator := uint(1)
+	task := domain.Task{Name: "Deliver mockups", CreatedByUserID: &creator}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	var stored *domain.ShareLink
+	shareRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.ShareLink)
+	}).Return(uint(2), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/shares", bytes.NewBufferString(`{"task_id":1}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		Data dto.ShareResp `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	shareRepo.On("GetByNonce", mock.Anything, stored.Nonce).Return(*stored, nil)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/shared/"+created.Data.Token, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Deliver mockups")
+	assert.NotContains(t, w.Body.String(), "created_by")
+	assert.NotContains(t, w.Body.String(), `"id"`)
+}
+
+func TestSharedHandler_InvalidToken(t *testing.T) {
+	router, _, _ := setupShareRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/shared/garbage", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
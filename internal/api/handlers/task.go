@@ -1,13 +1,24 @@
 package handlers
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"graph-interview/internal/api/handlers/dto"
 	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/filter"
 	"graph-interview/internal/services"
+	"graph-interview/pkg/jsonpatch"
+	"io"
+	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
 )
 
 // CreateTask godoc
@@ -17,10 +28,14 @@ import (
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        body  body      dto.CreateTaskReq  true  "Task data"
-// @Success      201   {object}  dto.Response{data=dto.TaskResp}
-// @Failure      400   {object}  dto.Response
-// @Failure      401   {object}  dto.Response
+// @Param        Idempotency-Key  header    string             false  "Makes retries return the first response instead of creating the task again"
+// @Param        body             body      dto.CreateTaskReq  true   "Task data"
+// @Success      201              {object}  dto.Response{data=dto.TaskResp}
+// @Failure      400              {object}  dto.Response
+// @Failure      401              {object}  dto.Response
+// @Failure      403              {object}  dto.Response
+// @Failure      404              {object}  dto.Response
+// @Failure      409              {object}  dto.Response
 // @Router       /v1/tasks [post]
 func CreateTask(taskSrv *services.TaskService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -38,9 +53,10 @@ func CreateTask(taskSrv *services.TaskService) gin.HandlerFunc {
 
 		resp, err := taskSrv.CreateTask(c, req, userID)
 		if err != nil {
-			dto.ErrInternal(c, err)
+			projectErr(c, err)
 			return
 		}
+		c.Header("ETag", taskETag(resp.Version))
 		dto.Created(c, "task created", resp)
 	}
 }
@@ -51,9 +67,12 @@ func CreateTask(taskSrv *services.TaskService) gin.HandlerFunc {
 // @Tags         tasks
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Task ID"
-// @Success      200  {object}  dto.Response{data=dto.TaskResp}
-// @Failure      404  {object}  dto.Response
+// @Param        id             path      int     true   "Task ID"
+// @Param        If-None-Match  header    string  false  "ETag of a cached copy"
+// @Success      200            {object}  dto.Response{data=dto.TaskResp}
+// @Success      304            "Cached copy is current"
+// @Failure      404            {object}  dto.Response
+// @Header       200,304        {string}  ETag  "Task version"
 // @Router       /v1/tasks/{id} [get]
 func GetTask(taskSrv *services.TaskService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -72,6 +91,12 @@ func GetTask(taskSrv *services.TaskService) gin.HandlerFunc {
 			dto.ErrInternal(c, err)
 			return
 		}
+		etag := taskETag(resp.Version)
+		c.Header("ETag", etag)
+		if noneMatch(c.GetHeader("If-None-Match"), etag) {
+			c.Status(http.StatusNotModified)
+			return
+		}
 		dto.OK(c, "task retrieved", resp)
 	}
 }
@@ -82,23 +107,35 @@ func GetTask(taskSrv *services.TaskService) gin.HandlerFunc {
 // @Tags         tasks
 // @Produce      json
 // @Security     BearerAuth
-// @Param        limit     query     int     false  "Limit"     default(20)
-// @Param        offset    query     int     false  "Offset"    default(0)
-// @Param        status    query     int     false  "Status filter (0=Created,1=Started,2=Done,3=Failed,4=Delayed,5=Canceled)"
-// @Param        assignee  query     int     false  "Assignee user ID"
-// @Success      200       {object}  dto.Response{data=dto.TaskListResp}
-// @Failure      400       {object}  dto.Response
+// @Param        limit       query     int     false  "Limit"     default(20)
+// @Param        offset      query     int     false  "Offset"    default(0)
+// @Param        status      query     int     false  "Status filter (0=Created,1=Started,2=Done,3=Failed,4=Delayed,5=Canceled)"
+// @Param        assignee    query     int     false  "Assignee user ID"
+// @Param        project_id  query     int     false  "Project ID"
+// @Param        series_id   query     int     false  "Recurring series ID"
+// @Param        label       query     string  false  "Label"
+// @Param        filter      query     string  false  "Filter expression, e.g. status in (Started, Delayed) and assignee = me and created >= -7d. Custom fields are cf.<key>. With project_id, statuses of its workflow can be named"
+// @Param        sort        query     string  false  "Sort fields, - for descending, e.g. -updated,name or cf.story_points"
+// @Success      200         {object}  dto.Response{data=dto.TaskListResp}
+// @Failure      400         {object}  dto.Response
+// @Failure      401         {object}  dto.Response
 // @Router       /v1/tasks [get]
 func ListTasks(taskSrv *services.TaskService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
 		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
 		if err := c.ShouldBindQuery(&pagination); err != nil {
 			dto.Err(c, err)
 			return
 		}
 
-		filter := dto.TaskListFilter{}
-		if err := c.ShouldBindQuery(&filter); err != nil {
+		filter, err := bindTaskFilter(c, userID)
+		if err != nil {
 			dto.Err(c, err)
 			return
 		}
@@ -113,17 +150,21 @@ func ListTasks(taskSrv *services.TaskService) gin.HandlerFunc {
 }
 
 // UpdateTask godoc
-// @Summary      Update a task
-// @Description  Update task fields (name, description, status)
+// @Summary      Replace a task
+// @Description  Replace the editable fields of a task (name, description, status, due date, recurrence, labels); omitted fields are cleared. Completing a recurring task creates its next occurrence. With If-Match, the update only applies to that version of the task
 // @Tags         tasks
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id    path      int              true  "Task ID"
-// @Param        body  body      dto.UpdateTaskReq  true  "Fields to update"
-// @Success      200   {object}  dto.Response{data=dto.TaskResp}
-// @Failure      400   {object}  dto.Response
-// @Failure      404   {object}  dto.Response
+// @Param        id        path      int                true   "Task ID"
+// @Param        If-Match  header    string             false  "ETag the update is based on"
+// @Param        body      body      dto.UpdateTaskReq  true   "New task state"
+// @Success      200       {object}  dto.Response{data=dto.TaskResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Header       200       {string}  ETag  "New task version"
 // @Router       /v1/tasks/{id} [put]
 func UpdateTask(taskSrv *services.TaskService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -145,43 +186,121 @@ func UpdateTask(taskSrv *services.TaskService) gin.HandlerFunc {
 			return
 		}
 
-		resp, err := taskSrv.UpdateTask(c, uint(taskID), req, userID)
+		resp, err := taskSrv.UpdateTask(c, uint(taskID), req, userID, matchVersion(c.GetHeader("If-Match")))
 		if err != nil {
-			if errors.Is(err, api_error.ErrTaskNotFound) {
-				dto.ErrNotFound(c, err)
-				return
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "task updated", resp)
+	}
+}
+
+// PatchTask godoc
+// @Summary      Patch a task
+// @Description  Apply a JSON Merge Patch (application/merge-patch+json, null clears a field) or a JSON Patch (application/json-patch+json) to the editable fields of a task, as returned by PUT. With If-Match, the patch only applies to that version of the task
+// @Tags         tasks
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int     true   "Task ID"
+// @Param        If-Match  header    string  false  "ETag the patch is based on"
+// @Param        body      body      object  true   "Patch document"
+// @Success      200       {object}  dto.Response{data=dto.TaskResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      409       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Failure      415       {object}  dto.Response
+// @Failure      422       {object}  dto.Response
+// @Header       200       {string}  ETag  "New task version"
+// @Router       /v1/tasks/{id} [patch]
+func PatchTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		var apply func(doc, patch []byte) ([]byte, error)
+		switch c.ContentType() {
+		case mergePatchType:
+			apply = jsonpatch.MergePatch
+		case jsonPatchType:
+			apply = jsonpatch.Apply
+		default:
+			c.Header("Accept-Patch", mergePatchType+", "+jsonPatchType)
+			dto.ErrStatus(c, http.StatusUnsupportedMediaType, api_error.ErrUnsupportedPatch)
+			return
+		}
+		patch, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.PatchTask(c, uint(taskID), func(current dto.UpdateTaskReq) (dto.UpdateTaskReq, error) {
+			doc, err := json.Marshal(current)
+			if err != nil {
+				return current, err
 			}
-			dto.ErrInternal(c, err)
+			if doc, err = apply(doc, patch); err != nil {
+				return current, err
+			}
+			var req dto.UpdateTaskReq
+			dec := json.NewDecoder(bytes.NewReader(doc))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&req); err != nil {
+				return current, fmt.Errorf("%w: %v", jsonpatch.ErrInvalidPatch, err)
+			}
+			return req, binding.Validator.ValidateStruct(req)
+		}, userID, matchVersion(c.GetHeader("If-Match")))
+		if err != nil {
+			patchErr(c, err)
 			return
 		}
+		c.Header("ETag", taskETag(resp.Version))
 		dto.OK(c, "task updated", resp)
 	}
 }
 
 // DeleteTask godoc
 // @Summary      Delete a task
-// @Description  Soft-delete a task by ID
+// @Description  Soft-delete a task by ID. With If-Match, only that version of the task is deleted
 // @Tags         tasks
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id   path      int  true  "Task ID"
-// @Success      200  {object}  dto.Response
-// @Failure      404  {object}  dto.Response
+// @Param        id        path      int     true   "Task ID"
+// @Param        If-Match  header    string  false  "ETag the deletion is based on"
+// @Success      200       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
 // @Router       /v1/tasks/{id} [delete]
 func DeleteTask(taskSrv *services.TaskService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
 		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
 		if err != nil {
 			dto.Err(c, err)
 			return
 		}
 
-		if err := taskSrv.DeleteTask(c, uint(taskID)); err != nil {
-			if errors.Is(err, api_error.ErrTaskNotFound) {
-				dto.ErrNotFound(c, err)
-				return
-			}
-			dto.ErrInternal(c, err)
+		if err := taskSrv.DeleteTask(c, uint(taskID), userID, matchVersion(c.GetHeader("If-Match"))); err != nil {
+			projectErr(c, err)
 			return
 		}
 		dto.OK(c, "task deleted", nil)
@@ -196,6 +315,7 @@ func DeleteTask(taskSrv *services.TaskService) gin.HandlerFunc {
 // @Security     BearerAuth
 // @Param        id   path      int  true  "Task ID"
 // @Success      200  {object}  dto.Response{data=dto.TaskResp}
+// @Failure      403  {object}  dto.Response
 // @Failure      404  {object}  dto.Response
 // @Router       /v1/tasks/{id}/archive [patch]
 func ArchiveTask(taskSrv *services.TaskService) gin.HandlerFunc {
@@ -214,13 +334,431 @@ func ArchiveTask(taskSrv *services.TaskService) gin.HandlerFunc {
 
 		resp, err := taskSrv.ArchiveTask(c, uint(taskID), userID)
 		if err != nil {
-			if errors.Is(err, api_error.ErrTaskNotFound) {
-				dto.ErrNotFound(c, err)
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "task archived", resp)
+	}
+}
+
+// MoveTask godoc
+// @Summary      Move a task to another project
+// @Description  Set the task's project; a null project_id removes it from its project
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int              true  "Task ID"
+// @Param        body  body      dto.MoveTaskReq  true  "Target project"
+// @Success      200   {object}  dto.Response{data=dto.TaskResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Failure      409   {object}  dto.Response
+// @Router       /v1/tasks/{id}/project [patch]
+func MoveTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.MoveTaskReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.MoveTask(c, uint(taskID), req.ProjectID, userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "task moved", resp)
+	}
+}
+
+// RepositionTask godoc
+// @Summary      Move a task on the board
+// @Description  Put a task into a status column, before before_id and/or after after_id, changing its status and position together; with neither it goes last. status_name picks the column of a status of the task's workflow, subject to its transitions. Completing a recurring task creates its next occurrence. With If-Match, the move only applies to that version of the task
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id        path      int                    true   "Task ID"
+// @Param        If-Match  header    string                 false  "ETag the move is based on"
+// @Param        body      body      dto.RepositionTaskReq  true   "Target column and neighbors"
+// @Success      200       {object}  dto.Response{data=dto.TaskResp}
+// @Failure      400       {object}  dto.Response
+// @Failure      403       {object}  dto.Response
+// @Failure      404       {object}  dto.Response
+// @Failure      409       {object}  dto.Response
+// @Failure      412       {object}  dto.Response
+// @Header       200       {string}  ETag  "New task version"
+// @Router       /v1/tasks/{id}/move [post]
+func RepositionTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.RepositionTaskReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.RepositionTask(c, uint(taskID), req, userID, matchVersion(c.GetHeader("If-Match")))
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		c.Header("ETag", taskETag(resp.Version))
+		dto.OK(c, "task moved", resp)
+	}
+}
+
+// GetBoard godoc
+// @Summary      Get the task board
+// @Description  List tasks by status column, each in board order, with up to limit tasks per column. The columns are the statuses of the workflow of project_id, or without it the built-in ones (Created, Started, Delayed, Done, Failed, Canceled). The filters of GET /v1/tasks apply; status narrows the board to the columns counting as it
+// @Tags         tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit       query     int     false  "Tasks per column"  default(20)
+// @Param        status      query     int     false  "Only the columns counting as this status (0=Created,1=Started,2=Done,3=Failed,4=Delayed,5=Canceled)"
+// @Param        assignee    query     int     false  "Assignee user ID"
+// @Param        project_id  query     int     false  "Project ID"
+// @Param        label       query     string  false  "Label"
+// @Param        filter      query     string  false  "Filter expression, e.g. assignee = me and label = bug"
+// @Success      200         {object}  dto.Response{data=dto.BoardResp}
+// @Failure      400         {object}  dto.Response
+// @Failure      401         {object}  dto.Response
+// @Router       /v1/board [get]
+func GetBoard(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		filter, err := bindTaskFilter(c, userID)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.Board(c, filter, pagination.Limit)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "board retrieved", resp)
+	}
+}
+
+// BulkTasks godoc
+// @Summary      Change many tasks at once
+// @Description  Apply operations (set_status, assign, unassign, add_labels, remove_labels, archive, delete) to the listed tasks or to those matching a filter, in one transaction. Nothing is written unless every task passes, nor in a dry run; each task gets its own result
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.BulkTaskReq  true  "Targets and operations"
+// @Success      200   {object}  dto.Response{data=dto.BulkTaskResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      422   {object}  dto.Response{data=dto.BulkTaskResp}
+// @Router       /v1/tasks/bulk [post]
+func BulkTasks(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.BulkTaskReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+		if req.Filter != nil {
+			if err := parseTaskFilter(req.Filter, userID); err != nil {
+				dto.Err(c, err)
 				return
 			}
-			dto.ErrInternal(c, err)
+		}
+
+		resp, err := taskSrv.BulkTasks(c, req, userID)
+		if err != nil {
+			projectErr(c, err)
 			return
 		}
-		dto.OK(c, "task archived", resp)
+		switch {
+		case resp.DryRun:
+			dto.OK(c, "bulk operation checked", resp)
+		case resp.Applied:
+			dto.OK(c, "bulk operation applied", resp)
+		default:
+			c.JSON(http.StatusUnprocessableEntity, dto.Response{Success: false, Error: "bulk operation not applied", Data: resp})
+		}
+	}
+}
+
+// AssignTask godoc
+// @Summary      Assign a task
+// @Description  Add a member of the organization to the task's assignees and notify them
+// @Tags         tasks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                true  "Task ID"
+// @Param        body  body      dto.AssignTaskReq  true  "Assignee"
+// @Success      200   {object}  dto.Response{data=dto.TaskResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/tasks/{id}/assignees [post]
+func AssignTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.AssignTaskReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := taskSrv.AssignTask(c, uint(taskID), req.UserID, userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "task assigned", resp)
+	}
+}
+
+// UnassignTask godoc
+// @Summary      Unassign a task
+// @Description  Remove a user from the task's assignees
+// @Tags         tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int  true  "Task ID"
+// @Param        user_id  path      int  true  "Assignee user ID"
+// @Success      200      {object}  dto.Response
+// @Failure      400      {object}  dto.Response
+// @Failure      403      {object}  dto.Response
+// @Failure      404      {object}  dto.Response
+// @Router       /v1/tasks/{id}/assignees/{user_id} [delete]
+func UnassignTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+		assigneeID, err := strconv.ParseUint(c.Param("user_id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := taskSrv.UnassignTask(c, uint(taskID), uint(assigneeID), userID); err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "task unassigned", nil)
+	}
+}
+
+// WatchTask godoc
+// @Summary      Watch a task
+// @Description  Get notified when the task changes
+// @Tags         tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/tasks/{id}/watch [put]
+func WatchTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := taskSrv.WatchTask(c, uint(taskID), userID); err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "task watched", nil)
+	}
+}
+
+// UnwatchTask godoc
+// @Summary      Stop watching a task
+// @Tags         tasks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Task ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/tasks/{id}/watch [delete]
+func UnwatchTask(taskSrv *services.TaskService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		taskID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := taskSrv.UnwatchTask(c, uint(taskID), userID); err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "task unwatched", nil)
+	}
+}
+
+const (
+	mergePatchType = "application/merge-patch+json"
+	jsonPatchType  = "application/json-patch+json"
+)
+
+// patchErr maps the errors of applying a patch to a task.
+func patchErr(c *gin.Context, err error) {
+	var invalid validator.ValidationErrors
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		dto.ErrStatus(c, http.StatusConflict, err)
+	case errors.Is(err, jsonpatch.ErrInvalidPatch), errors.As(err, &invalid):
+		dto.ErrStatus(c, http.StatusUnprocessableEntity, err)
+	default:
+		projectErr(c, err)
+	}
+}
+
+// bindTaskFilter binds the task list filter of the query string and parses its filter
+// expression for userID.
+func bindTaskFilter(c *gin.Context, userID uint) (dto.TaskListFilter, error) {
+	f := dto.TaskListFilter{}
+	if err := c.ShouldBindQuery(&f); err != nil {
+		return f, err
+	}
+	return f, parseTaskFilter(&f, userID)
+}
+
+// parseTaskFilter resolves the filter expression and sort order of f, if any, for userID.
+func parseTaskFilter(f *dto.TaskListFilter, userID uint) error {
+	orders, err := filter.ParseSort(f.Sort)
+	if err != nil {
+		return err
+	}
+	f.Orders = orders
+	if f.Query == "" {
+		return nil
+	}
+	expr, err := filter.Parse(f.Query, filter.Env{UserID: userID, Now: time.Now()})
+	if err != nil {
+		return err
+	}
+	f.Parsed = expr
+	return nil
+}
+
+// taskETag is the entity tag of a task version.
+func taskETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// matchVersion returns the task version an If-Match header asks for, or 0 when any will do.
+// Headers no task version can match, such as weak or several tags, yield -1.
+func matchVersion(header string) int {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0
+	}
+	tag, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return -1
+	}
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return -1
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		return -1
+	}
+	return version
+}
+
+// noneMatch reports whether an If-None-Match header lists etag, comparing weakly.
+func noneMatch(header, etag string) bool {
+	for tag := range strings.SplitSeq(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
 	}
+	return false
 }
@@ -11,7 +11,9 @@ import (
 	"graph-interview/internal/services"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -29,10 +31,19 @@ func setupTaskRouter(taskSrv *services.TaskService) *gin.Engine {
 	})
 	tasks.POST("", CreateTask(taskSrv))
 	tasks.GET("", ListTasks(taskSrv))
+	tasks.POST("/bulk", BulkTasks(taskSrv))
 	tasks.GET("/:id", GetTask(taskSrv))
 	tasks.PUT("/:id", UpdateTask(taskSrv))
+	tasks.PATCH("/:id", PatchTask(taskSrv))
 	tasks.DELETE("/:id", DeleteTask(taskSrv))
 	tasks.PATCH("/:id/archive", ArchiveTask(taskSrv))
+	tasks.PATCH("/:id/project", MoveTask(taskSrv))
+	tasks.POST("/:id/move", RepositionTask(taskSrv))
+	tasks.PUT("/:id/watch", WatchTask(taskSrv))
+	r.GET("/board", func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	}, GetBoard(taskSrv))
 	return r
 }
 
@@ -49,9 +60,10 @@ func setupTaskRouterNoAuth(taskSrv *services.TaskService) *gin.Engine {
 
 func TestCreateTaskHandler(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Created}).Return("", nil)
 	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).
 		Run(func(args mock.Arguments) {
 			task := args.Get(1).(*domain.Task)
@@ -79,7 +91,7 @@ func TestCreateTaskHandler(t *testing.T) {
 
 func TestCreateTaskHandler_InvalidBody(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	body, _ := json.Marshal(map[string]string{"invalid": "body"})
@@ -94,7 +106,7 @@ func TestCreateTaskHandler_InvalidBody(t *testing.T) {
 
 func TestCreateTaskHandler_Unauthorized(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouterNoAuth(taskSrv)
 
 	body, _ := json.Marshal(dto.CreateTaskReq{
@@ -112,9 +124,10 @@ func TestCreateTaskHandler_Unauthorized(t *testing.T) {
 
 func TestCreateTaskHandler_RepoError(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Created}).Return("", nil)
 	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).
 		Return(uint(0), errors.New("db error"))
 
@@ -134,7 +147,7 @@ func TestCreateTaskHandler_RepoError(t *testing.T) {
 
 func TestGetTaskHandler(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("GetByID", mock.Anything, uint(1)).
@@ -158,7 +171,7 @@ func TestGetTaskHandler(t *testing.T) {
 
 func TestGetTaskHandler_NotFound(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("GetByID", mock.Anything, uint(999)).
@@ -174,7 +187,7 @@ func TestGetTaskHandler_NotFound(t *testing.T) {
 
 func TestGetTaskHandler_InvalidID(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	w := httptest.NewRecorder()
@@ -184,9 +197,38 @@ func TestGetTaskHandler_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestGetTaskHandler_ETag(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Task", Version: 3}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/tasks/1", nil)
+	req.Header.Set("If-None-Match", `"2", W/"3"`)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/tasks/1", nil)
+	req.Header.Set("If-None-Match", `"2"`)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
 func TestListTasksHandler(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	tasks := []domain.Task{
@@ -211,7 +253,7 @@ func TestListTasksHandler(t *testing.T) {
 
 func TestListTasksHandler_EmptyResult(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("ListByFilter", mock.Anything, mock.Anything, 20, 0).
@@ -225,9 +267,44 @@ func TestListTasksHandler_EmptyResult(t *testing.T) {
 	taskRepo.AssertExpectations(t)
 }
 
+func TestListTasksHandler_Filter(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	taskRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.TaskListFilter) bool {
+		return f.Parsed != nil && f.Parsed.String() == "(status in (Started, Delayed) and assignee = 1)"
+	}), 20, 0).Return([]domain.Task{}, int64(0), nil)
+
+	q := url.Values{"filter": {"status in (Started, Delayed) and assignee = me"}}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks?"+q.Encode(), nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestListTasksHandler_InvalidFilter(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	q := url.Values{"filter": {"status = Started and stats = Done"}}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks?"+q.Encode(), nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var resp dto.Response
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, `filter: unknown field at position 22 ("stats")`, resp.Error)
+	taskRepo.AssertNotCalled(t, "ListByFilter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestListTasksHandler_RepoError(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("ListByFilter", mock.Anything, mock.Anything, 20, 0).
@@ -243,7 +320,7 @@ func TestListTasksHandler_RepoError(t *testing.T) {
 
 func TestUpdateTaskHandler(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	existingTask := domain.Task{
@@ -253,10 +330,9 @@ func TestUpdateTaskHandler(t *testing.T) {
 	existingTask.ID = 1
 
 	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existingTask, nil)
-	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
 
-	newName := "New Name"
-	body, _ := json.Marshal(dto.UpdateTaskReq{Name: &newName})
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/tasks/1", bytes.NewBuffer(body))
@@ -269,14 +345,13 @@ func TestUpdateTaskHandler(t *testing.T) {
 
 func TestUpdateTaskHandler_NotFound(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("GetByID", mock.Anything, uint(999)).
 		Return(domain.Task{}, gorm.ErrRecordNotFound)
 
-	newName := "New Name"
-	body, _ := json.Marshal(dto.UpdateTaskReq{Name: &newName})
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/tasks/999", bytes.NewBuffer(body))
@@ -289,11 +364,10 @@ func TestUpdateTaskHandler_NotFound(t *testing.T) {
 
 func TestUpdateTaskHandler_Unauthorized(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouterNoAuth(taskSrv)
 
-	newName := "New Name"
-	body, _ := json.Marshal(dto.UpdateTaskReq{Name: &newName})
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/tasks/1", bytes.NewBuffer(body))
@@ -305,11 +379,10 @@ func TestUpdateTaskHandler_Unauthorized(t *testing.T) {
 
 func TestUpdateTaskHandler_InvalidID(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
-	newName := "New Name"
-	body, _ := json.Marshal(dto.UpdateTaskReq{Name: &newName})
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PUT", "/tasks/abc", bytes.NewBuffer(body))
@@ -319,9 +392,168 @@ func TestUpdateTaskHandler_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestUpdateTaskHandler_IfMatch(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Old Name", Version: 2}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Task).Version++
+		}).
+		Return(true, nil)
+
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/tasks/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+}
+
+func TestUpdateTaskHandler_StaleIfMatch(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Old Name", Version: 3}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/tasks/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTaskHandler_ConcurrentUpdate(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Old Name", Version: 2}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(false, nil)
+
+	body, _ := json.Marshal(dto.UpdateTaskReq{Name: "New Name"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/tasks/1", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestPatchTaskHandler_MergePatch(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	task := domain.Task{Name: "Task", Description: "Desc", DueDate: &due, Version: 2}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.DueDate == nil && task.Description == "Desc"
+	}), []string{"updated_by_user_id", "status", "due_date"}).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Task).Version++
+		}).
+		Return(true, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(`{"due_date":null,"status":1}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	req.Header.Set("If-Match", `"2"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	taskRepo.AssertExpectations(t)
+}
+
+func TestPatchTaskHandler_JSONPatch(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Task", Labels: []string{"bug"}}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Name == "Renamed" && assert.ObjectsAreEqual([]string{"bug", "urgent"}, task.Labels)
+	}), []string{"updated_by_user_id", "name", "labels"}).Return(true, nil)
+
+	body := `[
+		{"op": "test", "path": "/name", "value": "Task"},
+		{"op": "replace", "path": "/name", "value": "Renamed"},
+		{"op": "add", "path": "/labels/-", "value": "urgent"}
+	]`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json-patch+json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestPatchTaskHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"failed test", "application/json-patch+json", `[{"op":"test","path":"/name","value":"Other"}]`, http.StatusConflict},
+		{"missing member", "application/json-patch+json", `[{"op":"remove","path":"/owner"}]`, http.StatusUnprocessableEntity},
+		{"unknown field", "application/merge-patch+json", `{"owner":"bob"}`, http.StatusUnprocessableEntity},
+		{"invalid result", "application/merge-patch+json", `{"name":null}`, http.StatusUnprocessableEntity},
+		{"malformed", "application/merge-patch+json", `{"name":`, http.StatusUnprocessableEntity},
+		{"media type", "application/json", `{"name":"Renamed"}`, http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(mockRepo.MockTaskRepo)
+			taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+			router := setupTaskRouter(taskSrv)
+
+			task := domain.Task{Name: "Task"}
+			task.ID = 1
+			taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil).Maybe()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status == http.StatusUnsupportedMediaType {
+				assert.Contains(t, w.Header().Get("Accept-Patch"), "application/merge-patch+json")
+			}
+			taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
 func TestDeleteTaskHandler(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{}, nil)
@@ -337,7 +569,7 @@ func TestDeleteTaskHandler(t *testing.T) {
 
 func TestDeleteTaskHandler_NotFound(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("GetByID", mock.Anything, uint(999)).
@@ -353,7 +585,7 @@ func TestDeleteTaskHandler_NotFound(t *testing.T) {
 
 func TestDeleteTaskHandler_InvalidID(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	w := httptest.NewRecorder()
@@ -363,9 +595,42 @@ func TestDeleteTaskHandler_InvalidID(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestDeleteTaskHandler_StaleIfMatch(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Version: 3}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/tasks/1", nil)
+	req.Header.Set("If-Match", `W/"3"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	taskRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestMatchVersion(t *testing.T) {
+	tests := map[string]int{
+		"":         0,
+		"*":        0,
+		`"4"`:      4,
+		` "4" `:    4,
+		`W/"4"`:    -1,
+		`"4", "5"`: -1,
+		`"abc"`:    -1,
+		`"0"`:      -1,
+		"4":        -1,
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, matchVersion(header), header)
+	}
+}
+
 func TestArchiveTaskHandler(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	existingTask := domain.Task{
@@ -375,7 +640,7 @@ func TestArchiveTaskHandler(t *testing.T) {
 	existingTask.ID = 1
 
 	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existingTask, nil)
-	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), []string{"status", "updated_by_user_id"}).Return(nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), []string{"status", "updated_by_user_id"}).Return(true, nil)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("PATCH", "/tasks/1/archive", nil)
@@ -387,7 +652,7 @@ func TestArchiveTaskHandler(t *testing.T) {
 
 func TestArchiveTaskHandler_NotFound(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	taskRepo.On("GetByID", mock.Anything, uint(999)).
@@ -403,7 +668,7 @@ func TestArchiveTaskHandler_NotFound(t *testing.T) {
 
 func TestArchiveTaskHandler_Unauthorized(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouterNoAuth(taskSrv)
 
 	w := httptest.NewRecorder()
@@ -415,7 +680,7 @@ func TestArchiveTaskHandler_Unauthorized(t *testing.T) {
 
 func TestArchiveTaskHandler_InvalidID(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 	router := setupTaskRouter(taskSrv)
 
 	w := httptest.NewRecorder()
@@ -424,3 +689,187 @@ func TestArchiveTaskHandler_InvalidID(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
+
+func TestWatchTaskHandler(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "t"}, nil)
+	taskRepo.On("Watch", mock.Anything, uint(1), uint(1)).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PUT", "/tasks/1/watch", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestMoveTaskHandler_ArchivedProject(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	taskSrv := services.NewTaskService(taskRepo, projectRepo, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "t"}, nil)
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Project{Archived: true}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/tasks/1/project", bytes.NewBufferString(`{"project_id":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestMoveTaskHandler_RemoveFromProject(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "t"}, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ProjectID == nil
+	}), []string{"project_id", "updated_by_user_id"}).Return(true, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/tasks/1/project", bytes.NewBufferString(`{"project_id":null}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestCreateTaskHandler_InvalidRecurrence(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	body, _ := json.Marshal(dto.CreateTaskReq{Name: "Report", Recurrence: "FREQ=DAILY"})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestBulkTasksHandler(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Task"}
+	task.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	taskRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.Task{}, gorm.ErrRecordNotFound)
+	taskRepo.On("DeleteByID", mock.Anything, uint(1)).Return(nil)
+
+	send := func(req dto.BulkTaskReq) (*httptest.ResponseRecorder, dto.BulkTaskResp) {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBuffer(body))
+		r.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, r)
+		var resp struct {
+			Data dto.BulkTaskResp `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp.Data
+	}
+	deleteOp := []dto.BulkTaskOp{{Op: services.BulkDelete}}
+
+	w, resp := send(dto.BulkTaskReq{TaskIDs: []uint{1, 2}, Operations: deleteOp})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.False(t, resp.Applied)
+	assert.Equal(t, "task not found", resp.Results[1].Error)
+
+	w, resp = send(dto.BulkTaskReq{TaskIDs: []uint{1}, Operations: deleteOp})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, resp.Applied)
+	taskRepo.AssertCalled(t, "DeleteByID", mock.Anything, uint(1))
+
+	w, _ = send(dto.BulkTaskReq{TaskIDs: []uint{1}, Operations: []dto.BulkTaskOp{{Op: "rename"}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestRepositionTaskHandler(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	task := domain.Task{Name: "Task", Status: enum.Created, Position: "c", Version: 2}
+	task.ID = 1
+	other := domain.Task{Name: "Other", Status: enum.Done, Position: "i"}
+	other.ID = 2
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(task, nil)
+	taskRepo.On("GetByID", mock.Anything, uint(2)).Return(other, nil)
+	taskRepo.On("PositionAfter", mock.Anything, domain.Column{Status: enum.Done}, "i", uint(1)).Return("", nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), []string{"updated_by_user_id", "status", "position"}).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.Task).Version++
+		}).
+		Return(true, nil)
+
+	send := func(req dto.RepositionTaskReq, ifMatch string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/tasks/1/move", bytes.NewBuffer(body))
+		r.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		router.ServeHTTP(w, r)
+		return w
+	}
+	after := uint(2)
+
+	w := send(dto.RepositionTaskReq{Status: enum.Done, AfterID: &after}, `"2"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	var resp struct {
+		Data dto.TaskResp `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, "Done", resp.Data.Status)
+	assert.Equal(t, "r", resp.Data.Position)
+
+	w = send(dto.RepositionTaskReq{Status: enum.Started, AfterID: &after}, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = send(dto.RepositionTaskReq{Status: enum.Done}, `"1"`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestGetBoardHandler(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskSrv := services.NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+	router := setupTaskRouter(taskSrv)
+
+	taskRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.TaskListFilter) bool {
+		return *f.Status == enum.Started && f.Parsed.String() == "assignee = 1"
+	}), 5, 0).Return([]domain.Task{{Name: "Task", Status: enum.Started, Position: "i"}}, int64(7), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/board?status=1&limit=5&filter="+url.QueryEscape("assignee = me"), nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data dto.BoardResp `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, 5, resp.Data.Limit)
+	assert.Len(t, resp.Data.Columns, 1)
+	assert.Equal(t, int64(7), resp.Data.Columns[0].Total)
+	assert.Equal(t, "Task", resp.Data.Columns[0].Tasks[0].Name)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/board?status=9", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
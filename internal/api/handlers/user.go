@@ -46,6 +46,7 @@ func Register(userSrv *services.UserService) gin.HandlerFunc {
 // @Param        body  body      dto.LoginUserReq  true  "Login credentials"
 // @Success      200   {object}  dto.Response{data=dto.JWTResp}
 // @Failure      401   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
 // @Router       /v1/auth/login [post]
 func Login(authSrv *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -56,13 +57,17 @@ func Login(authSrv *services.AuthService) gin.HandlerFunc {
 		}
 		resp, err := authSrv.LoginUser(c, req)
 		if err != nil {
-			if errors.Is(err, api_error.ErrInvalidCredentials) {
+			switch {
+			case errors.Is(err, api_error.ErrInvalidCredentials):
 				dto.ErrUnauthorized(c, err)
-				return
+			case errors.Is(err, api_error.ErrUserInactive), errors.Is(err, api_error.ErrPasswordReset):
+				dto.ErrStatus(c, http.StatusForbidden, err)
+			default:
+				dto.ErrInternal(c, err)
 			}
-			dto.ErrInternal(c, err)
 			return
 		}
+		authSrv.SetAuthCookies(c, resp)
 		dto.OK(c, "login successful", resp)
 	}
 }
@@ -78,7 +83,7 @@ func Login(authSrv *services.AuthService) gin.HandlerFunc {
 // @Router       /v1/auth/logout [post]
 func Logout(authSrv *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenStr, _ := c.Cookie("access_token")
+		tokenStr, _ := c.Cookie(services.AccessTokenCookie)
 		if tokenStr == "" {
 			h := c.GetHeader("Authorization")
 			if len(h) > 7 {
@@ -112,14 +117,28 @@ func Logout(authSrv *services.AuthService) gin.HandlerFunc {
 func RefreshToken(authSrv *services.AuthService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		req := dto.RefreshTokenReq{}
+		fromCookie := false
 		if err := c.ShouldBindJSON(&req); err != nil {
 			// Try from cookie
-			refreshStr, cookieErr := c.Cookie("refresh_token")
+			refreshStr, cookieErr := c.Cookie(services.RefreshTokenCookie)
 			if cookieErr != nil || refreshStr == "" {
 				dto.Err(c, err)
 				return
 			}
 			req.RefreshToken = refreshStr
+			fromCookie = true
+		}
+
+		if fromCookie {
+			claims, err := authSrv.ParseToken(req.RefreshToken)
+			if err != nil {
+				dto.ErrUnauthorized(c, api_error.ErrInvalidToken)
+				return
+			}
+			if !authSrv.VerifyCSRF(c, claims.Subject) {
+				dto.ErrStatus(c, http.StatusForbidden, api_error.ErrInvalidCSRFToken)
+				return
+			}
 		}
 
 		resp, err := authSrv.RefreshToken(c, req.RefreshToken)
@@ -127,10 +146,40 @@ func RefreshToken(authSrv *services.AuthService) gin.HandlerFunc {
 			dto.ErrUnauthorized(c, err)
 			return
 		}
+		authSrv.SetAuthCookies(c, resp)
 		dto.OK(c, "token refreshed", resp)
 	}
 }
 
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password using a reset token; all existing sessions are revoked
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body      dto.ResetPasswordReq  true  "Reset token and new password"
+// @Success      200   {object}  dto.Response
+// @Failure      400   {object}  dto.Response
+// @Router       /v1/auth/password/reset [post]
+func ResetPassword(authSrv *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req := dto.ResetPasswordReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+		if err := authSrv.ResetPassword(c, req); err != nil {
+			if errors.Is(err, api_error.ErrInvalidResetToken) {
+				dto.Err(c, err)
+				return
+			}
+			dto.ErrInternal(c, err)
+			return
+		}
+		dto.OK(c, "password updated", nil)
+	}
+}
+
 // GetProfile godoc
 // @Summary      Get user profile
 // @Description  Get the authenticated user's profile
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"graph-interview/internal/api/handlers/dto"
 	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
 	mockRepo "graph-interview/internal/repository/mock"
 	"graph-interview/internal/services"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
@@ -36,6 +38,16 @@ func setupUserRouter(userSrv *services.UserService) *gin.Engine {
 	return r
 }
 
+// memberOrgRepo is an org repository in which every user belongs to orgID.
+func memberOrgRepo(orgID uint) *mockRepo.MockOrgRepo {
+	orgRepo := new(mockRepo.MockOrgRepo)
+	orgRepo.On("ListMemberships", mock.Anything, mock.Anything).
+		Return([]domain.Membership{{OrganizationID: orgID, Role: enum.MemberOwner}}, nil).Maybe()
+	orgRepo.On("GetMembership", mock.Anything, orgID, mock.Anything).
+		Return(domain.Membership{OrganizationID: orgID, Role: enum.MemberOwner}, nil).Maybe()
+	return orgRepo
+}
+
 func setupAuthRouter(t *testing.T) (*gin.Engine, *mockRepo.MockUserRepo, *miniredis.Miniredis) {
 	t.Helper()
 	gin.SetMode(gin.TestMode)
@@ -47,11 +59,19 @@ func setupAuthRouter(t *testing.T) (*gin.Engine, *mockRepo.MockUserRepo, *minire
 
 	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
 	userRepo := new(mockRepo.MockUserRepo)
-	authSrv := services.NewAuthService(userRepo, rdb, "test-secret")
+	sessionRepo := new(mockRepo.MockSessionRepo)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil).Maybe()
+	sessionRepo.On("GetByField", mock.Anything, "access_jti", mock.Anything).
+		Return(domain.UserSession{}, gorm.ErrRecordNotFound).Maybe()
+	sessionRepo.On("GetByField", mock.Anything, "refresh_jti", mock.Anything).
+		Return(domain.UserSession{UserID: 1, Valid: true}, nil).Maybe()
+	sessionRepo.On("UpdateByID", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	authSrv := services.NewAuthService(userRepo, sessionRepo, memberOrgRepo(1), mockRepo.NoopTransactor{}, rdb, "test-secret")
 
 	r := gin.New()
 	r.POST("/login", Login(authSrv))
 	r.POST("/refresh", RefreshToken(authSrv))
+	r.POST("/password/reset", ResetPassword(authSrv))
 
 	protected := r.Group("")
 	protected.Use(func(c *gin.Context) {
@@ -65,7 +85,7 @@ func setupAuthRouter(t *testing.T) (*gin.Engine, *mockRepo.MockUserRepo, *minire
 
 func TestRegisterHandler_Success(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 	router := setupUserRouter(userSrv)
 
 	userRepo.On("GetByField", mock.Anything, "username", "newuser").
@@ -94,7 +114,7 @@ func TestRegisterHandler_Success(t *testing.T) {
 
 func TestRegisterHandler_InvalidBody(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 	router := setupUserRouter(userSrv)
 
 	body, _ := json.Marshal(map[string]string{"username": "ab"})
@@ -109,7 +129,7 @@ func TestRegisterHandler_InvalidBody(t *testing.T) {
 
 func TestRegisterHandler_EmptyBody(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 	router := setupUserRouter(userSrv)
 
 	w := httptest.NewRecorder()
@@ -122,7 +142,7 @@ func TestRegisterHandler_EmptyBody(t *testing.T) {
 
 func TestRegisterHandler_DuplicateUsername(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 	router := setupUserRouter(userSrv)
 
 	userRepo.On("GetByField", mock.Anything, "username", "existing").
@@ -145,7 +165,7 @@ func TestRegisterHandler_DuplicateUsername(t *testing.T) {
 
 func TestGetProfileHandler_Success(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 	router := setupUserRouter(userSrv)
 
 	userRepo.On("GetByID", mock.Anything, uint(1)).
@@ -168,7 +188,7 @@ func TestGetProfileHandler_Success(t *testing.T) {
 
 func TestGetProfileHandler_Unauthorized(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -183,7 +203,7 @@ func TestGetProfileHandler_Unauthorized(t *testing.T) {
 
 func TestGetProfileHandler_UserNotFound(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	userSrv := services.NewUserService(userRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
 	router := setupUserRouter(userSrv)
 
 	userRepo.On("GetByID", mock.Anything, uint(1)).
@@ -222,6 +242,15 @@ func TestLoginHandler_Success(t *testing.T) {
 	var resp dto.Response
 	json.Unmarshal(w.Body.Bytes(), &resp)
 	assert.True(t, resp.Success)
+
+	cookies := map[string]*http.Cookie{}
+	for _, ck := range w.Result().Cookies() {
+		cookies[ck.Name] = ck
+	}
+	assert.True(t, cookies["access_token"].HttpOnly)
+	assert.True(t, cookies["refresh_token"].HttpOnly)
+	assert.False(t, cookies["csrf_token"].HttpOnly)
+	assert.NotEmpty(t, cookies["csrf_token"].Value)
 	userRepo.AssertExpectations(t)
 }
 
@@ -296,7 +325,7 @@ func TestLogoutHandler_WithBearerToken(t *testing.T) {
 		Return(user, nil)
 
 	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
-	authSrv := services.NewAuthService(userRepo, rdb, "test-secret")
+	authSrv := services.NewAuthService(userRepo, nil, nil, mockRepo.NoopTransactor{}, rdb, "test-secret")
 
 	tokens, _ := authSrv.IssueTokens("1")
 	_ = authSrv.Persist(t.Context(), tokens)
@@ -316,7 +345,7 @@ func TestLogoutHandler_NoToken(t *testing.T) {
 
 	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
 	userRepo := new(mockRepo.MockUserRepo)
-	authSrv := services.NewAuthService(userRepo, rdb, "test-secret")
+	authSrv := services.NewAuthService(userRepo, nil, nil, mockRepo.NoopTransactor{}, rdb, "test-secret")
 
 	r := gin.New()
 	r.POST("/logout", Logout(authSrv))
@@ -369,3 +398,62 @@ func TestGetUserID_Valid(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, uint(42), id)
 }
+
+func TestRefreshTokenHandler_CookieRequiresCSRF(t *testing.T) {
+	router, userRepo, mr := setupAuthRouter(t)
+	defer mr.Close()
+
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	authSrv := services.NewAuthService(userRepo, nil, nil, mockRepo.NoopTransactor{}, rdb, "test-secret")
+	tokens, _ := authSrv.IssueOrgTokens("1", 1)
+	_ = authSrv.Persist(t.Context(), tokens)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tokens.Refresh})
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: tokens.CSRF})
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: tokens.Refresh})
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: tokens.CSRF})
+	req.Header.Set("X-CSRF-Token", tokens.CSRF)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestLoginHandler_Deactivated(t *testing.T) {
+	router, userRepo, mr := setupAuthRouter(t)
+	defer mr.Close()
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	deactivatedAt := time.Now()
+	user := domain.User{Username: "testuser", Password: string(hashed), DeactivatedAt: &deactivatedAt}
+	user.ID = 1
+	userRepo.On("GetByField", mock.Anything, "username", "testuser").Return(user, nil)
+
+	body, _ := json.Marshal(dto.LoginUserReq{Username: "testuser", Password: "password123"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestResetPasswordHandler_InvalidToken(t *testing.T) {
+	router, _, mr := setupAuthRouter(t)
+	defer mr.Close()
+
+	body, _ := json.Marshal(dto.ResetPasswordReq{Token: "unknown", NewPassword: "password123"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/password/reset", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateView godoc
+// @Summary      Save a view
+// @Description  Save a filter expression and sort order under a name. Shared views are visible to everyone in their project, or in the organization without one; sharing takes editor rights there
+// @Tags         views
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.ViewReq  true  "View data"
+// @Success      201   {object}  dto.Response{data=dto.ViewResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/views [post]
+func CreateView(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.ViewReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := viewSrv.CreateView(c, req, userID)
+		if err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.Created(c, "view created", resp)
+	}
+}
+
+// ListViews godoc
+// @Summary      List views
+// @Description  List the built-in views (my-open-tasks, overdue, recently-updated), the user's own views and the views shared with them. Pinned views come first
+// @Tags         views
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.ViewResp}
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Router       /v1/views [get]
+func ListViews(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := viewSrv.ListViews(c, userID)
+		if err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "views retrieved", resp)
+	}
+}
+
+// GetView godoc
+// @Summary      Get a view
+// @Description  Get a saved view by ID or a built-in view by key
+// @Tags         views
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "View ID or built-in key"
+// @Success      200  {object}  dto.Response{data=dto.ViewResp}
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/views/{id} [get]
+func GetView(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := viewSrv.GetView(c, c.Param("id"), userID)
+		if err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "view retrieved", resp)
+	}
+}
+
+// UpdateView godoc
+// @Summary      Update a view
+// @Description  Replace a saved view. Only its owner, or an owner of the project or organization it is shared with, may change it
+// @Tags         views
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int          true  "View ID"
+// @Param        body  body      dto.ViewReq  true  "View data"
+// @Success      200   {object}  dto.Response{data=dto.ViewResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/views/{id} [put]
+func UpdateView(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.ViewReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := viewSrv.UpdateView(c, c.Param("id"), req, userID)
+		if err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "view updated", resp)
+	}
+}
+
+// DeleteView godoc
+// @Summary      Delete a view
+// @Description  Delete a saved view and everyone's pins of it. Only its owner, or an owner of the project or organization it is shared with, may delete it
+// @Tags         views
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "View ID"
+// @Success      200  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/views/{id} [delete]
+func DeleteView(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		if err := viewSrv.DeleteView(c, c.Param("id"), userID); err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "view deleted", nil)
+	}
+}
+
+// PinView godoc
+// @Summary      Pin a view
+// @Description  Pin a saved or built-in view so it is listed first
+// @Tags         views
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "View ID or built-in key"
+// @Success      200  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/views/{id}/pin [put]
+func PinView(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		if err := viewSrv.PinView(c, c.Param("id"), userID); err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "view pinned", nil)
+	}
+}
+
+// UnpinView godoc
+// @Summary      Unpin a view
+// @Tags         views
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "View ID or built-in key"
+// @Success      200  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/views/{id}/pin [delete]
+func UnpinView(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		if err := viewSrv.UnpinView(c, c.Param("id"), userID); err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "view unpinned", nil)
+	}
+}
+
+// ListViewTasks godoc
+// @Summary      Run a view
+// @Description  List the tasks currently matching a view, in its sort order. "me" and relative dates in its filter resolve for the requesting user
+// @Tags         views
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true   "View ID or built-in key"
+// @Param        limit   query     int     false  "Limit"   default(20)
+// @Param        offset  query     int     false  "Offset"  default(0)
+// @Success      200     {object}  dto.Response{data=dto.TaskListResp}
+// @Failure      400     {object}  dto.Response
+// @Failure      401     {object}  dto.Response
+// @Failure      404     {object}  dto.Response
+// @Router       /v1/views/{id}/tasks [get]
+func ListViewTasks(viewSrv *services.ViewService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := viewSrv.ViewTasks(c, c.Param("id"), userID, pagination.Limit, pagination.Offset)
+		if err != nil {
+			viewErr(c, err)
+			return
+		}
+		dto.OK(c, "tasks retrieved", resp)
+	}
+}
+
+func viewErr(c *gin.Context, err error) {
+	var filterErr *filter.Error
+	switch {
+	case errors.As(err, &filterErr):
+		dto.Err(c, err)
+	case errors.Is(err, api_error.ErrViewNotFound), errors.Is(err, api_error.ErrProjectNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrForbidden), errors.Is(err, api_error.ErrBuiltinView):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
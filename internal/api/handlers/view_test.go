@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupViewRouter() (*gin.Engine, *mockRepo.MockViewRepo, *mockRepo.MockTaskRepo) {
+	gin.SetMode(gin.TestMode)
+	viewRepo := new(mockRepo.MockViewRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	viewSrv := services.NewViewService(viewRepo, taskRepo, nil, nil)
+
+	r := gin.New()
+	views := r.Group("/views")
+	views.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	views.POST("", CreateView(viewSrv))
+	views.GET("/:id", GetView(viewSrv))
+	views.GET("/:id/tasks", ListViewTasks(viewSrv))
+	return r, viewRepo, taskRepo
+}
+
+func TestCreateViewHandler_InvalidFilter(t *testing.T) {
+	router, viewRepo, _ := setupViewRouter()
+
+	body, _ := json.Marshal(dto.ViewReq{Name: "Mine", Filter: "assignee = me and"})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/views", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	viewRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestGetViewHandler_NotFound(t *testing.T) {
+	router, viewRepo, _ := setupViewRouter()
+	viewRepo.On("GetByID", mock.Anything, uint(9)).Return(domain.View{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/views/9", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListViewTasksHandler_Builtin(t *testing.T) {
+	router, _, taskRepo := setupViewRouter()
+	taskRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.TaskListFilter) bool {
+		return f.Parsed != nil && f.Query == "assignee = me and status in (Created, Started, Delayed)"
+	}), 10, 0).Return([]domain.Task{{Name: "Task"}}, int64(1), nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/views/"+services.ViewMyOpenTasks+"/tasks?limit=10", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data dto.TaskListResp `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, int64(1), resp.Data.Total)
+	taskRepo.AssertExpectations(t)
+}
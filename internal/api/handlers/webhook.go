@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateWebhook godoc
+// @Summary      Create a webhook
+// @Description  Subscribe a URL to task events of the active organization. Deliveries are signed with HMAC-SHA256 in the X-Webhook-Signature header; the secret is generated when not given and only returned here. Organization owners only
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      dto.CreateWebhookReq  true  "Webhook data"
+// @Success      201   {object}  dto.Response{data=dto.WebhookResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Router       /v1/webhooks [post]
+func CreateWebhook(webhookSrv *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		req := dto.CreateWebhookReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := webhookSrv.CreateWebhook(c, req, userID)
+		if err != nil {
+			webhookErr(c, err)
+			return
+		}
+		dto.Created(c, "webhook created", resp)
+	}
+}
+
+// ListWebhooks godoc
+// @Summary      List webhooks
+// @Description  List the webhooks of the active organization. Organization owners only
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  dto.Response{data=[]dto.WebhookResp}
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Router       /v1/webhooks [get]
+func ListWebhooks(webhookSrv *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		resp, err := webhookSrv.ListWebhooks(c, userID)
+		if err != nil {
+			webhookErr(c, err)
+			return
+		}
+		dto.OK(c, "webhooks retrieved", resp)
+	}
+}
+
+// UpdateWebhook godoc
+// @Summary      Update a webhook
+// @Description  Change a webhook's URL, secret or events. Organization owners only
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int                   true  "Webhook ID"
+// @Param        body  body      dto.UpdateWebhookReq  true  "Fields to change"
+// @Success      200   {object}  dto.Response{data=dto.WebhookResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Router       /v1/webhooks/{id} [put]
+func UpdateWebhook(webhookSrv *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		webhookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.UpdateWebhookReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := webhookSrv.UpdateWebhook(c, uint(webhookID), req, userID)
+		if err != nil {
+			webhookErr(c, err)
+			return
+		}
+		dto.OK(c, "webhook updated", resp)
+	}
+}
+
+// DeleteWebhook godoc
+// @Summary      Delete a webhook
+// @Description  Delete a webhook along with its delivery log. Organization owners only
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  dto.Response
+// @Failure      400  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/webhooks/{id} [delete]
+func DeleteWebhook(webhookSrv *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		webhookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		if err := webhookSrv.DeleteWebhook(c, uint(webhookID), userID); err != nil {
+			webhookErr(c, err)
+			return
+		}
+		dto.OK(c, "webhook deleted", nil)
+	}
+}
+
+// ListWebhookDeliveries godoc
+// @Summary      List webhook deliveries
+// @Description  List the deliveries of a webhook, newest first, with the outcome of their latest attempt. Organization owners only
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      int  true   "Webhook ID"
+// @Param        limit   query     int  false  "Limit"   default(20)
+// @Param        offset  query     int  false  "Offset"  default(0)
+// @Success      200     {object}  dto.Response{data=dto.WebhookDeliveryListResp}
+// @Failure      400     {object}  dto.Response
+// @Failure      403     {object}  dto.Response
+// @Failure      404     {object}  dto.Response
+// @Router       /v1/webhooks/{id}/deliveries [get]
+func ListWebhookDeliveries(webhookSrv *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		webhookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+		pagination := dto.PaginationQuery{Limit: 20, Offset: 0}
+		if err := c.ShouldBindQuery(&pagination); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := webhookSrv.ListDeliveries(c, uint(webhookID), userID, pagination.Limit, pagination.Offset)
+		if err != nil {
+			webhookErr(c, err)
+			return
+		}
+		dto.OK(c, "deliveries retrieved", resp)
+	}
+}
+
+// TestWebhook godoc
+// @Summary      Send a test event
+// @Description  Send a webhook.test event to the webhook right away and return the delivery. A failed test is retried like other deliveries. Organization owners only
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Webhook ID"
+// @Success      200  {object}  dto.Response{data=dto.WebhookDeliveryResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/webhooks/{id}/test [post]
+func TestWebhook(webhookSrv *services.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		webhookID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := webhookSrv.SendTest(c, uint(webhookID), userID)
+		if err != nil {
+			webhookErr(c, err)
+			return
+		}
+		dto.OK(c, "test event sent", resp)
+	}
+}
+
+func webhookErr(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, api_error.ErrWebhookNotFound):
+		dto.ErrNotFound(c, err)
+	case errors.Is(err, api_error.ErrForbiddenURL):
+		dto.Err(c, err)
+	case errors.Is(err, api_error.ErrForbidden):
+		dto.ErrStatus(c, http.StatusForbidden, err)
+	default:
+		dto.ErrInternal(c, err)
+	}
+}
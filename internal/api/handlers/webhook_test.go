@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// setupWebhookRouter delivers with client, which tests posting to a local receiver need
+// as the service's own client refuses loopback addresses.
+func setupWebhookRouter(client *http.Client) (*gin.Engine, *mockRepo.MockWebhookRepo) {
+	gin.SetMode(gin.TestMode)
+	webhookRepo := new(mockRepo.MockWebhookRepo)
+	webhookSrv := services.NewWebhookService(webhookRepo, nil, nil, mockRepo.NoopTransactor{}, nil, cfg.WebhookCfg{})
+	webhookSrv.Client = client
+
+	r := gin.New()
+	webhooks := r.Group("/webhooks")
+	webhooks.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	webhooks.POST("", CreateWebhook(webhookSrv))
+	webhooks.POST("/:id/test", TestWebhook(webhookSrv))
+	return r, webhookRepo
+}
+
+func TestTestWebhookHandler(t *testing.T) {
+	var received string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(services.WebhookEventHeader)
+	}))
+	defer receiver.Close()
+
+	router, webhookRepo := setupWebhookRouter(receiver.Client())
+	webhook := domain.Webhook{URL: receiver.URL, Secret: "0123456789abcdef"}
+	webhook.ID = 3
+	webhookRepo.On("GetByID", mock.Anything, uint(3)).Return(webhook, nil)
+	webhookRepo.On("CreateDelivery", mock.Anything, mock.AnythingOfType("*domain.WebhookDelivery")).Return(uint(1), nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.AnythingOfType("*domain.WebhookDelivery"), mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/webhooks/3/test", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, services.EventWebhookTest, received)
+	var resp struct {
+		Data dto.WebhookDeliveryResp `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.Equal(t, http.StatusOK, resp.Data.ResponseStatus)
+	assert.NotNil(t, resp.Data.DeliveredAt)
+}
+
+func TestTestWebhookHandler_NotFound(t *testing.T) {
+	router, webhookRepo := setupWebhookRouter(http.DefaultClient)
+	webhookRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Webhook{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/webhooks/3/test", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestCreateWebhookHandler_PrivateURL(t *testing.T) {
+	router, webhookRepo := setupWebhookRouter(http.DefaultClient)
+
+	for _, url := range []string{"http://127.0.0.1:8080/hook", "http://169.254.169.254/latest/meta-data", "http://localhost/hook"} {
+		body, _ := json.Marshal(dto.CreateWebhookReq{URL: url, Events: []string{"task.created"}})
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/webhooks", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+	webhookRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
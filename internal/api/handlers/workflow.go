@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/services"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWorkflow godoc
+// @Summary      Get a project's workflow
+// @Description  Get the statuses the project's tasks can be in, their categories and the allowed transitions. Projects without their own workflow use the default one, which mirrors the built-in statuses
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  dto.Response{data=dto.WorkflowResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Router       /v1/projects/{id}/workflow [get]
+func GetWorkflow(workflowSrv *services.WorkflowService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := workflowSrv.GetWorkflow(c, uint(projectID), userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "workflow retrieved", resp)
+	}
+}
+
+// UpdateWorkflow godoc
+// @Summary      Replace a project's workflow
+// @Description  Replace the project's statuses and transitions. Each status has a category (todo, in_progress, done) and counts as a built-in status for filters and the board. Without transitions every move is allowed. Statuses tasks are still in must be kept. Project owner or organization owner only
+// @Tags         projects
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int              true  "Project ID"
+// @Param        body  body      dto.WorkflowReq  true  "Workflow"
+// @Success      200   {object}  dto.Response{data=dto.WorkflowResp}
+// @Failure      400   {object}  dto.Response
+// @Failure      401   {object}  dto.Response
+// @Failure      403   {object}  dto.Response
+// @Failure      404   {object}  dto.Response
+// @Failure      409   {object}  dto.Response
+// @Router       /v1/projects/{id}/workflow [put]
+func UpdateWorkflow(workflowSrv *services.WorkflowService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		req := dto.WorkflowReq{}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := workflowSrv.UpdateWorkflow(c, uint(projectID), req, userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "workflow updated", resp)
+	}
+}
+
+// ResetWorkflow godoc
+// @Summary      Reset a project's workflow
+// @Description  Put the project back on the default workflow. Fails while tasks are in a custom status. Project owner or organization owner only
+// @Tags         projects
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int  true  "Project ID"
+// @Success      200  {object}  dto.Response{data=dto.WorkflowResp}
+// @Failure      400  {object}  dto.Response
+// @Failure      401  {object}  dto.Response
+// @Failure      403  {object}  dto.Response
+// @Failure      404  {object}  dto.Response
+// @Failure      409  {object}  dto.Response
+// @Router       /v1/projects/{id}/workflow [delete]
+func ResetWorkflow(workflowSrv *services.WorkflowService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := getUserID(c)
+		if err != nil {
+			dto.ErrUnauthorized(c, api_error.ErrUnauthorized)
+			return
+		}
+
+		projectID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			dto.Err(c, err)
+			return
+		}
+
+		resp, err := workflowSrv.ResetWorkflow(c, uint(projectID), userID)
+		if err != nil {
+			projectErr(c, err)
+			return
+		}
+		dto.OK(c, "workflow reset", resp)
+	}
+}
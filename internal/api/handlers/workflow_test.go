@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupWorkflowRouter() (*gin.Engine, *mockRepo.MockWorkflowRepo, *mockRepo.MockTaskRepo) {
+	gin.SetMode(gin.TestMode)
+	workflowRepo := new(mockRepo.MockWorkflowRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	project := domain.Project{Name: "Project", OwnerID: 1}
+	project.ID = 3
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(project, nil)
+	workflowSrv := services.NewWorkflowService(workflowRepo, taskRepo, projectRepo, nil)
+
+	r := gin.New()
+	projects := r.Group("/projects")
+	projects.Use(func(c *gin.Context) {
+		c.Set("userID", "1")
+		c.Next()
+	})
+	projects.GET("/:id/workflow", GetWorkflow(workflowSrv))
+	projects.PUT("/:id/workflow", UpdateWorkflow(workflowSrv))
+	return r, workflowRepo, taskRepo
+}
+
+func putWorkflow(router *gin.Engine, req dto.WorkflowReq) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest("PUT", "/projects/3/workflow", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, httpReq)
+	return w
+}
+
+func TestGetWorkflowHandler(t *testing.T) {
+	router, workflowRepo, _ := setupWorkflowRouter()
+	workflowRepo.On("GetByProject", mock.Anything, uint(3)).Return(domain.Workflow{}, gorm.ErrRecordNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/projects/3/workflow", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"default":true`)
+}
+
+func TestUpdateWorkflowHandler(t *testing.T) {
+	router, workflowRepo, taskRepo := setupWorkflowRouter()
+	taskRepo.On("ListStatusesInUse", mock.Anything, uint(3)).Return([]domain.WorkflowStatus{{Name: "Started", Status: enum.Started}}, nil)
+	workflowRepo.On("Save", mock.Anything, mock.AnythingOfType("*domain.Workflow")).Return(nil)
+
+	w := putWorkflow(router, dto.WorkflowReq{Statuses: []dto.WorkflowStatusReq{{Name: "Open", Category: "todo"}}})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = putWorkflow(router, dto.WorkflowReq{Statuses: []dto.WorkflowStatusReq{{Name: "Open", Category: "later"}}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = putWorkflow(router, dto.WorkflowReq{Statuses: []dto.WorkflowStatusReq{{Name: "Open", Category: "todo"}, {Name: "Started", Category: "in_progress"}}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	workflowRepo.AssertNumberOfCalls(t, "Save", 1)
+}
@@ -13,6 +13,9 @@ func CorsMiddleware(cfg cfg.CorsCfg) gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ","))
 		c.Writer.Header().Set("Access-Control-Allow-Methods", strings.Join(cfg.Methods, ","))
+		if len(cfg.ExposedHeaders) > 0 {
+			c.Writer.Header().Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposedHeaders, ","))
+		}
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
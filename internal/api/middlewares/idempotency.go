@@ -0,0 +1,191 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/logger"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	defaultIdempotencyTTL     = 24 * time.Hour
+	defaultIdempotencyLockTTL = time.Minute
+	defaultIdempotencyMaxBody = 1 << 20
+)
+
+// replayedHeaders are the response headers stored along with the body.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// idempotentResponse is what is stored under an idempotency key. Status is zero while the
+// first request is still being handled.
+type idempotentResponse struct {
+	Fingerprint string            `json:"fingerprint"`
+	Status      int               `json:"status,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Body        []byte            `json:"body,omitempty"`
+}
+
+// bodyRecorder keeps a copy of the response written through it.
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes POST requests sent with an Idempotency-Key safe to retry: the
+// first response is stored in Redis and replayed for later requests with the same key and
+// body. Reusing a key for another request, or while the first one is still running, is a
+// conflict. Keys belong to the authenticated user and active organization when
+// AuthMiddleware ran before it, otherwise to the client IP. Bodies are buffered for the
+// fingerprint, so those over MaxBody are rejected. Server errors are not stored, so the
+// request can be retried.
+func IdempotencyMiddleware(r *redis.Client, idemCfg cfg.IdempotencyCfg) gin.HandlerFunc {
+	if idemCfg.TTL <= 0 {
+		idemCfg.TTL = defaultIdempotencyTTL
+	}
+	if idemCfg.LockTTL <= 0 {
+		idemCfg.LockTTL = defaultIdempotencyLockTTL
+	}
+	if idemCfg.MaxBody <= 0 {
+		idemCfg.MaxBody = defaultIdempotencyMaxBody
+	}
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if !idemCfg.Enabled || key == "" || c.Request.Method != http.MethodPost {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "idempotency key is too long"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, idemCfg.MaxBody))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body is too large"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "unreadable request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		subject := "ip:" + c.ClientIP()
+		if userID, ok := c.Get("userID"); ok {
+			subject = "user:" + userID.(string)
+			if orgID, ok := tenant.FromContext(c); ok {
+				subject += ":org:" + strconv.FormatUint(uint64(orgID), 10)
+			}
+		}
+		redisKey := cache.IdempotencyKey(subject, key)
+		fingerprint := requestFingerprint(c.Request, body)
+
+		ctx := context.Background()
+		pending, _ := json.Marshal(idempotentResponse{Fingerprint: fingerprint})
+		claimed, err := r.SetNX(ctx, redisKey, pending, idemCfg.LockTTL).Result()
+		if err != nil {
+			// Fail open, as the rate limiter does: retries may then run twice.
+			logger.Logger.Warn("idempotency store unavailable", "err", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			replay(c, r, redisKey, fingerprint)
+			return
+		}
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			r.Del(ctx, redisKey)
+			return
+		}
+		stored := idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      map[string]string{},
+			Body:        recorder.body.Bytes(),
+		}
+		for _, name := range replayedHeaders {
+			if value := recorder.Header().Get(name); value != "" {
+				stored.Header[name] = value
+			}
+		}
+		data, _ := json.Marshal(stored)
+		if err := r.Set(ctx, redisKey, data, idemCfg.TTL).Err(); err != nil {
+			logger.Logger.Warn("idempotent response not stored", "err", err)
+		}
+	}
+}
+
+// replay answers a request whose key was already used with the stored response.
+func replay(c *gin.Context, r *redis.Client, redisKey, fingerprint string) {
+	data, err := r.Get(context.Background(), redisKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// The first request failed and released the key in the meantime.
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this idempotency key was interrupted, retry"})
+		return
+	}
+	var stored idempotentResponse
+	if err == nil {
+		err = json.Unmarshal(data, &stored)
+	}
+	if err != nil {
+		logger.Logger.Warn("idempotency store unavailable", "err", err)
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "idempotency store unavailable"})
+		return
+	}
+
+	switch {
+	case stored.Fingerprint != fingerprint:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "idempotency key was used for a different request"})
+	case stored.Status == 0:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "request with this idempotency key is still in progress"})
+	default:
+		for name, value := range stored.Header {
+			c.Header(name, value)
+		}
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Status(stored.Status)
+		_, _ = c.Writer.Write(stored.Body)
+		c.Abort()
+	}
+}
+
+// requestFingerprint identifies a request by its route and body.
+func requestFingerprint(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
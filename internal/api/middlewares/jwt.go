@@ -3,14 +3,21 @@ package middlewares
 import (
 	"context"
 	"errors"
+	"graph-interview/internal/repository/tenant"
 	"graph-interview/internal/services"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
+const (
+	AuthSourceCookie = "cookie"
+	AuthSourceHeader = "header"
+)
+
 func bearerFromHeader(c *gin.Context) string {
 	h := c.GetHeader("Authorization")
 	if strings.HasPrefix(h, "Bearer ") {
@@ -21,9 +28,11 @@ func bearerFromHeader(c *gin.Context) string {
 
 func AuthMiddleware(authSrv *services.AuthService, r *redis.Client) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenStr, _ := c.Cookie("access_token")
+		authSource := AuthSourceCookie
+		tokenStr, _ := c.Cookie(services.AccessTokenCookie)
 
 		if tokenStr == "" {
+			authSource = AuthSourceHeader
 			tokenStr = bearerFromHeader(c)
 		}
 
@@ -46,6 +55,34 @@ func AuthMiddleware(authSrv *services.AuthService, r *redis.Client) gin.HandlerF
 		}
 
 		c.Set("userID", claims.Subject)
+		c.Set("authSource", authSource)
+		if claims.OrgID != 0 {
+			c.Set(tenant.ContextKey, claims.OrgID)
+			if userID, err := strconv.ParseUint(claims.Subject, 10, 64); err == nil {
+				c.Set(tenant.ReaderKey, uint(userID))
+			}
+		}
+		c.Next()
+	}
+}
+
+// CSRFMiddleware enforces the double-submit token on state-changing requests that were
+// authenticated by cookie. Bearer-header clients are not exposed to CSRF and pass through.
+func CSRFMiddleware(authSrv *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			c.Next()
+			return
+		}
+		if c.GetString("authSource") != AuthSourceCookie {
+			c.Next()
+			return
+		}
+		if !authSrv.VerifyCSRF(c, c.GetString("userID")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "invalid csrf token"})
+			return
+		}
 		c.Next()
 	}
 }
@@ -57,3 +94,20 @@ func MustCookie(c *gin.Context, name string) (string, error) {
 	}
 	return val, nil
 }
+
+// AdminMiddleware only lets active admins through. It must run after AuthMiddleware.
+func AdminMiddleware(userSrv *services.UserService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := strconv.ParseUint(c.GetString("userID"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		isAdmin, err := userSrv.IsAdmin(c, uint(userID))
+		if err != nil || !isAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -2,12 +2,23 @@ package middlewares
 
 import (
 	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/internal/services"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func init() {
@@ -50,6 +61,7 @@ func TestCorsMiddleware(t *testing.T) {
 		Origins:        []string{"http://localhost:3000"},
 		Methods:        []string{"GET", "POST"},
 		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		ExposedHeaders: []string{"ETag"},
 	}
 
 	r := gin.New()
@@ -65,6 +77,7 @@ func TestCorsMiddleware(t *testing.T) {
 	assert.Equal(t, http.StatusOK, w.Code)
 	assert.Equal(t, "http://localhost:3000", w.Header().Get("Access-Control-Allow-Origin"))
 	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "ETag", w.Header().Get("Access-Control-Expose-Headers"))
 }
 
 func TestCorsMiddleware_Options(t *testing.T) {
@@ -151,3 +164,318 @@ func TestMustCookie_WithCookie(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "test_value", val)
 }
+
+func setupRateLimitRouter(t *testing.T, limitCfg cfg.RateLimitCfg) (*gin.Engine, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	r := gin.New()
+	r.GET("/public", RateLimitMiddleware(rdb, limitCfg, "auth"), func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+	r.GET("/private", func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-User"))
+		c.Next()
+	}, RateLimitMiddleware(rdb, limitCfg, "default"), func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+	return r, mr
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limitCfg := cfg.RateLimitCfg{
+		Enabled: true,
+		Default: cfg.RateLimitRule{Requests: 5, Window: time.Minute},
+		Groups:  map[string]cfg.RateLimitRule{"auth": {Requests: 2, Window: time.Minute}},
+	}
+	r, mr := setupRateLimitRouter(t, limitCfg)
+	defer mr.Close()
+
+	for i := range 2 {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/public", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get("X-RateLimit-Limit"))
+		assert.Equal(t, strconv.Itoa(1-i), w.Header().Get("X-RateLimit-Remaining"))
+		assert.NotEmpty(t, w.Header().Get("X-RateLimit-Reset"))
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/public", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+}
+
+func TestRateLimitMiddleware_PerUser(t *testing.T) {
+	limitCfg := cfg.RateLimitCfg{
+		Enabled: true,
+		Default: cfg.RateLimitRule{Requests: 1, Window: time.Minute},
+	}
+	r, mr := setupRateLimitRouter(t, limitCfg)
+	defer mr.Close()
+
+	send := func(user string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/private", nil)
+		req.Header.Set("X-User", user)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("1"))
+	assert.Equal(t, http.StatusTooManyRequests, send("1"))
+	// Another user behind the same IP has its own budget
+	assert.Equal(t, http.StatusOK, send("2"))
+	assert.True(t, mr.Exists("ratelimit:default:user:1"))
+}
+
+func TestRateLimitMiddleware_IgnoresUntrustedForwardedFor(t *testing.T) {
+	limitCfg := cfg.RateLimitCfg{
+		Enabled: true,
+		Groups:  map[string]cfg.RateLimitRule{"auth": {Requests: 1, Window: time.Minute}},
+	}
+	r, mr := setupRateLimitRouter(t, limitCfg)
+	defer mr.Close()
+	assert.NoError(t, r.SetTrustedProxies(nil))
+
+	send := func(forwardedFor string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/public", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("10.0.0.1"))
+	// A forged header does not buy a fresh budget
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.2"))
+	assert.True(t, mr.Exists("ratelimit:auth:ip:203.0.113.7"))
+}
+
+func TestRateLimitMiddleware_Disabled(t *testing.T) {
+	r, mr := setupRateLimitRouter(t, cfg.RateLimitCfg{
+		Default: cfg.RateLimitRule{Requests: 1, Window: time.Minute},
+	})
+	defer mr.Close()
+
+	for range 3 {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/public", nil)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func setupCSRFRouter(t *testing.T) (*gin.Engine, *services.Tokens, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("failed to start miniredis: %v", err)
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	authSrv := services.NewAuthService(new(mockRepo.MockUserRepo), new(mockRepo.MockSessionRepo), nil, mockRepo.NoopTransactor{}, rdb, "test-secret")
+	tokens, _ := authSrv.IssueTokens("1")
+	_ = authSrv.Persist(t.Context(), tokens)
+
+	r := gin.New()
+	r.Use(AuthMiddleware(authSrv, rdb), CSRFMiddleware(authSrv))
+	r.GET("/tasks", func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) })
+	r.POST("/tasks", func(c *gin.Context) { c.JSON(201, gin.H{"ok": true}) })
+	return r, tokens, mr
+}
+
+func TestCSRFMiddleware_CookieAuth(t *testing.T) {
+	r, tokens, mr := setupCSRFRouter(t)
+	defer mr.Close()
+
+	send := func(method, csrfHeader string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/tasks", nil)
+		req.AddCookie(&http.Cookie{Name: "access_token", Value: tokens.Access})
+		req.AddCookie(&http.Cookie{Name: "csrf_token", Value: tokens.CSRF})
+		if csrfHeader != "" {
+			req.Header.Set("X-CSRF-Token", csrfHeader)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("GET", ""))
+	assert.Equal(t, http.StatusForbidden, send("POST", ""))
+	assert.Equal(t, http.StatusForbidden, send("POST", "forged"))
+	assert.Equal(t, http.StatusCreated, send("POST", tokens.CSRF))
+}
+
+func TestCSRFMiddleware_BearerAuthUnaffected(t *testing.T) {
+	r, tokens, mr := setupCSRFRouter(t)
+	defer mr.Close()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks", nil)
+	req.Header.Set("Authorization", "Bearer "+tokens.Access)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	userRepo := new(mockRepo.MockUserRepo)
+	userSrv := services.NewUserService(userRepo, nil, nil)
+
+	admin := domain.User{Role: enum.RoleAdmin}
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(admin, nil)
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.User{Role: enum.RoleUser}, nil)
+
+	r := gin.New()
+	r.GET("/admin", func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-User"))
+		c.Next()
+	}, AdminMiddleware(userSrv), func(c *gin.Context) {
+		c.JSON(200, gin.H{"ok": true})
+	})
+
+	send := func(user string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin", nil)
+		req.Header.Set("X-User", user)
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("1"))
+	assert.Equal(t, http.StatusForbidden, send("2"))
+	assert.Equal(t, http.StatusUnauthorized, send(""))
+}
+
+func setupIdempotencyRouter(t *testing.T, status int) (*gin.Engine, *miniredis.Miniredis, *int) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	calls := 0
+	r := gin.New()
+	r.POST("/tasks", func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-User"))
+		if org, err := strconv.ParseUint(c.GetHeader("X-Org"), 10, 0); err == nil {
+			c.Set(tenant.ContextKey, uint(org))
+		}
+		c.Next()
+	}, IdempotencyMiddleware(rdb, cfg.IdempotencyCfg{Enabled: true, MaxBody: 64}), func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/tasks/"+strconv.Itoa(calls))
+		c.JSON(status, gin.H{"call": calls})
+	})
+	return r, mr, &calls
+}
+
+func postWithKey(r *gin.Engine, key, user, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks", strings.NewReader(body))
+	req.Header.Set("Idempotency-Key", key)
+	req.Header.Set("X-User", user)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyMiddleware_Replays(t *testing.T) {
+	r, _, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	first := postWithKey(r, "k1", "1", `{"name":"Task"}`)
+	second := postWithKey(r, "k1", "1", `{"name":"Task"}`)
+
+	assert.Equal(t, 1, *calls)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "/tasks/1", second.Header().Get("Location"))
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+}
+
+func TestIdempotencyMiddleware_DifferentBody(t *testing.T) {
+	r, _, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	postWithKey(r, "k1", "1", `{"name":"Task"}`)
+	w := postWithKey(r, "k1", "1", `{"name":"Other"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotencyMiddleware_KeysPerUser(t *testing.T) {
+	r, _, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	postWithKey(r, "k1", "1", `{}`)
+	w := postWithKey(r, "k1", "2", `{}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyMiddleware_KeysPerOrganization(t *testing.T) {
+	r, _, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	send := func(org string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/tasks", strings.NewReader(`{}`))
+		req.Header.Set("Idempotency-Key", "k1")
+		req.Header.Set("X-User", "1")
+		req.Header.Set("X-Org", org)
+		r.ServeHTTP(w, req)
+		return w
+	}
+	send("1")
+	w := send("2")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyMiddleware_BodyTooLarge(t *testing.T) {
+	r, mr, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	w := postWithKey(r, "k1", "1", `{"name":"`+strings.Repeat("x", 64)+`"}`)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 0, *calls)
+	assert.Empty(t, mr.Keys())
+}
+
+func TestIdempotencyMiddleware_InProgress(t *testing.T) {
+	r, mr, calls := setupIdempotencyRouter(t, http.StatusCreated)
+	mr.Set("idempotency:user:1:k1", `{"fingerprint":"`+requestFingerprint(httptest.NewRequest("POST", "/tasks", nil), []byte(`{}`))+`"}`)
+
+	w := postWithKey(r, "k1", "1", `{}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotencyMiddleware_ServerErrorNotStored(t *testing.T) {
+	r, mr, calls := setupIdempotencyRouter(t, http.StatusInternalServerError)
+
+	postWithKey(r, "k1", "1", `{}`)
+	postWithKey(r, "k1", "1", `{}`)
+
+	assert.Equal(t, 2, *calls)
+	assert.False(t, mr.Exists("idempotency:user:1:k1"))
+}
+
+func TestIdempotencyMiddleware_WithoutKey(t *testing.T) {
+	r, mr, calls := setupIdempotencyRouter(t, http.StatusCreated)
+
+	postWithKey(r, "", "1", `{}`)
+	postWithKey(r, "", "1", `{}`)
+
+	assert.Equal(t, 2, *calls)
+	assert.Empty(t, mr.Keys())
+}
@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/pkg/logger"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript keeps one sorted-set entry per accepted request, scored by its
+// timestamp in milliseconds, so every replica sharing the Redis instance sees the same window.
+// It returns {allowed, count, reset_at_ms}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = now + window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window
+end
+return {allowed, count, reset}
+`)
+
+// RateLimitMiddleware throttles requests of a route group using a sliding window stored in Redis.
+// Requests are keyed by the authenticated user when AuthMiddleware ran before it, otherwise by client IP,
+// which honours X-Forwarded-For only from the server's trusted proxies.
+func RateLimitMiddleware(r *redis.Client, limitCfg cfg.RateLimitCfg, group string) gin.HandlerFunc {
+	rule := limitCfg.Rule(group)
+	return func(c *gin.Context) {
+		if !limitCfg.Enabled || rule.Requests <= 0 || rule.Window <= 0 {
+			c.Next()
+			return
+		}
+
+		subject := "ip:" + c.ClientIP()
+		if userID, ok := c.Get("userID"); ok {
+			subject = "user:" + userID.(string)
+		}
+
+		now := time.Now().UnixMilli()
+		res, err := slidingWindowScript.Run(c.Request.Context(), r,
+			[]string{cache.RateLimitKey(group, subject)},
+			now, rule.Window.Milliseconds(), rule.Requests, strconv.FormatInt(now, 10)+"-"+uuid.NewString(),
+		).Int64Slice()
+		if err != nil {
+			// Fail open: an unavailable Redis must not take the whole API down.
+			logger.Logger.Warn("rate limiter unavailable", "group", group, "err", err)
+			c.Next()
+			return
+		}
+		allowed, count, resetAt := res[0] == 1, res[1], res[2]
+
+		remaining := max(int64(rule.Requests)-count, 0)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(rule.Requests))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(int64(math.Ceil(float64(resetAt)/1000)), 10))
+
+		if !allowed {
+			retryAfter := int64(math.Ceil(float64(resetAt-now) / 1000))
+			c.Header("Retry-After", strconv.FormatInt(max(retryAfter, 1), 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
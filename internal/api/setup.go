@@ -62,6 +62,11 @@ func ServeREST(ctx context.Context) error {
 	}
 	defer g.Close()
 
+	// Client IPs key the rate limiter, so X-Forwarded-For is only believed from our proxies.
+	if err := g.SetTrustedProxies(cfg.Cfg.Server.TrustedProxies); err != nil {
+		return err
+	}
+
 	// Middleware stack
 	g.Use(middlewares.PrometheusMiddleware())
 	g.Use(middlewares.CorsMiddleware(cfg.Cfg.Server.Cors))
@@ -85,7 +90,7 @@ func ServeREST(ctx context.Context) error {
 	g.GET("/swagger/*any", genSwagHandler(""))
 
 	// Register API routes
-	if err := RegisterV1Handlers(cfg.Cfg, g.Group("/v1")); err != nil {
+	if err := RegisterV1Handlers(ctx, cfg.Cfg, g.Group("/v1")); err != nil {
 		return err
 	}
 
@@ -1,19 +1,26 @@
 package api
 
 import (
+	"context"
 	"graph-interview/internal/api/handlers"
 	"graph-interview/internal/api/middlewares"
 	"graph-interview/internal/cfg"
+	"graph-interview/internal/repository"
 	"graph-interview/internal/repository/cache"
 	"graph-interview/internal/repository/storage"
 	storage_postgres "graph-interview/internal/repository/storage/postgres"
 	"graph-interview/internal/services"
+	"graph-interview/pkg/logger"
+	"graph-interview/pkg/mailer"
+	"graph-interview/pkg/safehttp"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func RegisterV1Handlers(cfg *cfg.Config, r gin.IRouter) error {
+// RegisterV1Handlers wires up the API and starts its background workers, which stop with ctx.
+func RegisterV1Handlers(ctx context.Context, cfg *cfg.Config, r gin.IRouter) error {
 	db, err := storage.NewDB(&cfg.DB)
 	if err != nil {
 		return err
@@ -23,27 +30,123 @@ func RegisterV1Handlers(cfg *cfg.Config, r gin.IRouter) error {
 		return err
 	}
 	userRepo := storage_postgres.NewUserRepo(db)
+	sessionRepo := storage_postgres.NewSessionRepo(db)
+	identityRepo := storage_postgres.NewIdentityRepo(db)
 	taskRepo := storage_postgres.NewTaskRepo(db)
-	authSrv := services.NewAuthService(userRepo, cacheStore.Client, cfg.Server.JWT.Secret)
-	userSrv := services.NewUserService(userRepo)
-	taskSrv := services.NewTaskService(taskRepo)
+	projectRepo := storage_postgres.NewProjectRepo(db)
+	orgRepo := storage_postgres.NewOrgRepo(db)
+	invitationRepo := storage_postgres.NewInvitationRepo(db)
+	shareRepo := storage_postgres.NewShareRepo(db)
+	reminderRepo := storage_postgres.NewReminderRepo(db)
+	notificationRepo := storage_postgres.NewNotificationRepo(db)
+	webhookRepo := storage_postgres.NewWebhookRepo(db)
+	viewRepo := storage_postgres.NewViewRepo(db)
+	workflowRepo := storage_postgres.NewWorkflowRepo(db)
+	customFieldRepo := storage_postgres.NewCustomFieldRepo(db)
+	outboxRepo := storage_postgres.NewOutboxRepo(db)
+	bus := services.NewEventBus(outboxRepo, cacheStore.Client, cfg.Outbox)
+	authSrv := services.NewAuthService(userRepo, sessionRepo, orgRepo, db, cacheStore.Client, cfg.Server.JWT.Secret)
+	oidcSrv := services.NewOIDCService(userRepo, identityRepo, authSrv, cacheStore.Client, cfg.Server.OIDC, nil)
+	userSrv := services.NewUserService(userRepo, db, bus)
+	adminSrv := services.NewAdminService(userRepo, sessionRepo, authSrv, newMailer(cfg.Mailer))
+	notificationSrv := services.NewNotificationService(notificationRepo, taskRepo, userRepo, orgRepo, projectRepo)
+	eventSrv := services.NewEventService(cacheStore.Client, cfg.Events, orgRepo, projectRepo)
+	webhookSrv := services.NewWebhookService(webhookRepo, projectRepo, orgRepo, db, cacheStore.Client, cfg.Webhooks)
+	taskSrv := services.NewTaskService(taskRepo, projectRepo, orgRepo, db, bus, workflowRepo, customFieldRepo)
+	projectSrv := services.NewProjectService(projectRepo, taskRepo, workflowRepo, customFieldRepo, db)
+	viewSrv := services.NewViewService(viewRepo, taskRepo, projectRepo, orgRepo)
+	workflowSrv := services.NewWorkflowService(workflowRepo, taskRepo, projectRepo, orgRepo)
+	customFieldSrv := services.NewCustomFieldService(customFieldRepo, taskRepo, projectRepo, orgRepo, db)
+	orgSrv := services.NewOrgService(orgRepo, userRepo, db, authSrv)
+	invitationSrv := services.NewInvitationService(invitationRepo, orgRepo, projectRepo, userRepo, db, authSrv, newMailer(cfg.Mailer), cfg.Invitations)
+	shareSrv := services.NewShareService(shareRepo, taskRepo, projectRepo, orgRepo, cfg.Server.JWT.Secret)
+	privacySrv := services.NewPrivacyService(userRepo, sessionRepo, identityRepo, taskRepo, projectRepo, orgRepo, reminderRepo, notificationRepo, viewRepo, webhookRepo, invitationRepo, db, authSrv, cfg.Privacy)
+	reminderSrv := services.NewReminderService(reminderRepo, taskRepo, projectRepo, orgRepo, reminderChannels(cfg, notificationRepo), cacheStore.Client, cfg.Reminders)
+
+	if err := userSrv.PromoteAdmins(ctx, cfg.Server.Admins); err != nil {
+		return err
+	}
+	bus.Subscribe("stream", services.NewStreamPublisher(cacheStore.Client, cfg.Outbox).Handle)
+	bus.Subscribe("realtime", eventSrv.HandleEvent)
+	bus.Subscribe("webhooks", webhookSrv.HandleEvent)
+	bus.Subscribe("notifications", notificationSrv.HandleEvent)
+	go bus.Run(ctx)
+	go reminderSrv.Run(ctx)
+	go webhookSrv.Run(ctx)
 
 	authMiddleware := middlewares.AuthMiddleware(authSrv, cacheStore.Client)
+	csrfMiddleware := middlewares.CSRFMiddleware(authSrv)
+	adminMiddleware := middlewares.AdminMiddleware(userSrv)
+	rateLimit := func(group string) gin.HandlerFunc {
+		return middlewares.RateLimitMiddleware(cacheStore.Client, cfg.Server.RateLimit, group)
+	}
+	idempotency := middlewares.IdempotencyMiddleware(cacheStore.Client, cfg.Server.Idempotency)
 
 	// Metrics endpoint
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
-	pubRoutes(userSrv, authSrv, r)
-	authRoutes(userSrv, authSrv, taskSrv, r, authMiddleware)
+	pubRoutes(userSrv, authSrv, oidcSrv, invitationSrv, r, rateLimit("auth"))
+	sharedRoutes(shareSrv, r, rateLimit("shared"))
+	authRoutes(userSrv, authSrv, taskSrv, projectSrv, orgSrv, invitationSrv, shareSrv, reminderSrv, notificationSrv, eventSrv, webhookSrv, viewSrv, workflowSrv, customFieldSrv, privacySrv, r, rateLimit("default"), idempotency, authMiddleware, csrfMiddleware)
+	adminRoutes(adminSrv, r, rateLimit("admin"), authMiddleware, csrfMiddleware, adminMiddleware)
 	return nil
 }
 
-func pubRoutes(userSrv *services.UserService, authSrv *services.AuthService, r gin.IRouter) {
+// reminderChannels maps the channel names reminders may use to their delivery.
+func reminderChannels(cfg *cfg.Config, notificationRepo repository.NotificationRepo) map[string]services.ReminderChannel {
+	timeout := cfg.Reminders.WebhookTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return map[string]services.ReminderChannel{
+		services.ReminderChannelEmail:   services.EmailChannel{Mailer: newMailer(cfg.Mailer)},
+		services.ReminderChannelWebhook: services.WebhookChannel{Client: safehttp.NewClient(timeout)},
+		services.ReminderChannelInApp:   services.InAppChannel{NotificationRepo: notificationRepo},
+	}
+}
+
+// newMailer sends through the configured SMTP relay, or only logs mail when there is none.
+func newMailer(mailerCfg cfg.MailerCfg) mailer.Mailer {
+	if mailerCfg.Host == "" {
+		return mailer.NewLog(logger.Logger)
+	}
+	return mailer.NewSMTP(mailer.Config{
+		Host:     mailerCfg.Host,
+		Port:     mailerCfg.Port,
+		Username: mailerCfg.Username,
+		Password: mailerCfg.Password,
+		From:     mailerCfg.From,
+	})
+}
+
+func pubRoutes(
+	userSrv *services.UserService,
+	authSrv *services.AuthService,
+	oidcSrv *services.OIDCService,
+	invitationSrv *services.InvitationService,
+	r gin.IRouter,
+	rateLimit gin.HandlerFunc,
+) {
 	auth := r.Group("/auth")
+	auth.Use(rateLimit)
 	{
 		auth.POST("/register", handlers.Register(userSrv))
 		auth.POST("/login", handlers.Login(authSrv))
 		auth.POST("/refresh", handlers.RefreshToken(authSrv))
+		auth.POST("/password/reset", handlers.ResetPassword(authSrv))
+		auth.GET("/oidc/:provider", handlers.OIDCLogin(oidcSrv))
+		auth.GET("/oidc/:provider/callback", handlers.OIDCCallback(oidcSrv))
+		auth.POST("/invitations/:token/signup", handlers.SignupWithInvitation(invitationSrv, authSrv))
+		auth.POST("/invitations/:token/decline", handlers.DeclineInvitation(invitationSrv))
+	}
+}
+
+// sharedRoutes serve share links. They bypass AuthMiddleware: the token is the credential.
+func sharedRoutes(shareSrv *services.ShareService, r gin.IRouter, rateLimit gin.HandlerFunc) {
+	shared := r.Group("/shared")
+	shared.Use(rateLimit)
+	{
+		shared.GET("/:token", handlers.GetShared(shareSrv))
 	}
 }
 
@@ -51,11 +154,27 @@ func authRoutes(
 	userSrv *services.UserService,
 	authSrv *services.AuthService,
 	taskSrv *services.TaskService,
+	projectSrv *services.ProjectService,
+	orgSrv *services.OrgService,
+	invitationSrv *services.InvitationService,
+	shareSrv *services.ShareService,
+	reminderSrv *services.ReminderService,
+	notificationSrv *services.NotificationService,
+	eventSrv *services.EventService,
+	webhookSrv *services.WebhookService,
+	viewSrv *services.ViewService,
+	workflowSrv *services.WorkflowService,
+	customFieldSrv *services.CustomFieldService,
+	privacySrv *services.PrivacyService,
 	r gin.IRouter,
-	authMiddleware gin.HandlerFunc,
+	rateLimit gin.HandlerFunc,
+	idempotency gin.HandlerFunc,
+	authMiddlewares ...gin.HandlerFunc,
 ) {
 	protected := r.Group("")
-	protected.Use(authMiddleware)
+	protected.Use(authMiddlewares...)
+	protected.Use(rateLimit)
+	protected.Use(idempotency)
 	{
 		// Auth routes
 		authGroup := protected.Group("/auth")
@@ -64,14 +183,125 @@ func authRoutes(
 		// User routes
 		userGroup := protected.Group("/user")
 		userGroup.GET("/profile", handlers.GetProfile(userSrv))
+		userGroup.GET("/export", handlers.ExportUserData(privacySrv))
+		userGroup.DELETE("", handlers.DeleteAccount(privacySrv, authSrv))
 
 		// Task routes
 		taskGroup := protected.Group("/tasks")
 		taskGroup.POST("", handlers.CreateTask(taskSrv))
 		taskGroup.GET("", handlers.ListTasks(taskSrv))
+		taskGroup.POST("/bulk", handlers.BulkTasks(taskSrv))
 		taskGroup.GET("/:id", handlers.GetTask(taskSrv))
 		taskGroup.PUT("/:id", handlers.UpdateTask(taskSrv))
+		taskGroup.PATCH("/:id", handlers.PatchTask(taskSrv))
 		taskGroup.DELETE("/:id", handlers.DeleteTask(taskSrv))
 		taskGroup.PATCH("/:id/archive", handlers.ArchiveTask(taskSrv))
+		taskGroup.PATCH("/:id/project", handlers.MoveTask(taskSrv))
+		taskGroup.POST("/:id/move", handlers.RepositionTask(taskSrv))
+		taskGroup.POST("/:id/reminders", handlers.CreateReminder(reminderSrv))
+		taskGroup.GET("/:id/reminders", handlers.ListReminders(reminderSrv))
+		taskGroup.POST("/:id/assignees", handlers.AssignTask(taskSrv))
+		taskGroup.DELETE("/:id/assignees/:user_id", handlers.UnassignTask(taskSrv))
+		taskGroup.PUT("/:id/watch", handlers.WatchTask(taskSrv))
+		taskGroup.DELETE("/:id/watch", handlers.UnwatchTask(taskSrv))
+		taskGroup.GET("/:id/checklist", handlers.GetChecklist(taskSrv))
+		taskGroup.POST("/:id/checklist", handlers.AddChecklistItem(taskSrv))
+		taskGroup.PATCH("/:id/checklist/:item_id", handlers.UpdateChecklistItem(taskSrv))
+		taskGroup.POST("/:id/checklist/:item_id/move", handlers.MoveChecklistItem(taskSrv))
+		taskGroup.DELETE("/:id/checklist/:item_id", handlers.DeleteChecklistItem(taskSrv))
+
+		// Board routes
+		protected.GET("/board", handlers.GetBoard(taskSrv))
+
+		// Project routes
+		projectGroup := protected.Group("/projects")
+		projectGroup.POST("", handlers.CreateProject(projectSrv))
+		projectGroup.GET("", handlers.ListProjects(projectSrv))
+		projectGroup.GET("/:id", handlers.GetProject(projectSrv))
+		projectGroup.PUT("/:id", handlers.UpdateProject(projectSrv))
+		projectGroup.DELETE("/:id", handlers.DeleteProject(projectSrv))
+		projectGroup.GET("/:id/tasks", handlers.ListProjectTasks(projectSrv))
+		projectGroup.GET("/:id/workflow", handlers.GetWorkflow(workflowSrv))
+		projectGroup.PUT("/:id/workflow", handlers.UpdateWorkflow(workflowSrv))
+		projectGroup.DELETE("/:id/workflow", handlers.ResetWorkflow(workflowSrv))
+		projectGroup.POST("/:id/custom-fields", handlers.CreateCustomField(customFieldSrv))
+		projectGroup.GET("/:id/custom-fields", handlers.ListCustomFields(customFieldSrv))
+		projectGroup.PUT("/:id/custom-fields/:field_id", handlers.UpdateCustomField(customFieldSrv))
+		projectGroup.DELETE("/:id/custom-fields/:field_id", handlers.DeleteCustomField(customFieldSrv))
+
+		// Organization routes
+		orgGroup := protected.Group("/orgs")
+		orgGroup.POST("", handlers.CreateOrg(orgSrv))
+		orgGroup.GET("", handlers.ListOrgs(orgSrv))
+		orgGroup.GET("/:id/members", handlers.ListOrgMembers(orgSrv))
+		orgGroup.PUT("/:id/members/:user_id", handlers.UpdateOrgMember(orgSrv))
+		orgGroup.POST("/:id/invitations", handlers.CreateInvitation(invitationSrv))
+		orgGroup.POST("/:id/switch", handlers.SwitchOrg(orgSrv, authSrv))
+
+		// Invitation routes
+		invitationGroup := protected.Group("/invitations")
+		invitationGroup.GET("", handlers.ListInvitations(invitationSrv))
+		invitationGroup.POST("/:token/accept", handlers.AcceptInvitation(invitationSrv))
+
+		// Share link routes
+		shareGroup := protected.Group("/shares")
+		shareGroup.POST("", handlers.CreateShare(shareSrv))
+		shareGroup.GET("", handlers.ListShares(shareSrv))
+		shareGroup.DELETE("/:id", handlers.RevokeShare(shareSrv))
+
+		// Reminder routes
+		reminderGroup := protected.Group("/reminders")
+		reminderGroup.DELETE("/:id", handlers.DeleteReminder(reminderSrv))
+
+		// Notification routes
+		notificationGroup := protected.Group("/notifications")
+		notificationGroup.GET("", handlers.ListNotifications(notificationSrv))
+		notificationGroup.POST("/:id/read", handlers.MarkNotificationRead(notificationSrv))
+		notificationGroup.POST("/read-all", handlers.MarkAllNotificationsRead(notificationSrv))
+		notificationGroup.GET("/preferences", handlers.GetNotificationPrefs(notificationSrv))
+		notificationGroup.PUT("/preferences", handlers.UpdateNotificationPrefs(notificationSrv))
+
+		// Webhook routes
+		webhookGroup := protected.Group("/webhooks")
+		webhookGroup.POST("", handlers.CreateWebhook(webhookSrv))
+		webhookGroup.GET("", handlers.ListWebhooks(webhookSrv))
+		webhookGroup.PUT("/:id", handlers.UpdateWebhook(webhookSrv))
+		webhookGroup.DELETE("/:id", handlers.DeleteWebhook(webhookSrv))
+		webhookGroup.GET("/:id/deliveries", handlers.ListWebhookDeliveries(webhookSrv))
+		webhookGroup.POST("/:id/test", handlers.TestWebhook(webhookSrv))
+
+		// View routes
+		viewGroup := protected.Group("/views")
+		viewGroup.POST("", handlers.CreateView(viewSrv))
+		viewGroup.GET("", handlers.ListViews(viewSrv))
+		viewGroup.GET("/:id", handlers.GetView(viewSrv))
+		viewGroup.PUT("/:id", handlers.UpdateView(viewSrv))
+		viewGroup.DELETE("/:id", handlers.DeleteView(viewSrv))
+		viewGroup.PUT("/:id/pin", handlers.PinView(viewSrv))
+		viewGroup.DELETE("/:id/pin", handlers.UnpinView(viewSrv))
+		viewGroup.GET("/:id/tasks", handlers.ListViewTasks(viewSrv))
+
+		// Event stream
+		protected.GET("/events", handlers.StreamEvents(eventSrv))
+	}
+}
+
+func adminRoutes(
+	adminSrv *services.AdminService,
+	r gin.IRouter,
+	rateLimit gin.HandlerFunc,
+	adminMiddlewares ...gin.HandlerFunc,
+) {
+	admin := r.Group("/admin")
+	admin.Use(adminMiddlewares...)
+	admin.Use(rateLimit)
+	{
+		users := admin.Group("/users")
+		users.GET("", handlers.AdminListUsers(adminSrv))
+		users.GET("/:id", handlers.AdminGetUser(adminSrv))
+		users.POST("/:id/deactivate", handlers.AdminDeactivateUser(adminSrv))
+		users.POST("/:id/reactivate", handlers.AdminReactivateUser(adminSrv))
+		users.POST("/:id/password-reset", handlers.AdminForcePasswordReset(adminSrv))
+		users.GET("/:id/sessions", handlers.AdminListUserSessions(adminSrv))
 	}
 }
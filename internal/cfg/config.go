@@ -29,15 +29,32 @@ type Config struct {
 	Log         LogCfg         `mapstructure:"log"`
 	DB          DatabaseConfig `mapstructure:"db"`
 	Cache       CacheConfig    `mapstructure:"cache"`
+	Privacy     PrivacyCfg     `mapstructure:"privacy"`
+	Mailer      MailerCfg      `mapstructure:"mailer"`
+	Invitations InvitationCfg  `mapstructure:"invitations"`
+	Reminders   ReminderCfg    `mapstructure:"reminders"`
+	Events      EventCfg       `mapstructure:"events"`
+	Webhooks    WebhookCfg     `mapstructure:"webhooks"`
+	Outbox      OutboxCfg      `mapstructure:"outbox"`
 	Verbose     bool           `mapstructure:"verbose" `
 }
 
 type ServerCfg struct {
-	Port      int     `mapstructure:"port"`
-	Host      string  `mapstructure:"host"`
-	AccessLog bool    `mapstructure:"accesslog"`
-	Cors      CorsCfg `mapstructure:"cors"`
-	JWT       JWTCfg  `mapstructure:"jwt"`
+	Port      int                        `mapstructure:"port"`
+	Host      string                     `mapstructure:"host"`
+	AccessLog bool                       `mapstructure:"accesslog"`
+	Cors      CorsCfg                    `mapstructure:"cors"`
+	JWT       JWTCfg                     `mapstructure:"jwt"`
+	RateLimit RateLimitCfg               `mapstructure:"ratelimit"`
+	OIDC      map[string]OIDCProviderCfg `mapstructure:"oidc"`
+	// Idempotency controls replaying responses to POST requests retried with the same
+	// Idempotency-Key.
+	Idempotency IdempotencyCfg `mapstructure:"idempotency"`
+	// Admins lists usernames promoted to the admin role on startup.
+	Admins []string `mapstructure:"admins"`
+	// TrustedProxies lists the addresses or CIDRs whose X-Forwarded-For headers are believed
+	// when resolving the client IP. When empty, the peer address is used.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 type JWTCfg struct {
@@ -46,10 +63,129 @@ type JWTCfg struct {
 	RefreshTimeout time.Duration `mapstructure:"refresh_timeout"`
 }
 
+type OIDCProviderCfg struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+type RateLimitCfg struct {
+	Enabled bool                     `mapstructure:"enabled"`
+	Default RateLimitRule            `mapstructure:"default"`
+	Groups  map[string]RateLimitRule `mapstructure:"groups"`
+}
+
+type RateLimitRule struct {
+	Requests int           `mapstructure:"requests"`
+	Window   time.Duration `mapstructure:"window"`
+}
+
+// Rule returns the limit configured for a route group, falling back to the default one.
+func (c RateLimitCfg) Rule(group string) RateLimitRule {
+	if r, ok := c.Groups[group]; ok && r.Requests > 0 && r.Window > 0 {
+		return r
+	}
+	return c.Default
+}
+
+type IdempotencyCfg struct {
+	Enabled bool `mapstructure:"enabled"`
+	// TTL is how long a response is kept for replay.
+	TTL time.Duration `mapstructure:"ttl"`
+	// LockTTL bounds how long a request holds its key while it is being handled.
+	LockTTL time.Duration `mapstructure:"lock_ttl"`
+	// MaxBody caps, in bytes, the request bodies buffered to fingerprint a request.
+	MaxBody int64 `mapstructure:"max_body"`
+}
+
+// PrivacyCfg controls what happens to personal data when a user deletes their account.
+type PrivacyCfg struct {
+	// DeletionMode is "anonymize" (default) or "delete".
+	DeletionMode string `mapstructure:"deletion_mode"`
+	// TaskPolicy is "orphan" (default), "reassign" or "delete" and applies to tasks the user created.
+	TaskPolicy string `mapstructure:"task_policy"`
+	// ReassignTo is the user receiving the tasks when TaskPolicy is "reassign".
+	ReassignTo uint `mapstructure:"reassign_to"`
+}
+
+// MailerCfg points at the SMTP relay used for outgoing mail. Without a host, mail is only logged.
+type MailerCfg struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+type InvitationCfg struct {
+	// AcceptURL is the page invitees are sent to; the invitation token is appended as ?token=.
+	AcceptURL string        `mapstructure:"accept_url"`
+	TTL       time.Duration `mapstructure:"ttl"`
+}
+
+// ReminderCfg tunes the scheduler firing task reminders.
+type ReminderCfg struct {
+	// Interval is how often due reminders are looked for.
+	Interval time.Duration `mapstructure:"interval"`
+	// LockTTL bounds how long one instance may hold the scheduler lock for a batch.
+	LockTTL     time.Duration `mapstructure:"lock_ttl"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	// WebhookTimeout limits each outbound webhook request.
+	WebhookTimeout time.Duration `mapstructure:"webhook_timeout"`
+}
+
+// WebhookCfg tunes the worker delivering outbound webhooks.
+type WebhookCfg struct {
+	// Interval is how often queued deliveries are looked for.
+	Interval time.Duration `mapstructure:"interval"`
+	// LockTTL bounds how long one instance may hold the delivery lock for a batch.
+	LockTTL     time.Duration `mapstructure:"lock_ttl"`
+	BatchSize   int           `mapstructure:"batch_size"`
+	MaxAttempts int           `mapstructure:"max_attempts"`
+	// Timeout limits each delivery request.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Failed deliveries are retried after RetryBackoff, doubling with every attempt up to
+	// MaxBackoff.
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+}
+
+// OutboxCfg tunes the relay handing domain events from the outbox to their subscribers.
+type OutboxCfg struct {
+	// Interval is how often the outbox is polled; new events are also relayed right after
+	// they are committed.
+	Interval time.Duration `mapstructure:"interval"`
+	// LockTTL bounds how long one instance may hold the relay lock for a batch.
+	LockTTL      time.Duration `mapstructure:"lock_ttl"`
+	BatchSize    int           `mapstructure:"batch_size"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	// Retention is how long published events are kept.
+	Retention time.Duration `mapstructure:"retention"`
+	// Stream is the Redis stream events are appended to for consumers outside the API,
+	// trimmed to about StreamMaxLen entries.
+	Stream       string `mapstructure:"stream"`
+	StreamMaxLen int64  `mapstructure:"stream_max_len"`
+}
+
+// EventCfg tunes the real-time event stream.
+type EventCfg struct {
+	// Backlog is how many recent events per organization are kept for clients reconnecting
+	// with Last-Event-ID.
+	Backlog int `mapstructure:"backlog"`
+	// Heartbeat is how often idle streams get a comment to keep proxies from closing them.
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+}
+
 type CorsCfg struct {
 	Origins        []string `mapstructure:"origins"`
 	Methods        []string `mapstructure:"methods"`
 	AllowedHeaders []string `mapstructure:"allowed-headers"`
+	// ExposedHeaders lists the response headers browsers let scripts read, such as ETag.
+	ExposedHeaders []string `mapstructure:"exposed-headers"`
 }
 
 type LogCfg struct {
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"graph-interview/internal/repository/enum"
+
+	"gorm.io/gorm"
+)
+
+// CustomField defines an extra field the tasks of a project can have, such as "customer" or
+// "story points". Tasks keep their values in Task.CustomFields under Key, which stays the
+// same for the life of the field; Name is what people see and can change.
+type CustomField struct {
+	gorm.Model
+	OrganizationID uint   `gorm:"index"`
+	ProjectID      uint   `gorm:"uniqueIndex:idx_custom_fields_project_key"`
+	Key            string `gorm:"uniqueIndex:idx_custom_fields_project_key"`
+	Name           string
+	Type           enum.CustomFieldType
+	// Options are the values a select field allows.
+	Options  []string `gorm:"serializer:json;type:jsonb"`
+	Required bool
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Types of the domain events services emit.
+const (
+	EventTaskCreated = "task.created"
+	EventTaskUpdated = "task.updated"
+	EventTaskDeleted = "task.deleted"
+	EventUserCreated = "user.created"
+)
+
+// Event is a domain event. It is written to the outbox in the same transaction as the change
+// it describes and relayed to subscribers afterwards, at least once.
+type Event struct {
+	ID             uint `gorm:"primarykey"`
+	OrganizationID uint `gorm:"index"`
+	Type           string
+	// AggregateID is the ID of the task or user the event is about.
+	AggregateID uint
+	ActorID     *uint
+	Payload     string `gorm:"type:jsonb"`
+	CreatedAt   time.Time
+	// Handled lists the subscribers that already processed the event, so retries skip them.
+	Handled       []string `gorm:"serializer:json;type:jsonb"`
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time  `gorm:"index"`
+	PublishedAt   *time.Time `gorm:"index"`
+	FailedAt      *time.Time
+}
+
+func (Event) TableName() string {
+	return "outbox_events"
+}
+
+// Decode unmarshals the payload into v.
+func (e Event) Decode(v any) error {
+	return json.Unmarshal([]byte(e.Payload), v)
+}
+
+// AggregateType is the kind of aggregate the event is about, task or user, from its type.
+func (e Event) AggregateType() string {
+	kind, _, _ := strings.Cut(e.Type, ".")
+	return kind
+}
+
+// HandledBy reports whether subscriber already processed the event.
+func (e Event) HandledBy(subscriber string) bool {
+	return slices.Contains(e.Handled, subscriber)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notification is an entry in a user's in-app inbox.
+type Notification struct {
+	gorm.Model
+	UserID         uint `gorm:"index"`
+	OrganizationID uint
+	TaskID         *uint
+	// ActorID is the user whose action caused the notification, if any.
+	ActorID *uint
+	Kind    string
+	Title   string
+	Body    string
+	ReadAt  *time.Time
+}
+
+// NotificationPreference turns one kind of notification on or off for a user. Kinds without
+// a preference are on.
+type NotificationPreference struct {
+	gorm.Model
+	UserID  uint   `gorm:"uniqueIndex:idx_notification_pref"`
+	Kind    string `gorm:"uniqueIndex:idx_notification_pref"`
+	Enabled bool
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"graph-interview/internal/repository/enum"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Organization is a tenant. Tasks and projects belong to exactly one organization and are
+// only visible to its members.
+type Organization struct {
+	gorm.Model
+	Name string
+	// Personal marks the organization created automatically for a user's own work.
+	Personal bool
+	// PersonalOwnerID is the user a personal organization was created for. It is unique, so
+	// each user has one personal organization at most.
+	PersonalOwnerID *uint `gorm:"uniqueIndex"`
+}
+
+// Membership grants a user access to an organization.
+type Membership struct {
+	gorm.Model
+	Organization   *Organization `gorm:"foreignKey:OrganizationID"`
+	OrganizationID uint          `gorm:"uniqueIndex:idx_membership_org_user"`
+	User           *User         `gorm:"foreignKey:UserID"`
+	UserID         uint          `gorm:"uniqueIndex:idx_membership_org_user;index"`
+	Role           enum.MemberRole
+	// Guest marks members who joined through a project invitation. They only reach the
+	// projects they belong to, not the rest of the organization.
+	Guest bool
+}
+
+// Invitation offers an email address membership of an organization, or of one of its
+// projects when ProjectID is set. Only a hash of the emailed token is stored.
+type Invitation struct {
+	gorm.Model
+	Organization   *Organization `gorm:"foreignKey:OrganizationID"`
+	OrganizationID uint          `gorm:"index"`
+	Project        *Project      `gorm:"foreignKey:ProjectID"`
+	ProjectID      *uint
+	Email          string `gorm:"index"`
+	Role           enum.MemberRole
+	TokenHash      string `gorm:"uniqueIndex"`
+	InvitedByID    uint
+	ExpiresAt      time.Time
+	AcceptedAt     *time.Time
+	DeclinedAt     *time.Time
+}
+
+// Pending reports whether the invitation can still be answered.
+func (i Invitation) Pending() bool {
+	return i.AcceptedAt == nil && i.DeclinedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
+// OrgScoped is implemented by models whose rows belong to a single organization. The
+// storage layer restricts every query on them to the organization of the request.
+type OrgScoped interface {
+	orgScoped()
+}
+
+func (Task) orgScoped()            {}
+func (TaskWatcher) orgScoped()     {}
+func (Project) orgScoped()         {}
+func (ShareLink) orgScoped()       {}
+func (Reminder) orgScoped()        {}
+func (Webhook) orgScoped()         {}
+func (WebhookDelivery) orgScoped() {}
+func (View) orgScoped()            {}
+func (ViewPin) orgScoped()         {}
+func (Workflow) orgScoped()        {}
+func (CustomField) orgScoped()     {}
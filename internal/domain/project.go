@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"graph-interview/internal/repository/enum"
+
+	"gorm.io/gorm"
+)
+
+// Project groups tasks into a named list owned by a user.
+type Project struct {
+	gorm.Model
+	OrganizationID uint `gorm:"index"`
+	Name           string
+	Description    string
+	Owner          *User `gorm:"foreignKey:OwnerID"`
+	OwnerID        uint  `gorm:"index"`
+	Archived       bool
+}
+
+// ProjectMember gives a user a role on a single project that takes precedence over their
+// organization role.
+type ProjectMember struct {
+	gorm.Model
+	Project   *Project `gorm:"foreignKey:ProjectID"`
+	ProjectID uint     `gorm:"uniqueIndex:idx_project_member"`
+	User      *User    `gorm:"foreignKey:UserID"`
+	UserID    uint     `gorm:"uniqueIndex:idx_project_member;index"`
+	Role      enum.MemberRole
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reminder notifies its user about a task through one channel, either at RemindAt or
+// BeforeDueMinutes ahead of the task's due date. Relative reminders follow the due date
+// when it moves.
+type Reminder struct {
+	gorm.Model
+	OrganizationID   uint  `gorm:"index"`
+	Task             *Task `gorm:"foreignKey:TaskID"`
+	TaskID           uint  `gorm:"index"`
+	User             *User `gorm:"foreignKey:UserID"`
+	UserID           uint  `gorm:"index"`
+	RemindAt         *time.Time
+	BeforeDueMinutes *int
+	Channel          string
+	// Target is the URL called by webhook reminders.
+	Target    string
+	Attempts  int
+	LastError string
+	SentAt    *time.Time
+	FailedAt  *time.Time
+}
+
+// FiresAt returns when the reminder is due, or nil while a relative reminder's task has no
+// due date. Task must be loaded for relative reminders.
+func (r Reminder) FiresAt() *time.Time {
+	if r.RemindAt != nil {
+		return r.RemindAt
+	}
+	if r.BeforeDueMinutes == nil || r.Task == nil || r.Task.DueDate == nil {
+		return nil
+	}
+	at := r.Task.DueDate.Add(-time.Duration(*r.BeforeDueMinutes) * time.Minute)
+	return &at
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShareLink grants anyone holding its token read-only access to a task or a project. The
+// token is the nonce signed with the server secret; only the nonce is stored.
+type ShareLink struct {
+	gorm.Model
+	OrganizationID uint     `gorm:"index"`
+	Nonce          string   `gorm:"uniqueIndex"`
+	Task           *Task    `gorm:"foreignKey:TaskID"`
+	TaskID         *uint    `gorm:"index"`
+	Project        *Project `gorm:"foreignKey:ProjectID"`
+	ProjectID      *uint    `gorm:"index"`
+	CreatedByID    uint
+	ExpiresAt      *time.Time
+	RevokedAt      *time.Time
+}
+
+// Active reports whether the link still grants access.
+func (l ShareLink) Active() bool {
+	return l.RevokedAt == nil && (l.ExpiresAt == nil || time.Now().Before(*l.ExpiresAt))
+}
@@ -2,18 +2,98 @@ package domain
 
 import (
 	"graph-interview/internal/repository/enum"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Task struct {
 	gorm.Model
+	OrganizationID  uint `gorm:"index"`
 	Name            string
 	Description     string
 	Status          enum.TaskStatus
-	CreatedBy       *User `gorm:"foreignKey:CreatedByUserID"`
-	CreatedByUserID uint
+	Project         *Project `gorm:"foreignKey:ProjectID"`
+	ProjectID       *uint    `gorm:"index"`
+	CreatedBy       *User    `gorm:"foreignKey:CreatedByUserID"`
+	CreatedByUserID *uint
 	UpdatedBy       *User `gorm:"foreignKey:UpdatedByUserID"`
-	UpdatedByUserID uint
+	UpdatedByUserID *uint
 	Assignees       []*User `gorm:"many2many:user_tasks;"`
+
+	DueDate *time.Time
+	// Recurrence is an RRULE for recurring tasks. Completing an occurrence creates the next
+	// one and hands the rule on to it; all occurrences share the SeriesID of the first.
+	Recurrence string
+	SeriesID   *uint `gorm:"index"`
+	Occurrence int
+
+	// Labels are free-form tags, kept trimmed, unique and sorted.
+	Labels []string `gorm:"serializer:json;type:jsonb"`
+
+	// CustomStatus names the task's status in its project's workflow when that differs
+	// from the name of Status, such as "In Review" for a task that counts as Started.
+	CustomStatus string
+
+	// CustomFields holds the values of the project's custom fields by key: strings for
+	// text, date (2026-01-05) and select fields, numbers for number and user fields. Unset
+	// fields have no key.
+	CustomFields map[string]any `gorm:"serializer:json;type:jsonb"`
+
+	// Checklist holds the task's to-do items in order. ChecklistNextID is the ID the next
+	// item added gets, so IDs of deleted items are never handed out again; it is 0 on tasks
+	// from before it was kept.
+	Checklist       []ChecklistItem `gorm:"serializer:json;type:jsonb"`
+	ChecklistNextID uint
+
+	// Position orders the task within its status column on the board. It is a lexorank
+	// key compared byte by byte; tasks without one come last, oldest first.
+	Position string `gorm:"index"`
+
+	// Version goes up with every update and backs the task's ETag, so clients can tell
+	// when their copy is stale.
+	Version int `gorm:"not null;default:1"`
+}
+
+// TaskWatcher subscribes a user to the changes of a task.
+type TaskWatcher struct {
+	OrganizationID uint `gorm:"index"`
+	TaskID         uint `gorm:"primaryKey"`
+	UserID         uint `gorm:"primaryKey;index"`
+	CreatedAt      time.Time
+}
+
+// ChecklistItem is a to-do item in a task's checklist. Its ID is unique within the task
+// only; CheckedBy and CheckedAt are set while it is checked.
+type ChecklistItem struct {
+	ID        uint       `json:"id"`
+	Text      string     `json:"text"`
+	Checked   bool       `json:"checked"`
+	CheckedBy *uint      `json:"checked_by,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// StatusName returns the name of the task's status in its workflow.
+func (t *Task) StatusName() string {
+	if t.CustomStatus != "" {
+		return t.CustomStatus
+	}
+	return t.Status.String()
+}
+
+// Column identifies a board column: the tasks of one project, or of none when ProjectID is
+// 0, in one status of its workflow. CustomStatus is set as on Task.
+type Column struct {
+	ProjectID    uint
+	Status       enum.TaskStatus
+	CustomStatus string
+}
+
+// Column returns the board column the task is in.
+func (t *Task) Column() Column {
+	column := Column{Status: t.Status, CustomStatus: t.CustomStatus}
+	if t.ProjectID != nil {
+		column.ProjectID = *t.ProjectID
+	}
+	return column
 }
@@ -1,18 +1,48 @@
 package domain
 
-import "gorm.io/gorm"
+import (
+	"graph-interview/internal/repository/enum"
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
 	gorm.Model
 	Username string `gorm:"uniqueIndex"`
 	Email    string
-	Password string
-	Avatar   string
+	// EmailVerified is set once the address is known to reach the user: it received an
+	// invitation link or an identity provider vouched for it.
+	EmailVerified         bool
+	Password              string
+	Avatar                string
+	Role                  enum.UserRole `gorm:"default:0"`
+	DeactivatedAt         *time.Time
+	PasswordResetRequired bool
+}
+
+func (u *User) Active() bool {
+	return u.DeactivatedAt == nil
 }
 
 type UserSession struct {
 	gorm.Model
-	User   *User `gorm:"foreignKey:UserID"`
-	UserID uint
-	Valid  bool `gorm:"default:true;"`
+	User       *User  `gorm:"foreignKey:UserID"`
+	UserID     uint   `gorm:"index"`
+	Valid      bool   `gorm:"default:true;"`
+	AccessJTI  string `gorm:"index"`
+	RefreshJTI string `gorm:"index"`
+	UserAgent  string
+	IP         string
+	ExpiresAt  time.Time
+}
+
+// UserIdentity links a user to an account of an external OIDC identity provider.
+type UserIdentity struct {
+	gorm.Model
+	User     *User  `gorm:"foreignKey:UserID"`
+	UserID   uint   `gorm:"index"`
+	Provider string `gorm:"uniqueIndex:idx_identity_provider_subject"`
+	Subject  string `gorm:"uniqueIndex:idx_identity_provider_subject"`
+	Email    string
 }
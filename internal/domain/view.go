@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// View is a saved task query: a filter expression and a sort order, run live whenever it is
+// opened. Views are private to their owner unless Shared, in which case everyone in the
+// project, or the whole organization when there is no project, can use them. A view with
+// a project only lists that project's tasks.
+type View struct {
+	gorm.Model
+	OrganizationID uint `gorm:"index"`
+	OwnerID        uint `gorm:"index"`
+	Name           string
+	Filter         string
+	Sort           string
+	Shared         bool
+	ProjectID      *uint `gorm:"index"`
+}
+
+// ViewPin pins a view for a user. View is the ID of a saved view or the key of a built-in
+// one.
+type ViewPin struct {
+	OrganizationID uint   `gorm:"primaryKey"`
+	UserID         uint   `gorm:"primaryKey"`
+	View           string `gorm:"primaryKey"`
+	CreatedAt      time.Time
+}
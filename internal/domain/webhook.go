@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Webhook posts the task events it subscribes to to URL, signed with Secret.
+type Webhook struct {
+	gorm.Model
+	OrganizationID uint `gorm:"index"`
+	CreatedByID    uint
+	URL            string
+	Secret         string
+	Events         []string `gorm:"serializer:json;type:jsonb"`
+}
+
+// Subscribes reports whether the webhook wants events of eventType.
+func (w Webhook) Subscribes(eventType string) bool {
+	return slices.Contains(w.Events, eventType)
+}
+
+// WebhookDelivery is one event queued for a webhook, along with the outcome of its latest
+// attempt. It is retried at NextAttemptAt until it is delivered or fails for good.
+type WebhookDelivery struct {
+	gorm.Model
+	OrganizationID uint     `gorm:"index"`
+	Webhook        *Webhook `gorm:"foreignKey:WebhookID"`
+	WebhookID      uint     `gorm:"index"`
+	EventID        string
+	EventType      string
+	Payload        string
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index"`
+	ResponseStatus int
+	LastError      string
+	DeliveredAt    *time.Time
+	FailedAt       *time.Time
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"graph-interview/internal/repository/enum"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Workflow lists the statuses a project's tasks can be in and which moves between them are
+// allowed. Every custom status counts as one of the built-in ones, its Status, so filters,
+// the board and recurring tasks keep working whatever the project calls its statuses.
+// Projects without a workflow, and tasks without a project, use DefaultWorkflow.
+type Workflow struct {
+	gorm.Model
+	OrganizationID uint                 `gorm:"index"`
+	ProjectID      uint                 `gorm:"uniqueIndex"`
+	Statuses       []WorkflowStatus     `gorm:"serializer:json;type:jsonb"`
+	Transitions    []WorkflowTransition `gorm:"serializer:json;type:jsonb"`
+}
+
+type WorkflowStatus struct {
+	Name     string              `json:"name"`
+	Category enum.StatusCategory `json:"category"`
+	Status   enum.TaskStatus     `json:"status"`
+}
+
+// WorkflowTransition allows tasks to move from one status to another, by name. A workflow
+// without transitions allows every move.
+type WorkflowTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DefaultWorkflow has a status for each built-in status, named after it, and allows every
+// move between them.
+func DefaultWorkflow() *Workflow {
+	statuses := []enum.TaskStatus{enum.Created, enum.Started, enum.Delayed, enum.Done, enum.Failed, enum.Canceled}
+	w := &Workflow{Statuses: make([]WorkflowStatus, len(statuses))}
+	for i, status := range statuses {
+		w.Statuses[i] = WorkflowStatus{Name: status.String(), Category: status.Category(), Status: status}
+	}
+	return w
+}
+
+// Find returns the status called name, ignoring case.
+func (w *Workflow) Find(name string) (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// ForStatus returns the first status that counts as status.
+func (w *Workflow) ForStatus(status enum.TaskStatus) (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Status == status {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// Initial returns the status new tasks start in: the first one in the todo category.
+func (w *Workflow) Initial() (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Category == enum.CategoryTodo {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// Allows reports whether tasks may move from the status called from to the one called to.
+func (w *Workflow) Allows(from, to string) bool {
+	if len(w.Transitions) == 0 || strings.EqualFold(from, to) {
+		return true
+	}
+	for _, t := range w.Transitions {
+		if strings.EqualFold(t.From, from) && strings.EqualFold(t.To, to) {
+			return true
+		}
+	}
+	return false
+}
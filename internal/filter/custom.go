@@ -0,0 +1,196 @@
+package filter
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CustomPrefix starts the names of custom fields in filters and sorts, as in cf.points.
+const CustomPrefix = "cf."
+
+// customKey matches the keys custom fields may have. Such keys are safe to put in SQL as
+// they are.
+var customKey = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// ValidKey reports whether key can be the key of a custom field.
+func ValidKey(key string) bool {
+	return customKey.MatchString(key)
+}
+
+// customField returns the field name stands for if it names a custom field, remembering
+// pos as where it was named.
+func customField(name string, pos int) (Field, bool) {
+	key, ok := strings.CutPrefix(strings.ToLower(name), CustomPrefix)
+	if !ok || !ValidKey(key) {
+		return Field{}, false
+	}
+	return Field{Name: CustomPrefix + key, Kind: KindCustom, Nullable: true, Key: key, pos: pos}, true
+}
+
+// Raw is a value compared with a custom field, as written. Parse cannot tell how to read it
+// before the field's type is known, so it keeps the readings that depend on the Env.
+type Raw struct {
+	Text string
+	Pos  int
+
+	user   uint
+	time   time.Time
+	isTime bool
+}
+
+// CustomKeys returns the keys of the custom fields e and orders use, each once.
+func CustomKeys(e Expr, orders []Order) []string {
+	var keys []string
+	add := func(f Field) {
+		if f.Kind == KindCustom && !slices.Contains(keys, f.Key) {
+			keys = append(keys, f.Key)
+		}
+	}
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch e := e.(type) {
+		case And:
+			walk(e.Left)
+			walk(e.Right)
+		case Or:
+			walk(e.Left)
+			walk(e.Right)
+		case Not:
+			walk(e.Expr)
+		case Cmp:
+			add(e.Field)
+		}
+	}
+	if e != nil {
+		walk(e)
+	}
+	for _, order := range orders {
+		add(order.Field)
+	}
+	return keys
+}
+
+// Bind resolves the custom fields of e against kinds, the kinds of their values by key: it
+// checks the operators they are used with and reads their values as the kind says. A kind
+// of KindCustom marks a key whose type differs between the fields it names.
+func Bind(e Expr, kinds map[string]Kind) (Expr, error) {
+	return mapCmp(e, func(e Cmp) (Expr, error) { return bindCmp(e, kinds) })
+}
+
+// mapCmp rebuilds e with each comparison replaced by what fn makes of it.
+func mapCmp(e Expr, fn func(Cmp) (Expr, error)) (Expr, error) {
+	switch e := e.(type) {
+	case And:
+		left, err := mapCmp(e.Left, fn)
+		if err != nil {
+			return nil, err
+		}
+		right, err := mapCmp(e.Right, fn)
+		return And{left, right}, err
+	case Or:
+		left, err := mapCmp(e.Left, fn)
+		if err != nil {
+			return nil, err
+		}
+		right, err := mapCmp(e.Right, fn)
+		return Or{left, right}, err
+	case Not:
+		expr, err := mapCmp(e.Expr, fn)
+		return Not{expr}, err
+	case Cmp:
+		return fn(e)
+	default:
+		return e, nil
+	}
+}
+
+// BindOrders resolves the custom fields of orders against kinds like Bind does.
+func BindOrders(orders []Order, kinds map[string]Kind) ([]Order, error) {
+	bound := make([]Order, len(orders))
+	for i, order := range orders {
+		if order.Field.Kind == KindCustom {
+			field, err := bindField(order.Field, kinds)
+			if err != nil {
+				return nil, err
+			}
+			order.Field = field
+		}
+		bound[i] = order
+	}
+	return bound, nil
+}
+
+func bindField(field Field, kinds map[string]Kind) (Field, error) {
+	kind, ok := kinds[field.Key]
+	if !ok {
+		return Field{}, &Error{Pos: field.pos, Token: field.Name, Msg: "unknown custom field"}
+	}
+	if kind == KindCustom {
+		return Field{}, &Error{Pos: field.pos, Token: field.Name, Msg: "custom field has a different type in each project; filter by project"}
+	}
+	field.Kind = kind
+	return field, nil
+}
+
+func bindCmp(e Cmp, kinds map[string]Kind) (Expr, error) {
+	if e.Field.Kind != KindCustom {
+		return e, nil
+	}
+	field, err := bindField(e.Field, kinds)
+	if err != nil {
+		return nil, err
+	}
+	switch e.Op {
+	case OpLt, OpLe, OpGt, OpGe:
+		if field.Kind != KindNumber && field.Kind != KindDate {
+			return nil, &Error{Pos: field.pos, Token: field.Name, Msg: field.Name + " only supports =, != and in"}
+		}
+	case OpContains:
+		if field.Kind != KindText {
+			return nil, &Error{Pos: field.pos, Token: field.Name, Msg: "~ is only supported for text fields"}
+		}
+	}
+
+	values := make([]any, len(e.Values))
+	for i, value := range e.Values {
+		raw, ok := value.(Raw)
+		if !ok {
+			continue
+		}
+		if values[i], err = readRaw(raw, field.Kind); err != nil {
+			return nil, err
+		}
+	}
+	return Cmp{Field: field, Op: e.Op, Values: values}, nil
+}
+
+// readRaw reads raw as a value of kind.
+func readRaw(raw Raw, kind Kind) (any, error) {
+	switch kind {
+	case KindNumber:
+		n, err := strconv.ParseFloat(raw.Text, 64)
+		if err != nil {
+			return nil, &Error{Pos: raw.Pos, Token: raw.Text, Msg: "expected a number"}
+		}
+		return n, nil
+	case KindDate:
+		if !raw.isTime {
+			return nil, &Error{Pos: raw.Pos, Token: raw.Text, Msg: "expected a date such as 2026-01-05, today or -7d"}
+		}
+		return raw.time.Format(time.DateOnly), nil
+	case KindUser:
+		if raw.user != 0 {
+			return raw.user, nil
+		}
+		id, err := strconv.ParseUint(raw.Text, 10, 32)
+		if err != nil || id == 0 {
+			return nil, &Error{Pos: raw.Pos, Token: raw.Text, Msg: "expected an ID"}
+		}
+		return uint(id), nil
+	default:
+		return raw.Text, nil
+	}
+}
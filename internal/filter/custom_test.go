@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"graph-interview/internal/repository/enum"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKinds = map[string]Kind{
+	"points":   KindNumber,
+	"customer": KindText,
+	"launch":   KindDate,
+	"owner":    KindUser,
+	"env":      KindCustom,
+}
+
+func TestCustomKeys(t *testing.T) {
+	expr, err := Parse(`cf.points > 1 or (status = Done and not cf.customer = acme) or cf.points < 9`, testEnv)
+	require.NoError(t, err)
+	orders, err := ParseSort("cf.launch,-cf.points,due")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"points", "customer", "launch"}, CustomKeys(expr, orders))
+	assert.Empty(t, CustomKeys(nil, nil))
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		src    string
+		field  string
+		kind   Kind
+		values []any
+	}{
+		{`cf.points >= 2.5`, "points", KindNumber, []any{2.5}},
+		{`cf.points in (1, 2)`, "points", KindNumber, []any{1.0, 2.0}},
+		{`cf.customer ~ "Acme Inc"`, "customer", KindText, []any{"Acme Inc"}},
+		{`cf.launch < +2w`, "launch", KindDate, []any{"2026-03-24"}},
+		{`cf.launch = 2026-01-05`, "launch", KindDate, []any{"2026-01-05"}},
+		{`cf.owner = me`, "owner", KindUser, []any{uint(7)}},
+		{`cf.owner in (3, me)`, "owner", KindUser, []any{uint(3), uint(7)}},
+		{`cf.owner != null`, "owner", KindUser, []any{nil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := Parse(tt.src, testEnv)
+			require.NoError(t, err)
+			bound, err := Bind(expr, testKinds)
+			require.NoError(t, err)
+
+			cmp := bound.(Cmp)
+			assert.Equal(t, tt.field, cmp.Field.Key)
+			assert.Equal(t, tt.kind, cmp.Field.Kind)
+			assert.Equal(t, tt.values, cmp.Values)
+		})
+	}
+}
+
+func TestBind_KeepsBuiltinFields(t *testing.T) {
+	expr, err := Parse(`status = Started and not cf.points = 3`, testEnv)
+	require.NoError(t, err)
+
+	bound, err := Bind(expr, testKinds)
+	require.NoError(t, err)
+	assert.Equal(t, `(status = Started and not cf.points = 3)`, bound.String())
+	assert.Equal(t, Cmp{Field: Fields["status"], Op: OpEq, Values: []any{enum.Started}}, bound.(And).Left)
+}
+
+func TestBind_Errors(t *testing.T) {
+	tests := []struct {
+		src   string
+		pos   int
+		token string
+		msg   string
+	}{
+		{`cf.budget = 3`, 1, "cf.budget", "unknown custom field"},
+		{`cf.env = prod`, 1, "cf.env", "custom field has a different type in each project; filter by project"},
+		{`cf.customer > b`, 1, "cf.customer", "cf.customer only supports =, != and in"},
+		{`cf.points ~ 3`, 1, "cf.points", "~ is only supported for text fields"},
+		{`cf.points = three`, 13, "three", "expected a number"},
+		{`cf.launch = soon`, 13, "soon", "expected a date such as 2026-01-05, today or -7d"},
+		{`cf.owner in (1, bob)`, 17, "bob", "expected an ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := Parse(tt.src, testEnv)
+			require.NoError(t, err)
+
+			_, err = Bind(expr, testKinds)
+			var ferr *Error
+			require.ErrorAs(t, err, &ferr)
+			assert.Equal(t, tt.pos, ferr.Pos)
+			assert.Equal(t, tt.token, ferr.Token)
+			assert.Equal(t, tt.msg, ferr.Msg)
+		})
+	}
+}
+
+func TestBindOrders(t *testing.T) {
+	orders, err := ParseSort("cf.points,-name")
+	require.NoError(t, err)
+
+	bound, err := BindOrders(orders, testKinds)
+	require.NoError(t, err)
+	assert.Equal(t, KindNumber, bound[0].Field.Kind)
+	assert.Equal(t, orders[1], bound[1])
+
+	orders, err = ParseSort("due,cf.budget")
+	require.NoError(t, err)
+	_, err = BindOrders(orders, testKinds)
+	var ferr *Error
+	require.ErrorAs(t, err, &ferr)
+	assert.Equal(t, 5, ferr.Pos)
+}
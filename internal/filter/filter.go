@@ -0,0 +1,155 @@
+// Package filter parses the task filter language used by the filter query parameter, e.g.
+//
+//	status in (Started, Delayed) and assignee = me and created >= -7d
+//
+// Parse resolves the expression against an Env into an AST whose values are plain Go
+// values, ready to be compiled into a query by the storage layer. Custom fields, named
+// cf.<key>, are typed by their definitions, so the storage layer Binds them first, and it
+// looks the names of custom statuses up in the project's workflow with BindStatuses.
+package filter
+
+import (
+	"fmt"
+	"time"
+)
+
+// Limits keep a single filter cheap to parse and to run.
+const (
+	MaxLength = 1000
+	MaxTerms  = 50
+	MaxValues = 100
+)
+
+// Kind is the type of a field's values.
+type Kind int
+
+const (
+	KindStatus Kind = iota
+	KindUser
+	KindID
+	KindText
+	KindLabel
+	KindTime
+	KindNumber
+	// KindDate values are calendar days, compared as 2026-01-05 strings.
+	KindDate
+	// KindCustom is the kind of custom fields until Bind learns their type.
+	KindCustom
+)
+
+// Field is a task attribute a filter can compare. Custom fields are named cf.<key> and
+// have their key set.
+type Field struct {
+	Name     string
+	Kind     Kind
+	Nullable bool
+	Key      string
+	// pos is where a custom field was named, for Bind to point at.
+	pos int
+}
+
+// Fields are the fields a filter may use, by name.
+var Fields = map[string]Field{
+	"status":      {Name: "status", Kind: KindStatus},
+	"assignee":    {Name: "assignee", Kind: KindUser, Nullable: true},
+	"created_by":  {Name: "created_by", Kind: KindUser, Nullable: true},
+	"project":     {Name: "project", Kind: KindID, Nullable: true},
+	"series":      {Name: "series", Kind: KindID, Nullable: true},
+	"name":        {Name: "name", Kind: KindText},
+	"description": {Name: "description", Kind: KindText},
+	"label":       {Name: "label", Kind: KindLabel, Nullable: true},
+	"due":         {Name: "due", Kind: KindTime, Nullable: true},
+	"created":     {Name: "created", Kind: KindTime},
+	"updated":     {Name: "updated", Kind: KindTime},
+}
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEq       Op = "="
+	OpNe       Op = "!="
+	OpLt       Op = "<"
+	OpLe       Op = "<="
+	OpGt       Op = ">"
+	OpGe       Op = ">="
+	OpContains Op = "~"
+	OpIn       Op = "in"
+)
+
+// Expr is a node of a parsed filter: And, Or, Not or Cmp.
+type Expr interface {
+	String() string
+}
+
+type And struct{ Left, Right Expr }
+
+type Or struct{ Left, Right Expr }
+
+type Not struct{ Expr Expr }
+
+// Cmp compares a field with its values. Values hold a single value except for OpIn, and
+// are enum.TaskStatus, uint, string, float64 or time.Time according to the field's kind,
+// or nil for null. Custom fields, and statuses other than the built-in ones, hold Raw
+// values until bound; bound statuses may be WorkflowStatus values.
+type Cmp struct {
+	Field  Field
+	Op     Op
+	Values []any
+}
+
+func (e And) String() string { return "(" + e.Left.String() + " and " + e.Right.String() + ")" }
+
+func (e Or) String() string { return "(" + e.Left.String() + " or " + e.Right.String() + ")" }
+
+func (e Not) String() string { return "not " + e.Expr.String() }
+
+func (e Cmp) String() string {
+	values := ""
+	for i, value := range e.Values {
+		if i > 0 {
+			values += ", "
+		}
+		switch v := value.(type) {
+		case nil:
+			values += "null"
+		case string:
+			values += fmt.Sprintf("%q", v)
+		case Raw:
+			values += fmt.Sprintf("%q", v.Text)
+		case WorkflowStatus:
+			values += fmt.Sprintf("%q", v.Name)
+		case time.Time:
+			values += v.Format(time.RFC3339)
+		default:
+			values += fmt.Sprint(v)
+		}
+	}
+	if e.Op == OpIn {
+		values = "(" + values + ")"
+	}
+	return e.Field.Name + " " + string(e.Op) + " " + values
+}
+
+// Env is what a filter is resolved against.
+type Env struct {
+	// UserID is the user "me" stands for.
+	UserID uint
+	// Now anchors relative dates; "today" is its midnight in Now's location.
+	Now time.Time
+}
+
+// Error points at the token a filter could not be parsed at.
+type Error struct {
+	// Pos is the 1-based offset of the token in the filter.
+	Pos   int
+	Token string
+	Msg   string
+}
+
+func (e *Error) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("filter: %s at end of filter", e.Msg)
+	}
+	return fmt.Sprintf("filter: %s at position %d (%q)", e.Msg, e.Pos, e.Token)
+}
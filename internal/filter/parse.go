@@ -0,0 +1,341 @@
+package filter
+
+import (
+	"fmt"
+	"graph-interview/internal/repository/enum"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// relative matches dates relative to now, such as -7d or +2w.
+var relative = regexp.MustCompile(`^([+-])(\d{1,4})([mhdw])$`)
+
+// Parse parses a filter expression and resolves its values against env. Words are
+// case-insensitive; strings containing spaces or operators are quoted with " or '.
+func Parse(src string, env Env) (Expr, error) {
+	if len(src) > MaxLength {
+		return nil, &Error{Pos: MaxLength + 1, Msg: fmt.Sprintf("filter longer than %d characters", MaxLength)}
+	}
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, env: env}
+	expr, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "expected and, or or end of filter")
+	}
+	return expr, nil
+}
+
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "(", start + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")", start + 1})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{tokComma, ",", start + 1})
+			i++
+		case r == '"' || r == '\'':
+			end := strings.IndexRune(src[i+1:], r)
+			if end < 0 {
+				return nil, &Error{Pos: start + 1, Token: src[start:], Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{tokString, src[i+1 : i+1+end], start + 1})
+			i += end + 2
+		case strings.ContainsRune("=!<>~", r):
+			op := src[i : i+1]
+			if i+1 < len(src) && src[i+1] == '=' && op != "=" && op != "~" {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: start + 1, Token: op, Msg: "unknown operator"}
+			}
+			tokens = append(tokens, token{tokOp, op, start + 1})
+			i += len(op)
+		default:
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if unicode.IsSpace(r) || strings.ContainsRune(`(),"'=!<>~`, r) {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{tokWord, src[start:i], start + 1})
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(src) + 1}), nil
+}
+
+type parser struct {
+	tokens []token
+	next   int
+	terms  int
+	env    Env
+}
+
+func (p *parser) peek() token { return p.tokens[p.next] }
+
+func (p *parser) take() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokEOF {
+		p.next++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the word kw, and consumes it if so.
+func (p *parser) keyword(kw string) bool {
+	tok := p.peek()
+	if tok.kind == tokWord && strings.EqualFold(tok.text, kw) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(tok token, format string, args ...any) error {
+	return &Error{Pos: tok.pos, Token: tok.text, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) or() (Expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and() (Expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (Expr, error) {
+	if p.keyword("not") {
+		expr, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{expr}, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.take()
+		expr, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.take(); tok.kind != tokRParen {
+			return nil, p.errorf(tok, "expected )")
+		}
+		return expr, nil
+	}
+	return p.cmp()
+}
+
+func (p *parser) cmp() (Expr, error) {
+	tok := p.take()
+	if tok.kind != tokWord {
+		return nil, p.errorf(tok, "expected a field")
+	}
+	field, ok := Fields[strings.ToLower(tok.text)]
+	if !ok {
+		if field, ok = customField(tok.text, tok.pos); !ok {
+			return nil, p.errorf(tok, "unknown field")
+		}
+	}
+	if p.terms++; p.terms > MaxTerms {
+		return nil, p.errorf(tok, "more than %d comparisons", MaxTerms)
+	}
+
+	if p.keyword("in") {
+		if field.Kind == KindTime {
+			return nil, p.errorf(p.tokens[p.next-1], "in is not supported for %s", field.Name)
+		}
+		values, err := p.list(field)
+		if err != nil {
+			return nil, err
+		}
+		return Cmp{Field: field, Op: OpIn, Values: values}, nil
+	}
+
+	opTok := p.take()
+	if opTok.kind != tokOp {
+		return nil, p.errorf(opTok, "expected an operator")
+	}
+	op := Op(opTok.text)
+	switch op {
+	case OpLt, OpLe, OpGt, OpGe:
+		if field.Kind != KindTime && field.Kind != KindCustom {
+			return nil, p.errorf(opTok, "%s only supports =, != and in", field.Name)
+		}
+	case OpContains:
+		if field.Kind != KindText && field.Kind != KindCustom {
+			return nil, p.errorf(opTok, "~ is only supported for name and description")
+		}
+	}
+	value, err := p.value(field, op == OpEq || op == OpNe)
+	if err != nil {
+		return nil, err
+	}
+	return Cmp{Field: field, Op: op, Values: []any{value}}, nil
+}
+
+func (p *parser) list(field Field) ([]any, error) {
+	if tok := p.take(); tok.kind != tokLParen {
+		return nil, p.errorf(tok, "expected ( after in")
+	}
+	var values []any
+	for {
+		if len(values) == MaxValues {
+			return nil, p.errorf(p.peek(), "more than %d values", MaxValues)
+		}
+		value, err := p.value(field, false)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		switch tok := p.take(); tok.kind {
+		case tokComma:
+		case tokRParen:
+			return values, nil
+		default:
+			return nil, p.errorf(tok, "expected , or )")
+		}
+	}
+}
+
+// value reads one value of field. null is only accepted where allowNull is set.
+func (p *parser) value(field Field, allowNull bool) (any, error) {
+	tok := p.take()
+	if tok.kind != tokWord && tok.kind != tokString {
+		return nil, p.errorf(tok, "expected a value")
+	}
+	word := tok.kind == tokWord
+	if word && strings.EqualFold(tok.text, "null") {
+		if !allowNull || !field.Nullable {
+			return nil, p.errorf(tok, "%s cannot be compared with null here", field.Name)
+		}
+		return nil, nil
+	}
+
+	switch field.Kind {
+	case KindStatus:
+		for status := enum.Created; status <= enum.Canceled; status++ {
+			if strings.EqualFold(tok.text, status.String()) || tok.text == strconv.Itoa(int(status)) {
+				return status, nil
+			}
+		}
+		// It may name a status of a project's workflow; BindStatuses tells.
+		return Raw{Text: tok.text, Pos: tok.pos}, nil
+	case KindUser, KindID:
+		if field.Kind == KindUser && word && strings.EqualFold(tok.text, "me") {
+			return p.env.UserID, nil
+		}
+		id, err := strconv.ParseUint(tok.text, 10, 32)
+		if err != nil || id == 0 {
+			return nil, p.errorf(tok, "expected an ID")
+		}
+		return uint(id), nil
+	case KindCustom:
+		raw := Raw{Text: tok.text, Pos: tok.pos}
+		if word && strings.EqualFold(tok.text, "me") {
+			raw.user = p.env.UserID
+		}
+		raw.time, raw.isTime = p.time(tok.text)
+		return raw, nil
+	case KindTime:
+		t, ok := p.time(tok.text)
+		if !ok {
+			return nil, p.errorf(tok, "expected a date such as 2026-01-05, now, today or -7d")
+		}
+		return t, nil
+	default:
+		return tok.text, nil
+	}
+}
+
+func (p *parser) time(s string) (time.Time, bool) {
+	now := p.env.Now
+	switch strings.ToLower(s) {
+	case "now":
+		return now, true
+	case "today":
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()), true
+	}
+	if m := relative.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[2])
+		if m[1] == "-" {
+			n = -n
+		}
+		switch m[3] {
+		case "m":
+			return now.Add(time.Duration(n) * time.Minute), true
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), true
+		case "d":
+			return now.AddDate(0, 0, n), true
+		default:
+			return now.AddDate(0, 0, 7*n), true
+		}
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, now.Location()); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,118 @@
+package filter
+
+import (
+	"graph-interview/internal/repository/enum"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEnv = Env{UserID: 7, Now: time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`status = Started`, `status = Started`},
+		{`STATUS = done`, `status = Done`},
+		{`status != 3`, `status != Failed`},
+		{`status in (Started, Delayed)`, `status in (Started, Delayed)`},
+		{`assignee = me`, `assignee = 7`},
+		{`assignee in (me, 4)`, `assignee in (7, 4)`},
+		{`assignee = null`, `assignee = null`},
+		{`project != null`, `project != null`},
+		{`name ~ "weekly report"`, `name ~ "weekly report"`},
+		{`label = 'needs review'`, `label = "needs review"`},
+		{`created >= -7d`, `created >= 2026-03-03T15:30:00Z`},
+		{`due < +2w`, `due < 2026-03-24T15:30:00Z`},
+		{`updated > -3h`, `updated > 2026-03-10T12:30:00Z`},
+		{`due <= today`, `due <= 2026-03-10T00:00:00Z`},
+		{`due < now`, `due < 2026-03-10T15:30:00Z`},
+		{`created >= 2026-01-05`, `created >= 2026-01-05T00:00:00Z`},
+		{`created < 2026-01-05T09:00:00+01:00`, `created < 2026-01-05T09:00:00+01:00`},
+		{`status=Started and assignee=me or label=bug`, `((status = Started and assignee = 7) or label = "bug")`},
+		{`status = Started and (assignee = me or label = bug)`, `(status = Started and (assignee = 7 or label = "bug"))`},
+		{`not status = Done and not (label = a or label = b)`, `(not status = Done and not (label = "a" or label = "b"))`},
+		{`CF.Points >= 3 and cf.customer = null`, `(cf.points >= "3" and cf.customer = null)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := Parse(tt.src, testEnv)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, expr.String())
+		})
+	}
+}
+
+func TestParse_Values(t *testing.T) {
+	expr, err := Parse(`status in (Started, Delayed) and assignee = me`, testEnv)
+	require.NoError(t, err)
+
+	and := expr.(And)
+	assert.Equal(t, Cmp{Field: Fields["status"], Op: OpIn, Values: []any{enum.Started, enum.Delayed}}, and.Left)
+	assert.Equal(t, Cmp{Field: Fields["assignee"], Op: OpEq, Values: []any{uint(7)}}, and.Right)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		src   string
+		pos   int
+		token string
+		msg   string
+	}{
+		{`stats = Started`, 1, "stats", "unknown field"},
+		{`cf.Story-Points = 3`, 1, "cf.Story-Points", "unknown field"},
+		{`cf. = 3`, 1, "cf.", "unknown field"},
+		{`status < Done`, 8, "<", "status only supports =, != and in"},
+		{`status = Started and`, 21, "", "expected a field"},
+		{`status = Started label = bug`, 18, "label", "expected and, or or end of filter"},
+		{`(status = Started`, 18, "", "expected )"},
+		{`status in Started`, 11, "Started", "expected ( after in"},
+		{`status in (Started Done)`, 20, "Done", "expected , or )"},
+		{`assignee = bob`, 12, "bob", "expected an ID"},
+		{`assignee in (null)`, 14, "null", "assignee cannot be compared with null here"},
+		{`status = null`, 10, "null", "status cannot be compared with null here"},
+		{`created >= yesterday`, 12, "yesterday", "expected a date such as 2026-01-05, now, today or -7d"},
+		{`created in (today)`, 9, "in", "in is not supported for created"},
+		{`label ~ bug`, 7, "~", "~ is only supported for name and description"},
+		{`name ! x`, 6, "!", "unknown operator"},
+		{`name = "x`, 8, `"x`, "unterminated string"},
+		{`name x`, 6, "x", "expected an operator"},
+		{`= x`, 1, "=", "expected a field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src, testEnv)
+			var ferr *Error
+			require.ErrorAs(t, err, &ferr)
+			assert.Equal(t, tt.pos, ferr.Pos)
+			assert.Equal(t, tt.token, ferr.Token)
+			assert.Equal(t, tt.msg, ferr.Msg)
+		})
+	}
+}
+
+func TestParse_Limits(t *testing.T) {
+	_, err := Parse(strings.Repeat("x", MaxLength+1), testEnv)
+	assert.ErrorContains(t, err, "longer than")
+
+	terms := make([]string, MaxTerms+1)
+	for i := range terms {
+		terms[i] = "label = a"
+	}
+	_, err = Parse(strings.Join(terms, " or "), testEnv)
+	assert.ErrorContains(t, err, "more than 50 comparisons")
+
+	values := strings.TrimSuffix(strings.Repeat("a, ", MaxValues+1), ", ")
+	_, err = Parse("label in ("+values+")", testEnv)
+	assert.ErrorContains(t, err, "more than 100 values")
+}
+
+func TestError_Error(t *testing.T) {
+	assert.Equal(t, `filter: unknown field at position 1 ("stats")`, (&Error{Pos: 1, Token: "stats", Msg: "unknown field"}).Error())
+	assert.Equal(t, `filter: expected a field at end of filter`, (&Error{Pos: 9, Msg: "expected a field"}).Error())
+}
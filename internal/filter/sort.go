@@ -0,0 +1,75 @@
+package filter
+
+import (
+	"strings"
+)
+
+// MaxSortKeys bounds how many fields a sort may use.
+const MaxSortKeys = 5
+
+// sortable are the fields tasks can be sorted by.
+var sortable = map[string]bool{
+	"status":   true,
+	"name":     true,
+	"project":  true,
+	"due":      true,
+	"created":  true,
+	"updated":  true,
+	"position": true,
+}
+
+// Position is the board order of tasks within their status column. Tasks can be sorted
+// by it but not filtered.
+var Position = Field{Name: "position", Kind: KindText}
+
+// Order sorts by one field.
+type Order struct {
+	Field Field
+	Desc  bool
+}
+
+func (o Order) String() string {
+	if o.Desc {
+		return "-" + o.Field.Name
+	}
+	return o.Field.Name
+}
+
+// ParseSort parses a comma separated list of fields such as "-updated,name". A leading -
+// sorts that field in descending order.
+func ParseSort(src string) ([]Order, error) {
+	if strings.TrimSpace(src) == "" {
+		return nil, nil
+	}
+	var orders []Order
+	pos := 1
+	for _, part := range strings.Split(src, ",") {
+		key := strings.TrimSpace(part)
+		at := pos + strings.Index(part, key)
+		pos += len(part) + 1
+
+		if len(orders) == MaxSortKeys {
+			return nil, &Error{Pos: at, Token: key, Msg: "too many sort fields"}
+		}
+		desc := strings.HasPrefix(key, "-")
+		name := strings.ToLower(strings.TrimPrefix(key, "-"))
+		custom, isCustom := customField(name, at)
+		if !sortable[name] && !isCustom {
+			return nil, &Error{Pos: at, Token: key, Msg: "cannot sort by this field"}
+		}
+		for _, order := range orders {
+			if order.Field.Name == name {
+				return nil, &Error{Pos: at, Token: key, Msg: "field sorted twice"}
+			}
+		}
+		field, ok := Fields[name]
+		switch {
+		case isCustom:
+			field = custom
+		case !ok:
+			field = Position
+		}
+		orders = append(orders, Order{Field: field, Desc: desc})
+	}
+	return orders, nil
+}
@@ -0,0 +1,52 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	orders, err := ParseSort(" -updated, Name ")
+	require.NoError(t, err)
+	assert.Equal(t, []Order{{Field: Fields["updated"], Desc: true}, {Field: Fields["name"]}}, orders)
+
+	orders, err = ParseSort("status,position")
+	require.NoError(t, err)
+	assert.Equal(t, []Order{{Field: Fields["status"]}, {Field: Position}}, orders)
+
+	orders, err = ParseSort("-cf.points,due")
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, Field{Name: "cf.points", Kind: KindCustom, Nullable: true, Key: "points", pos: 1}, orders[0].Field)
+	assert.True(t, orders[0].Desc)
+
+	orders, err = ParseSort("")
+	assert.NoError(t, err)
+	assert.Empty(t, orders)
+
+	tests := []struct {
+		src   string
+		pos   int
+		token string
+		msg   string
+	}{
+		{"due,assignee", 5, "assignee", "cannot sort by this field"},
+		{"due, -due", 6, "-due", "field sorted twice"},
+		{"cf.a,cf.A", 6, "cf.A", "field sorted twice"},
+		{"cf.1st", 1, "cf.1st", "cannot sort by this field"},
+		{"due,,name", 5, "", "cannot sort by this field"},
+		{"status,name,project,due,created,updated", 33, "updated", "too many sort fields"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := ParseSort(tt.src)
+			var ferr *Error
+			require.ErrorAs(t, err, &ferr)
+			assert.Equal(t, tt.pos, ferr.Pos)
+			assert.Equal(t, tt.token, ferr.Token)
+			assert.Equal(t, tt.msg, ferr.Msg)
+		})
+	}
+}
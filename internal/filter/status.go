@@ -0,0 +1,46 @@
+package filter
+
+import "graph-interview/internal/repository/enum"
+
+// WorkflowStatus is a status of a project's workflow as the value of a status comparison.
+// It matches the tasks in that status only, where a built-in status matches every task
+// counting as it.
+type WorkflowStatus struct {
+	Name   string
+	Status enum.TaskStatus
+}
+
+// BindStatuses resolves the statuses e compares with against the workflow of the project
+// being filtered, whose statuses find looks up by name; find is nil when there is none.
+// Statuses of the workflow stand for themselves, built-in ones it lacks still match every
+// task counting as them, and other names are unknown.
+func BindStatuses(e Expr, find func(name string) (WorkflowStatus, bool)) (Expr, error) {
+	return mapCmp(e, func(e Cmp) (Expr, error) {
+		if e.Field.Kind != KindStatus {
+			return e, nil
+		}
+		values := make([]any, len(e.Values))
+		for i, value := range e.Values {
+			values[i] = value
+			var name string
+			switch v := value.(type) {
+			case Raw:
+				name = v.Text
+			case enum.TaskStatus:
+				name = v.String()
+			default:
+				continue
+			}
+			if find != nil {
+				if status, ok := find(name); ok {
+					values[i] = status
+					continue
+				}
+			}
+			if raw, ok := value.(Raw); ok {
+				return nil, &Error{Pos: raw.Pos, Token: raw.Text, Msg: "unknown status"}
+			}
+		}
+		return Cmp{Field: e.Field, Op: e.Op, Values: values}, nil
+	})
+}
@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"graph-interview/internal/repository/enum"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findReview looks statuses up in a workflow with a Todo, an In Review counting as Started
+// and a Done.
+func findReview(name string) (WorkflowStatus, bool) {
+	for _, status := range []WorkflowStatus{{"Todo", enum.Created}, {"In Review", enum.Started}, {"Done", enum.Done}} {
+		if strings.EqualFold(status.Name, name) {
+			return status, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+func TestBindStatuses(t *testing.T) {
+	tests := []struct {
+		src    string
+		values []any
+	}{
+		{`status = "in review"`, []any{WorkflowStatus{"In Review", enum.Started}}},
+		{`status = done`, []any{WorkflowStatus{"Done", enum.Done}}},
+		{`status in (Todo, Delayed)`, []any{WorkflowStatus{"Todo", enum.Created}, enum.Delayed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := Parse(tt.src, testEnv)
+			require.NoError(t, err)
+			bound, err := BindStatuses(expr, findReview)
+			require.NoError(t, err)
+			assert.Equal(t, tt.values, bound.(Cmp).Values)
+		})
+	}
+}
+
+func TestBindStatuses_WithoutWorkflow(t *testing.T) {
+	expr, err := Parse(`status in (Started, 2) and name = Todo`, testEnv)
+	require.NoError(t, err)
+	bound, err := BindStatuses(expr, nil)
+	require.NoError(t, err)
+	assert.Equal(t, expr, bound)
+}
+
+func TestBindStatuses_Unknown(t *testing.T) {
+	for _, find := range []func(string) (WorkflowStatus, bool){nil, findReview} {
+		expr, err := Parse(`label = bug or status = Begun`, testEnv)
+		require.NoError(t, err)
+		_, err = BindStatuses(expr, find)
+		var ferr *Error
+		require.ErrorAs(t, err, &ferr)
+		assert.Equal(t, 25, ferr.Pos)
+		assert.Equal(t, "Begun", ferr.Token)
+		assert.Equal(t, "unknown status", ferr.Msg)
+	}
+}
@@ -3,10 +3,18 @@ package cache
 import "fmt"
 
 const (
-	AccessTokenPrefix  = "access:"
-	RefreshTokenPrefix = "refresh:"
-	TaskCachePrefix    = "task:"
-	TaskListCacheKey   = "tasks:list"
+	AccessTokenPrefix   = "access:"
+	RefreshTokenPrefix  = "refresh:"
+	TaskCachePrefix     = "task:"
+	TaskListCacheKey    = "tasks:list"
+	RateLimitPrefix     = "ratelimit:"
+	IdempotencyPrefix   = "idempotency:"
+	OIDCStatePrefix     = "oidc:state:"
+	PasswordResetPrefix = "pwreset:"
+	ReminderLockKey     = "lock:reminders"
+	WebhookLockKey      = "lock:webhooks"
+	OutboxLockKey       = "lock:outbox"
+	EventPrefix         = "events:"
 )
 
 func AccessTokenKey(jti string) string {
@@ -24,3 +32,36 @@ func TaskCacheKey(id uint) string {
 func TaskListCacheKeyWithParams(limit, offset int, status string) string {
 	return fmt.Sprintf("%s:%d:%d:%s", TaskListCacheKey, limit, offset, status)
 }
+
+func RateLimitKey(group, subject string) string {
+	return fmt.Sprintf("%s%s:%s", RateLimitPrefix, group, subject)
+}
+
+// IdempotencyKey holds the response to the request subject sent with an Idempotency-Key.
+func IdempotencyKey(subject, key string) string {
+	return fmt.Sprintf("%s%s:%s", IdempotencyPrefix, subject, key)
+}
+
+func OIDCStateKey(state string) string {
+	return OIDCStatePrefix + state
+}
+
+func PasswordResetKey(token string) string {
+	return PasswordResetPrefix + token
+}
+
+// EventChannel is the pub/sub channel task events of orgID are published on. Zero stands
+// for data outside any organization.
+func EventChannel(orgID uint) string {
+	return fmt.Sprintf("%s%d", EventPrefix, orgID)
+}
+
+// EventSeqKey holds the last event ID handed out for orgID.
+func EventSeqKey(orgID uint) string {
+	return fmt.Sprintf("%sseq:%d", EventPrefix, orgID)
+}
+
+// EventLogKey holds the recent events of orgID, scored by ID, for replay.
+func EventLogKey(orgID uint) string {
+	return fmt.Sprintf("%slog:%d", EventPrefix, orgID)
+}
@@ -22,3 +22,17 @@ func TestTaskListCacheKeyWithParams(t *testing.T) {
 	result := TaskListCacheKeyWithParams(20, 0, "Created")
 	assert.Equal(t, "tasks:list:20:0:Created", result)
 }
+
+func TestRateLimitKey(t *testing.T) {
+	assert.Equal(t, "ratelimit:auth:ip:127.0.0.1", RateLimitKey("auth", "ip:127.0.0.1"))
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	assert.Equal(t, "idempotency:user:1:abc", IdempotencyKey("user:1", "abc"))
+}
+
+func TestEventKeys(t *testing.T) {
+	assert.Equal(t, "events:7", EventChannel(7))
+	assert.Equal(t, "events:seq:7", EventSeqKey(7))
+	assert.Equal(t, "events:log:7", EventLogKey(7))
+}
@@ -0,0 +1,39 @@
+package enum
+
+// CustomFieldType is the type of the values a custom field holds.
+type CustomFieldType int
+
+const (
+	FieldText CustomFieldType = iota
+	FieldNumber
+	FieldDate
+	FieldSelect
+	FieldUser
+)
+
+func (t CustomFieldType) String() string {
+	switch t {
+	case FieldText:
+		return "text"
+	case FieldNumber:
+		return "number"
+	case FieldDate:
+		return "date"
+	case FieldSelect:
+		return "select"
+	case FieldUser:
+		return "user"
+	default:
+		return ""
+	}
+}
+
+// ParseCustomFieldType returns the type named s, as returned by String.
+func ParseCustomFieldType(s string) (CustomFieldType, bool) {
+	for t := FieldText; t <= FieldUser; t++ {
+		if t.String() == s {
+			return t, true
+		}
+	}
+	return 0, false
+}
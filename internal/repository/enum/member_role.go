@@ -0,0 +1,24 @@
+package enum
+
+// MemberRole is a user's role inside an organization or project. Roles are ordered, so a
+// higher value grants everything a lower one does.
+type MemberRole int
+
+const (
+	MemberViewer MemberRole = iota
+	MemberEditor
+	MemberOwner
+)
+
+func (r MemberRole) String() string {
+	switch r {
+	case MemberViewer:
+		return "Viewer"
+	case MemberEditor:
+		return "Editor"
+	case MemberOwner:
+		return "Owner"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,34 @@
+package enum
+
+// StatusCategory groups task statuses by how far along the work is, so reports and
+// workflows can reason about custom statuses.
+type StatusCategory int
+
+const (
+	CategoryTodo StatusCategory = iota
+	CategoryInProgress
+	CategoryDone
+)
+
+func (c StatusCategory) String() string {
+	switch c {
+	case CategoryTodo:
+		return "todo"
+	case CategoryInProgress:
+		return "in_progress"
+	case CategoryDone:
+		return "done"
+	default:
+		return ""
+	}
+}
+
+// ParseStatusCategory returns the category named s, as returned by String.
+func ParseStatusCategory(s string) (StatusCategory, bool) {
+	for c := CategoryTodo; c <= CategoryDone; c++ {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return 0, false
+}
@@ -29,3 +29,15 @@ func (t TaskStatus) String() string {
 		return ""
 	}
 }
+
+// Category returns the category the status belongs to.
+func (t TaskStatus) Category() StatusCategory {
+	switch t {
+	case Created:
+		return CategoryTodo
+	case Started, Delayed:
+		return CategoryInProgress
+	default:
+		return CategoryDone
+	}
+}
@@ -26,3 +26,32 @@ func TestTaskStatus_String(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRole_String(t *testing.T) {
+	assert.Equal(t, "User", RoleUser.String())
+	assert.Equal(t, "Admin", RoleAdmin.String())
+	assert.Equal(t, "", UserRole(99).String())
+}
+
+func TestMemberRole_String(t *testing.T) {
+	assert.Equal(t, "Viewer", MemberViewer.String())
+	assert.Equal(t, "Editor", MemberEditor.String())
+	assert.Equal(t, "Owner", MemberOwner.String())
+	assert.Equal(t, "", MemberRole(99).String())
+}
+
+func TestTaskStatus_Category(t *testing.T) {
+	assert.Equal(t, CategoryTodo, Created.Category())
+	assert.Equal(t, CategoryInProgress, Delayed.Category())
+	assert.Equal(t, CategoryDone, Canceled.Category())
+}
+
+func TestParseStatusCategory(t *testing.T) {
+	c, ok := ParseStatusCategory("in_progress")
+	assert.True(t, ok)
+	assert.Equal(t, CategoryInProgress, c)
+	assert.Equal(t, "done", CategoryDone.String())
+
+	_, ok = ParseStatusCategory("blocked")
+	assert.False(t, ok)
+}
@@ -0,0 +1,19 @@
+package enum
+
+type UserRole int
+
+const (
+	RoleUser UserRole = iota
+	RoleAdmin
+)
+
+func (r UserRole) String() string {
+	switch r {
+	case RoleUser:
+		return "User"
+	case RoleAdmin:
+		return "Admin"
+	default:
+		return ""
+	}
+}
@@ -4,15 +4,110 @@ import (
 	"context"
 	"graph-interview/internal/api/handlers/dto"
 	"graph-interview/internal/domain"
+	"time"
 )
 
+// Transactor runs fn in a single database transaction. Repository calls made with
+// the ctx passed to fn take part in it.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 type UserRepo interface {
 	Create(ctx context.Context, user *domain.User) (uint, error)
 	GetByID(ctx context.Context, ID uint) (domain.User, error)
 	GetByField(ctx context.Context, field string, value any) (domain.User, error)
+	GetByVerifiedEmail(ctx context.Context, email string) (domain.User, error)
 	List(ctx context.Context, limit, offset int) ([]domain.User, error)
-	ListByFilter(ctx context.Context, filter dto.UserListFilter, limit, offset int) ([]domain.User, error)
+	ListByFilter(ctx context.Context, filter dto.UserListFilter, limit, offset int) ([]domain.User, int64, error)
 	UpdateByID(ctx context.Context, user *domain.User, fields []string) error
+	DeleteByID(ctx context.Context, ID uint) error
+}
+
+type SessionRepo interface {
+	Create(ctx context.Context, session *domain.UserSession) (uint, error)
+	GetByField(ctx context.Context, field string, value any) (domain.UserSession, error)
+	ListByUser(ctx context.Context, userID uint, onlyValid bool) ([]domain.UserSession, error)
+	UpdateByID(ctx context.Context, session *domain.UserSession, fields []string) error
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+type IdentityRepo interface {
+	Create(ctx context.Context, identity *domain.UserIdentity) (uint, error)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (domain.UserIdentity, error)
+	ListByUser(ctx context.Context, userID uint) ([]domain.UserIdentity, error)
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+type OrgRepo interface {
+	Create(ctx context.Context, org *domain.Organization) (uint, error)
+	// CreatePersonal creates a personal organization unless its owner already has one,
+	// reporting whether it did.
+	CreatePersonal(ctx context.Context, org *domain.Organization) (bool, error)
+	GetByID(ctx context.Context, ID uint) (domain.Organization, error)
+	GetPersonal(ctx context.Context, userID uint) (domain.Organization, error)
+	AddMember(ctx context.Context, membership *domain.Membership) error
+	GetMembership(ctx context.Context, orgID, userID uint) (domain.Membership, error)
+	ListMemberships(ctx context.Context, userID uint) ([]domain.Membership, error)
+	ListMembers(ctx context.Context, orgID uint) ([]domain.Membership, error)
+	UpdateMembership(ctx context.Context, membership *domain.Membership, fields []string) error
+	DeleteMembershipsByUser(ctx context.Context, userID uint) error
+}
+
+type ProjectRepo interface {
+	Create(ctx context.Context, project *domain.Project) (uint, error)
+	GetByID(ctx context.Context, ID uint) (domain.Project, error)
+	ListByFilter(ctx context.Context, filter dto.ProjectListFilter, limit, offset int) ([]domain.Project, int64, error)
+	UpdateByID(ctx context.Context, project *domain.Project, fields []string) error
+	DeleteByID(ctx context.Context, ID uint) error
+	ReassignOwner(ctx context.Context, from, to uint) error
+	DeleteByOwner(ctx context.Context, ownerID uint) error
+	AddMember(ctx context.Context, member *domain.ProjectMember) error
+	GetMember(ctx context.Context, projectID, userID uint) (domain.ProjectMember, error)
+	// ListIDsByMember returns the projects userID owns or is a member of.
+	ListIDsByMember(ctx context.Context, userID uint) ([]uint, error)
+	DeleteMembersByUser(ctx context.Context, userID uint) error
+}
+
+type InvitationRepo interface {
+	Create(ctx context.Context, invitation *domain.Invitation) (uint, error)
+	GetByTokenHash(ctx context.Context, hash string) (domain.Invitation, error)
+	ListPendingByEmail(ctx context.Context, email string) ([]domain.Invitation, error)
+	UpdateByID(ctx context.Context, invitation *domain.Invitation, fields []string) error
+	// DeletePendingByUser removes the unanswered invitations sent by userID or to email.
+	DeletePendingByUser(ctx context.Context, userID uint, email string) error
+}
+
+type ShareRepo interface {
+	Create(ctx context.Context, link *domain.ShareLink) (uint, error)
+	GetByID(ctx context.Context, ID uint) (domain.ShareLink, error)
+	GetByNonce(ctx context.Context, nonce string) (domain.ShareLink, error)
+	ListActive(ctx context.Context, filter dto.ShareListFilter) ([]domain.ShareLink, error)
+	UpdateByID(ctx context.Context, link *domain.ShareLink, fields []string) error
+}
+
+type ReminderRepo interface {
+	Create(ctx context.Context, reminder *domain.Reminder) (uint, error)
+	GetByID(ctx context.Context, ID uint) (domain.Reminder, error)
+	ListByTask(ctx context.Context, taskID, userID uint) ([]domain.Reminder, error)
+	ListByUser(ctx context.Context, userID uint) ([]domain.Reminder, error)
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Reminder, error)
+	UpdateByID(ctx context.Context, reminder *domain.Reminder, fields []string) error
+	DeleteByID(ctx context.Context, ID uint) error
+	DeleteByUser(ctx context.Context, userID uint) error
+}
+
+type NotificationRepo interface {
+	Create(ctx context.Context, notification *domain.Notification) (uint, error)
+	ListByUser(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, ID, userID uint) (bool, error)
+	MarkAllRead(ctx context.Context, userID uint) error
+	DeleteByUser(ctx context.Context, userID uint) error
+	ListPreferences(ctx context.Context, userID uint) ([]domain.NotificationPreference, error)
+	SavePreference(ctx context.Context, pref *domain.NotificationPreference) error
+	// MutedUsers returns those of userIDs who turned kind off.
+	MutedUsers(ctx context.Context, kind string, userIDs []uint) ([]uint, error)
 }
 
 type TaskRepo interface {
@@ -20,6 +115,105 @@ type TaskRepo interface {
 	GetByID(ctx context.Context, ID uint) (domain.Task, error)
 	List(ctx context.Context, limit, offset int) ([]domain.Task, error)
 	ListByFilter(ctx context.Context, filter dto.TaskListFilter, limit, offset int) ([]domain.Task, int64, error)
-	UpdateByID(ctx context.Context, task *domain.Task, fields []string) error
+	// UpdateByID writes fields of task and bumps its version, unless the task was changed
+	// since task.Version was read; it reports false then.
+	UpdateByID(ctx context.Context, task *domain.Task, fields []string) (bool, error)
 	DeleteByID(ctx context.Context, ID uint) error
+	ReassignCreator(ctx context.Context, from uint, to *uint) error
+	ClearUpdater(ctx context.Context, userID uint) error
+	RemoveAssignee(ctx context.Context, userID uint) error
+	DeleteByCreator(ctx context.Context, userID uint) error
+	AssignUser(ctx context.Context, taskID, userID uint) error
+	UnassignUser(ctx context.Context, taskID, userID uint) error
+	ListAssigneeIDs(ctx context.Context, taskID uint) ([]uint, error)
+	Watch(ctx context.Context, taskID, userID uint) error
+	Unwatch(ctx context.Context, taskID, userID uint) error
+	ListWatcherIDs(ctx context.Context, taskID uint) ([]uint, error)
+	// RemoveWatcher stops userID from watching any task.
+	RemoveWatcher(ctx context.Context, userID uint) error
+	ClearProject(ctx context.Context, projectID uint) error
+	// LastPosition returns the highest board position in the column, or "" when no task
+	// there has one.
+	LastPosition(ctx context.Context, column domain.Column) (string, error)
+	// PositionBefore and PositionAfter return the nearest position below or above
+	// position in the column, leaving out the task excludeID; "" when there is none.
+	PositionBefore(ctx context.Context, column domain.Column, position string, excludeID uint) (string, error)
+	PositionAfter(ctx context.Context, column domain.Column, position string, excludeID uint) (string, error)
+	// ListColumnIDs returns the IDs of the tasks in the column in board order.
+	ListColumnIDs(ctx context.Context, column domain.Column) ([]uint, error)
+	// SetPosition moves a task within its column. It bumps the task's version, as the
+	// position is part of the task's representation, but leaves updated_at alone.
+	SetPosition(ctx context.Context, taskID uint, position string) error
+	// ListStatusesInUse returns the distinct statuses the tasks of the project are in, by
+	// workflow name and the built-in status they count as.
+	ListStatusesInUse(ctx context.Context, projectID uint) ([]domain.WorkflowStatus, error)
+	// DropCustomField removes the value of the custom field key from the project's tasks.
+	DropCustomField(ctx context.Context, projectID uint, key string) error
+}
+
+type WebhookRepo interface {
+	Create(ctx context.Context, webhook *domain.Webhook) (uint, error)
+	GetByID(ctx context.Context, ID uint) (domain.Webhook, error)
+	List(ctx context.Context) ([]domain.Webhook, error)
+	UpdateByID(ctx context.Context, webhook *domain.Webhook, fields []string) error
+	// DeleteByID removes the webhook along with its deliveries.
+	DeleteByID(ctx context.Context, ID uint) error
+	CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (uint, error)
+	ListDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]domain.WebhookDelivery, int64, error)
+	// ListDueDeliveries returns pending deliveries whose next attempt is due at now, with
+	// their webhook loaded.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery, fields []string) error
+	ReassignCreator(ctx context.Context, from, to uint) error
+	// DeleteByCreator removes the webhooks userID created along with their deliveries.
+	DeleteByCreator(ctx context.Context, userID uint) error
+}
+
+type ViewRepo interface {
+	Create(ctx context.Context, view *domain.View) (uint, error)
+	GetByID(ctx context.Context, ID uint) (domain.View, error)
+	// ListVisible returns the views userID owns and the shared ones, ordered by name.
+	ListVisible(ctx context.Context, userID uint) ([]domain.View, error)
+	UpdateByID(ctx context.Context, view *domain.View, fields []string) error
+	// DeleteByID removes the view along with its pins.
+	DeleteByID(ctx context.Context, ID uint) error
+	Pin(ctx context.Context, userID uint, view string) error
+	Unpin(ctx context.Context, userID uint, view string) error
+	// ListPins returns the views userID pinned, in the order they were pinned.
+	ListPins(ctx context.Context, userID uint) ([]string, error)
+	ListByOwner(ctx context.Context, userID uint) ([]domain.View, error)
+	// ReassignShared hands the shared views of from over to to.
+	ReassignShared(ctx context.Context, from, to uint) error
+	// DeleteByOwner removes the views userID owns along with their pins, and the pins
+	// userID made.
+	DeleteByOwner(ctx context.Context, userID uint) error
+}
+
+type CustomFieldRepo interface {
+	Create(ctx context.Context, field *domain.CustomField) (uint, error)
+	GetByID(ctx context.Context, ID uint) (domain.CustomField, error)
+	// ListByProject returns the project's custom fields in the order they were created.
+	ListByProject(ctx context.Context, projectID uint) ([]domain.CustomField, error)
+	UpdateByID(ctx context.Context, field *domain.CustomField, fields []string) error
+	DeleteByID(ctx context.Context, ID uint) error
+	DeleteByProject(ctx context.Context, projectID uint) error
+}
+
+type WorkflowRepo interface {
+	// GetByProject returns the project's workflow, or gorm.ErrRecordNotFound when it uses
+	// the default one.
+	GetByProject(ctx context.Context, projectID uint) (domain.Workflow, error)
+	// Save creates the project's workflow or replaces its statuses and transitions.
+	Save(ctx context.Context, workflow *domain.Workflow) error
+	DeleteByProject(ctx context.Context, projectID uint) error
+}
+
+type OutboxRepo interface {
+	Create(ctx context.Context, event *domain.Event) (uint, error)
+	// ListPending returns unpublished events due at now, oldest first, across organizations.
+	// Events behind an older pending event about the same aggregate wait for it.
+	ListPending(ctx context.Context, now time.Time, limit int) ([]domain.Event, error)
+	UpdateByID(ctx context.Context, event *domain.Event, fields []string) error
+	// DeletePublished removes events published before the given time.
+	DeletePublished(ctx context.Context, before time.Time) error
 }
@@ -4,10 +4,18 @@ import (
 	"context"
 	"graph-interview/internal/api/handlers/dto"
 	"graph-interview/internal/domain"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 )
 
+// NoopTransactor runs fn directly, without opening a transaction
+type NoopTransactor struct{}
+
+func (NoopTransactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
 // MockUserRepo is a mock of UserRepo interface
 type MockUserRepo struct {
 	mock.Mock
@@ -28,14 +36,19 @@ func (m *MockUserRepo) GetByField(ctx context.Context, field string, value any)
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
+func (m *MockUserRepo) GetByVerifiedEmail(ctx context.Context, email string) (domain.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
 func (m *MockUserRepo) List(ctx context.Context, limit, offset int) ([]domain.User, error) {
 	args := m.Called(ctx, limit, offset)
 	return args.Get(0).([]domain.User), args.Error(1)
 }
 
-func (m *MockUserRepo) ListByFilter(ctx context.Context, filter dto.UserListFilter, limit, offset int) ([]domain.User, error) {
+func (m *MockUserRepo) ListByFilter(ctx context.Context, filter dto.UserListFilter, limit, offset int) ([]domain.User, int64, error) {
 	args := m.Called(ctx, filter, limit, offset)
-	return args.Get(0).([]domain.User), args.Error(1)
+	return args.Get(0).([]domain.User), args.Get(1).(int64), args.Error(2)
 }
 
 func (m *MockUserRepo) UpdateByID(ctx context.Context, user *domain.User, fields []string) error {
@@ -43,6 +56,336 @@ func (m *MockUserRepo) UpdateByID(ctx context.Context, user *domain.User, fields
 	return args.Error(0)
 }
 
+func (m *MockUserRepo) DeleteByID(ctx context.Context, ID uint) error {
+	args := m.Called(ctx, ID)
+	return args.Error(0)
+}
+
+// MockSessionRepo is a mock of SessionRepo interface
+type MockSessionRepo struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepo) Create(ctx context.Context, session *domain.UserSession) (uint, error) {
+	args := m.Called(ctx, session)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockSessionRepo) GetByField(ctx context.Context, field string, value any) (domain.UserSession, error) {
+	args := m.Called(ctx, field, value)
+	return args.Get(0).(domain.UserSession), args.Error(1)
+}
+
+func (m *MockSessionRepo) ListByUser(ctx context.Context, userID uint, onlyValid bool) ([]domain.UserSession, error) {
+	args := m.Called(ctx, userID, onlyValid)
+	return args.Get(0).([]domain.UserSession), args.Error(1)
+}
+
+func (m *MockSessionRepo) UpdateByID(ctx context.Context, session *domain.UserSession, fields []string) error {
+	args := m.Called(ctx, session, fields)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockIdentityRepo is a mock of IdentityRepo interface
+type MockIdentityRepo struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepo) Create(ctx context.Context, identity *domain.UserIdentity) (uint, error) {
+	args := m.Called(ctx, identity)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockIdentityRepo) GetByProviderSubject(ctx context.Context, provider, subject string) (domain.UserIdentity, error) {
+	args := m.Called(ctx, provider, subject)
+	return args.Get(0).(domain.UserIdentity), args.Error(1)
+}
+
+func (m *MockIdentityRepo) ListByUser(ctx context.Context, userID uint) ([]domain.UserIdentity, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.UserIdentity), args.Error(1)
+}
+
+func (m *MockIdentityRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockOrgRepo is a mock of OrgRepo interface
+type MockOrgRepo struct {
+	mock.Mock
+}
+
+func (m *MockOrgRepo) Create(ctx context.Context, org *domain.Organization) (uint, error) {
+	args := m.Called(ctx, org)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockOrgRepo) CreatePersonal(ctx context.Context, org *domain.Organization) (bool, error) {
+	args := m.Called(ctx, org)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrgRepo) GetByID(ctx context.Context, ID uint) (domain.Organization, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.Organization), args.Error(1)
+}
+
+func (m *MockOrgRepo) GetPersonal(ctx context.Context, userID uint) (domain.Organization, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(domain.Organization), args.Error(1)
+}
+
+func (m *MockOrgRepo) AddMember(ctx context.Context, membership *domain.Membership) error {
+	args := m.Called(ctx, membership)
+	return args.Error(0)
+}
+
+func (m *MockOrgRepo) GetMembership(ctx context.Context, orgID, userID uint) (domain.Membership, error) {
+	args := m.Called(ctx, orgID, userID)
+	return args.Get(0).(domain.Membership), args.Error(1)
+}
+
+func (m *MockOrgRepo) ListMemberships(ctx context.Context, userID uint) ([]domain.Membership, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Membership), args.Error(1)
+}
+
+func (m *MockOrgRepo) ListMembers(ctx context.Context, orgID uint) ([]domain.Membership, error) {
+	args := m.Called(ctx, orgID)
+	return args.Get(0).([]domain.Membership), args.Error(1)
+}
+
+func (m *MockOrgRepo) UpdateMembership(ctx context.Context, membership *domain.Membership, fields []string) error {
+	args := m.Called(ctx, membership, fields)
+	return args.Error(0)
+}
+
+func (m *MockOrgRepo) DeleteMembershipsByUser(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockProjectRepo is a mock of ProjectRepo interface
+type MockProjectRepo struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepo) Create(ctx context.Context, project *domain.Project) (uint, error) {
+	args := m.Called(ctx, project)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockProjectRepo) GetByID(ctx context.Context, ID uint) (domain.Project, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.Project), args.Error(1)
+}
+
+func (m *MockProjectRepo) ListByFilter(ctx context.Context, filter dto.ProjectListFilter, limit, offset int) ([]domain.Project, int64, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]domain.Project), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProjectRepo) UpdateByID(ctx context.Context, project *domain.Project, fields []string) error {
+	args := m.Called(ctx, project, fields)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) DeleteByID(ctx context.Context, ID uint) error {
+	args := m.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) ReassignOwner(ctx context.Context, from, to uint) error {
+	args := m.Called(ctx, from, to)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) DeleteByOwner(ctx context.Context, ownerID uint) error {
+	args := m.Called(ctx, ownerID)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) AddMember(ctx context.Context, member *domain.ProjectMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) GetMember(ctx context.Context, projectID, userID uint) (domain.ProjectMember, error) {
+	args := m.Called(ctx, projectID, userID)
+	return args.Get(0).(domain.ProjectMember), args.Error(1)
+}
+
+func (m *MockProjectRepo) ListIDsByMember(ctx context.Context, userID uint) ([]uint, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockProjectRepo) DeleteMembersByUser(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockInvitationRepo is a mock of InvitationRepo interface
+type MockInvitationRepo struct {
+	mock.Mock
+}
+
+func (m *MockInvitationRepo) Create(ctx context.Context, invitation *domain.Invitation) (uint, error) {
+	args := m.Called(ctx, invitation)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockInvitationRepo) GetByTokenHash(ctx context.Context, hash string) (domain.Invitation, error) {
+	args := m.Called(ctx, hash)
+	return args.Get(0).(domain.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepo) ListPendingByEmail(ctx context.Context, email string) ([]domain.Invitation, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]domain.Invitation), args.Error(1)
+}
+
+func (m *MockInvitationRepo) UpdateByID(ctx context.Context, invitation *domain.Invitation, fields []string) error {
+	args := m.Called(ctx, invitation, fields)
+	return args.Error(0)
+}
+
+func (m *MockInvitationRepo) DeletePendingByUser(ctx context.Context, userID uint, email string) error {
+	args := m.Called(ctx, userID, email)
+	return args.Error(0)
+}
+
+// MockShareRepo is a mock of ShareRepo interface
+type MockShareRepo struct {
+	mock.Mock
+}
+
+func (m *MockShareRepo) Create(ctx context.Context, link *domain.ShareLink) (uint, error) {
+	args := m.Called(ctx, link)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockShareRepo) GetByID(ctx context.Context, ID uint) (domain.ShareLink, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.ShareLink), args.Error(1)
+}
+
+func (m *MockShareRepo) GetByNonce(ctx context.Context, nonce string) (domain.ShareLink, error) {
+	args := m.Called(ctx, nonce)
+	return args.Get(0).(domain.ShareLink), args.Error(1)
+}
+
+func (m *MockShareRepo) ListActive(ctx context.Context, filter dto.ShareListFilter) ([]domain.ShareLink, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]domain.ShareLink), args.Error(1)
+}
+
+func (m *MockShareRepo) UpdateByID(ctx context.Context, link *domain.ShareLink, fields []string) error {
+	args := m.Called(ctx, link, fields)
+	return args.Error(0)
+}
+
+// MockReminderRepo is a mock of ReminderRepo interface
+type MockReminderRepo struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepo) Create(ctx context.Context, reminder *domain.Reminder) (uint, error) {
+	args := m.Called(ctx, reminder)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockReminderRepo) GetByID(ctx context.Context, ID uint) (domain.Reminder, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepo) ListByTask(ctx context.Context, taskID, userID uint) ([]domain.Reminder, error) {
+	args := m.Called(ctx, taskID, userID)
+	return args.Get(0).([]domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepo) ListByUser(ctx context.Context, userID uint) ([]domain.Reminder, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Reminder, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.Reminder), args.Error(1)
+}
+
+func (m *MockReminderRepo) UpdateByID(ctx context.Context, reminder *domain.Reminder, fields []string) error {
+	args := m.Called(ctx, reminder, fields)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepo) DeleteByID(ctx context.Context, ID uint) error {
+	args := m.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (m *MockReminderRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockNotificationRepo is a mock of NotificationRepo interface
+type MockNotificationRepo struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepo) Create(ctx context.Context, notification *domain.Notification) (uint, error) {
+	args := m.Called(ctx, notification)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockNotificationRepo) ListByUser(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit, offset)
+	return args.Get(0).([]domain.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepo) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, ID, userID uint) (bool, error) {
+	args := m.Called(ctx, ID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkAllRead(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) DeleteByUser(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) ListPreferences(ctx context.Context, userID uint) ([]domain.NotificationPreference, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationRepo) SavePreference(ctx context.Context, pref *domain.NotificationPreference) error {
+	args := m.Called(ctx, pref)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) MutedUsers(ctx context.Context, kind string, userIDs []uint) ([]uint, error) {
+	args := m.Called(ctx, kind, userIDs)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
 // MockTaskRepo is a mock of TaskRepo interface
 type MockTaskRepo struct {
 	mock.Mock
@@ -68,12 +411,307 @@ func (m *MockTaskRepo) ListByFilter(ctx context.Context, filter dto.TaskListFilt
 	return args.Get(0).([]domain.Task), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTaskRepo) UpdateByID(ctx context.Context, task *domain.Task, fields []string) error {
+func (m *MockTaskRepo) UpdateByID(ctx context.Context, task *domain.Task, fields []string) (bool, error) {
 	args := m.Called(ctx, task, fields)
-	return args.Error(0)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *MockTaskRepo) DeleteByID(ctx context.Context, ID uint) error {
 	args := m.Called(ctx, ID)
 	return args.Error(0)
 }
+
+func (m *MockTaskRepo) AssignUser(ctx context.Context, taskID, userID uint) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UnassignUser(ctx context.Context, taskID, userID uint) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) ListAssigneeIDs(ctx context.Context, taskID uint) ([]uint, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTaskRepo) Watch(ctx context.Context, taskID, userID uint) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) Unwatch(ctx context.Context, taskID, userID uint) error {
+	args := m.Called(ctx, taskID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) ListWatcherIDs(ctx context.Context, taskID uint) ([]uint, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTaskRepo) RemoveWatcher(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) ReassignCreator(ctx context.Context, from uint, to *uint) error {
+	args := m.Called(ctx, from, to)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) ClearUpdater(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) RemoveAssignee(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) DeleteByCreator(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) ClearProject(ctx context.Context, projectID uint) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) LastPosition(ctx context.Context, column domain.Column) (string, error) {
+	args := m.Called(ctx, column)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) PositionBefore(ctx context.Context, column domain.Column, position string, excludeID uint) (string, error) {
+	args := m.Called(ctx, column, position, excludeID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) PositionAfter(ctx context.Context, column domain.Column, position string, excludeID uint) (string, error) {
+	args := m.Called(ctx, column, position, excludeID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) ListColumnIDs(ctx context.Context, column domain.Column) ([]uint, error) {
+	args := m.Called(ctx, column)
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *MockTaskRepo) ListStatusesInUse(ctx context.Context, projectID uint) ([]domain.WorkflowStatus, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]domain.WorkflowStatus), args.Error(1)
+}
+
+func (m *MockTaskRepo) DropCustomField(ctx context.Context, projectID uint, key string) error {
+	args := m.Called(ctx, projectID, key)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) SetPosition(ctx context.Context, taskID uint, position string) error {
+	args := m.Called(ctx, taskID, position)
+	return args.Error(0)
+}
+
+// MockWebhookRepo is a mock of WebhookRepo interface
+type MockWebhookRepo struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepo) Create(ctx context.Context, webhook *domain.Webhook) (uint, error) {
+	args := m.Called(ctx, webhook)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockWebhookRepo) GetByID(ctx context.Context, ID uint) (domain.Webhook, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) List(ctx context.Context) ([]domain.Webhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepo) UpdateByID(ctx context.Context, webhook *domain.Webhook, fields []string) error {
+	args := m.Called(ctx, webhook, fields)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) DeleteByID(ctx context.Context, ID uint) error {
+	args := m.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (uint, error) {
+	args := m.Called(ctx, delivery)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockWebhookRepo) ListDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]domain.WebhookDelivery, int64, error) {
+	args := m.Called(ctx, webhookID, limit, offset)
+	return args.Get(0).([]domain.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWebhookRepo) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepo) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery, fields []string) error {
+	args := m.Called(ctx, delivery, fields)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) ReassignCreator(ctx context.Context, from, to uint) error {
+	args := m.Called(ctx, from, to)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepo) DeleteByCreator(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockOutboxRepo is a mock of OutboxRepo interface
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepo) Create(ctx context.Context, event *domain.Event) (uint, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockOutboxRepo) ListPending(ctx context.Context, now time.Time, limit int) ([]domain.Event, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockOutboxRepo) UpdateByID(ctx context.Context, event *domain.Event, fields []string) error {
+	args := m.Called(ctx, event, fields)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepo) DeletePublished(ctx context.Context, before time.Time) error {
+	args := m.Called(ctx, before)
+	return args.Error(0)
+}
+
+// MockViewRepo is a mock of ViewRepo interface
+type MockViewRepo struct {
+	mock.Mock
+}
+
+func (m *MockViewRepo) Create(ctx context.Context, view *domain.View) (uint, error) {
+	args := m.Called(ctx, view)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockViewRepo) GetByID(ctx context.Context, ID uint) (domain.View, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.View), args.Error(1)
+}
+
+func (m *MockViewRepo) ListVisible(ctx context.Context, userID uint) ([]domain.View, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.View), args.Error(1)
+}
+
+func (m *MockViewRepo) UpdateByID(ctx context.Context, view *domain.View, fields []string) error {
+	args := m.Called(ctx, view, fields)
+	return args.Error(0)
+}
+
+func (m *MockViewRepo) DeleteByID(ctx context.Context, ID uint) error {
+	args := m.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (m *MockViewRepo) Pin(ctx context.Context, userID uint, view string) error {
+	args := m.Called(ctx, userID, view)
+	return args.Error(0)
+}
+
+func (m *MockViewRepo) Unpin(ctx context.Context, userID uint, view string) error {
+	args := m.Called(ctx, userID, view)
+	return args.Error(0)
+}
+
+func (m *MockViewRepo) ListPins(ctx context.Context, userID uint) ([]string, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockViewRepo) ListByOwner(ctx context.Context, userID uint) ([]domain.View, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]domain.View), args.Error(1)
+}
+
+func (m *MockViewRepo) ReassignShared(ctx context.Context, from, to uint) error {
+	args := m.Called(ctx, from, to)
+	return args.Error(0)
+}
+
+func (m *MockViewRepo) DeleteByOwner(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// MockWorkflowRepo is a mock of WorkflowRepo interface
+type MockWorkflowRepo struct {
+	mock.Mock
+}
+
+func (m *MockWorkflowRepo) GetByProject(ctx context.Context, projectID uint) (domain.Workflow, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).(domain.Workflow), args.Error(1)
+}
+
+func (m *MockWorkflowRepo) Save(ctx context.Context, workflow *domain.Workflow) error {
+	args := m.Called(ctx, workflow)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRepo) DeleteByProject(ctx context.Context, projectID uint) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+// MockCustomFieldRepo is a mock of CustomFieldRepo interface
+type MockCustomFieldRepo struct {
+	mock.Mock
+}
+
+func (m *MockCustomFieldRepo) Create(ctx context.Context, field *domain.CustomField) (uint, error) {
+	args := m.Called(ctx, field)
+	return args.Get(0).(uint), args.Error(1)
+}
+
+func (m *MockCustomFieldRepo) GetByID(ctx context.Context, ID uint) (domain.CustomField, error) {
+	args := m.Called(ctx, ID)
+	return args.Get(0).(domain.CustomField), args.Error(1)
+}
+
+func (m *MockCustomFieldRepo) ListByProject(ctx context.Context, projectID uint) ([]domain.CustomField, error) {
+	args := m.Called(ctx, projectID)
+	return args.Get(0).([]domain.CustomField), args.Error(1)
+}
+
+func (m *MockCustomFieldRepo) UpdateByID(ctx context.Context, field *domain.CustomField, fields []string) error {
+	args := m.Called(ctx, field, fields)
+	return args.Error(0)
+}
+
+func (m *MockCustomFieldRepo) DeleteByID(ctx context.Context, ID uint) error {
+	args := m.Called(ctx, ID)
+	return args.Error(0)
+}
+
+func (m *MockCustomFieldRepo) DeleteByProject(ctx context.Context, projectID uint) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
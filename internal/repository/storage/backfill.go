@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// Projects and tasks from before organizations existed have organization_id 0 and so are
+// hidden by the tenant scope. backfillOrganizations moves them into their owners' personal
+// organizations, creating those first where needed. The statements only touch such rows,
+// so running them again is a no-op.
+var (
+	personalOrgStatements = []string{
+		// Personal organizations created before they recorded their owner take the user
+		// they were made for, their first member; a user with several keeps the oldest.
+		`UPDATE organizations o SET personal_owner_id = f.user_id
+		FROM (
+			SELECT DISTINCT ON (fm.user_id) fm.user_id, fm.organization_id
+			FROM (
+				SELECT DISTINCT ON (m.organization_id) m.organization_id, m.user_id
+				FROM memberships m JOIN organizations po ON po.id = m.organization_id
+				WHERE po.personal AND po.personal_owner_id IS NULL AND m.role = @owner
+				ORDER BY m.organization_id, m.id
+			) fm
+			ORDER BY fm.user_id, fm.organization_id
+		) f
+		WHERE o.id = f.organization_id
+			AND NOT EXISTS (SELECT 1 FROM organizations x WHERE x.personal_owner_id = f.user_id)`,
+		// Users with rows to move get a personal organization, and ownership of it.
+		`INSERT INTO organizations (created_at, updated_at, name, personal, personal_owner_id)
+		SELECT now(), now(), u.username, true, u.id FROM users u
+		WHERE NOT EXISTS (SELECT 1 FROM organizations o WHERE o.personal_owner_id = u.id)
+			AND (EXISTS (SELECT 1 FROM projects p WHERE p.organization_id = 0 AND p.owner_id = u.id)
+				OR EXISTS (SELECT 1 FROM tasks t WHERE t.organization_id = 0 AND t.created_by_user_id = u.id))
+		ON CONFLICT (personal_owner_id) DO NOTHING`,
+		`INSERT INTO memberships (created_at, updated_at, organization_id, user_id, role)
+		SELECT now(), now(), o.id, o.personal_owner_id, @owner FROM organizations o
+		WHERE o.personal_owner_id IS NOT NULL
+			AND NOT EXISTS (SELECT 1 FROM memberships m WHERE m.organization_id = o.id AND m.user_id = o.personal_owner_id)
+		ON CONFLICT DO NOTHING`,
+	}
+	moveStatements = []string{
+		// Projects go to their owner's organization, tasks to their project's or, without
+		// one, to their creator's.
+		`UPDATE projects p SET organization_id = o.id
+		FROM organizations o
+		WHERE p.organization_id = 0 AND o.personal_owner_id = p.owner_id`,
+		`UPDATE tasks t SET organization_id = p.organization_id
+		FROM projects p
+		WHERE t.organization_id = 0 AND t.project_id = p.id AND p.organization_id <> 0`,
+		`UPDATE tasks t SET organization_id = o.id
+		FROM organizations o
+		WHERE t.organization_id = 0 AND o.personal_owner_id = t.created_by_user_id`,
+	}
+)
+
+// backfillOrganizations runs the backfill in one transaction.
+func (p *DB) backfillOrganizations() error {
+	ctx := tenant.Unscoped(context.Background())
+	args := map[string]any{"owner": int(enum.MemberOwner)}
+	return p.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range personalOrgStatements {
+			if err := tx.Exec(stmt, args).Error; err != nil {
+				return err
+			}
+		}
+		var moved int64
+		for _, stmt := range moveStatements {
+			res := tx.Exec(stmt)
+			if res.Error != nil {
+				return res.Error
+			}
+			moved += res.RowsAffected
+		}
+		if moved > 0 {
+			logger.Logger.Info("moved rows into personal organizations", "rows", moved)
+		}
+		return nil
+	})
+}
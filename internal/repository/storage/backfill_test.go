@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillStatements_BindOwnerRole(t *testing.T) {
+	db := dryRunDB(t).WithContext(tenant.Unscoped(context.Background()))
+
+	for _, stmt := range personalOrgStatements {
+		res := db.Exec(stmt, map[string]any{"owner": int(enum.MemberOwner)})
+		require.NoError(t, res.Error)
+		assert.NotContains(t, res.Statement.SQL.String(), "@owner")
+	}
+	for _, stmt := range moveStatements {
+		assert.Contains(t, stmt, "organization_id = 0", "only rows from before organizations are moved")
+	}
+}
@@ -1,9 +1,11 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"graph-interview/internal/cfg"
 	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/tenant"
 	"graph-interview/pkg/logger"
 	"time"
 
@@ -12,6 +14,31 @@ import (
 	gormLogger "gorm.io/gorm/logger"
 )
 
+// models are the tables migrated on startup.
+var models = []any{
+	&domain.User{},
+	&domain.UserSession{},
+	&domain.UserIdentity{},
+	&domain.Organization{},
+	&domain.Membership{},
+	&domain.Project{},
+	&domain.ProjectMember{},
+	&domain.Invitation{},
+	&domain.Task{},
+	&domain.TaskWatcher{},
+	&domain.ShareLink{},
+	&domain.Reminder{},
+	&domain.Notification{},
+	&domain.NotificationPreference{},
+	&domain.Webhook{},
+	&domain.WebhookDelivery{},
+	&domain.View{},
+	&domain.ViewPin{},
+	&domain.Workflow{},
+	&domain.CustomField{},
+	&domain.Event{},
+}
+
 type DB struct {
 	DB *gorm.DB
 }
@@ -29,6 +56,9 @@ func NewDB(cfg *cfg.DatabaseConfig) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database :%w", err)
 	}
+	if err := registerTenantScope(gormDb); err != nil {
+		return nil, fmt.Errorf("failed to register tenant scope: %w", err)
+	}
 	// Set the pool size
 	sqlDB, err := gormDb.DB()
 	if err != nil {
@@ -58,13 +88,14 @@ func NewDB(cfg *cfg.DatabaseConfig) (*DB, error) {
 }
 
 func (p *DB) migration() error {
-	err := p.DB.AutoMigrate(
-		&domain.User{},
-		&domain.UserSession{},
-		&domain.Task{},
-	)
-	if err == nil {
-		logger.Logger.Info("database migration successfully done")
+	err := p.DB.WithContext(tenant.Unscoped(context.Background())).AutoMigrate(models...)
+	if err != nil {
+		return err
+	}
+	// Before anything queries through the tenant scope.
+	if err := p.backfillOrganizations(); err != nil {
+		return fmt.Errorf("organization backfill failed: %w", err)
 	}
-	return err
+	logger.Logger.Info("database migration successfully done")
+	return nil
 }
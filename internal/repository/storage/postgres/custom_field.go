@@ -0,0 +1,54 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+
+	"gorm.io/gorm"
+)
+
+type customFieldImp struct {
+	db *gorm.DB
+}
+
+func NewCustomFieldRepo(db *storage.DB) *customFieldImp {
+	return &customFieldImp{
+		db: db.DB,
+	}
+}
+
+func (i *customFieldImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *customFieldImp) Create(ctx context.Context, field *domain.CustomField) (uint, error) {
+	err := gorm.G[domain.CustomField](i.conn(ctx)).Create(ctx, field)
+	if err != nil {
+		return 0, err
+	}
+	return field.ID, nil
+}
+
+func (i *customFieldImp) GetByID(ctx context.Context, ID uint) (domain.CustomField, error) {
+	return gorm.G[domain.CustomField](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *customFieldImp) ListByProject(ctx context.Context, projectID uint) ([]domain.CustomField, error) {
+	return gorm.G[domain.CustomField](i.conn(ctx)).Where("project_id = ?", projectID).Order("id").Find(ctx)
+}
+
+func (i *customFieldImp) UpdateByID(ctx context.Context, field *domain.CustomField, fields []string) error {
+	_, err := gorm.G[domain.CustomField](i.conn(ctx)).Where("id = ?", field.ID).Select(fields[0], fields[1:]).Updates(ctx, *field)
+	return err
+}
+
+// DeleteByID removes the field for good, so its key can be used again.
+func (i *customFieldImp) DeleteByID(ctx context.Context, ID uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Where("id = ?", ID).Delete(&domain.CustomField{}).Error
+}
+
+// DeleteByProject removes all of the project's fields for good.
+func (i *customFieldImp) DeleteByProject(ctx context.Context, projectID uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Where("project_id = ?", projectID).Delete(&domain.CustomField{}).Error
+}
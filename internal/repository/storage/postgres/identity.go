@@ -0,0 +1,44 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+
+	"gorm.io/gorm"
+)
+
+type identityImp struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepo(db *storage.DB) *identityImp {
+	return &identityImp{
+		db: db.DB,
+	}
+}
+
+func (i *identityImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *identityImp) Create(ctx context.Context, identity *domain.UserIdentity) (uint, error) {
+	err := gorm.G[domain.UserIdentity](i.conn(ctx)).Create(ctx, identity)
+	if err != nil {
+		return 0, err
+	}
+	return identity.ID, nil
+}
+
+func (i *identityImp) GetByProviderSubject(ctx context.Context, provider, subject string) (domain.UserIdentity, error) {
+	return gorm.G[domain.UserIdentity](i.conn(ctx)).Where("provider = ? AND subject = ?", provider, subject).Take(ctx)
+}
+
+func (i *identityImp) ListByUser(ctx context.Context, userID uint) ([]domain.UserIdentity, error) {
+	return gorm.G[domain.UserIdentity](i.conn(ctx)).Where("user_id = ?", userID).Find(ctx)
+}
+
+func (i *identityImp) DeleteByUser(ctx context.Context, userID uint) error {
+	_, err := gorm.G[domain.UserIdentity](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx)
+	return err
+}
@@ -0,0 +1,55 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type invitationImp struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepo(db *storage.DB) *invitationImp {
+	return &invitationImp{
+		db: db.DB,
+	}
+}
+
+func (i *invitationImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *invitationImp) Create(ctx context.Context, invitation *domain.Invitation) (uint, error) {
+	err := gorm.G[domain.Invitation](i.conn(ctx)).Create(ctx, invitation)
+	if err != nil {
+		return 0, err
+	}
+	return invitation.ID, nil
+}
+
+func (i *invitationImp) GetByTokenHash(ctx context.Context, hash string) (domain.Invitation, error) {
+	return gorm.G[domain.Invitation](i.conn(ctx)).Where("token_hash = ?", hash).Take(ctx)
+}
+
+// ListPendingByEmail returns the unanswered, unexpired invitations sent to email.
+func (i *invitationImp) ListPendingByEmail(ctx context.Context, email string) ([]domain.Invitation, error) {
+	return gorm.G[domain.Invitation](i.conn(ctx)).Preload("Organization", nil).
+		Where("email = ? AND accepted_at IS NULL AND declined_at IS NULL AND expires_at > ?", email, time.Now()).
+		Order("id").Find(ctx)
+}
+
+func (i *invitationImp) UpdateByID(ctx context.Context, invitation *domain.Invitation, fields []string) error {
+	_, err := gorm.G[domain.Invitation](i.conn(ctx)).Where("id = ?", invitation.ID).Select(fields[0], fields[1:]).Updates(ctx, *invitation)
+	return err
+}
+
+func (i *invitationImp) DeletePendingByUser(ctx context.Context, userID uint, email string) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().
+		Where("accepted_at IS NULL AND declined_at IS NULL").
+		Where("invited_by_id = ? OR email = ?", userID, email).
+		Delete(&domain.Invitation{}).Error
+}
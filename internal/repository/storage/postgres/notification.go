@@ -0,0 +1,96 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationImp struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepo(db *storage.DB) *notificationImp {
+	return &notificationImp{
+		db: db.DB,
+	}
+}
+
+func (i *notificationImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *notificationImp) Create(ctx context.Context, notification *domain.Notification) (uint, error) {
+	err := gorm.G[domain.Notification](i.conn(ctx)).Create(ctx, notification)
+	if err != nil {
+		return 0, err
+	}
+	return notification.ID, nil
+}
+
+// ListByUser returns the user's notifications, newest first, and how many there are in total.
+func (i *notificationImp) ListByUser(ctx context.Context, userID uint, unreadOnly bool, limit, offset int) ([]domain.Notification, int64, error) {
+	q := i.conn(ctx).WithContext(ctx).Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var notifications []domain.Notification
+	if err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (i *notificationImp) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	return gorm.G[domain.Notification](i.conn(ctx)).Where("user_id = ? AND read_at IS NULL", userID).Count(ctx, "id")
+}
+
+// MarkRead marks one of the user's notifications read and reports whether it exists.
+func (i *notificationImp) MarkRead(ctx context.Context, ID, userID uint) (bool, error) {
+	res := i.conn(ctx).WithContext(ctx).Model(&domain.Notification{}).
+		Where("id = ? AND user_id = ?", ID, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	return res.RowsAffected > 0, res.Error
+}
+
+func (i *notificationImp) MarkAllRead(ctx context.Context, userID uint) error {
+	return i.conn(ctx).WithContext(ctx).Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+func (i *notificationImp) DeleteByUser(ctx context.Context, userID uint) error {
+	if _, err := gorm.G[domain.Notification](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx); err != nil {
+		return err
+	}
+	_, err := gorm.G[domain.NotificationPreference](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx)
+	return err
+}
+
+func (i *notificationImp) ListPreferences(ctx context.Context, userID uint) ([]domain.NotificationPreference, error) {
+	return gorm.G[domain.NotificationPreference](i.conn(ctx)).Where("user_id = ?", userID).Find(ctx)
+}
+
+func (i *notificationImp) SavePreference(ctx context.Context, pref *domain.NotificationPreference) error {
+	return i.conn(ctx).WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "kind"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(pref).Error
+}
+
+func (i *notificationImp) MutedUsers(ctx context.Context, kind string, userIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := i.conn(ctx).WithContext(ctx).Model(&domain.NotificationPreference{}).
+		Where("kind = ? AND user_id IN ? AND NOT enabled", kind, userIDs).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
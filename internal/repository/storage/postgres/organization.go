@@ -0,0 +1,75 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type orgImp struct {
+	db *gorm.DB
+}
+
+func NewOrgRepo(db *storage.DB) *orgImp {
+	return &orgImp{
+		db: db.DB,
+	}
+}
+
+func (i *orgImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *orgImp) Create(ctx context.Context, org *domain.Organization) (uint, error) {
+	err := gorm.G[domain.Organization](i.conn(ctx)).Create(ctx, org)
+	if err != nil {
+		return 0, err
+	}
+	return org.ID, nil
+}
+
+func (i *orgImp) CreatePersonal(ctx context.Context, org *domain.Organization) (bool, error) {
+	res := i.conn(ctx).WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "personal_owner_id"}},
+		DoNothing: true,
+	}).Create(org)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (i *orgImp) GetByID(ctx context.Context, ID uint) (domain.Organization, error) {
+	return gorm.G[domain.Organization](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *orgImp) GetPersonal(ctx context.Context, userID uint) (domain.Organization, error) {
+	return gorm.G[domain.Organization](i.conn(ctx)).Where("personal_owner_id = ?", userID).Take(ctx)
+}
+
+func (i *orgImp) AddMember(ctx context.Context, membership *domain.Membership) error {
+	return gorm.G[domain.Membership](i.conn(ctx)).Create(ctx, membership)
+}
+
+func (i *orgImp) GetMembership(ctx context.Context, orgID, userID uint) (domain.Membership, error) {
+	return gorm.G[domain.Membership](i.conn(ctx)).Where("organization_id = ? AND user_id = ?", orgID, userID).Take(ctx)
+}
+
+// ListMemberships returns the organizations userID belongs to, oldest membership first.
+func (i *orgImp) ListMemberships(ctx context.Context, userID uint) ([]domain.Membership, error) {
+	return gorm.G[domain.Membership](i.conn(ctx)).Preload("Organization", nil).Where("user_id = ?", userID).Order("id").Find(ctx)
+}
+
+func (i *orgImp) ListMembers(ctx context.Context, orgID uint) ([]domain.Membership, error) {
+	return gorm.G[domain.Membership](i.conn(ctx)).Preload("User", nil).Where("organization_id = ?", orgID).Order("id").Find(ctx)
+}
+
+func (i *orgImp) UpdateMembership(ctx context.Context, membership *domain.Membership, fields []string) error {
+	_, err := gorm.G[domain.Membership](i.conn(ctx)).Where("id = ?", membership.ID).Select(fields[0], fields[1:]).Updates(ctx, *membership)
+	return err
+}
+
+func (i *orgImp) DeleteMembershipsByUser(ctx context.Context, userID uint) error {
+	_, err := gorm.G[domain.Membership](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx)
+	return err
+}
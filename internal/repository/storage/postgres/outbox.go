@@ -0,0 +1,56 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type outboxImp struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepo(db *storage.DB) *outboxImp {
+	return &outboxImp{
+		db: db.DB,
+	}
+}
+
+func (i *outboxImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *outboxImp) Create(ctx context.Context, event *domain.Event) (uint, error) {
+	err := gorm.G[domain.Event](i.conn(ctx)).Create(ctx, event)
+	if err != nil {
+		return 0, err
+	}
+	return event.ID, nil
+}
+
+// pendingBefore holds back events while an older one about the same aggregate is still
+// pending, such as one waiting to be retried.
+const pendingBefore = `NOT EXISTS (SELECT 1 FROM outbox_events o
+	WHERE o.aggregate_id = outbox_events.aggregate_id
+		AND split_part(o.type, '.', 1) = split_part(outbox_events.type, '.', 1)
+		AND o.id < outbox_events.id AND o.published_at IS NULL AND o.failed_at IS NULL)`
+
+func (i *outboxImp) ListPending(ctx context.Context, now time.Time, limit int) ([]domain.Event, error) {
+	return gorm.G[domain.Event](i.conn(ctx)).
+		Where("published_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+		Where(pendingBefore).
+		Order("id").Limit(limit).Find(ctx)
+}
+
+func (i *outboxImp) UpdateByID(ctx context.Context, event *domain.Event, fields []string) error {
+	_, err := gorm.G[domain.Event](i.conn(ctx)).Where("id = ?", event.ID).Select(fields[0], fields[1:]).Updates(ctx, *event)
+	return err
+}
+
+func (i *outboxImp) DeletePublished(ctx context.Context, before time.Time) error {
+	_, err := gorm.G[domain.Event](i.conn(ctx)).Where("published_at < ?", before).Delete(ctx)
+	return err
+}
@@ -0,0 +1,122 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+
+	"gorm.io/gorm"
+)
+
+type projectImp struct {
+	db *gorm.DB
+}
+
+func NewProjectRepo(db *storage.DB) *projectImp {
+	return &projectImp{
+		db: db.DB,
+	}
+}
+
+func (i *projectImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *projectImp) Create(ctx context.Context, project *domain.Project) (uint, error) {
+	err := gorm.G[domain.Project](i.conn(ctx)).Create(ctx, project)
+	if err != nil {
+		return 0, err
+	}
+	return project.ID, nil
+}
+
+func (i *projectImp) GetByID(ctx context.Context, ID uint) (domain.Project, error) {
+	return gorm.G[domain.Project](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *projectImp) ListByFilter(ctx context.Context, filter dto.ProjectListFilter, limit, offset int) ([]domain.Project, int64, error) {
+	q := i.conn(ctx).WithContext(ctx).Model(&domain.Project{})
+
+	if filter.Owner != 0 {
+		q = q.Where("owner_id = ?", filter.Owner)
+	}
+	if filter.Archived != nil {
+		q = q.Where("archived = ?", *filter.Archived)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var projects []domain.Project
+	if err := q.Order("id").Limit(limit).Offset(offset).Find(&projects).Error; err != nil {
+		return nil, 0, err
+	}
+	return projects, total, nil
+}
+
+func (i *projectImp) UpdateByID(ctx context.Context, project *domain.Project, fields []string) error {
+	_, err := gorm.G[domain.Project](i.conn(ctx)).Where("id = ?", project.ID).Select(fields[0], fields[1:]).Updates(ctx, *project)
+	return err
+}
+
+func (i *projectImp) DeleteByID(ctx context.Context, ID uint) error {
+	_, err := gorm.G[domain.Project](i.conn(ctx)).Where("id = ?", ID).Delete(ctx)
+	return err
+}
+
+// ReassignOwner hands every project of from, soft-deleted ones included, over to to.
+func (i *projectImp) ReassignOwner(ctx context.Context, from, to uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Model(&domain.Project{}).
+		Where("owner_id = ?", from).
+		Update("owner_id", to).Error
+}
+
+// DeleteByOwner permanently removes the projects of ownerID. Their tasks are kept without a
+// project, detached as by TaskRepo.ClearProject.
+func (i *projectImp) DeleteByOwner(ctx context.Context, ownerID uint) error {
+	db := i.conn(ctx).WithContext(ctx)
+	owned := db.Unscoped().Model(&domain.Project{}).Select("id").Where("owner_id = ?", ownerID)
+	err := db.Unscoped().Model(&domain.Task{}).
+		Where("project_id IN (?)", owned).
+		Updates(detachTask()).Error
+	if err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("project_id IN (?)", owned).Delete(&domain.ProjectMember{}).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("project_id IN (?)", owned).Delete(&domain.ShareLink{}).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("project_id IN (?)", owned).Delete(&domain.Workflow{}).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("project_id IN (?)", owned).Delete(&domain.CustomField{}).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Where("owner_id = ?", ownerID).Delete(&domain.Project{}).Error
+}
+
+func (i *projectImp) AddMember(ctx context.Context, member *domain.ProjectMember) error {
+	return gorm.G[domain.ProjectMember](i.conn(ctx)).Create(ctx, member)
+}
+
+func (i *projectImp) GetMember(ctx context.Context, projectID, userID uint) (domain.ProjectMember, error) {
+	return gorm.G[domain.ProjectMember](i.conn(ctx)).Where("project_id = ? AND user_id = ?", projectID, userID).Take(ctx)
+}
+
+func (i *projectImp) ListIDsByMember(ctx context.Context, userID uint) ([]uint, error) {
+	var ids []uint
+	err := i.conn(ctx).WithContext(ctx).Model(&domain.Project{}).
+		Where("owner_id = ? OR id IN (SELECT project_id FROM project_members WHERE user_id = ? AND deleted_at IS NULL)", userID, userID).
+		Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (i *projectImp) DeleteMembersByUser(ctx context.Context, userID uint) error {
+	_, err := gorm.G[domain.ProjectMember](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx)
+	return err
+}
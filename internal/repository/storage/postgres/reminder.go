@@ -0,0 +1,80 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type reminderImp struct {
+	db *gorm.DB
+}
+
+func NewReminderRepo(db *storage.DB) *reminderImp {
+	return &reminderImp{
+		db: db.DB,
+	}
+}
+
+func (i *reminderImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *reminderImp) Create(ctx context.Context, reminder *domain.Reminder) (uint, error) {
+	err := gorm.G[domain.Reminder](i.conn(ctx)).Create(ctx, reminder)
+	if err != nil {
+		return 0, err
+	}
+	return reminder.ID, nil
+}
+
+func (i *reminderImp) GetByID(ctx context.Context, ID uint) (domain.Reminder, error) {
+	return gorm.G[domain.Reminder](i.conn(ctx)).Preload("Task", nil).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *reminderImp) ListByTask(ctx context.Context, taskID, userID uint) ([]domain.Reminder, error) {
+	return gorm.G[domain.Reminder](i.conn(ctx)).Preload("Task", nil).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Order("id").Find(ctx)
+}
+
+func (i *reminderImp) ListByUser(ctx context.Context, userID uint) ([]domain.Reminder, error) {
+	return gorm.G[domain.Reminder](i.conn(ctx)).Where("user_id = ?", userID).Order("id").Find(ctx)
+}
+
+// ListDue returns up to limit unsent reminders that are due at now, across organizations,
+// with their task and user loaded. Reminders of finished or deleted tasks are skipped.
+func (i *reminderImp) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.Reminder, error) {
+	var reminders []domain.Reminder
+	err := i.conn(ctx).WithContext(ctx).
+		Joins("JOIN tasks ON tasks.id = reminders.task_id AND tasks.deleted_at IS NULL").
+		Where("reminders.sent_at IS NULL AND reminders.failed_at IS NULL").
+		Where("tasks.status NOT IN ?", []enum.TaskStatus{enum.Done, enum.Canceled}).
+		Where("COALESCE(reminders.remind_at, tasks.due_date - make_interval(mins => reminders.before_due_minutes)) <= ?", now).
+		Preload("Task").Preload("User").
+		Order("reminders.id").Limit(limit).
+		Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
+
+func (i *reminderImp) UpdateByID(ctx context.Context, reminder *domain.Reminder, fields []string) error {
+	_, err := gorm.G[domain.Reminder](i.conn(ctx)).Where("id = ?", reminder.ID).Select(fields[0], fields[1:]).Updates(ctx, *reminder)
+	return err
+}
+
+func (i *reminderImp) DeleteByID(ctx context.Context, ID uint) error {
+	_, err := gorm.G[domain.Reminder](i.conn(ctx)).Where("id = ?", ID).Delete(ctx)
+	return err
+}
+
+func (i *reminderImp) DeleteByUser(ctx context.Context, userID uint) error {
+	_, err := gorm.G[domain.Reminder](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx)
+	return err
+}
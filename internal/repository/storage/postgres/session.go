@@ -0,0 +1,54 @@
+package storage_postgres
+
+import (
+	"context"
+	"fmt"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+
+	"gorm.io/gorm"
+)
+
+type sessionImp struct {
+	db *gorm.DB
+}
+
+func NewSessionRepo(db *storage.DB) *sessionImp {
+	return &sessionImp{
+		db: db.DB,
+	}
+}
+
+func (i *sessionImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *sessionImp) Create(ctx context.Context, session *domain.UserSession) (uint, error) {
+	err := gorm.G[domain.UserSession](i.conn(ctx)).Create(ctx, session)
+	if err != nil {
+		return 0, err
+	}
+	return session.ID, nil
+}
+
+func (i *sessionImp) GetByField(ctx context.Context, field string, value any) (domain.UserSession, error) {
+	return gorm.G[domain.UserSession](i.conn(ctx)).Where(fmt.Sprintf("%s = ?", field), value).Take(ctx)
+}
+
+func (i *sessionImp) ListByUser(ctx context.Context, userID uint, onlyValid bool) ([]domain.UserSession, error) {
+	q := gorm.G[domain.UserSession](i.conn(ctx)).Where("user_id = ?", userID)
+	if onlyValid {
+		q = q.Where("valid = ?", true)
+	}
+	return q.Order("created_at DESC").Find(ctx)
+}
+
+func (i *sessionImp) UpdateByID(ctx context.Context, session *domain.UserSession, fields []string) error {
+	_, err := gorm.G[domain.UserSession](i.conn(ctx)).Where("id = ?", session.ID).Select(fields[0], fields[1:]).Updates(ctx, *session)
+	return err
+}
+
+func (i *sessionImp) DeleteByUser(ctx context.Context, userID uint) error {
+	_, err := gorm.G[domain.UserSession](i.conn(ctx).Unscoped()).Where("user_id = ?", userID).Delete(ctx)
+	return err
+}
@@ -0,0 +1,65 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type shareImp struct {
+	db *gorm.DB
+}
+
+func NewShareRepo(db *storage.DB) *shareImp {
+	return &shareImp{
+		db: db.DB,
+	}
+}
+
+func (i *shareImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *shareImp) Create(ctx context.Context, link *domain.ShareLink) (uint, error) {
+	err := gorm.G[domain.ShareLink](i.conn(ctx)).Create(ctx, link)
+	if err != nil {
+		return 0, err
+	}
+	return link.ID, nil
+}
+
+func (i *shareImp) GetByID(ctx context.Context, ID uint) (domain.ShareLink, error) {
+	return gorm.G[domain.ShareLink](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *shareImp) GetByNonce(ctx context.Context, nonce string) (domain.ShareLink, error) {
+	return gorm.G[domain.ShareLink](i.conn(ctx)).Where("nonce = ?", nonce).Take(ctx)
+}
+
+// ListActive returns the links that are neither revoked nor expired, newest first.
+func (i *shareImp) ListActive(ctx context.Context, filter dto.ShareListFilter) ([]domain.ShareLink, error) {
+	q := i.conn(ctx).WithContext(ctx).Model(&domain.ShareLink{}).
+		Where("revoked_at IS NULL AND (expires_at IS NULL OR expires_at > ?)", time.Now())
+
+	if filter.TaskID != 0 {
+		q = q.Where("task_id = ?", filter.TaskID)
+	}
+	if filter.ProjectID != 0 {
+		q = q.Where("project_id = ?", filter.ProjectID)
+	}
+
+	var links []domain.ShareLink
+	if err := q.Order("id DESC").Find(&links).Error; err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (i *shareImp) UpdateByID(ctx context.Context, link *domain.ShareLink, fields []string) error {
+	_, err := gorm.G[domain.ShareLink](i.conn(ctx)).Where("id = ?", link.ID).Select(fields[0], fields[1:]).Updates(ctx, *link)
+	return err
+}
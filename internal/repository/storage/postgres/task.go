@@ -2,6 +2,7 @@ package storage_postgres
 
 import (
 	"context"
+	"encoding/json"
 	"graph-interview/internal/api/handlers/dto"
 	"graph-interview/internal/domain"
 	"graph-interview/internal/repository/storage"
@@ -9,6 +10,7 @@ import (
 	"reflect"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type taskImp struct {
@@ -21,8 +23,12 @@ func NewTaskRepo(db *storage.DB) *taskImp {
 	}
 }
 
+func (i *taskImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
 func (i *taskImp) Create(ctx context.Context, task *domain.Task) (uint, error) {
-	err := gorm.G[domain.Task](i.db).Create(ctx, task)
+	err := gorm.G[domain.Task](i.conn(ctx)).Create(ctx, task)
 	if err != nil {
 		return 0, err
 	}
@@ -30,12 +36,12 @@ func (i *taskImp) Create(ctx context.Context, task *domain.Task) (uint, error) {
 }
 
 func (i *taskImp) GetByID(ctx context.Context, ID uint) (domain.Task, error) {
-	return gorm.G[domain.Task](i.db).Where("id = ?", ID).Take(ctx)
+	return gorm.G[domain.Task](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
 }
 
 func (i *taskImp) List(ctx context.Context, limit, offset int) ([]domain.Task, error) {
 	r := make([]domain.Task, int(math.Abs(float64(limit-offset))))
-	err := gorm.G[domain.Task](i.db).Select("*").Limit(limit).Offset(offset).Scan(ctx, &r)
+	err := gorm.G[domain.Task](i.conn(ctx)).Select("*").Limit(limit).Offset(offset).Scan(ctx, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +49,13 @@ func (i *taskImp) List(ctx context.Context, limit, offset int) ([]domain.Task, e
 }
 
 func (i *taskImp) ListByFilter(ctx context.Context, filter dto.TaskListFilter, limit, offset int) ([]domain.Task, int64, error) {
-	q := i.db.Model(&domain.Task{})
+	if err := i.bindCustomFields(ctx, &filter); err != nil {
+		return nil, 0, err
+	}
+	if err := i.bindStatuses(ctx, &filter); err != nil {
+		return nil, 0, err
+	}
+	q := i.conn(ctx).WithContext(ctx).Model(&domain.Task{})
 
 	if filter.Status != nil {
 		q = q.Where("status = ?", *filter.Status)
@@ -51,16 +63,40 @@ func (i *taskImp) ListByFilter(ctx context.Context, filter dto.TaskListFilter, l
 	if filter.Assignee != 0 {
 		q = q.Where("id IN (SELECT task_id FROM user_tasks WHERE user_id = ?)", filter.Assignee)
 	}
+	if filter.ProjectID != 0 {
+		q = q.Where("project_id = ?", filter.ProjectID)
+	}
+	if filter.SeriesID != 0 {
+		q = q.Where("series_id = ?", filter.SeriesID)
+	}
+	if filter.CreatedBy != 0 {
+		q = q.Where("created_by_user_id = ?", filter.CreatedBy)
+	}
+	if filter.Label != "" {
+		label, _ := json.Marshal([]string{filter.Label})
+		q = q.Where("labels @> ?::jsonb", string(label))
+	}
 	if !reflect.ValueOf(filter.CreatedAt).IsZero() {
 		q = q.Where("created_at >= ?", filter.CreatedAt)
 	}
 	if !reflect.ValueOf(filter.UpdatedAt).IsZero() {
 		q = q.Where("updated_at >= ?", filter.UpdatedAt)
 	}
+	if filter.Parsed != nil {
+		cond, args, err := compileFilter(filter.Parsed)
+		if err != nil {
+			return nil, 0, err
+		}
+		q = q.Where(cond, args...)
+	}
 
 	var total int64
 	q.Count(&total)
 
+	if len(filter.Orders) > 0 {
+		q = q.Order(compileOrders(filter.Orders))
+	}
+
 	var tasks []domain.Task
 	if err := q.Limit(limit).Offset(offset).Find(&tasks).Error; err != nil {
 		return nil, 0, err
@@ -68,12 +104,197 @@ func (i *taskImp) ListByFilter(ctx context.Context, filter dto.TaskListFilter, l
 	return tasks, total, nil
 }
 
-func (i *taskImp) UpdateByID(ctx context.Context, task *domain.Task, fields []string) error {
-	_, err := gorm.G[domain.Task](i.db).Where("id = ?", task.ID).Select(fields[0], fields[1:]).Updates(ctx, *task)
-	return err
+func (i *taskImp) UpdateByID(ctx context.Context, task *domain.Task, fields []string) (bool, error) {
+	version := task.Version
+	task.Version++
+	rows, err := gorm.G[domain.Task](i.conn(ctx)).Where("id = ? AND version = ?", task.ID, version).
+		Select("version", fields).Updates(ctx, *task)
+	if err != nil || rows == 0 {
+		task.Version = version
+		return false, err
+	}
+	return true, nil
 }
 
 func (i *taskImp) DeleteByID(ctx context.Context, ID uint) error {
-	_, err := gorm.G[domain.Task](i.db).Where("id = ?", ID).Delete(ctx)
+	_, err := gorm.G[domain.Task](i.conn(ctx)).Where("id = ?", ID).Delete(ctx)
+	return err
+}
+
+// ReassignCreator moves every task created by from, soft-deleted ones included, to to.
+// A nil to leaves the tasks without a creator.
+func (i *taskImp) ReassignCreator(ctx context.Context, from uint, to *uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Model(&domain.Task{}).
+		Where("created_by_user_id = ?", from).
+		Update("created_by_user_id", to).Error
+}
+
+func (i *taskImp) ClearUpdater(ctx context.Context, userID uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Model(&domain.Task{}).
+		Where("updated_by_user_id = ?", userID).
+		Update("updated_by_user_id", nil).Error
+}
+
+func (i *taskImp) RemoveAssignee(ctx context.Context, userID uint) error {
+	return i.assignments(ctx).Where("user_id = ?", userID).Delete(map[string]any{}).Error
+}
+
+// assignments queries user_tasks, limited to the tasks, soft-deleted ones included, that the
+// tenant scope lets ctx see: the join table has no organization of its own.
+func (i *taskImp) assignments(ctx context.Context) *gorm.DB {
+	db := i.conn(ctx).WithContext(ctx)
+	visible := db.Unscoped().Model(&domain.Task{}).Select("id")
+	return db.Table("user_tasks").Where("task_id IN (?)", visible)
+}
+
+// DeleteByCreator permanently removes the tasks created by userID along with their
+// assignments, share links and reminders.
+func (i *taskImp) DeleteByCreator(ctx context.Context, userID uint) error {
+	db := i.conn(ctx).WithContext(ctx)
+	created := db.Unscoped().Model(&domain.Task{}).Select("id").Where("created_by_user_id = ?", userID)
+	if err := i.assignments(ctx).Where("task_id IN (?)", created).Delete(map[string]any{}).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("task_id IN (?)", created).Delete(&domain.ShareLink{}).Error; err != nil {
+		return err
+	}
+	if err := db.Unscoped().Where("task_id IN (?)", created).Delete(&domain.Reminder{}).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Where("created_by_user_id = ?", userID).Delete(&domain.Task{}).Error
+}
+
+// AssignUser leaves tasks outside the tenant scope alone, as user_tasks cannot be scoped.
+func (i *taskImp) AssignUser(ctx context.Context, taskID, userID uint) error {
+	db := i.conn(ctx).WithContext(ctx)
+	var visible int64
+	if err := db.Unscoped().Model(&domain.Task{}).Where("id = ?", taskID).Count(&visible).Error; err != nil || visible == 0 {
+		return err
+	}
+	return db.Table("user_tasks").Clauses(clause.OnConflict{DoNothing: true}).
+		Create(map[string]any{"task_id": taskID, "user_id": userID}).Error
+}
+
+func (i *taskImp) UnassignUser(ctx context.Context, taskID, userID uint) error {
+	return i.assignments(ctx).Where("task_id = ? AND user_id = ?", taskID, userID).Delete(map[string]any{}).Error
+}
+
+func (i *taskImp) ListAssigneeIDs(ctx context.Context, taskID uint) ([]uint, error) {
+	var ids []uint
+	err := i.assignments(ctx).Where("task_id = ?", taskID).Order("user_id").Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (i *taskImp) Watch(ctx context.Context, taskID, userID uint) error {
+	watcher := &domain.TaskWatcher{TaskID: taskID, UserID: userID}
+	return gorm.G[domain.TaskWatcher](i.conn(ctx), clause.OnConflict{DoNothing: true}).Create(ctx, watcher)
+}
+
+func (i *taskImp) Unwatch(ctx context.Context, taskID, userID uint) error {
+	_, err := gorm.G[domain.TaskWatcher](i.conn(ctx)).Where("task_id = ? AND user_id = ?", taskID, userID).Delete(ctx)
+	return err
+}
+
+func (i *taskImp) ListWatcherIDs(ctx context.Context, taskID uint) ([]uint, error) {
+	var ids []uint
+	err := i.conn(ctx).WithContext(ctx).Model(&domain.TaskWatcher{}).
+		Where("task_id = ?", taskID).Order("user_id").Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+func (i *taskImp) RemoveWatcher(ctx context.Context, userID uint) error {
+	_, err := gorm.G[domain.TaskWatcher](i.conn(ctx)).Where("user_id = ?", userID).Delete(ctx)
 	return err
 }
+
+// ClearProject detaches every task from projectID, see detachTask.
+func (i *taskImp) ClearProject(ctx context.Context, projectID uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Model(&domain.Task{}).
+		Where("project_id = ?", projectID).
+		Updates(detachTask()).Error
+}
+
+// detachTask leaves a task without a project and so in the default workflow, under the
+// built-in status its custom one counts as, and without custom fields. Its version is bumped.
+func detachTask() map[string]any {
+	return map[string]any{"project_id": nil, "custom_status": "", "custom_fields": nil, "version": gorm.Expr("version + 1")}
+}
+
+// DropCustomField removes the value of the custom field key from the tasks of projectID,
+// bumping their versions.
+func (i *taskImp) DropCustomField(ctx context.Context, projectID uint, key string) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Model(&domain.Task{}).
+		Where("project_id = ? AND custom_fields -> ? IS NOT NULL", projectID, key).
+		Updates(map[string]any{"custom_fields": gorm.Expr("custom_fields - ?", key), "version": gorm.Expr("version + 1")}).Error
+}
+
+// boardOrder sorts tasks by board position, byte by byte, with unpositioned tasks last.
+const boardOrder = `NULLIF(position, '') COLLATE "C" NULLS LAST, id`
+
+// inColumn narrows q to the tasks in column.
+func inColumn(q *gorm.DB, column domain.Column) *gorm.DB {
+	q = q.Where("status = ? AND custom_status = ?", column.Status, column.CustomStatus)
+	if column.ProjectID == 0 {
+		return q.Where("project_id IS NULL")
+	}
+	return q.Where("project_id = ?", column.ProjectID)
+}
+
+func (i *taskImp) LastPosition(ctx context.Context, column domain.Column) (string, error) {
+	var position *string
+	err := inColumn(i.conn(ctx).WithContext(ctx).Model(&domain.Task{}), column).
+		Where("position <> ''").
+		Select(`MAX(position COLLATE "C")`).Scan(&position).Error
+	if err != nil || position == nil {
+		return "", err
+	}
+	return *position, nil
+}
+
+func (i *taskImp) PositionBefore(ctx context.Context, column domain.Column, position string, excludeID uint) (string, error) {
+	return i.neighbor(ctx, column, excludeID, `position <> '' AND position COLLATE "C" < ?`, position, `position COLLATE "C" DESC`)
+}
+
+func (i *taskImp) PositionAfter(ctx context.Context, column domain.Column, position string, excludeID uint) (string, error) {
+	return i.neighbor(ctx, column, excludeID, `position COLLATE "C" > ?`, position, `position COLLATE "C"`)
+}
+
+func (i *taskImp) neighbor(ctx context.Context, column domain.Column, excludeID uint, cond, position, order string) (string, error) {
+	var positions []string
+	err := inColumn(i.conn(ctx).WithContext(ctx).Model(&domain.Task{}), column).
+		Where("id <> ?", excludeID).
+		Where(cond, position).
+		Order(order).Limit(1).Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+func (i *taskImp) ListColumnIDs(ctx context.Context, column domain.Column) ([]uint, error) {
+	var ids []uint
+	err := inColumn(i.conn(ctx).WithContext(ctx).Model(&domain.Task{}), column).
+		Order(boardOrder).Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (i *taskImp) SetPosition(ctx context.Context, taskID uint, position string) error {
+	return i.conn(ctx).WithContext(ctx).Model(&domain.Task{}).
+		Where("id = ?", taskID).
+		UpdateColumns(map[string]any{"position": position, "version": gorm.Expr("version + 1")}).Error
+}
+
+func (i *taskImp) ListStatusesInUse(ctx context.Context, projectID uint) ([]domain.WorkflowStatus, error) {
+	var tasks []domain.Task
+	err := i.conn(ctx).WithContext(ctx).Model(&domain.Task{}).
+		Where("project_id = ?", projectID).
+		Distinct("status", "custom_status").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]domain.WorkflowStatus, len(tasks))
+	for i, task := range tasks {
+		statuses[i] = domain.WorkflowStatus{Name: task.StatusName(), Category: task.Status.Category(), Status: task.Status}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,280 @@
+package storage_postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository/enum"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// filterColumns are the task columns of the plain filter fields.
+var filterColumns = map[string]string{
+	"status":      "status",
+	"created_by":  "created_by_user_id",
+	"project":     "project_id",
+	"series":      "series_id",
+	"name":        "name",
+	"description": "description",
+	"due":         "due_date",
+	"created":     "created_at",
+	"updated":     "updated_at",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// customKinds are the filter kinds of the custom field types.
+var customKinds = map[enum.CustomFieldType]filter.Kind{
+	enum.FieldText:   filter.KindText,
+	enum.FieldNumber: filter.KindNumber,
+	enum.FieldDate:   filter.KindDate,
+	enum.FieldSelect: filter.KindText,
+	enum.FieldUser:   filter.KindUser,
+}
+
+// bindCustomFields resolves the custom fields f filters and sorts by against their
+// definitions: the project's when f has one, or else every project's.
+func (i *taskImp) bindCustomFields(ctx context.Context, f *dto.TaskListFilter) error {
+	keys := filter.CustomKeys(f.Parsed, f.Orders)
+	if len(keys) == 0 {
+		return nil
+	}
+	q := gorm.G[domain.CustomField](i.conn(ctx)).Where("key IN ?", keys)
+	if f.ProjectID != 0 {
+		q = q.Where("project_id = ?", f.ProjectID)
+	}
+	fields, err := q.Find(ctx)
+	if err != nil {
+		return err
+	}
+	kinds := make(map[string]filter.Kind, len(keys))
+	for _, field := range fields {
+		kind := customKinds[field.Type]
+		if known, ok := kinds[field.Key]; ok && known != kind {
+			kind = filter.KindCustom
+		}
+		kinds[field.Key] = kind
+	}
+
+	if f.Parsed != nil {
+		if f.Parsed, err = filter.Bind(f.Parsed, kinds); err != nil {
+			return err
+		}
+	}
+	f.Orders, err = filter.BindOrders(f.Orders, kinds)
+	return err
+}
+
+// bindStatuses resolves the statuses f filters by against the workflow of its project,
+// when it has one. The workflow is only loaded once a comparison needs it.
+func (i *taskImp) bindStatuses(ctx context.Context, f *dto.TaskListFilter) error {
+	if f.Parsed == nil {
+		return nil
+	}
+	var find func(string) (filter.WorkflowStatus, bool)
+	var workflow *domain.Workflow
+	var loadErr error
+	if f.ProjectID != 0 {
+		find = func(name string) (filter.WorkflowStatus, bool) {
+			if workflow == nil && loadErr == nil {
+				w, err := gorm.G[domain.Workflow](i.conn(ctx)).Where("project_id = ?", f.ProjectID).First(ctx)
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					w, err = domain.Workflow{}, nil
+				}
+				workflow, loadErr = &w, err
+			}
+			status, ok := workflow.Find(name)
+			return filter.WorkflowStatus{Name: status.Name, Status: status.Status}, ok
+		}
+	}
+	parsed, err := filter.BindStatuses(f.Parsed, find)
+	if loadErr != nil {
+		return loadErr
+	}
+	f.Parsed = parsed
+	return err
+}
+
+// customColumn is the expression for the values of a bound custom field. Numbers are only
+// read from JSON numbers, so a value of another type never breaks the cast. Keys are
+// checked by the filter parser and safe to inline.
+func customColumn(field filter.Field) (string, bool) {
+	value := "(custom_fields->>'" + field.Key + "')"
+	typed := func(jsonType, cast string) string {
+		return "(CASE WHEN jsonb_typeof(custom_fields->'" + field.Key + "') = '" + jsonType + "' THEN " + value + "::" + cast + " END)"
+	}
+	switch field.Kind {
+	case filter.KindText, filter.KindDate:
+		return value, true
+	case filter.KindNumber:
+		return typed("number", "numeric"), true
+	case filter.KindUser:
+		return typed("number", "bigint"), true
+	default:
+		return "", false
+	}
+}
+
+// compileFilter turns a parsed filter into a SQL condition on tasks. Values are always
+// passed as arguments and columns come from filterColumns, so the condition is safe to
+// hand to Where.
+func compileFilter(e filter.Expr) (string, []any, error) {
+	switch e := e.(type) {
+	case filter.And:
+		return compileBinary(e.Left, "AND", e.Right)
+	case filter.Or:
+		return compileBinary(e.Left, "OR", e.Right)
+	case filter.Not:
+		sql, args, err := compileFilter(e.Expr)
+		return "NOT (" + sql + ")", args, err
+	case filter.Cmp:
+		return compileCmp(e)
+	default:
+		return "", nil, fmt.Errorf("filter: unexpected node %T", e)
+	}
+}
+
+func compileBinary(left filter.Expr, op string, right filter.Expr) (string, []any, error) {
+	l, lArgs, err := compileFilter(left)
+	if err != nil {
+		return "", nil, err
+	}
+	r, rArgs, err := compileFilter(right)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + l + " " + op + " " + r + ")", append(lArgs, rArgs...), nil
+}
+
+func compileCmp(e filter.Cmp) (string, []any, error) {
+	switch e.Field.Name {
+	case "assignee":
+		return compileAssignee(e)
+	case "label":
+		return compileLabel(e)
+	case "status":
+		if slices.ContainsFunc(e.Values, isWorkflowStatus) {
+			return compileStatus(e)
+		}
+	}
+	column, ok := filterColumns[e.Field.Name]
+	if e.Field.Key != "" {
+		column, ok = customColumn(e.Field)
+	}
+	if !ok {
+		return "", nil, fmt.Errorf("filter: unknown field %q", e.Field.Name)
+	}
+
+	value := e.Values[0]
+	switch {
+	case e.Op == filter.OpIn:
+		return column + " IN ?", []any{e.Values}, nil
+	case e.Op == filter.OpContains:
+		return column + " ILIKE ?", []any{"%" + likeEscaper.Replace(value.(string)) + "%"}, nil
+	case value == nil && e.Op == filter.OpEq:
+		return column + " IS NULL", nil, nil
+	case value == nil:
+		return column + " IS NOT NULL", nil, nil
+	case e.Op == filter.OpNe:
+		// Unlike !=, IS DISTINCT FROM keeps the rows where the column is null.
+		return column + " IS DISTINCT FROM ?", []any{value}, nil
+	default:
+		return column + " " + string(e.Op) + " ?", []any{value}, nil
+	}
+}
+
+func isWorkflowStatus(value any) bool {
+	_, ok := value.(filter.WorkflowStatus)
+	return ok
+}
+
+// compileStatus compiles a status comparison with statuses of a workflow, which match
+// tasks by name: custom_status holds it, unless it is the name of the built-in status.
+func compileStatus(e filter.Cmp) (string, []any, error) {
+	conds := make([]string, len(e.Values))
+	args := make([]any, len(e.Values))
+	for i, value := range e.Values {
+		status, ok := value.(filter.WorkflowStatus)
+		switch {
+		case !ok:
+			conds[i], args[i] = "status = ?", value
+		case status.Name == status.Status.String():
+			conds[i], args[i] = "(custom_status = '' AND status = ?)", status.Status
+		default:
+			conds[i], args[i] = "custom_status = ?", status.Name
+		}
+	}
+	sql := "(" + strings.Join(conds, " OR ") + ")"
+	if e.Op == filter.OpNe {
+		sql = "NOT " + sql
+	}
+	return sql, args, nil
+}
+
+func compileAssignee(e filter.Cmp) (string, []any, error) {
+	const assigned = "id IN (SELECT task_id FROM user_tasks WHERE user_id IN ?)"
+	const anyAssignee = "id IN (SELECT task_id FROM user_tasks)"
+	switch {
+	case e.Op == filter.OpIn:
+		return assigned, []any{e.Values}, nil
+	case e.Values[0] == nil && e.Op == filter.OpEq:
+		return "NOT " + anyAssignee, nil, nil
+	case e.Values[0] == nil:
+		return anyAssignee, nil, nil
+	case e.Op == filter.OpEq:
+		return assigned, []any{e.Values}, nil
+	default:
+		return "NOT " + assigned, []any{e.Values}, nil
+	}
+}
+
+func compileLabel(e filter.Cmp) (string, []any, error) {
+	const empty = "(labels IS NULL OR labels = '[]'::jsonb)"
+	if e.Values[0] == nil {
+		if e.Op == filter.OpEq {
+			return empty, nil, nil
+		}
+		return "NOT " + empty, nil, nil
+	}
+
+	conds := make([]string, len(e.Values))
+	args := make([]any, len(e.Values))
+	for i, value := range e.Values {
+		label, _ := json.Marshal([]string{value.(string)})
+		conds[i] = "labels @> ?::jsonb"
+		args[i] = string(label)
+	}
+	sql := "(" + strings.Join(conds, " OR ") + ")"
+	if e.Op == filter.OpNe {
+		sql = "NOT COALESCE(" + sql + ", false)"
+	}
+	return sql, args, nil
+}
+
+// compileOrders turns sort orders into an ORDER BY list. Tasks without a value come last
+// either way, and the ID keeps pages stable between equal values.
+func compileOrders(orders []filter.Order) string {
+	terms := make([]string, 0, len(orders)+1)
+	for _, order := range orders {
+		dir := "ASC"
+		if order.Desc {
+			dir = "DESC"
+		}
+		column := filterColumns[order.Field.Name]
+		switch {
+		case order.Field == filter.Position:
+			column = `NULLIF(position, '') COLLATE "C"`
+		case order.Field.Key != "":
+			column, _ = customColumn(order.Field)
+		}
+		terms = append(terms, column+" "+dir+" NULLS LAST")
+	}
+	return strings.Join(append(terms, "id"), ", ")
+}
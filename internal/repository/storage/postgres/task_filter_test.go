@@ -0,0 +1,127 @@
+package storage_postgres
+
+import (
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository/enum"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileFilter(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		src  string
+		sql  string
+		args []any
+	}{
+		{`status in (Started, Delayed)`, `status IN ?`, []any{[]any{enum.Started, enum.Delayed}}},
+		{`status != Done`, `status IS DISTINCT FROM ?`, []any{enum.Done}},
+		{`project = null`, `project_id IS NULL`, nil},
+		{`due != null`, `due_date IS NOT NULL`, nil},
+		{`created >= today`, `created_at >= ?`, []any{now}},
+		{`name ~ "50%_off"`, `name ILIKE ?`, []any{`%50\%\_off%`}},
+		{`assignee = me`, `id IN (SELECT task_id FROM user_tasks WHERE user_id IN ?)`, []any{[]any{uint(7)}}},
+		{`assignee != 3`, `NOT id IN (SELECT task_id FROM user_tasks WHERE user_id IN ?)`, []any{[]any{uint(3)}}},
+		{`assignee = null`, `NOT id IN (SELECT task_id FROM user_tasks)`, nil},
+		{`label in (bug, ui)`, `(labels @> ?::jsonb OR labels @> ?::jsonb)`, []any{`["bug"]`, `["ui"]`}},
+		{`label != bug`, `NOT COALESCE((labels @> ?::jsonb), false)`, []any{`["bug"]`}},
+		{`label = null`, `(labels IS NULL OR labels = '[]'::jsonb)`, nil},
+		{
+			`status = Started and not (assignee = me or created_by = me)`,
+			`(status = ? AND NOT ((id IN (SELECT task_id FROM user_tasks WHERE user_id IN ?) OR created_by_user_id = ?)))`,
+			[]any{enum.Started, []any{uint(7)}, uint(7)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := filter.Parse(tt.src, filter.Env{UserID: 7, Now: now})
+			require.NoError(t, err)
+
+			sql, args, err := compileFilter(expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestCompileOrders(t *testing.T) {
+	orders, err := filter.ParseSort("-updated,due")
+	require.NoError(t, err)
+	assert.Equal(t, "updated_at DESC NULLS LAST, due_date ASC NULLS LAST, id", compileOrders(orders))
+
+	orders, err = filter.ParseSort("position")
+	require.NoError(t, err)
+	assert.Equal(t, `NULLIF(position, '') COLLATE "C" ASC NULLS LAST, id`, compileOrders(orders))
+}
+
+func TestCompileFilter_CustomFields(t *testing.T) {
+	kinds := map[string]filter.Kind{"points": filter.KindNumber, "customer": filter.KindText, "owner": filter.KindUser}
+	tests := []struct {
+		src  string
+		sql  string
+		args []any
+	}{
+		{`cf.points >= 3`, `(CASE WHEN jsonb_typeof(custom_fields->'points') = 'number' THEN (custom_fields->>'points')::numeric END) >= ?`, []any{3.0}},
+		{`cf.customer ~ acme`, `(custom_fields->>'customer') ILIKE ?`, []any{`%acme%`}},
+		{`cf.customer = null`, `(custom_fields->>'customer') IS NULL`, nil},
+		{`cf.owner in (me)`, `(CASE WHEN jsonb_typeof(custom_fields->'owner') = 'number' THEN (custom_fields->>'owner')::bigint END) IN ?`, []any{[]any{uint(7)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := filter.Parse(tt.src, filter.Env{UserID: 7, Now: time.Now()})
+			require.NoError(t, err)
+			expr, err = filter.Bind(expr, kinds)
+			require.NoError(t, err)
+
+			sql, args, err := compileFilter(expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+
+	// Unbound custom fields cannot be compiled.
+	expr, err := filter.Parse(`cf.points = 3`, filter.Env{})
+	require.NoError(t, err)
+	_, _, err = compileFilter(expr)
+	assert.Error(t, err)
+}
+
+func TestCompileOrders_CustomFields(t *testing.T) {
+	orders, err := filter.ParseSort("-cf.customer")
+	require.NoError(t, err)
+	orders, err = filter.BindOrders(orders, map[string]filter.Kind{"customer": filter.KindText})
+	require.NoError(t, err)
+	assert.Equal(t, "(custom_fields->>'customer') DESC NULLS LAST, id", compileOrders(orders))
+}
+
+func TestCompileFilter_WorkflowStatuses(t *testing.T) {
+	review := filter.WorkflowStatus{Name: "In Review", Status: enum.Started}
+	done := filter.WorkflowStatus{Name: "Done", Status: enum.Done}
+	tests := []struct {
+		cmp  filter.Cmp
+		sql  string
+		args []any
+	}{
+		{filter.Cmp{Op: filter.OpEq, Values: []any{review}}, `(custom_status = ?)`, []any{"In Review"}},
+		{filter.Cmp{Op: filter.OpNe, Values: []any{done}}, `NOT ((custom_status = '' AND status = ?))`, []any{enum.Done}},
+		{
+			filter.Cmp{Op: filter.OpIn, Values: []any{review, enum.Delayed}},
+			`(custom_status = ? OR status = ?)`,
+			[]any{"In Review", enum.Delayed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmp.String(), func(t *testing.T) {
+			tt.cmp.Field = filter.Fields["status"]
+			sql, args, err := compileFilter(tt.cmp)
+			require.NoError(t, err)
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"graph-interview/internal/domain"
 	"graph-interview/internal/repository/storage"
 	"math"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -21,24 +22,32 @@ func NewUserRepo(db *storage.DB) *userImp {
 	}
 }
 
+func (i *userImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
 func (i *userImp) Create(ctx context.Context, user *domain.User) (uint, error) {
-	err := gorm.G[domain.User](i.db).Create(ctx, user)
+	err := gorm.G[domain.User](i.conn(ctx)).Create(ctx, user)
 	if err != nil {
 		return 0, err
 	}
 	return user.ID, nil
 }
 func (i *userImp) GetByID(ctx context.Context, ID uint) (domain.User, error) {
-	return gorm.G[domain.User](i.db).Where("id = ?", ID).Take(ctx)
+	return gorm.G[domain.User](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
 }
 
 func (i *userImp) GetByField(ctx context.Context, field string, value any) (domain.User, error) {
-	return gorm.G[domain.User](i.db).Where(fmt.Sprintf("%s = ?", field), value).Take(ctx)
+	return gorm.G[domain.User](i.conn(ctx)).Where(fmt.Sprintf("%s = ?", field), value).Take(ctx)
+}
+
+func (i *userImp) GetByVerifiedEmail(ctx context.Context, email string) (domain.User, error) {
+	return gorm.G[domain.User](i.conn(ctx)).Where("email = ? AND email_verified", email).Order("id").Take(ctx)
 }
 
 func (i *userImp) List(ctx context.Context, limit, offset int) ([]domain.User, error) {
 	r := make([]domain.User, int(math.Abs(float64(limit-offset))))
-	err := gorm.G[domain.User](i.db).Select("*").Limit(limit).Offset(offset).Scan(ctx, &r)
+	err := gorm.G[domain.User](i.conn(ctx)).Select("*").Limit(limit).Offset(offset).Scan(ctx, &r)
 	if err != nil {
 		return nil, err
 	} else {
@@ -46,12 +55,55 @@ func (i *userImp) List(ctx context.Context, limit, offset int) ([]domain.User, e
 	}
 }
 
-func (i *userImp) ListByFilter(ctx context.Context, filter dto.UserListFilter, limit, offset int) ([]domain.User, error) {
-	q := gorm.G[domain.User](i.db).Select("*").Limit(limit).Offset(offset)
-	return q.Find(ctx)
+func (i *userImp) ListByFilter(ctx context.Context, filter dto.UserListFilter, limit, offset int) ([]domain.User, int64, error) {
+	q := i.conn(ctx).WithContext(ctx).Model(&domain.User{})
+
+	if filter.Username != "" {
+		q = q.Where("username ILIKE ?", "%"+escapeLike(filter.Username)+"%")
+	}
+	if filter.Email != "" {
+		q = q.Where("email ILIKE ?", "%"+escapeLike(filter.Email)+"%")
+	}
+	if !filter.CreatedFrom.IsZero() {
+		q = q.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		q = q.Where("created_at <= ?", filter.CreatedTo)
+	}
+	if filter.Role != nil {
+		q = q.Where("role = ?", *filter.Role)
+	}
+	if filter.Active != nil {
+		if *filter.Active {
+			q = q.Where("deactivated_at IS NULL")
+		} else {
+			q = q.Where("deactivated_at IS NOT NULL")
+		}
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	if err := q.Order("id").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func (i *userImp) UpdateByID(ctx context.Context, user *domain.User, fields []string) error {
-	_, err := gorm.G[domain.User](i.db).Where("id = ?", user.ID).Select(fields[0], fields[1:]).Updates(ctx, *user)
+	_, err := gorm.G[domain.User](i.conn(ctx)).Where("id = ?", user.ID).Select(fields[0], fields[1:]).Updates(ctx, *user)
+	return err
+}
+
+// DeleteByID removes the user row permanently, bypassing soft delete.
+func (i *userImp) DeleteByID(ctx context.Context, ID uint) error {
+	_, err := gorm.G[domain.User](i.conn(ctx).Unscoped()).Where("id = ?", ID).Delete(ctx)
 	return err
 }
@@ -0,0 +1,96 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+	"strconv"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type viewImp struct {
+	db *gorm.DB
+}
+
+func NewViewRepo(db *storage.DB) *viewImp {
+	return &viewImp{
+		db: db.DB,
+	}
+}
+
+func (i *viewImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *viewImp) Create(ctx context.Context, view *domain.View) (uint, error) {
+	err := gorm.G[domain.View](i.conn(ctx)).Create(ctx, view)
+	if err != nil {
+		return 0, err
+	}
+	return view.ID, nil
+}
+
+func (i *viewImp) GetByID(ctx context.Context, ID uint) (domain.View, error) {
+	return gorm.G[domain.View](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *viewImp) ListVisible(ctx context.Context, userID uint) ([]domain.View, error) {
+	return gorm.G[domain.View](i.conn(ctx)).Where("owner_id = ? OR shared", userID).Order("name, id").Find(ctx)
+}
+
+func (i *viewImp) UpdateByID(ctx context.Context, view *domain.View, fields []string) error {
+	_, err := gorm.G[domain.View](i.conn(ctx)).Where("id = ?", view.ID).Select(fields[0], fields[1:]).Updates(ctx, *view)
+	return err
+}
+
+func (i *viewImp) DeleteByID(ctx context.Context, ID uint) error {
+	if _, err := gorm.G[domain.ViewPin](i.conn(ctx)).Where("view = ?", strconv.FormatUint(uint64(ID), 10)).Delete(ctx); err != nil {
+		return err
+	}
+	_, err := gorm.G[domain.View](i.conn(ctx)).Where("id = ?", ID).Delete(ctx)
+	return err
+}
+
+func (i *viewImp) Pin(ctx context.Context, userID uint, view string) error {
+	pin := &domain.ViewPin{UserID: userID, View: view}
+	return gorm.G[domain.ViewPin](i.conn(ctx), clause.OnConflict{DoNothing: true}).Create(ctx, pin)
+}
+
+func (i *viewImp) Unpin(ctx context.Context, userID uint, view string) error {
+	_, err := gorm.G[domain.ViewPin](i.conn(ctx)).Where("user_id = ? AND view = ?", userID, view).Delete(ctx)
+	return err
+}
+
+func (i *viewImp) ListPins(ctx context.Context, userID uint) ([]string, error) {
+	pins, err := gorm.G[domain.ViewPin](i.conn(ctx)).Where("user_id = ?", userID).Order("created_at").Find(ctx)
+	if err != nil {
+		return nil, err
+	}
+	views := make([]string, len(pins))
+	for i, pin := range pins {
+		views[i] = pin.View
+	}
+	return views, nil
+}
+
+func (i *viewImp) ListByOwner(ctx context.Context, userID uint) ([]domain.View, error) {
+	return gorm.G[domain.View](i.conn(ctx)).Where("owner_id = ?", userID).Order("name, id").Find(ctx)
+}
+
+func (i *viewImp) ReassignShared(ctx context.Context, from, to uint) error {
+	return i.conn(ctx).WithContext(ctx).Model(&domain.View{}).
+		Where("owner_id = ? AND shared", from).
+		Update("owner_id", to).Error
+}
+
+func (i *viewImp) DeleteByOwner(ctx context.Context, userID uint) error {
+	db := i.conn(ctx).WithContext(ctx)
+	err := db.Where("user_id = ? OR view IN (SELECT CAST(id AS text) FROM views WHERE owner_id = ?)", userID, userID).
+		Delete(&domain.ViewPin{}).Error
+	if err != nil {
+		return err
+	}
+	return db.Unscoped().Where("owner_id = ?", userID).Delete(&domain.View{}).Error
+}
@@ -0,0 +1,104 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type webhookImp struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepo(db *storage.DB) *webhookImp {
+	return &webhookImp{
+		db: db.DB,
+	}
+}
+
+func (i *webhookImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *webhookImp) Create(ctx context.Context, webhook *domain.Webhook) (uint, error) {
+	err := gorm.G[domain.Webhook](i.conn(ctx)).Create(ctx, webhook)
+	if err != nil {
+		return 0, err
+	}
+	return webhook.ID, nil
+}
+
+func (i *webhookImp) GetByID(ctx context.Context, ID uint) (domain.Webhook, error) {
+	return gorm.G[domain.Webhook](i.conn(ctx)).Where("id = ?", ID).Take(ctx)
+}
+
+func (i *webhookImp) List(ctx context.Context) ([]domain.Webhook, error) {
+	return gorm.G[domain.Webhook](i.conn(ctx)).Order("id").Find(ctx)
+}
+
+func (i *webhookImp) UpdateByID(ctx context.Context, webhook *domain.Webhook, fields []string) error {
+	_, err := gorm.G[domain.Webhook](i.conn(ctx)).Where("id = ?", webhook.ID).Select(fields[0], fields[1:]).Updates(ctx, *webhook)
+	return err
+}
+
+func (i *webhookImp) DeleteByID(ctx context.Context, ID uint) error {
+	if _, err := gorm.G[domain.WebhookDelivery](i.conn(ctx).Unscoped()).Where("webhook_id = ?", ID).Delete(ctx); err != nil {
+		return err
+	}
+	_, err := gorm.G[domain.Webhook](i.conn(ctx)).Where("id = ?", ID).Delete(ctx)
+	return err
+}
+
+func (i *webhookImp) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (uint, error) {
+	err := gorm.G[domain.WebhookDelivery](i.conn(ctx)).Create(ctx, delivery)
+	if err != nil {
+		return 0, err
+	}
+	return delivery.ID, nil
+}
+
+// ListDeliveries returns the deliveries of a webhook, newest first, and how many there are.
+func (i *webhookImp) ListDeliveries(ctx context.Context, webhookID uint, limit, offset int) ([]domain.WebhookDelivery, int64, error) {
+	q := i.conn(ctx).WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var deliveries []domain.WebhookDelivery
+	if err := q.Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+func (i *webhookImp) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	return gorm.G[domain.WebhookDelivery](i.conn(ctx)).Preload("Webhook", nil).
+		Where("delivered_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now).
+		Order("next_attempt_at, id").Limit(limit).Find(ctx)
+}
+
+func (i *webhookImp) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery, fields []string) error {
+	_, err := gorm.G[domain.WebhookDelivery](i.conn(ctx)).Where("id = ?", delivery.ID).Select(fields[0], fields[1:]).Updates(ctx, *delivery)
+	return err
+}
+
+// ReassignCreator hands every webhook created by from, soft-deleted ones included, over to to.
+func (i *webhookImp) ReassignCreator(ctx context.Context, from, to uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Model(&domain.Webhook{}).
+		Where("created_by_id = ?", from).
+		Update("created_by_id", to).Error
+}
+
+func (i *webhookImp) DeleteByCreator(ctx context.Context, userID uint) error {
+	db := i.conn(ctx).WithContext(ctx).Unscoped()
+	err := db.Where("webhook_id IN (SELECT id FROM webhooks WHERE created_by_id = ?)", userID).
+		Delete(&domain.WebhookDelivery{}).Error
+	if err != nil {
+		return err
+	}
+	return db.Where("created_by_id = ?", userID).Delete(&domain.Webhook{}).Error
+}
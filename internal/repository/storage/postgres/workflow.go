@@ -0,0 +1,40 @@
+package storage_postgres
+
+import (
+	"context"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type workflowImp struct {
+	db *gorm.DB
+}
+
+func NewWorkflowRepo(db *storage.DB) *workflowImp {
+	return &workflowImp{
+		db: db.DB,
+	}
+}
+
+func (i *workflowImp) conn(ctx context.Context) *gorm.DB {
+	return storage.Conn(ctx, i.db)
+}
+
+func (i *workflowImp) GetByProject(ctx context.Context, projectID uint) (domain.Workflow, error) {
+	return gorm.G[domain.Workflow](i.conn(ctx)).Where("project_id = ?", projectID).Take(ctx)
+}
+
+func (i *workflowImp) Save(ctx context.Context, workflow *domain.Workflow) error {
+	return gorm.G[domain.Workflow](i.conn(ctx), clause.OnConflict{
+		Columns:   []clause.Column{{Name: "project_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"statuses", "transitions", "updated_at"}),
+	}).Create(ctx, workflow)
+}
+
+// DeleteByProject removes the workflow for good, so the project can get a new one.
+func (i *workflowImp) DeleteByProject(ctx context.Context, projectID uint) error {
+	return i.conn(ctx).WithContext(ctx).Unscoped().Where("project_id = ?", projectID).Delete(&domain.Workflow{}).Error
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/tenant"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrMissingTenant is returned for queries on organization scoped models made without an
+// active organization in the context.
+var ErrMissingTenant = errors.New("no organization in context for a tenant scoped query")
+
+// ErrUnscopedRawSQL is returned for raw SQL touching organization scoped tables, which the
+// tenant scope cannot rewrite, made without tenant.Unscoped in the context.
+var ErrUnscopedRawSQL = errors.New("raw SQL on a tenant scoped table requires tenant.Unscoped")
+
+// tableRef finds the tables a raw statement reads or writes. It is a safety net, not a parser.
+var tableRef = regexp.MustCompile(`(?i)\b(?:from|join|into|update)\s+"?(\w+)"?`)
+
+var (
+	orgScopedType = reflect.TypeOf((*domain.OrgScoped)(nil)).Elem()
+	userType      = reflect.TypeOf(domain.User{})
+	taskType      = reflect.TypeOf(domain.Task{})
+	projectType   = reflect.TypeOf(domain.Project{})
+)
+
+// guestVisible limits the rows of a project column to the projects a guest of the
+// organization owns or belongs to. Everyone else passes the NOT EXISTS.
+const guestVisible = `(NOT EXISTS (SELECT 1 FROM memberships WHERE organization_id = @org AND user_id = @user AND guest AND deleted_at IS NULL)` +
+	` OR @project IN (SELECT project_id FROM project_members WHERE user_id = @user AND deleted_at IS NULL` +
+	` UNION SELECT id FROM projects WHERE owner_id = @user AND deleted_at IS NULL))`
+
+// registerTenantScope installs callbacks that restrict every query to the organization found
+// in the statement context, so repositories cannot leak rows by forgetting a condition.
+// Organization scoped models require an organization; users are narrowed to the members of
+// the active organization when there is one. With a reader in the context, projects and
+// tasks are further narrowed to what that user may see. Raw SQL cannot be scoped, so it
+// fails on organization scoped tables, and on their join tables, unless the context is
+// tenant.Unscoped.
+func registerTenantScope(db *gorm.DB) error {
+	tables, err := orgScopedTables(db)
+	if err != nil {
+		return err
+	}
+	guard := guardRawSQL(tables)
+	cb := db.Callback()
+	if err := cb.Raw().Before("gorm:raw").Register("tenant:guard", guard); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:guard", guard); err != nil {
+		return err
+	}
+	if err := cb.Row().Before("gorm:row").Register("tenant:guard", guard); err != nil {
+		return err
+	}
+	if err := cb.Create().Before("gorm:create").Register("tenant:assign", assignTenant); err != nil {
+		return err
+	}
+	if err := cb.Query().Before("gorm:query").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("tenant:scope", scopeTenant); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("tenant:scope", scopeTenant)
+}
+
+// orgScopedTables returns the tables of the organization scoped models, and the join tables
+// of their many to many relations, which hold rows of an organization without saying so.
+func orgScopedTables(db *gorm.DB) (map[string]bool, error) {
+	tables := map[string]bool{}
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, err
+		}
+		if !reflect.PointerTo(stmt.Schema.ModelType).Implements(orgScopedType) {
+			continue
+		}
+		tables[stmt.Schema.Table] = true
+		for _, rel := range stmt.Schema.Relationships.Many2Many {
+			tables[rel.JoinTable.Table] = true
+		}
+	}
+	return tables, nil
+}
+
+// guardRawSQL fails statements written as raw SQL that touch one of tables. Statements built
+// by gorm have no SQL yet when it runs, and are scoped by scopeTenant instead.
+func guardRawSQL(tables map[string]bool) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Error != nil || db.Statement.SQL.Len() == 0 || tenant.IsUnscoped(db.Statement.Context) {
+			return
+		}
+		for _, ref := range tableRef.FindAllStringSubmatch(db.Statement.SQL.String(), -1) {
+			if table := strings.ToLower(ref[1]); tables[table] {
+				_ = db.AddError(fmt.Errorf("%w: %s", ErrUnscopedRawSQL, table))
+				return
+			}
+		}
+	}
+}
+
+func isOrgScoped(db *gorm.DB) bool {
+	s := db.Statement.Schema
+	return s != nil && reflect.PointerTo(s.ModelType).Implements(orgScopedType)
+}
+
+func assignTenant(db *gorm.DB) {
+	if db.Error != nil || !isOrgScoped(db) || tenant.IsUnscoped(db.Statement.Context) {
+		return
+	}
+	orgID, ok := tenant.FromContext(db.Statement.Context)
+	if !ok {
+		_ = db.AddError(ErrMissingTenant)
+		return
+	}
+	db.Statement.SetColumn("OrganizationID", orgID)
+}
+
+func scopeTenant(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || tenant.IsUnscoped(db.Statement.Context) {
+		return
+	}
+	orgID, ok := tenant.FromContext(db.Statement.Context)
+
+	switch {
+	case isOrgScoped(db):
+		if !ok {
+			_ = db.AddError(ErrMissingTenant)
+			return
+		}
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "organization_id"}, Value: orgID},
+		}})
+		scopeReader(db, orgID)
+	case db.Statement.Schema.ModelType == userType && ok:
+		db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+			clause.Expr{
+				SQL:  "? IN (SELECT user_id FROM memberships WHERE organization_id = ? AND deleted_at IS NULL)",
+				Vars: []any{clause.Column{Table: clause.CurrentTable, Name: "id"}, orgID},
+			},
+		}})
+	}
+}
+
+// scopeReader hides the projects, and their tasks, that the reader of the request cannot see.
+func scopeReader(db *gorm.DB, orgID uint) {
+	userID, ok := tenant.ReaderFromContext(db.Statement.Context)
+	if !ok {
+		return
+	}
+	var column string
+	switch db.Statement.Schema.ModelType {
+	case taskType:
+		column = "project_id"
+	case projectType:
+		column = "id"
+	default:
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.NamedExpr{SQL: guestVisible, Vars: []any{map[string]any{
+			"org":     orgID,
+			"user":    userID,
+			"project": clause.Column{Table: clause.CurrentTable, Name: column},
+		}}},
+	}})
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/tenant"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	postgresDrv "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB builds statements without ever reaching a database.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	sqlDB, err := sql.Open("pgx", "host=127.0.0.1")
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(postgresDrv.New(postgresDrv.Config{Conn: sqlDB}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	require.NoError(t, registerTenantScope(db))
+	return db
+}
+
+func TestTenantScope_ScopesOrgModels(t *testing.T) {
+	db := dryRunDB(t)
+	ctx := tenant.WithOrg(context.Background(), 7)
+
+	q := db.WithContext(ctx).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("status = ?", 1).Find(&[]domain.Task{})
+	})
+
+	assert.Contains(t, q, `"tasks"."organization_id" = 7`)
+}
+
+func TestTenantScope_MissingOrgFails(t *testing.T) {
+	db := dryRunDB(t)
+
+	err := db.WithContext(context.Background()).Find(&[]domain.Project{}).Error
+
+	assert.ErrorIs(t, err, ErrMissingTenant)
+}
+
+func TestTenantScope_UnscopedContext(t *testing.T) {
+	db := dryRunDB(t)
+	ctx := tenant.Unscoped(tenant.WithOrg(context.Background(), 7))
+
+	q := db.WithContext(ctx).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]domain.Task{})
+	})
+
+	assert.NotContains(t, q, "organization_id")
+}
+
+func TestTenantScope_UsersLimitedToMembers(t *testing.T) {
+	db := dryRunDB(t)
+
+	scoped := db.WithContext(tenant.WithOrg(context.Background(), 7)).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]domain.User{})
+	})
+	global := db.WithContext(context.Background()).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]domain.User{})
+	})
+
+	assert.Contains(t, scoped, "SELECT user_id FROM memberships WHERE organization_id = 7")
+	assert.NotContains(t, global, "memberships")
+}
+
+func TestTenantScope_AssignsOrgOnCreate(t *testing.T) {
+	db := dryRunDB(t)
+	task := &domain.Task{Name: "t"}
+
+	err := db.WithContext(tenant.WithOrg(context.Background(), 7)).Create(task).Error
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), task.OrganizationID)
+}
+
+func TestTenantScope_ReaderLimitsGuests(t *testing.T) {
+	db := dryRunDB(t)
+	ctx := tenant.WithReader(tenant.WithOrg(context.Background(), 7), 3)
+
+	tasks := db.WithContext(ctx).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]domain.Task{})
+	})
+	projects := db.WithContext(ctx).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]domain.Project{})
+	})
+	views := db.WithContext(ctx).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Find(&[]domain.View{})
+	})
+
+	assert.Contains(t, tasks, "organization_id = 7 AND user_id = 3 AND guest")
+	assert.Contains(t, tasks, `OR "tasks"."project_id" IN (SELECT project_id FROM project_members WHERE user_id = 3`)
+	assert.Contains(t, projects, `OR "projects"."id" IN (SELECT project_id FROM project_members WHERE user_id = 3`)
+	assert.NotContains(t, views, "guest")
+}
+
+func TestTenantScope_RawSQLOnScopedTablesFails(t *testing.T) {
+	db := dryRunDB(t)
+	ctx := tenant.WithOrg(context.Background(), 7)
+
+	exec := db.WithContext(ctx).Exec("DELETE FROM user_tasks WHERE user_id = ?", 1).Error
+	var ids []uint
+	scan := db.WithContext(ctx).Raw("SELECT id FROM share_links WHERE task_id = ?", 1).Scan(&ids).Error
+	find := db.WithContext(ctx).Raw(`SELECT * FROM "tasks"`).Find(&[]domain.Task{}).Error
+
+	assert.ErrorIs(t, exec, ErrUnscopedRawSQL)
+	assert.ErrorIs(t, scan, ErrUnscopedRawSQL)
+	assert.ErrorIs(t, find, ErrUnscopedRawSQL)
+}
+
+func TestTenantScope_RawSQLAllowedWhenUnscopedOrGlobal(t *testing.T) {
+	db := dryRunDB(t)
+	ctx := tenant.WithOrg(context.Background(), 7)
+
+	unscoped := db.WithContext(tenant.Unscoped(ctx)).Exec("DELETE FROM reminders WHERE task_id = ?", 1).Error
+	global := db.WithContext(ctx).Exec("UPDATE users SET name = ? WHERE id = ?", "n", 1).Error
+
+	assert.NoError(t, unscoped)
+	assert.NoError(t, global)
+}
+
+func TestTenantScope_SubqueriesAreScoped(t *testing.T) {
+	db := dryRunDB(t).WithContext(tenant.WithOrg(context.Background(), 7))
+	visible := db.Model(&domain.Task{}).Select("id")
+
+	stmt := db.Table("user_tasks").Where("task_id IN (?)", visible).Delete(map[string]any{}).Statement
+
+	assert.Contains(t, stmt.SQL.String(), `WHERE task_id IN (SELECT "id" FROM "tasks" WHERE "tasks"."organization_id" = $1`)
+	assert.Equal(t, uint(7), stmt.Vars[0])
+}
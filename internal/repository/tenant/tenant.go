@@ -0,0 +1,70 @@
+// Package tenant carries the active organization through request contexts so the storage
+// layer can scope every query to it.
+package tenant
+
+import "context"
+
+// ContextKey is the gin context key holding the active organization ID (uint).
+const ContextKey = "orgID"
+
+// ReaderKey is the gin context key holding the ID (uint) of the user a request acts for.
+const ReaderKey = "readerID"
+
+type orgKey struct{}
+
+type readerKey struct{}
+
+type unscopedKey struct{}
+
+// WithOrg returns a context scoped to orgID.
+func WithOrg(ctx context.Context, orgID uint) context.Context {
+	return context.WithValue(ctx, orgKey{}, orgID)
+}
+
+// WithReader returns a context whose queries only return what userID may read in the active
+// organization. Guests of the organization are limited to their own projects.
+func WithReader(ctx context.Context, userID uint) context.Context {
+	return context.WithValue(ctx, readerKey{}, userID)
+}
+
+// ReaderFromContext returns the user set either with WithReader or under ReaderKey on a gin
+// context.
+func ReaderFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	if id, ok := ctx.Value(readerKey{}).(uint); ok && id != 0 {
+		return id, true
+	}
+	if id, ok := ctx.Value(ReaderKey).(uint); ok && id != 0 {
+		return id, true
+	}
+	return 0, false
+}
+
+// Unscoped returns a context whose queries are not restricted to an organization. It is meant
+// for platform-wide operations such as administration and personal data handling.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// IsUnscoped reports whether tenant scoping was explicitly lifted for ctx.
+func IsUnscoped(ctx context.Context) bool {
+	v, _ := ctx.Value(unscopedKey{}).(bool)
+	return v
+}
+
+// FromContext returns the active organization, set either with WithOrg or under ContextKey
+// on a gin context.
+func FromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	if id, ok := ctx.Value(orgKey{}).(uint); ok && id != 0 {
+		return id, true
+	}
+	if id, ok := ctx.Value(ContextKey).(uint); ok && id != 0 {
+		return id, true
+	}
+	return 0, false
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/mailer"
+	"time"
+)
+
+// AdminService manages accounts platform wide, so every method lifts the tenant scope
+// of the request.
+type AdminService struct {
+	UserRepo    repository.UserRepo
+	SessionRepo repository.SessionRepo
+	AuthSrv     *AuthService
+	// Mailer sends the reset tokens of forced password resets to their users.
+	Mailer mailer.Mailer
+}
+
+func NewAdminService(userRepo repository.UserRepo, sessionRepo repository.SessionRepo, authSrv *AuthService, mail mailer.Mailer) *AdminService {
+	return &AdminService{
+		UserRepo:    userRepo,
+		SessionRepo: sessionRepo,
+		AuthSrv:     authSrv,
+		Mailer:      mail,
+	}
+}
+
+func (s *AdminService) ListUsers(ctx context.Context, filter dto.UserListFilter, limit, offset int) (*dto.UserListResp, error) {
+	ctx = tenant.Unscoped(ctx)
+	users, total, err := s.UserRepo.ListByFilter(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	userResps := make([]dto.AdminUserResp, len(users))
+	for i, u := range users {
+		userResps[i] = *userToAdminResp(&u)
+	}
+
+	return &dto.UserListResp{
+		Users:  userResps,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+func (s *AdminService) GetUser(ctx context.Context, userID uint) (*dto.AdminUserResp, error) {
+	ctx = tenant.Unscoped(ctx)
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+	return userToAdminResp(&user), nil
+}
+
+// SetUserActive deactivates or reactivates an account. Deactivation revokes all its tokens.
+func (s *AdminService) SetUserActive(ctx context.Context, userID uint, active bool, adminID uint) (*dto.AdminUserResp, error) {
+	if userID == adminID {
+		return nil, api_error.ErrCannotModifySelf
+	}
+	ctx = tenant.Unscoped(ctx)
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+
+	if active {
+		user.DeactivatedAt = nil
+	} else if user.DeactivatedAt == nil {
+		now := time.Now()
+		user.DeactivatedAt = &now
+	}
+	if err := s.UserRepo.UpdateByID(ctx, &user, []string{"deactivated_at"}); err != nil {
+		return nil, err
+	}
+
+	if !active {
+		if err := s.AuthSrv.RevokeUserSessions(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+	return userToAdminResp(&user), nil
+}
+
+// ForcePasswordReset clears the user's password and ends all current sessions, then
+// mails the user a reset token to set a new password with. The token only goes to the
+// user, so users without an email address cannot be reset.
+func (s *AdminService) ForcePasswordReset(ctx context.Context, userID uint) error {
+	ctx = tenant.Unscoped(ctx)
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return api_error.ErrUserNotFound
+	}
+	if user.Email == "" {
+		return api_error.ErrNoEmail
+	}
+
+	user.Password = ""
+	user.PasswordResetRequired = true
+	if err := s.UserRepo.UpdateByID(ctx, &user, []string{"password", "password_reset_required"}); err != nil {
+		return err
+	}
+	if err := s.AuthSrv.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+	reset, err := s.AuthSrv.IssuePasswordReset(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.Mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your password has been reset",
+		Body: fmt.Sprintf("An administrator has reset the password of your account %s and signed you out everywhere.\n\nChoose a new password with this reset token:\n%s\n\nThe token can be used once and expires on %s.\n",
+			user.Username, reset.ResetToken, reset.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+func (s *AdminService) ListUserSessions(ctx context.Context, userID uint) ([]dto.SessionResp, error) {
+	ctx = tenant.Unscoped(ctx)
+	if _, err := s.UserRepo.GetByID(ctx, userID); err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+	sessions, err := s.SessionRepo.ListByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.SessionResp, len(sessions))
+	for i, ss := range sessions {
+		resp[i] = *sessionToResp(&ss)
+	}
+	return resp, nil
+}
+
+func userToAdminResp(user *domain.User) *dto.AdminUserResp {
+	return &dto.AdminUserResp{
+		ID:                    user.ID,
+		Username:              user.Username,
+		Email:                 user.Email,
+		Role:                  user.Role.String(),
+		Active:                user.Active(),
+		PasswordResetRequired: user.PasswordResetRequired,
+		CreatedAt:             user.CreatedAt,
+	}
+}
+
+func sessionToResp(session *domain.UserSession) *dto.SessionResp {
+	return &dto.SessionResp{
+		ID:        session.ID,
+		Valid:     session.Valid,
+		UserAgent: session.UserAgent,
+		IP:        session.IP,
+		CreatedAt: session.CreatedAt,
+		UpdatedAt: session.UpdatedAt,
+		ExpiresAt: session.ExpiresAt,
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func setupAdminTest(t *testing.T) (*AdminService, *mockRepo.MockUserRepo, *mockRepo.MockSessionRepo) {
+	t.Helper()
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
+	t.Cleanup(mr.Close)
+	return NewAdminService(userRepo, sessionRepo, authSrv, &recordingMailer{}), userRepo, sessionRepo
+}
+
+func TestListUsers_Success(t *testing.T) {
+	svc, userRepo, _ := setupAdminTest(t)
+
+	active := true
+	filter := dto.UserListFilter{Username: "jo", Active: &active}
+	users := []domain.User{{Username: "john", Role: enum.RoleAdmin}, {Username: "joe"}}
+	userRepo.On("ListByFilter", mock.Anything, filter, 20, 0).Return(users, int64(2), nil)
+
+	resp, err := svc.ListUsers(context.Background(), filter, 20, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Total)
+	assert.Equal(t, "Admin", resp.Users[0].Role)
+	assert.True(t, resp.Users[1].Active)
+	userRepo.AssertExpectations(t)
+}
+
+func TestSetUserActive_DeactivateRevokesSessions(t *testing.T) {
+	svc, userRepo, sessionRepo := setupAdminTest(t)
+
+	user := domain.User{Username: "john"}
+	user.ID = 2
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(user, nil)
+	userRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.DeactivatedAt != nil
+	}), []string{"deactivated_at"}).Return(nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(2), true).
+		Return([]domain.UserSession{{UserID: 2, Valid: true, AccessJTI: "a", RefreshJTI: "r"}}, nil)
+	sessionRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"valid"}).Return(nil)
+
+	resp, err := svc.SetUserActive(context.Background(), 2, false, 1)
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Active)
+	userRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestSetUserActive_Reactivate(t *testing.T) {
+	svc, userRepo, sessionRepo := setupAdminTest(t)
+
+	deactivatedAt := time.Now()
+	user := domain.User{Username: "john", DeactivatedAt: &deactivatedAt}
+	user.ID = 2
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(user, nil)
+	userRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.DeactivatedAt == nil
+	}), []string{"deactivated_at"}).Return(nil)
+
+	resp, err := svc.SetUserActive(context.Background(), 2, true, 1)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Active)
+	sessionRepo.AssertNotCalled(t, "ListByUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetUserActive_Self(t *testing.T) {
+	svc, _, _ := setupAdminTest(t)
+
+	resp, err := svc.SetUserActive(context.Background(), 1, false, 1)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrCannotModifySelf, err)
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	svc, userRepo, sessionRepo := setupAdminTest(t)
+
+	user := domain.User{Username: "john", Email: "john@example.com", Password: "hash"}
+	user.ID = 2
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(user, nil)
+	userRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.PasswordResetRequired && u.Password == ""
+	}), []string{"password", "password_reset_required"}).Return(nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(2), true).Return([]domain.UserSession{}, nil)
+
+	err := svc.ForcePasswordReset(context.Background(), 2)
+
+	require.NoError(t, err)
+	userRepo.AssertExpectations(t)
+	sent := svc.Mailer.(*recordingMailer).sent
+	require.Len(t, sent, 1)
+	assert.Equal(t, "john@example.com", sent[0].To)
+
+	// The mailed token is the one that sets the new password.
+	token := strings.Split(sent[0].Body, "\n")[3]
+	userRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"password", "password_reset_required"}).Return(nil)
+	require.NoError(t, svc.AuthSrv.ResetPassword(context.Background(), dto.ResetPasswordReq{Token: token, NewPassword: "new-password"}))
+}
+
+func TestForcePasswordReset_LoginAsksForReset(t *testing.T) {
+	svc, userRepo, sessionRepo := setupAdminTest(t)
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.DefaultCost)
+	user := domain.User{Username: "john", Email: "john@example.com", Password: string(hashed)}
+	user.ID = 2
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(user, nil)
+	var stored domain.User
+	userRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"password", "password_reset_required"}).
+		Run(func(args mock.Arguments) { stored = *args.Get(1).(*domain.User) }).Return(nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(2), true).Return([]domain.UserSession{}, nil)
+	require.NoError(t, svc.ForcePasswordReset(context.Background(), 2))
+	userRepo.On("GetByField", mock.Anything, "username", "john").Return(stored, nil)
+
+	resp, err := svc.AuthSrv.LoginUser(context.Background(), dto.LoginUserReq{Username: "john", Password: "old-password"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrPasswordReset, err)
+}
+
+func TestForcePasswordReset_NoEmail(t *testing.T) {
+	svc, userRepo, _ := setupAdminTest(t)
+
+	userRepo.On("GetByID", mock.Anything, uint(2)).Return(domain.User{Username: "john"}, nil)
+
+	err := svc.ForcePasswordReset(context.Background(), 2)
+
+	assert.Equal(t, api_error.ErrNoEmail, err)
+	userRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListUserSessions_NotFound(t *testing.T) {
+	svc, userRepo, _ := setupAdminTest(t)
+
+	userRepo.On("GetByID", mock.Anything, uint(999)).Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	resp, err := svc.ListUserSessions(context.Background(), 999)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrUserNotFound, err)
+}
@@ -2,12 +2,21 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"graph-interview/internal/api/handlers/dto"
 	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
 	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/enum"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,32 +26,81 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	AccessTokenCookie  = "access_token"
+	RefreshTokenCookie = "refresh_token"
+	CSRFCookie         = "csrf_token"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+const passwordResetTTL = 24 * time.Hour
+
 type AuthService struct {
-	UserRepo  repository.UserRepo
-	JwtSecret []byte
-	redis     *redis.Client
+	UserRepo    repository.UserRepo
+	SessionRepo repository.SessionRepo
+	OrgRepo     repository.OrgRepo
+	Tx          repository.Transactor
+	JwtSecret   []byte
+	redis       *redis.Client
 }
 
-func NewAuthService(userRepo repository.UserRepo, redis *redis.Client, jwtSecret string) *AuthService {
+func NewAuthService(userRepo repository.UserRepo, sessionRepo repository.SessionRepo, orgRepo repository.OrgRepo, tx repository.Transactor, redis *redis.Client, jwtSecret string) *AuthService {
 
 	return &AuthService{
-		UserRepo:  userRepo,
-		redis:     redis,
-		JwtSecret: []byte(jwtSecret),
+		UserRepo:    userRepo,
+		SessionRepo: sessionRepo,
+		OrgRepo:     orgRepo,
+		Tx:          tx,
+		redis:       redis,
+		JwtSecret:   []byte(jwtSecret),
 	}
 }
 
+// Claims are the JWT claims issued by the service. OrgID is the organization the token
+// is scoped to.
+type Claims struct {
+	jwt.RegisteredClaims
+	OrgID uint `json:"org,omitempty"`
+}
+
 func (s *AuthService) LoginUser(ctx context.Context, req dto.LoginUserReq) (*dto.JWTResp, error) {
 	user, err := s.UserRepo.GetByField(ctx, "username", req.Username)
 	if err != nil {
 		return nil, api_error.ErrInvalidCredentials
 	}
 
+	// A forced reset clears the password, so no password could get past the check below.
+	if user.PasswordResetRequired {
+		return nil, api_error.ErrPasswordReset
+	}
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 		return nil, api_error.ErrInvalidCredentials
 	}
 
-	tokens, err := s.IssueTokens(fmt.Sprintf("%d", user.ID))
+	return s.StartSession(ctx, user)
+}
+
+// StartSession issues and persists a new token pair for an already authenticated user
+// and records it as a session. The tokens are scoped to the user's default organization.
+func (s *AuthService) StartSession(ctx context.Context, user domain.User) (*dto.JWTResp, error) {
+	if !user.Active() {
+		return nil, api_error.ErrUserInactive
+	}
+	orgID, err := s.defaultOrg(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.StartOrgSession(ctx, user, orgID)
+}
+
+// StartOrgSession is StartSession for an organization the caller already checked the
+// user belongs to.
+func (s *AuthService) StartOrgSession(ctx context.Context, user domain.User, orgID uint) (*dto.JWTResp, error) {
+	if !user.Active() {
+		return nil, api_error.ErrUserInactive
+	}
+
+	tokens, err := s.IssueOrgTokens(fmt.Sprintf("%d", user.ID), orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -51,11 +109,24 @@ func (s *AuthService) LoginUser(ctx context.Context, req dto.LoginUserReq) (*dto
 		return nil, err
 	}
 
-	return &dto.JWTResp{
-		Access:    tokens.Access,
-		Refresh:   tokens.Refresh,
-		ExpiresAt: time.Now().Add(tokens.ExpAcc),
-	}, nil
+	session := &domain.UserSession{
+		UserID:     user.ID,
+		Valid:      true,
+		AccessJTI:  tokens.JTIAcc,
+		RefreshJTI: tokens.JTIRef,
+		ExpiresAt:  time.Now().Add(tokens.ExpRef),
+	}
+	// Handlers pass the gin context down, which is where the client details live.
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		session.UserAgent = c.Request.UserAgent()
+		session.IP = c.ClientIP()
+	}
+	if _, err := s.SessionRepo.Create(ctx, session); err != nil {
+		_ = s.RevokeToken(ctx, tokens)
+		return nil, err
+	}
+
+	return tokensToResp(tokens), nil
 }
 
 func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string) (*dto.JWTResp, error) {
@@ -69,11 +140,33 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return nil, api_error.ErrTokenRevoked
 	}
 
-	// Delete old refresh token
-	s.redis.Del(ctx, "refresh:"+claims.ID)
+	session, err := s.SessionRepo.GetByField(ctx, "refresh_jti", claims.ID)
+	if err != nil || !session.Valid {
+		return nil, api_error.ErrTokenRevoked
+	}
+
+	// Delete old tokens
+	s.redis.Del(ctx, "refresh:"+claims.ID, "access:"+session.AccessJTI)
+
+	// Keep the organization unless the user was removed from it meanwhile
+	orgID := claims.OrgID
+	if orgID != 0 {
+		if _, err := s.OrgRepo.GetMembership(ctx, orgID, session.UserID); err != nil {
+			orgID = 0
+		}
+	}
+	if orgID == 0 {
+		user, err := s.UserRepo.GetByID(ctx, session.UserID)
+		if err != nil {
+			return nil, api_error.ErrTokenRevoked
+		}
+		if orgID, err = s.defaultOrg(ctx, user); err != nil {
+			return nil, err
+		}
+	}
 
 	// Issue new tokens
-	tokens, err := s.IssueTokens(claims.Subject)
+	tokens, err := s.IssueOrgTokens(claims.Subject, orgID)
 	if err != nil {
 		return nil, err
 	}
@@ -82,29 +175,40 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshTokenStr string)
 		return nil, err
 	}
 
-	return &dto.JWTResp{
-		Access:    tokens.Access,
-		Refresh:   tokens.Refresh,
-		ExpiresAt: time.Now().Add(tokens.ExpAcc),
-	}, nil
+	session.AccessJTI = tokens.JTIAcc
+	session.RefreshJTI = tokens.JTIRef
+	session.ExpiresAt = time.Now().Add(tokens.ExpRef)
+	if err := s.SessionRepo.UpdateByID(ctx, &session, []string{"access_jti", "refresh_jti", "expires_at"}); err != nil {
+		return nil, err
+	}
+
+	return tokensToResp(tokens), nil
 }
 
 type Tokens struct {
 	Access   string
 	Refresh  string
+	CSRF     string
 	JTIAcc   string
 	JTIRef   string
 	ExpAcc   time.Duration
 	ExpRef   time.Duration
 	UserID   string
+	OrgID    uint
 	Issuer   string
 	Audience string
 }
 
 func (s *AuthService) IssueTokens(userID string) (*Tokens, error) {
+	return s.IssueOrgTokens(userID, 0)
+}
+
+// IssueOrgTokens issues a token pair scoped to orgID; zero leaves the tokens unscoped.
+func (s *AuthService) IssueOrgTokens(userID string, orgID uint) (*Tokens, error) {
 	now := time.Now().UTC()
 	t := &Tokens{
 		UserID:   userID,
+		OrgID:    orgID,
 		JTIAcc:   uuid.NewString(),
 		JTIRef:   uuid.NewString(),
 		ExpAcc:   15 * time.Minute,
@@ -115,24 +219,32 @@ func (s *AuthService) IssueTokens(userID string) (*Tokens, error) {
 	ExpRefFromNow := now.Add(7 * 24 * time.Hour)
 	ExpAccFromNow := now.Add(15 * time.Minute)
 
-	acc := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Subject:   userID,
-		ID:        t.JTIAcc,
-		Issuer:    t.Issuer,
-		Audience:  jwt.ClaimStrings{t.Audience},
-		IssuedAt:  jwt.NewNumericDate(now),
-		ExpiresAt: jwt.NewNumericDate(ExpAccFromNow),
+	acc := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ID:        t.JTIAcc,
+			Issuer:    t.Issuer,
+			Audience:  jwt.ClaimStrings{t.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(ExpAccFromNow),
+		},
+		OrgID: orgID,
 	})
 
-	ref := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Subject:   userID,
-		ID:        t.JTIRef,
-		Issuer:    t.Issuer,
-		Audience:  jwt.ClaimStrings{t.Audience},
-		IssuedAt:  jwt.NewNumericDate(now),
-		ExpiresAt: jwt.NewNumericDate(ExpRefFromNow),
+	ref := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			ID:        t.JTIRef,
+			Issuer:    t.Issuer,
+			Audience:  jwt.ClaimStrings{t.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(ExpRefFromNow),
+		},
+		OrgID: orgID,
 	})
 
+	t.CSRF = s.csrfToken(userID)
+
 	var signErr error
 	t.Access, signErr = acc.SignedString(s.JwtSecret)
 	if signErr != nil {
@@ -155,16 +267,59 @@ func (s *AuthService) Persist(ctx context.Context, t *Tokens) error {
 	return nil
 }
 
-func (s *AuthService) SetAuthCookies(c *gin.Context, t *Tokens) {
+func tokensToResp(t *Tokens) *dto.JWTResp {
+	now := time.Now()
+	return &dto.JWTResp{
+		Access:           t.Access,
+		Refresh:          t.Refresh,
+		CSRFToken:        t.CSRF,
+		ExpiresAt:        now.Add(t.ExpAcc),
+		RefreshExpiresAt: now.Add(t.ExpRef),
+	}
+}
+
+func (s *AuthService) SetAuthCookies(c *gin.Context, resp *dto.JWTResp) {
 	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("access_token", t.Access, int(t.ExpAcc.Seconds()), "/", "", true, true)
-	c.SetCookie("refresh_token", t.Refresh, int(t.ExpRef.Seconds()), "/", "", true, true)
+	c.SetCookie(AccessTokenCookie, resp.Access, int(time.Until(resp.ExpiresAt).Seconds()), "/", "", true, true)
+	c.SetCookie(RefreshTokenCookie, resp.Refresh, int(time.Until(resp.RefreshExpiresAt).Seconds()), "/", "", true, true)
+	// Readable by scripts on purpose: clients echo it back in the CSRF header.
+	c.SetCookie(CSRFCookie, resp.CSRFToken, int(time.Until(resp.RefreshExpiresAt).Seconds()), "/", "", true, false)
 }
 
 func (s *AuthService) ClearAuthCookies(c *gin.Context) {
 	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("access_token", "", -1, "/", "", true, true)
-	c.SetCookie("refresh_token", "", -1, "/", "", true, true)
+	c.SetCookie(AccessTokenCookie, "", -1, "/", "", true, true)
+	c.SetCookie(RefreshTokenCookie, "", -1, "/", "", true, true)
+	c.SetCookie(CSRFCookie, "", -1, "/", "", true, false)
+}
+
+// csrfToken builds a double-submit token signed for the user, so a cookie planted
+// from a sibling domain for another account is rejected.
+func (s *AuthService) csrfToken(userID string) string {
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	encNonce := base64.RawURLEncoding.EncodeToString(nonce)
+	return encNonce + "." + s.csrfSignature(encNonce, userID)
+}
+
+func (s *AuthService) csrfSignature(nonce, userID string) string {
+	mac := hmac.New(sha256.New, s.JwtSecret)
+	mac.Write([]byte("csrf:" + nonce + ":" + userID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyCSRF checks that the CSRF header matches the CSRF cookie and was issued for userID.
+func (s *AuthService) VerifyCSRF(c *gin.Context, userID string) bool {
+	header := c.GetHeader(CSRFHeader)
+	cookie, _ := c.Cookie(CSRFCookie)
+	if header == "" || subtle.ConstantTimeCompare([]byte(header), []byte(cookie)) != 1 {
+		return false
+	}
+	nonce, sig, ok := strings.Cut(header, ".")
+	if !ok {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(s.csrfSignature(nonce, userID)))
 }
 
 func (s *AuthService) RevokeTokenByString(ctx context.Context, tokenStr string) error {
@@ -173,9 +328,74 @@ func (s *AuthService) RevokeTokenByString(ctx context.Context, tokenStr string)
 		return err
 	}
 	s.redis.Del(ctx, "access:"+claims.ID)
+
+	// Logging out ends the whole session, including its refresh token.
+	session, err := s.SessionRepo.GetByField(ctx, "access_jti", claims.ID)
+	if err != nil {
+		return nil
+	}
+	return s.invalidateSession(ctx, &session)
+}
+
+// RevokeUserSessions ends every active session of a user.
+func (s *AuthService) RevokeUserSessions(ctx context.Context, userID uint) error {
+	sessions, err := s.SessionRepo.ListByUser(ctx, userID, true)
+	if err != nil {
+		return err
+	}
+	for i := range sessions {
+		if err := s.invalidateSession(ctx, &sessions[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func (s *AuthService) invalidateSession(ctx context.Context, session *domain.UserSession) error {
+	if cmd := s.redis.Del(ctx, "access:"+session.AccessJTI, "refresh:"+session.RefreshJTI); cmd.Err() != nil {
+		return cmd.Err()
+	}
+	session.Valid = false
+	return s.SessionRepo.UpdateByID(ctx, session, []string{"valid"})
+}
+
+// IssuePasswordReset creates a single-use token allowing the user to choose a new password.
+func (s *AuthService) IssuePasswordReset(ctx context.Context, userID uint) (*dto.PasswordResetResp, error) {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := s.redis.Set(ctx, cache.PasswordResetKey(token), userID, passwordResetTTL).Err(); err != nil {
+		return nil, err
+	}
+	return &dto.PasswordResetResp{
+		ResetToken: token,
+		ExpiresAt:  time.Now().Add(passwordResetTTL),
+	}, nil
+}
+
+func (s *AuthService) ResetPassword(ctx context.Context, req dto.ResetPasswordReq) error {
+	userID, err := s.redis.GetDel(ctx, cache.PasswordResetKey(req.Token)).Uint64()
+	if err != nil {
+		return api_error.ErrInvalidResetToken
+	}
+	user, err := s.UserRepo.GetByID(ctx, uint(userID))
+	if err != nil {
+		return api_error.ErrInvalidResetToken
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	user.Password = string(hashed)
+	user.PasswordResetRequired = false
+	if err := s.UserRepo.UpdateByID(ctx, &user, []string{"password", "password_reset_required"}); err != nil {
+		return err
+	}
+	return s.RevokeUserSessions(ctx, user.ID)
+}
+
 func (s *AuthService) RevokeToken(ctx context.Context, t *Tokens) error {
 	if cmd := s.redis.Del(ctx, "access:"+t.JTIAcc); cmd.Err() != nil {
 		return cmd.Err()
@@ -186,10 +406,10 @@ func (s *AuthService) RevokeToken(ctx context.Context, t *Tokens) error {
 	return nil
 }
 
-func (s *AuthService) ParseToken(tokenStr string) (*jwt.RegisteredClaims, error) {
+func (s *AuthService) ParseToken(tokenStr string) (*Claims, error) {
 	parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
-	token, err := parser.ParseWithClaims(tokenStr, &jwt.RegisteredClaims{}, func(t *jwt.Token) (interface{}, error) {
+	token, err := parser.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, errors.New("unexpected signing method")
 		}
@@ -199,7 +419,7 @@ func (s *AuthService) ParseToken(tokenStr string) (*jwt.RegisteredClaims, error)
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims, ok := token.Claims.(*Claims)
 	if !ok || !token.Valid {
 		return nil, errors.New("invalid token")
 	}
@@ -210,3 +430,37 @@ func (s *AuthService) ParseToken(tokenStr string) (*jwt.RegisteredClaims, error)
 
 	return claims, nil
 }
+
+// defaultOrg returns the organization a new session is scoped to: the user's oldest
+// membership. Users without any get a personal organization.
+func (s *AuthService) defaultOrg(ctx context.Context, user domain.User) (uint, error) {
+	memberships, err := s.OrgRepo.ListMemberships(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+	if len(memberships) > 0 {
+		return memberships[0].OrganizationID, nil
+	}
+
+	// A user has one personal organization at most, so of two first logins racing here
+	// only one creates it and the other picks it up.
+	var orgID uint
+	err = s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		org := &domain.Organization{Name: user.Username, Personal: true, PersonalOwnerID: &user.ID}
+		created, err := s.OrgRepo.CreatePersonal(ctx, org)
+		if err != nil {
+			return err
+		}
+		if !created {
+			existing, err := s.OrgRepo.GetPersonal(ctx, user.ID)
+			orgID = existing.ID
+			return err
+		}
+		orgID = org.ID
+		return s.OrgRepo.AddMember(ctx, &domain.Membership{OrganizationID: orgID, UserID: user.ID, Role: enum.MemberOwner})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return orgID, nil
+}
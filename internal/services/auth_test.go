@@ -5,10 +5,15 @@ import (
 	"graph-interview/internal/api/handlers/dto"
 	api_error "graph-interview/internal/api/handlers/errors"
 	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
 	mockRepo "graph-interview/internal/repository/mock"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -16,7 +21,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func setupAuthTest(t *testing.T) (*AuthService, *mockRepo.MockUserRepo, *miniredis.Miniredis) {
+func setupAuthTest(t *testing.T) (*AuthService, *mockRepo.MockUserRepo, *mockRepo.MockSessionRepo, *miniredis.Miniredis) {
 	t.Helper()
 	mr, err := miniredis.Run()
 	if err != nil {
@@ -28,15 +33,26 @@ func setupAuthTest(t *testing.T) (*AuthService, *mockRepo.MockUserRepo, *minired
 	})
 
 	userRepo := new(mockRepo.MockUserRepo)
-	authSrv := NewAuthService(userRepo, rdb, "test-secret")
+	sessionRepo := new(mockRepo.MockSessionRepo)
+	authSrv := NewAuthService(userRepo, sessionRepo, memberOrgRepo(1), mockRepo.NoopTransactor{}, rdb, "test-secret")
 	if rdb == nil {
 		t.FailNow()
 	}
-	return authSrv, userRepo, mr
+	return authSrv, userRepo, sessionRepo, mr
+}
+
+// memberOrgRepo is an org repository in which every user belongs to orgID.
+func memberOrgRepo(orgID uint) *mockRepo.MockOrgRepo {
+	orgRepo := new(mockRepo.MockOrgRepo)
+	orgRepo.On("ListMemberships", mock.Anything, mock.Anything).
+		Return([]domain.Membership{{OrganizationID: orgID, Role: enum.MemberOwner}}, nil).Maybe()
+	orgRepo.On("GetMembership", mock.Anything, orgID, mock.Anything).
+		Return(domain.Membership{OrganizationID: orgID, Role: enum.MemberOwner}, nil).Maybe()
+	return orgRepo
 }
 
 func TestLoginUser_Success(t *testing.T) {
-	authSrv, userRepo, mr := setupAuthTest(t)
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
@@ -48,6 +64,9 @@ func TestLoginUser_Success(t *testing.T) {
 
 	userRepo.On("GetByField", mock.Anything, "username", "testuser").
 		Return(user, nil)
+	sessionRepo.On("Create", mock.Anything, mock.MatchedBy(func(s *domain.UserSession) bool {
+		return s.UserID == 1 && s.Valid && s.AccessJTI != "" && s.RefreshJTI != ""
+	})).Return(uint(1), nil)
 
 	req := dto.LoginUserReq{
 		Username: "testuser",
@@ -61,10 +80,11 @@ func TestLoginUser_Success(t *testing.T) {
 	assert.NotEmpty(t, resp.Access)
 	assert.NotEmpty(t, resp.Refresh)
 	userRepo.AssertExpectations(t)
+	sessionRepo.AssertExpectations(t)
 }
 
 func TestLoginUser_InvalidUsername(t *testing.T) {
-	authSrv, userRepo, mr := setupAuthTest(t)
+	authSrv, userRepo, _, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	userRepo.On("GetByField", mock.Anything, "username", "nonexistent").
@@ -84,7 +104,7 @@ func TestLoginUser_InvalidUsername(t *testing.T) {
 }
 
 func TestLoginUser_WrongPassword(t *testing.T) {
-	authSrv, userRepo, mr := setupAuthTest(t)
+	authSrv, userRepo, _, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	hashed, _ := bcrypt.GenerateFromPassword([]byte("correctpassword"), bcrypt.DefaultCost)
@@ -111,7 +131,7 @@ func TestLoginUser_WrongPassword(t *testing.T) {
 }
 
 func TestIssueTokens(t *testing.T) {
-	authSrv, _, mr := setupAuthTest(t)
+	authSrv, _, _, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	tokens, err := authSrv.IssueTokens("1")
@@ -124,7 +144,7 @@ func TestIssueTokens(t *testing.T) {
 }
 
 func TestParseToken(t *testing.T) {
-	authSrv, _, mr := setupAuthTest(t)
+	authSrv, _, _, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	tokens, err := authSrv.IssueTokens("42")
@@ -138,7 +158,7 @@ func TestParseToken(t *testing.T) {
 }
 
 func TestParseToken_Invalid(t *testing.T) {
-	authSrv, _, mr := setupAuthTest(t)
+	authSrv, _, _, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	claims, err := authSrv.ParseToken("invalid-token")
@@ -148,7 +168,7 @@ func TestParseToken_Invalid(t *testing.T) {
 }
 
 func TestPersistAndRevoke(t *testing.T) {
-	authSrv, _, mr := setupAuthTest(t)
+	authSrv, _, _, mr := setupAuthTest(t)
 	defer mr.Close()
 
 	tokens, err := authSrv.IssueTokens("1")
@@ -172,21 +192,233 @@ func TestPersistAndRevoke(t *testing.T) {
 }
 
 func TestRefreshToken_Success(t *testing.T) {
-	authSrv, _, mr := setupAuthTest(t)
+	authSrv, _, sessionRepo, mr := setupAuthTest(t)
 	defer mr.Close()
 
-	tokens, err := authSrv.IssueTokens("1")
+	tokens, err := authSrv.IssueOrgTokens("1", 1)
 	assert.NoError(t, err)
 
 	err = authSrv.Persist(context.Background(), tokens)
 	assert.NoError(t, err)
 
+	session := domain.UserSession{UserID: 1, Valid: true, AccessJTI: tokens.JTIAcc, RefreshJTI: tokens.JTIRef}
+	sessionRepo.On("GetByField", mock.Anything, "refresh_jti", tokens.JTIRef).Return(session, nil)
+	sessionRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(s *domain.UserSession) bool {
+		return s.RefreshJTI != tokens.JTIRef
+	}), []string{"access_jti", "refresh_jti", "expires_at"}).Return(nil)
+
 	resp, err := authSrv.RefreshToken(context.Background(), tokens.Refresh)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.NotEmpty(t, resp.Access)
 	assert.NotEmpty(t, resp.Refresh)
-	// Old refresh token should be gone
+	// Old tokens should be gone
 	assert.False(t, mr.Exists("refresh:"+tokens.JTIRef))
+	assert.False(t, mr.Exists("access:"+tokens.JTIAcc))
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestRefreshToken_FallsBackWhenRemovedFromOrg(t *testing.T) {
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	orgRepo := new(mockRepo.MockOrgRepo)
+	authSrv.OrgRepo = orgRepo
+	tokens, _ := authSrv.IssueOrgTokens("1", 9)
+	_ = authSrv.Persist(context.Background(), tokens)
+
+	user := domain.User{Username: "john"}
+	user.ID = 1
+	session := domain.UserSession{UserID: 1, Valid: true, AccessJTI: tokens.JTIAcc, RefreshJTI: tokens.JTIRef}
+	sessionRepo.On("GetByField", mock.Anything, "refresh_jti", tokens.JTIRef).Return(session, nil)
+	sessionRepo.On("UpdateByID", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(9), uint(1)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	orgRepo.On("ListMemberships", mock.Anything, uint(1)).Return([]domain.Membership{{OrganizationID: 2}}, nil)
+
+	resp, err := authSrv.RefreshToken(context.Background(), tokens.Refresh)
+	assert.NoError(t, err)
+
+	claims, err := authSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), claims.OrgID)
+}
+
+func TestStartSession_CreatesPersonalOrg(t *testing.T) {
+	authSrv, _, sessionRepo, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	orgRepo := new(mockRepo.MockOrgRepo)
+	authSrv.OrgRepo = orgRepo
+	user := domain.User{Username: "john"}
+	user.ID = 4
+	orgRepo.On("ListMemberships", mock.Anything, uint(4)).Return([]domain.Membership{}, nil)
+	orgRepo.On("CreatePersonal", mock.Anything, mock.MatchedBy(func(o *domain.Organization) bool {
+		return o.Personal && o.Name == "john" && *o.PersonalOwnerID == 4
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Organization).ID = 11
+	}).Return(true, nil)
+	orgRepo.On("AddMember", mock.Anything, mock.MatchedBy(func(m *domain.Membership) bool {
+		return m.OrganizationID == 11 && m.UserID == 4 && m.Role == enum.MemberOwner
+	})).Return(nil)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil)
+
+	resp, err := authSrv.StartSession(context.Background(), user)
+	assert.NoError(t, err)
+
+	claims, err := authSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(11), claims.OrgID)
+	orgRepo.AssertExpectations(t)
+}
+
+func TestStartSession_ReusesConcurrentPersonalOrg(t *testing.T) {
+	authSrv, _, sessionRepo, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	orgRepo := new(mockRepo.MockOrgRepo)
+	authSrv.OrgRepo = orgRepo
+	user := domain.User{Username: "john"}
+	user.ID = 4
+	// Another login created the personal organization after memberships were listed.
+	orgRepo.On("ListMemberships", mock.Anything, uint(4)).Return([]domain.Membership{}, nil)
+	orgRepo.On("CreatePersonal", mock.Anything, mock.AnythingOfType("*domain.Organization")).Return(false, nil)
+	existing := domain.Organization{Name: "john", Personal: true}
+	existing.ID = 12
+	orgRepo.On("GetPersonal", mock.Anything, uint(4)).Return(existing, nil)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil)
+
+	resp, err := authSrv.StartSession(context.Background(), user)
+	assert.NoError(t, err)
+
+	claims, err := authSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(12), claims.OrgID)
+	orgRepo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestRefreshToken_InvalidatedSession(t *testing.T) {
+	authSrv, _, sessionRepo, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	tokens, _ := authSrv.IssueTokens("1")
+	_ = authSrv.Persist(context.Background(), tokens)
+	sessionRepo.On("GetByField", mock.Anything, "refresh_jti", tokens.JTIRef).
+		Return(domain.UserSession{Valid: false}, nil)
+
+	resp, err := authSrv.RefreshToken(context.Background(), tokens.Refresh)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrTokenRevoked, err)
+}
+
+func TestLoginUser_Inactive(t *testing.T) {
+	authSrv, userRepo, _, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	deactivatedAt := time.Now()
+	user := domain.User{Username: "testuser", Password: string(hashed), DeactivatedAt: &deactivatedAt}
+	user.ID = 1
+	userRepo.On("GetByField", mock.Anything, "username", "testuser").Return(user, nil)
+
+	resp, err := authSrv.LoginUser(context.Background(), dto.LoginUserReq{Username: "testuser", Password: "password123"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrUserInactive, err)
+}
+
+func TestLoginUser_PasswordResetRequired(t *testing.T) {
+	authSrv, userRepo, _, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	user := domain.User{Username: "testuser", Password: string(hashed), PasswordResetRequired: true}
+	user.ID = 1
+	userRepo.On("GetByField", mock.Anything, "username", "testuser").Return(user, nil)
+
+	resp, err := authSrv.LoginUser(context.Background(), dto.LoginUserReq{Username: "testuser", Password: "password123"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrPasswordReset, err)
+}
+
+func TestRevokeUserSessions(t *testing.T) {
+	authSrv, _, sessionRepo, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	tokens, _ := authSrv.IssueTokens("1")
+	_ = authSrv.Persist(context.Background(), tokens)
+	sessionRepo.On("ListByUser", mock.Anything, uint(1), true).
+		Return([]domain.UserSession{{UserID: 1, Valid: true, AccessJTI: tokens.JTIAcc, RefreshJTI: tokens.JTIRef}}, nil)
+	sessionRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(s *domain.UserSession) bool {
+		return !s.Valid
+	}), []string{"valid"}).Return(nil)
+
+	err := authSrv.RevokeUserSessions(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.False(t, mr.Exists("access:"+tokens.JTIAcc))
+	assert.False(t, mr.Exists("refresh:"+tokens.JTIRef))
+	sessionRepo.AssertExpectations(t)
+}
+
+func TestResetPassword(t *testing.T) {
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	user := domain.User{Username: "testuser", PasswordResetRequired: true}
+	user.ID = 1
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	userRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return !u.PasswordResetRequired && bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("newpassword")) == nil
+	}), []string{"password", "password_reset_required"}).Return(nil)
+	sessionRepo.On("ListByUser", mock.Anything, uint(1), true).Return([]domain.UserSession{}, nil)
+
+	reset, err := authSrv.IssuePasswordReset(context.Background(), 1)
+	assert.NoError(t, err)
+
+	err = authSrv.ResetPassword(context.Background(), dto.ResetPasswordReq{Token: reset.ResetToken, NewPassword: "newpassword"})
+	assert.NoError(t, err)
+
+	// Tokens are single use
+	err = authSrv.ResetPassword(context.Background(), dto.ResetPasswordReq{Token: reset.ResetToken, NewPassword: "newpassword"})
+	assert.Equal(t, api_error.ErrInvalidResetToken, err)
+	userRepo.AssertExpectations(t)
+}
+
+func TestVerifyCSRF(t *testing.T) {
+	authSrv, _, _, mr := setupAuthTest(t)
+	defer mr.Close()
+
+	tokens, err := authSrv.IssueTokens("1")
+	assert.NoError(t, err)
+	other, err := authSrv.IssueTokens("2")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		valid  bool
+	}{
+		{"matching token", tokens.CSRF, tokens.CSRF, true},
+		{"missing header", tokens.CSRF, "", false},
+		{"header differs from cookie", tokens.CSRF, other.CSRF, false},
+		{"token issued for another user", other.CSRF, other.CSRF, false},
+		{"malformed token", "abc", "abc", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("POST", "/", nil)
+			c.Request.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			if tt.header != "" {
+				c.Request.Header.Set(CSRFHeader, tt.header)
+			}
+			assert.Equal(t, tt.valid, authSrv.VerifyCSRF(c, "1"))
+		})
+	}
 }
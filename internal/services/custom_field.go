@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxCustomFields bounds how many custom fields a project can have.
+	MaxCustomFields = 50
+	// maxTextValue bounds the length of text field values, in characters.
+	maxTextValue = 1000
+)
+
+// CustomFieldService manages the custom fields of projects. Anyone who can see a project
+// can list its fields; defining them takes the project's owner or an owner role.
+type CustomFieldService struct {
+	CustomFieldRepo repository.CustomFieldRepo
+	TaskRepo        repository.TaskRepo
+	ProjectRepo     repository.ProjectRepo
+	OrgRepo         repository.OrgRepo
+	Tx              repository.Transactor
+}
+
+func NewCustomFieldService(
+	customFieldRepo repository.CustomFieldRepo,
+	taskRepo repository.TaskRepo,
+	projectRepo repository.ProjectRepo,
+	orgRepo repository.OrgRepo,
+	tx repository.Transactor,
+) *CustomFieldService {
+	return &CustomFieldService{
+		CustomFieldRepo: customFieldRepo,
+		TaskRepo:        taskRepo,
+		ProjectRepo:     projectRepo,
+		OrgRepo:         orgRepo,
+		Tx:              tx,
+	}
+}
+
+func (s *CustomFieldService) CreateCustomField(ctx context.Context, projectID uint, req dto.CustomFieldReq, userID uint) (*dto.CustomFieldResp, error) {
+	if _, err := requireProjectRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberOwner); err != nil {
+		return nil, err
+	}
+	if !filter.ValidKey(req.Key) {
+		return nil, fmt.Errorf("%w: key must start with a lowercase letter and hold only lowercase letters, digits and _", api_error.ErrInvalidCustomField)
+	}
+	fieldType, ok := enum.ParseCustomFieldType(req.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown type %q", api_error.ErrInvalidCustomField, req.Type)
+	}
+	options, err := fieldOptions(fieldType, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := s.CustomFieldRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range fields {
+		if field.Key == req.Key {
+			return nil, api_error.ErrCustomFieldExists
+		}
+	}
+	if len(fields) >= MaxCustomFields {
+		return nil, fmt.Errorf("%w: a project can have at most %d custom fields", api_error.ErrInvalidCustomField, MaxCustomFields)
+	}
+
+	field := &domain.CustomField{
+		ProjectID: projectID,
+		Key:       req.Key,
+		Name:      strings.TrimSpace(req.Name),
+		Type:      fieldType,
+		Options:   options,
+		Required:  req.Required,
+	}
+	if _, err := s.CustomFieldRepo.Create(ctx, field); err != nil {
+		return nil, err
+	}
+	return customFieldToResp(field), nil
+}
+
+func (s *CustomFieldService) ListCustomFields(ctx context.Context, projectID, userID uint) ([]dto.CustomFieldResp, error) {
+	if _, err := requireProjectRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberViewer); err != nil {
+		return nil, err
+	}
+	fields, err := s.CustomFieldRepo.ListByProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.CustomFieldResp, len(fields))
+	for i, field := range fields {
+		resp[i] = *customFieldToResp(&field)
+	}
+	return resp, nil
+}
+
+// UpdateCustomField renames a field or changes its options or whether it is required. Tasks
+// keep their values; they are checked against the new definition when next changed.
+func (s *CustomFieldService) UpdateCustomField(ctx context.Context, projectID, fieldID uint, req dto.UpdateCustomFieldReq, userID uint) (*dto.CustomFieldResp, error) {
+	field, err := s.field(ctx, projectID, fieldID, userID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := fieldOptions(field.Type, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	field.Name = strings.TrimSpace(req.Name)
+	field.Options = options
+	field.Required = req.Required
+	if err := s.CustomFieldRepo.UpdateByID(ctx, &field, []string{"name", "options", "required"}); err != nil {
+		return nil, err
+	}
+	return customFieldToResp(&field), nil
+}
+
+// DeleteCustomField removes a field along with its values on the project's tasks.
+func (s *CustomFieldService) DeleteCustomField(ctx context.Context, projectID, fieldID, userID uint) error {
+	field, err := s.field(ctx, projectID, fieldID, userID)
+	if err != nil {
+		return err
+	}
+	return s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.TaskRepo.DropCustomField(ctx, projectID, field.Key); err != nil {
+			return err
+		}
+		return s.CustomFieldRepo.DeleteByID(ctx, field.ID)
+	})
+}
+
+// field loads a field of the project for userID to manage.
+func (s *CustomFieldService) field(ctx context.Context, projectID, fieldID, userID uint) (domain.CustomField, error) {
+	if _, err := requireProjectRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberOwner); err != nil {
+		return domain.CustomField{}, err
+	}
+	field, err := s.CustomFieldRepo.GetByID(ctx, fieldID)
+	if err != nil || field.ProjectID != projectID {
+		return domain.CustomField{}, api_error.ErrCustomFieldNotFound
+	}
+	return field, nil
+}
+
+// fieldOptions validates the options of a field of type t: select fields need at least one,
+// unique regardless of case, and other fields none.
+func fieldOptions(t enum.CustomFieldType, options []string) ([]string, error) {
+	if t != enum.FieldSelect {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("%w: only select fields have options", api_error.ErrInvalidCustomField)
+		}
+		return nil, nil
+	}
+	var cleaned []string
+	for _, option := range options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, fmt.Errorf("%w: options cannot be blank", api_error.ErrInvalidCustomField)
+		}
+		if _, ok := matchOption(cleaned, option); ok {
+			return nil, fmt.Errorf("%w: option %q is listed twice", api_error.ErrInvalidCustomField, option)
+		}
+		cleaned = append(cleaned, option)
+	}
+	if len(cleaned) == 0 {
+		return nil, fmt.Errorf("%w: select fields need options", api_error.ErrInvalidCustomField)
+	}
+	return cleaned, nil
+}
+
+// matchOption returns the option value names, ignoring case.
+func matchOption(options []string, value string) (string, bool) {
+	for _, option := range options {
+		if strings.EqualFold(option, value) {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// customFields checks values, the custom fields a task in projectID is to have, and
+// returns them as they are stored. Values equal to the task's current ones are kept as
+// they are, so changes to a field do not get in the way of unrelated edits. Required
+// fields must be set on new tasks, and cannot be cleared later.
+func (s *TaskService) customFields(ctx context.Context, projectID *uint, current, values map[string]any, create bool) (map[string]any, error) {
+	values = withoutNulls(values)
+	if projectID == nil || s.CustomFieldRepo == nil {
+		for key := range values {
+			return nil, fmt.Errorf("%w: unknown field %q", api_error.ErrInvalidFieldValue, key)
+		}
+		return nil, nil
+	}
+	if len(values) == 0 && len(current) == 0 && !create {
+		return nil, nil
+	}
+	fields, err := s.CustomFieldRepo.ListByProject(ctx, *projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := make(map[string]any, len(values))
+	for key, value := range values {
+		field, ok := findField(fields, key)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown field %q", api_error.ErrInvalidFieldValue, key)
+		}
+		if old, ok := current[key]; ok && reflect.DeepEqual(old, value) {
+			checked[key] = value
+			continue
+		}
+		if checked[key], err = s.fieldValue(ctx, field, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range fields {
+		_, had := current[field.Key]
+		if _, has := checked[field.Key]; field.Required && !has && (create || had) {
+			return nil, fmt.Errorf("%w: %s is required", api_error.ErrInvalidFieldValue, field.Key)
+		}
+	}
+	if len(checked) == 0 {
+		return nil, nil
+	}
+	return checked, nil
+}
+
+// carryCustomFields returns those of values a task moving to projectID can keep: the ones
+// the project has a field of the same key for that accepts them.
+func (s *TaskService) carryCustomFields(ctx context.Context, projectID *uint, values map[string]any) (map[string]any, error) {
+	if projectID == nil || s.CustomFieldRepo == nil || len(values) == 0 {
+		return nil, nil
+	}
+	fields, err := s.CustomFieldRepo.ListByProject(ctx, *projectID)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string]any, len(values))
+	for key, value := range values {
+		field, ok := findField(fields, key)
+		if !ok {
+			continue
+		}
+		if value, err := s.fieldValue(ctx, field, value); err == nil {
+			kept[key] = value
+		}
+	}
+	if len(kept) == 0 {
+		return nil, nil
+	}
+	return kept, nil
+}
+
+// fieldValue checks that value suits field and returns it as stored: select values take
+// the case of their option and user IDs are checked against the organization.
+func (s *TaskService) fieldValue(ctx context.Context, field domain.CustomField, value any) (any, error) {
+	invalid := func(want string) error {
+		return fmt.Errorf("%w: %s must be %s", api_error.ErrInvalidFieldValue, field.Key, want)
+	}
+	switch field.Type {
+	case enum.FieldText:
+		text, ok := value.(string)
+		if !ok || utf8.RuneCountInString(text) > maxTextValue {
+			return nil, invalid(fmt.Sprintf("text of at most %d characters", maxTextValue))
+		}
+		return text, nil
+	case enum.FieldNumber:
+		n, ok := value.(float64)
+		if !ok {
+			return nil, invalid("a number")
+		}
+		return n, nil
+	case enum.FieldDate:
+		date, ok := value.(string)
+		if _, err := time.Parse(time.DateOnly, date); !ok || err != nil {
+			return nil, invalid("a date such as 2026-01-05")
+		}
+		return date, nil
+	case enum.FieldSelect:
+		text, _ := value.(string)
+		option, ok := matchOption(field.Options, text)
+		if !ok {
+			return nil, invalid("one of " + strings.Join(field.Options, ", "))
+		}
+		return option, nil
+	case enum.FieldUser:
+		id, ok := value.(float64)
+		if !ok || id < 1 || id > math.MaxUint32 || id != math.Trunc(id) {
+			return nil, invalid("a user ID")
+		}
+		if orgID, ok := tenant.FromContext(ctx); ok {
+			if _, err := s.OrgRepo.GetMembership(ctx, orgID, uint(id)); err != nil {
+				return nil, invalid("a member of the organization")
+			}
+		}
+		return id, nil
+	default:
+		return nil, fmt.Errorf("%w: %s has an unknown type", api_error.ErrInvalidFieldValue, field.Key)
+	}
+}
+
+func findField(fields []domain.CustomField, key string) (domain.CustomField, bool) {
+	for _, field := range fields {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return domain.CustomField{}, false
+}
+
+// sameCustomFields reports whether a and b hold the same values, taking nil for empty.
+func sameCustomFields(a, b map[string]any) bool {
+	return (len(a) == 0 && len(b) == 0) || reflect.DeepEqual(a, b)
+}
+
+// withoutNulls returns values without the keys set to null, which leave a field unset.
+func withoutNulls(values map[string]any) map[string]any {
+	cleaned := make(map[string]any, len(values))
+	for key, value := range values {
+		if value != nil {
+			cleaned[key] = value
+		}
+	}
+	return cleaned
+}
+
+func customFieldToResp(field *domain.CustomField) *dto.CustomFieldResp {
+	return &dto.CustomFieldResp{
+		ID:        field.ID,
+		ProjectID: field.ProjectID,
+		Key:       field.Key,
+		Name:      field.Name,
+		Type:      field.Type.String(),
+		Options:   field.Options,
+		Required:  field.Required,
+	}
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCustomFieldTest() (*CustomFieldService, *mockRepo.MockCustomFieldRepo, *mockRepo.MockTaskRepo) {
+	customFieldRepo := new(mockRepo.MockCustomFieldRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	project := domain.Project{Name: "Project", OwnerID: 1}
+	project.ID = 3
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(project, nil)
+	return NewCustomFieldService(customFieldRepo, taskRepo, projectRepo, nil, mockRepo.NoopTransactor{}), customFieldRepo, taskRepo
+}
+
+// projectFields are the custom fields of project 3.
+func projectFields() []domain.CustomField {
+	return []domain.CustomField{
+		{ProjectID: 3, Key: "points", Name: "Points", Type: enum.FieldNumber},
+		{ProjectID: 3, Key: "tier", Name: "Tier", Type: enum.FieldSelect, Options: []string{"Gold", "Silver"}, Required: true},
+		{ProjectID: 3, Key: "launch", Name: "Launch", Type: enum.FieldDate},
+	}
+}
+
+func TestCreateCustomField(t *testing.T) {
+	svc, customFieldRepo, _ := setupCustomFieldTest()
+	customFieldRepo.On("ListByProject", mock.Anything, uint(3)).Return(projectFields(), nil)
+	customFieldRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.CustomField")).Return(uint(4), nil)
+
+	resp, err := svc.CreateCustomField(context.Background(), 3, dto.CustomFieldReq{
+		Key: "customer", Name: " Customer ", Type: "select", Options: []string{" Acme ", "Globex"},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Customer", resp.Name)
+	assert.Equal(t, "select", resp.Type)
+	assert.Equal(t, []string{"Acme", "Globex"}, resp.Options)
+}
+
+func TestCreateCustomField_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  dto.CustomFieldReq
+		err  error
+	}{
+		{"bad key", dto.CustomFieldReq{Key: "Story Points", Name: "Points", Type: "number"}, api_error.ErrInvalidCustomField},
+		{"unknown type", dto.CustomFieldReq{Key: "due", Name: "Due", Type: "datetime"}, api_error.ErrInvalidCustomField},
+		{"select without options", dto.CustomFieldReq{Key: "size", Name: "Size", Type: "select"}, api_error.ErrInvalidCustomField},
+		{"duplicate option", dto.CustomFieldReq{Key: "size", Name: "Size", Type: "select", Options: []string{"S", "s"}}, api_error.ErrInvalidCustomField},
+		{"options on text", dto.CustomFieldReq{Key: "note", Name: "Note", Type: "text", Options: []string{"a"}}, api_error.ErrInvalidCustomField},
+		{"duplicate key", dto.CustomFieldReq{Key: "points", Name: "Points", Type: "number"}, api_error.ErrCustomFieldExists},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, customFieldRepo, _ := setupCustomFieldTest()
+			customFieldRepo.On("ListByProject", mock.Anything, uint(3)).Return(projectFields(), nil)
+
+			_, err := svc.CreateCustomField(context.Background(), 3, tt.req, 1)
+
+			assert.ErrorIs(t, err, tt.err)
+			customFieldRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCreateCustomField_NotOwner(t *testing.T) {
+	svc, customFieldRepo, _ := setupCustomFieldTest()
+
+	_, err := svc.CreateCustomField(context.Background(), 3, dto.CustomFieldReq{Key: "points", Name: "Points", Type: "number"}, 2)
+
+	assert.ErrorIs(t, err, api_error.ErrForbidden)
+	customFieldRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateCustomField(t *testing.T) {
+	svc, customFieldRepo, _ := setupCustomFieldTest()
+	field := projectFields()[1]
+	field.ID = 5
+	customFieldRepo.On("GetByID", mock.Anything, uint(5)).Return(field, nil)
+	customFieldRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.CustomField"), []string{"name", "options", "required"}).Return(nil)
+
+	resp, err := svc.UpdateCustomField(context.Background(), 3, 5, dto.UpdateCustomFieldReq{Name: "Plan", Options: []string{"Gold", "Bronze"}}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Plan", resp.Name)
+	assert.Equal(t, []string{"Gold", "Bronze"}, resp.Options)
+	assert.False(t, resp.Required)
+
+	// Fields of other projects are not found through this one.
+	other := domain.Project{Name: "Other", OwnerID: 1}
+	other.ID = 4
+	svc.ProjectRepo.(*mockRepo.MockProjectRepo).On("GetByID", mock.Anything, uint(4)).Return(other, nil)
+	_, err = svc.UpdateCustomField(context.Background(), 4, 5, dto.UpdateCustomFieldReq{Name: "Plan"}, 1)
+	assert.ErrorIs(t, err, api_error.ErrCustomFieldNotFound)
+}
+
+func TestDeleteCustomField(t *testing.T) {
+	svc, customFieldRepo, taskRepo := setupCustomFieldTest()
+	field := projectFields()[0]
+	field.ID = 5
+	customFieldRepo.On("GetByID", mock.Anything, uint(5)).Return(field, nil)
+	customFieldRepo.On("DeleteByID", mock.Anything, uint(5)).Return(nil)
+	taskRepo.On("DropCustomField", mock.Anything, uint(3), "points").Return(nil)
+
+	require.NoError(t, svc.DeleteCustomField(context.Background(), 3, 5, 1))
+	taskRepo.AssertExpectations(t)
+	customFieldRepo.AssertExpectations(t)
+}
+
+func setupCustomValueTest() (*TaskService, *mockRepo.MockTaskRepo) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	customFieldRepo := new(mockRepo.MockCustomFieldRepo)
+	project := domain.Project{Name: "Project", OwnerID: 1}
+	project.ID = 3
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(project, nil)
+	customFieldRepo.On("ListByProject", mock.Anything, uint(3)).Return(projectFields(), nil)
+	return NewTaskService(taskRepo, projectRepo, nil, mockRepo.NoopTransactor{}, nil, nil, customFieldRepo), taskRepo
+}
+
+func TestCreateTask_CustomFields(t *testing.T) {
+	svc, taskRepo := setupCustomValueTest()
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{ProjectID: 3, Status: enum.Created}).Return("", nil)
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(uint(1), nil)
+	projectID := uint(3)
+
+	resp, err := svc.CreateTask(context.Background(), dto.CreateTaskReq{
+		Name:         "Task",
+		ProjectID:    &projectID,
+		CustomFields: map[string]any{"points": 3.0, "tier": "gold", "launch": nil},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"points": 3.0, "tier": "Gold"}, resp.CustomFields)
+}
+
+func TestCustomFields_Invalid(t *testing.T) {
+	projectID := uint(3)
+	tests := []struct {
+		name    string
+		current map[string]any
+		values  map[string]any
+		create  bool
+	}{
+		{"required on create", nil, map[string]any{"points": 3.0}, true},
+		{"required once set", map[string]any{"tier": "Gold"}, map[string]any{}, false},
+		{"unknown field", nil, map[string]any{"tier": "Gold", "owner": 1.0}, true},
+		{"not a number", nil, map[string]any{"tier": "Gold", "points": "3"}, true},
+		{"not an option", nil, map[string]any{"tier": "Bronze"}, true},
+		{"not a date", nil, map[string]any{"tier": "Gold", "launch": "next week"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := setupCustomValueTest()
+
+			_, err := svc.customFields(context.Background(), &projectID, tt.current, tt.values, tt.create)
+
+			assert.ErrorIs(t, err, api_error.ErrInvalidFieldValue)
+		})
+	}
+}
+
+func TestCustomFields_KeepsUnchangedValues(t *testing.T) {
+	svc, _ := setupCustomValueTest()
+	projectID := uint(3)
+	// Bronze is no longer an option, but the task had it already.
+	current := map[string]any{"tier": "Bronze"}
+
+	values, err := svc.customFields(context.Background(), &projectID, current, map[string]any{"tier": "Bronze", "points": 2.0}, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"tier": "Bronze", "points": 2.0}, values)
+
+	_, err = svc.customFields(context.Background(), nil, nil, map[string]any{"points": 2.0}, true)
+	assert.ErrorIs(t, err, api_error.ErrInvalidFieldValue)
+}
+
+func TestCarryCustomFields(t *testing.T) {
+	svc, _ := setupCustomValueTest()
+	projectID := uint(3)
+
+	values, err := svc.carryCustomFields(context.Background(), &projectID, map[string]any{
+		"points": "high", "tier": "silver", "launch": "2026-03-01", "owner": 4.0,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"tier": "Silver", "launch": "2026-03-01"}, values)
+}
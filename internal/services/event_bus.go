@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/logger"
+	redis_pkg "graph-interview/pkg/redis"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultOutboxInterval     = 5 * time.Second
+	defaultOutboxLockTTL      = time.Minute
+	defaultOutboxBatchSize    = 200
+	defaultOutboxMaxAttempts  = 10
+	defaultOutboxRetryBackoff = 10 * time.Second
+	defaultOutboxRetention    = 7 * 24 * time.Hour
+	defaultOutboxStream       = "stream:events"
+	defaultOutboxStreamMaxLen = 100000
+)
+
+// EventHandler reacts to a domain event relayed from the outbox. Events may be seen again
+// after a failure, so handlers should tolerate duplicates.
+type EventHandler func(ctx context.Context, event *domain.Event) error
+
+type subscriber struct {
+	name   string
+	handle EventHandler
+}
+
+// TaskChange is the payload of task events.
+type TaskChange struct {
+	Task *dto.TaskResp `json:"task,omitempty"`
+	// PreviousStatus is the name of the workflow status the task left, set when it changed.
+	PreviousStatus string `json:"previous_status,omitempty"`
+	// AssigneeID is set when the change assigned a user to the task.
+	AssigneeID *uint `json:"assignee_id,omitempty"`
+	// Mentions holds the usernames the change newly @mentioned in the task's description.
+	Mentions []string `json:"mentions,omitempty"`
+}
+
+// UserChange is the payload of user events.
+type UserChange struct {
+	Username string `json:"username"`
+}
+
+// EventBus records domain events in the outbox as part of the transaction making the change,
+// and relays them to its subscribers afterwards. Instances share a Redis lock, so only one of
+// them relays at a time and events reach subscribers in order.
+type EventBus struct {
+	OutboxRepo  repository.OutboxRepo
+	subscribers []subscriber
+	kick        chan struct{}
+	lock        *redis_pkg.Lock
+	cfg         cfg.OutboxCfg
+}
+
+func NewEventBus(outboxRepo repository.OutboxRepo, rdb *redis.Client, outboxCfg cfg.OutboxCfg) *EventBus {
+	if outboxCfg.Interval <= 0 {
+		outboxCfg.Interval = defaultOutboxInterval
+	}
+	if outboxCfg.LockTTL <= 0 {
+		outboxCfg.LockTTL = defaultOutboxLockTTL
+	}
+	if outboxCfg.BatchSize <= 0 {
+		outboxCfg.BatchSize = defaultOutboxBatchSize
+	}
+	if outboxCfg.MaxAttempts <= 0 {
+		outboxCfg.MaxAttempts = defaultOutboxMaxAttempts
+	}
+	if outboxCfg.RetryBackoff <= 0 {
+		outboxCfg.RetryBackoff = defaultOutboxRetryBackoff
+	}
+	if outboxCfg.Retention <= 0 {
+		outboxCfg.Retention = defaultOutboxRetention
+	}
+	if outboxCfg.Stream == "" {
+		outboxCfg.Stream = defaultOutboxStream
+	}
+	if outboxCfg.StreamMaxLen <= 0 {
+		outboxCfg.StreamMaxLen = defaultOutboxStreamMaxLen
+	}
+	return &EventBus{
+		OutboxRepo: outboxRepo,
+		kick:       make(chan struct{}, 1),
+		lock:       redis_pkg.NewLock(rdb, cache.OutboxLockKey, outboxCfg.LockTTL),
+		cfg:        outboxCfg,
+	}
+}
+
+// Subscribe registers handle under name, which must stay the same across releases: it
+// records which subscribers are done with an event. Subscribe before Run.
+func (b *EventBus) Subscribe(name string, handle EventHandler) {
+	b.subscribers = append(b.subscribers, subscriber{name: name, handle: handle})
+}
+
+// Emit writes an event about aggregateID to the outbox, in the transaction carried by ctx
+// when there is one. A nil bus drops the event.
+func (b *EventBus) Emit(ctx context.Context, eventType string, aggregateID uint, actorID *uint, payload any) error {
+	if b == nil {
+		return nil
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	orgID, _ := tenant.FromContext(ctx)
+	_, err = b.OutboxRepo.Create(ctx, &domain.Event{
+		OrganizationID: orgID,
+		Type:           eventType,
+		AggregateID:    aggregateID,
+		ActorID:        actorID,
+		Payload:        string(data),
+		NextAttemptAt:  time.Now(),
+	})
+	return err
+}
+
+// Kick asks the relay to run now rather than at its next tick.
+func (b *EventBus) Kick() {
+	select {
+	case b.kick <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays events every interval, and whenever kicked, until ctx is done.
+func (b *EventBus) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.kick:
+		}
+		if err := b.tick(ctx); err != nil {
+			logger.Logger.Warn("event relay failed", "err", err)
+		}
+	}
+}
+
+func (b *EventBus) tick(ctx context.Context) error {
+	ok, err := b.lock.Acquire(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	defer func() {
+		_ = b.lock.Release(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, b.cfg.LockTTL/2)
+	defer cancel()
+	now := time.Now()
+	if _, err := b.Relay(ctx, now); err != nil {
+		return err
+	}
+	return b.OutboxRepo.DeletePublished(tenant.Unscoped(ctx), now.Add(-b.cfg.Retention))
+}
+
+// Relay hands the pending events to the subscribers that have not processed them yet and
+// returns how many events were published. An event failing in some subscriber is retried
+// for those alone, until MaxAttempts is reached. Until then the later events about the
+// same task or user wait for it, so subscribers see each aggregate's events in order.
+func (b *EventBus) Relay(ctx context.Context, now time.Time) (int, error) {
+	events, err := b.OutboxRepo.ListPending(tenant.Unscoped(ctx), now, b.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	type aggregate struct {
+		kind string
+		id   uint
+	}
+	blocked := map[aggregate]bool{}
+	published := 0
+	for i := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		event := &events[i]
+		key := aggregate{event.AggregateType(), event.AggregateID}
+		if blocked[key] {
+			continue
+		}
+
+		err := b.dispatch(ctx, event)
+		fields := []string{"handled", "attempts", "last_error"}
+		event.Attempts++
+		if err == nil {
+			event.LastError = ""
+			event.PublishedAt = &now
+			fields = append(fields, "published_at")
+			published++
+		} else {
+			event.LastError = err.Error()
+			if event.Attempts >= b.cfg.MaxAttempts {
+				event.FailedAt = &now
+				fields = append(fields, "failed_at")
+			} else {
+				event.NextAttemptAt = now.Add(time.Duration(event.Attempts) * b.cfg.RetryBackoff)
+				fields = append(fields, "next_attempt_at")
+				blocked[key] = true
+			}
+			logger.Logger.Warn("event handling failed", "event", event.ID, "type", event.Type, "err", err)
+		}
+		// Record the outcome even when the batch ran out of time meanwhile.
+		if err := b.OutboxRepo.UpdateByID(tenant.Unscoped(context.WithoutCancel(ctx)), event, fields); err != nil {
+			return published, err
+		}
+	}
+	return published, nil
+}
+
+// dispatch runs the subscribers still owing event in the event's organization, and returns
+// the first error met.
+func (b *EventBus) dispatch(ctx context.Context, event *domain.Event) error {
+	if event.OrganizationID != 0 {
+		ctx = tenant.WithOrg(ctx, event.OrganizationID)
+	}
+	var failed error
+	for _, sub := range b.subscribers {
+		if event.HandledBy(sub.name) {
+			continue
+		}
+		if err := sub.handle(ctx, event); err != nil {
+			if failed == nil {
+				failed = err
+			}
+			continue
+		}
+		event.Handled = append(event.Handled, sub.name)
+	}
+	return failed
+}
+
+// StreamPublisher appends every event to a Redis stream, for consumers outside the API.
+type StreamPublisher struct {
+	rdb    *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewStreamPublisher(rdb *redis.Client, outboxCfg cfg.OutboxCfg) *StreamPublisher {
+	if outboxCfg.Stream == "" {
+		outboxCfg.Stream = defaultOutboxStream
+	}
+	if outboxCfg.StreamMaxLen <= 0 {
+		outboxCfg.StreamMaxLen = defaultOutboxStreamMaxLen
+	}
+	return &StreamPublisher{rdb: rdb, stream: outboxCfg.Stream, maxLen: outboxCfg.StreamMaxLen}
+}
+
+func (p *StreamPublisher) Handle(ctx context.Context, event *domain.Event) error {
+	values := map[string]any{
+		"id":              event.ID,
+		"type":            event.Type,
+		"organization_id": event.OrganizationID,
+		"aggregate_id":    event.AggregateID,
+		"payload":         event.Payload,
+		"created_at":      event.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if event.ActorID != nil {
+		values["actor_id"] = strconv.FormatUint(uint64(*event.ActorID), 10)
+	}
+	return p.rdb.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.stream,
+		MaxLen: p.maxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+// withEvents runs write, which emits events through bus, in one transaction and wakes the
+// relay once it is committed. Without a bus, write runs on its own.
+func withEvents(ctx context.Context, tx repository.Transactor, bus *EventBus, write func(ctx context.Context) error) error {
+	if bus == nil {
+		return write(ctx)
+	}
+	if err := tx.WithinTx(ctx, write); err != nil {
+		return err
+	}
+	bus.Kick()
+	return nil
+}
+
+// taskEvent turns a task domain event into its stream and webhook form. It reports false
+// for other events.
+func taskEvent(event *domain.Event) (dto.TaskEvent, bool, error) {
+	switch event.Type {
+	case domain.EventTaskCreated, domain.EventTaskUpdated, domain.EventTaskDeleted:
+	default:
+		return dto.TaskEvent{}, false, nil
+	}
+	var change TaskChange
+	if err := event.Decode(&change); err != nil {
+		return dto.TaskEvent{}, false, err
+	}
+	return dto.TaskEvent{
+		Type:   event.Type,
+		TaskID: event.AggregateID,
+		Task:   change.Task,
+		At:     event.CreatedAt,
+	}, true, nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupBusTest returns a bus whose outbox records the emitted events in the returned slice.
+func setupBusTest(t *testing.T) (*EventBus, *mockRepo.MockOutboxRepo, *[]domain.Event) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	outboxRepo := new(mockRepo.MockOutboxRepo)
+	emitted := &[]domain.Event{}
+	outboxRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Event")).
+		Run(func(args mock.Arguments) {
+			event := *args.Get(1).(*domain.Event)
+			event.ID = uint(len(*emitted) + 1)
+			*emitted = append(*emitted, event)
+		}).Return(uint(1), nil).Maybe()
+	return NewEventBus(outboxRepo, client, cfg.OutboxCfg{MaxAttempts: 2}), outboxRepo, emitted
+}
+
+func TestEventBus_Emit(t *testing.T) {
+	bus, _, emitted := setupBusTest(t)
+	actor := uint(1)
+
+	err := bus.Emit(tenant.WithOrg(context.Background(), 7), domain.EventUserCreated, 3, &actor, UserChange{Username: "alice"})
+
+	require.NoError(t, err)
+	require.Len(t, *emitted, 1)
+	event := (*emitted)[0]
+	assert.Equal(t, uint(7), event.OrganizationID)
+	assert.Equal(t, domain.EventUserCreated, event.Type)
+	assert.Equal(t, uint(3), event.AggregateID)
+	assert.JSONEq(t, `{"username":"alice"}`, event.Payload)
+}
+
+func TestEventBus_EmitNilBus(t *testing.T) {
+	var bus *EventBus
+	assert.NoError(t, bus.Emit(context.Background(), domain.EventTaskDeleted, 1, nil, TaskChange{}))
+}
+
+func TestEventBus_RelayPublishes(t *testing.T) {
+	bus, outboxRepo, _ := setupBusTest(t)
+	now := time.Now()
+	var seen []string
+	bus.Subscribe("a", func(ctx context.Context, event *domain.Event) error {
+		orgID, _ := tenant.FromContext(ctx)
+		assert.Equal(t, uint(7), orgID)
+		seen = append(seen, "a")
+		return nil
+	})
+	bus.Subscribe("b", func(ctx context.Context, event *domain.Event) error {
+		seen = append(seen, "b")
+		return nil
+	})
+
+	event := domain.Event{OrganizationID: 7, Type: domain.EventTaskCreated, Payload: `{}`}
+	outboxRepo.On("ListPending", mock.Anything, now, defaultOutboxBatchSize).Return([]domain.Event{event}, nil)
+	outboxRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.PublishedAt != nil && e.Attempts == 1 && assert.ObjectsAreEqual([]string{"a", "b"}, e.Handled)
+	}), []string{"handled", "attempts", "last_error", "published_at"}).Return(nil)
+
+	published, err := bus.Relay(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+	assert.Equal(t, []string{"a", "b"}, seen)
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestEventBus_RelayRetriesFailedSubscribers(t *testing.T) {
+	bus, outboxRepo, _ := setupBusTest(t)
+	now := time.Now()
+	calls := 0
+	bus.Subscribe("done", func(ctx context.Context, event *domain.Event) error {
+		t.Fatal("handled subscriber called again")
+		return nil
+	})
+	bus.Subscribe("flaky", func(ctx context.Context, event *domain.Event) error {
+		calls++
+		return errors.New("unavailable")
+	})
+
+	event := domain.Event{Type: domain.EventTaskCreated, Payload: `{}`, Handled: []string{"done"}}
+	outboxRepo.On("ListPending", mock.Anything, now, defaultOutboxBatchSize).Return([]domain.Event{event}, nil).Once()
+	outboxRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Attempts == 1 && e.LastError == "unavailable" && e.NextAttemptAt.Equal(now.Add(defaultOutboxRetryBackoff))
+	}), []string{"handled", "attempts", "last_error", "next_attempt_at"}).Return(nil).Once()
+
+	published, err := bus.Relay(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, published)
+
+	// The last attempt gives up on the event.
+	event.Attempts = 1
+	outboxRepo.On("ListPending", mock.Anything, now, defaultOutboxBatchSize).Return([]domain.Event{event}, nil).Once()
+	outboxRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool {
+		return e.Attempts == 2 && e.FailedAt != nil
+	}), []string{"handled", "attempts", "last_error", "failed_at"}).Return(nil).Once()
+
+	_, err = bus.Relay(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	outboxRepo.AssertExpectations(t)
+}
+
+func TestEventBus_RelayHoldsBackAggregateBehindRetry(t *testing.T) {
+	bus, outboxRepo, _ := setupBusTest(t)
+	now := time.Now()
+	var seen []uint
+	bus.Subscribe("flaky", func(ctx context.Context, event *domain.Event) error {
+		seen = append(seen, event.ID)
+		if event.ID == 1 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	events := []domain.Event{
+		{ID: 1, Type: domain.EventTaskCreated, AggregateID: 5, Payload: `{}`},
+		{ID: 2, Type: domain.EventTaskUpdated, AggregateID: 5, Payload: `{}`},
+		{ID: 3, Type: domain.EventUserCreated, AggregateID: 5, Payload: `{}`},
+		{ID: 4, Type: domain.EventTaskUpdated, AggregateID: 6, Payload: `{}`},
+	}
+	outboxRepo.On("ListPending", mock.Anything, now, defaultOutboxBatchSize).Return(events, nil)
+	outboxRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool { return e.ID == 1 }), mock.Anything).Return(nil)
+	outboxRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool { return e.PublishedAt != nil }), mock.Anything).Return(nil)
+
+	published, err := bus.Relay(context.Background(), now)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+	// Task 5 waits for its first event; user 5 is another aggregate.
+	assert.Equal(t, []uint{1, 3, 4}, seen)
+	outboxRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.MatchedBy(func(e *domain.Event) bool { return e.ID == 2 }), mock.Anything)
+}
+
+func TestEventBus_RelaysTaskEventsToStream(t *testing.T) {
+	bus, outboxRepo, emitted := setupBusTest(t)
+	eventSrv := setupEventTest(t, 10)
+	bus.Subscribe("realtime", eventSrv.HandleEvent)
+
+	taskRepo := new(mockRepo.MockTaskRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewTaskService(taskRepo, nil, orgRepo, mockRepo.NoopTransactor{}, bus, nil, nil)
+	ctx, cancel := context.WithCancel(tenant.WithOrg(context.Background(), 7))
+	defer cancel()
+
+	events, err := eventSrv.Subscribe(ctx, 7, 0, nil)
+	require.NoError(t, err)
+
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Created}).Return("", nil)
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(uint(3), nil)
+	taskRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Task{Name: "Task"}, nil)
+	taskRepo.On("DeleteByID", mock.Anything, uint(3)).Return(nil)
+
+	_, err = svc.CreateTask(ctx, dto.CreateTaskReq{Name: "Task"}, 1)
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteTask(ctx, 3, 1, 0))
+	require.Len(t, *emitted, 2)
+
+	now := time.Now()
+	outboxRepo.On("ListPending", mock.Anything, now, defaultOutboxBatchSize).Return(*emitted, nil)
+	outboxRepo.On("UpdateByID", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	published, err := bus.Relay(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, published)
+
+	created := receive(t, events)
+	assert.Equal(t, domain.EventTaskCreated, created.Type)
+	assert.Equal(t, "Task", created.Task.Name)
+	deleted := receive(t, events)
+	assert.Equal(t, domain.EventTaskDeleted, deleted.Type)
+	assert.Equal(t, uint(3), deleted.TaskID)
+	assert.Nil(t, deleted.Task)
+}
+
+func TestStreamPublisher_Handle(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	publisher := NewStreamPublisher(client, cfg.OutboxCfg{})
+	actor := uint(2)
+
+	err := publisher.Handle(context.Background(), &domain.Event{
+		ID: 5, OrganizationID: 7, Type: domain.EventTaskCreated, AggregateID: 3, ActorID: &actor, Payload: `{}`,
+	})
+	require.NoError(t, err)
+
+	messages, err := client.XRange(context.Background(), defaultOutboxStream, "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, domain.EventTaskCreated, messages[0].Values["type"])
+	assert.Equal(t, "3", messages[0].Values["aggregate_id"])
+	assert.Equal(t, "2", messages[0].Values["actor_id"])
+}
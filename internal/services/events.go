@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/tenant"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// EventResync tells a reconnecting client that it missed events and should reload.
+const EventResync = "resync"
+
+const (
+	defaultEventBacklog   = 1000
+	defaultEventHeartbeat = 25 * time.Second
+)
+
+// publishEvent numbers an event, keeps it in the replay log and publishes it in one step, so
+// every subscriber sees the events of an organization in ID order whichever replica sent them.
+var publishEvent = redis.NewScript(`
+local id = redis.call('INCR', KEYS[1])
+local msg = id .. ' ' .. ARGV[1]
+redis.call('ZADD', KEYS[2], id, msg)
+redis.call('ZREMRANGEBYRANK', KEYS[2], 0, -tonumber(ARGV[2]) - 1)
+redis.call('PUBLISH', KEYS[3], msg)
+return id
+`)
+
+// EventService fans task changes out to the clients streaming /v1/events, across replicas,
+// through Redis pub/sub. Events are per organization: every member may read its tasks,
+// except guests, who only see the tasks of their projects.
+type EventService struct {
+	OrgRepo     repository.OrgRepo
+	ProjectRepo repository.ProjectRepo
+	rdb         *redis.Client
+	cfg         cfg.EventCfg
+}
+
+func NewEventService(rdb *redis.Client, eventCfg cfg.EventCfg, orgRepo repository.OrgRepo, projectRepo repository.ProjectRepo) *EventService {
+	if eventCfg.Backlog <= 0 {
+		eventCfg.Backlog = defaultEventBacklog
+	}
+	if eventCfg.Heartbeat <= 0 {
+		eventCfg.Heartbeat = defaultEventHeartbeat
+	}
+	return &EventService{OrgRepo: orgRepo, ProjectRepo: projectRepo, rdb: rdb, cfg: eventCfg}
+}
+
+// Heartbeat is how often idle streams should be kept alive.
+func (s *EventService) Heartbeat() time.Duration {
+	return s.cfg.Heartbeat
+}
+
+// Publish sends event to the subscribers of orgID and returns the ID it was given.
+func (s *EventService) Publish(ctx context.Context, orgID uint, event dto.TaskEvent) (uint64, error) {
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	keys := []string{cache.EventSeqKey(orgID), cache.EventLogKey(orgID), cache.EventChannel(orgID)}
+	return publishEvent.Run(ctx, s.rdb, keys, payload, s.cfg.Backlog).Uint64()
+}
+
+// HandleEvent streams task events relayed from the outbox.
+func (s *EventService) HandleEvent(ctx context.Context, event *domain.Event) error {
+	taskEvent, ok, err := taskEvent(event)
+	if !ok || err != nil {
+		return err
+	}
+	_, err = s.Publish(ctx, event.OrganizationID, taskEvent)
+	return err
+}
+
+// Visibility returns which task events of orgID userID may receive: nil lets members see them
+// all, guests only get the tasks of the projects they belong to.
+func (s *EventService) Visibility(ctx context.Context, orgID, userID uint) (func(dto.TaskEvent) bool, error) {
+	membership, err := s.OrgRepo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, api_error.ErrOrgNotFound
+	}
+	if !membership.Guest {
+		return nil, nil
+	}
+	ids, err := s.ProjectRepo.ListIDsByMember(tenant.WithOrg(ctx, orgID), userID)
+	if err != nil {
+		return nil, err
+	}
+	projects := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		projects[id] = true
+	}
+	return func(event dto.TaskEvent) bool {
+		return event.Task != nil && event.Task.ProjectID != nil && projects[*event.Task.ProjectID]
+	}, nil
+}
+
+// Subscribe streams the events of orgID that pass visible, all of them when it is nil, until
+// ctx is done. With a lastID it first replays what came after it, or sends a resync event
+// when that is no longer in the log.
+func (s *EventService) Subscribe(ctx context.Context, orgID uint, lastID uint64, visible func(dto.TaskEvent) bool) (<-chan dto.TaskEvent, error) {
+	sub := s.rdb.Subscribe(ctx, cache.EventChannel(orgID))
+	// Subscribing before reading the log means nothing published in between is lost.
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+	backlog, err := s.backlog(ctx, orgID, lastID)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	out := make(chan dto.TaskEvent, 16)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+
+		last := lastID
+		send := func(event dto.TaskEvent) bool {
+			if event.Type != EventResync {
+				if event.ID <= last {
+					return true
+				}
+				last = event.ID
+				if visible != nil && !visible(event) {
+					return true
+				}
+			}
+			select {
+			case out <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for _, event := range backlog {
+			if !send(event) {
+				return
+			}
+		}
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				event, err := parseEvent(msg.Payload)
+				if err != nil {
+					continue
+				}
+				if !send(event) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// backlog returns the logged events after lastID.
+func (s *EventService) backlog(ctx context.Context, orgID uint, lastID uint64) ([]dto.TaskEvent, error) {
+	if lastID == 0 {
+		return nil, nil
+	}
+	seq, err := s.rdb.Get(ctx, cache.EventSeqKey(orgID)).Uint64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	if lastID >= seq {
+		// Either nothing was missed or the IDs started over, as after losing Redis data.
+		if lastID > seq {
+			return []dto.TaskEvent{{Type: EventResync, At: time.Now()}}, nil
+		}
+		return nil, nil
+	}
+
+	msgs, err := s.rdb.ZRangeByScore(ctx, cache.EventLogKey(orgID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatUint(lastID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]dto.TaskEvent, 0, len(msgs)+1)
+	for _, msg := range msgs {
+		if event, err := parseEvent(msg); err == nil {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 || events[0].ID != lastID+1 {
+		events = append([]dto.TaskEvent{{Type: EventResync, At: time.Now()}}, events...)
+	}
+	return events, nil
+}
+
+// parseEvent reads a logged or published event, which is its ID, a space and its JSON.
+func parseEvent(msg string) (dto.TaskEvent, error) {
+	var event dto.TaskEvent
+	id, payload, ok := strings.Cut(msg, " ")
+	if !ok {
+		return event, errors.New("malformed event")
+	}
+	var err error
+	if event.ID, err = strconv.ParseUint(id, 10, 64); err != nil {
+		return event, err
+	}
+	err = json.Unmarshal([]byte(payload), &event)
+	return event, err
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEventTest(t *testing.T, backlog int) *EventService {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	return NewEventService(client, cfg.EventCfg{Backlog: backlog}, new(mockRepo.MockOrgRepo), new(mockRepo.MockProjectRepo))
+}
+
+func receive(t *testing.T, events <-chan dto.TaskEvent) dto.TaskEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+		return dto.TaskEvent{}
+	}
+}
+
+func TestEvents_PublishAndSubscribe(t *testing.T) {
+	svc := setupEventTest(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := svc.Subscribe(ctx, 7, 0, nil)
+	require.NoError(t, err)
+
+	id, err := svc.Publish(ctx, 7, dto.TaskEvent{Type: domain.EventTaskCreated, TaskID: 3})
+	require.NoError(t, err)
+	_, err = svc.Publish(ctx, 8, dto.TaskEvent{Type: domain.EventTaskCreated, TaskID: 4})
+	require.NoError(t, err)
+	_, err = svc.Publish(ctx, 7, dto.TaskEvent{Type: domain.EventTaskDeleted, TaskID: 3})
+	require.NoError(t, err)
+
+	first := receive(t, events)
+	assert.Equal(t, id, first.ID)
+	assert.Equal(t, domain.EventTaskCreated, first.Type)
+	second := receive(t, events)
+	assert.Equal(t, id+1, second.ID)
+	assert.Equal(t, domain.EventTaskDeleted, second.Type)
+}
+
+func TestEvents_ReplayAfterLastID(t *testing.T) {
+	svc := setupEventTest(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := uint(1); i <= 3; i++ {
+		_, err := svc.Publish(ctx, 7, dto.TaskEvent{Type: domain.EventTaskUpdated, TaskID: i})
+		require.NoError(t, err)
+	}
+
+	events, err := svc.Subscribe(ctx, 7, 1, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint(2), receive(t, events).TaskID)
+	assert.Equal(t, uint(3), receive(t, events).TaskID)
+
+	_, err = svc.Publish(ctx, 7, dto.TaskEvent{Type: domain.EventTaskUpdated, TaskID: 4})
+	require.NoError(t, err)
+	event := receive(t, events)
+	assert.Equal(t, uint64(4), event.ID)
+	assert.Equal(t, uint(4), event.TaskID)
+}
+
+func TestEvents_ResyncWhenTrimmed(t *testing.T) {
+	svc := setupEventTest(t, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for i := uint(1); i <= 5; i++ {
+		_, err := svc.Publish(ctx, 7, dto.TaskEvent{Type: domain.EventTaskUpdated, TaskID: i})
+		require.NoError(t, err)
+	}
+
+	events, err := svc.Subscribe(ctx, 7, 1, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, EventResync, receive(t, events).Type)
+	assert.Equal(t, uint64(4), receive(t, events).ID)
+	assert.Equal(t, uint64(5), receive(t, events).ID)
+}
+
+func TestEvents_ResyncWhenIDsStartedOver(t *testing.T) {
+	svc := setupEventTest(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := svc.Subscribe(ctx, 7, 42, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, EventResync, receive(t, events).Type)
+}
+
+func TestEvents_GuestsOnlySeeTheirProjects(t *testing.T) {
+	svc := setupEventTest(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	svc.OrgRepo.(*mockRepo.MockOrgRepo).On("GetMembership", mock.Anything, uint(7), uint(2)).
+		Return(domain.Membership{Role: enum.MemberViewer, Guest: true}, nil)
+	svc.ProjectRepo.(*mockRepo.MockProjectRepo).On("ListIDsByMember", mock.Anything, uint(2)).Return([]uint{3}, nil)
+
+	visible, err := svc.Visibility(ctx, 7, 2)
+	require.NoError(t, err)
+	events, err := svc.Subscribe(ctx, 7, 0, visible)
+	require.NoError(t, err)
+
+	own, other := uint(3), uint(4)
+	for _, event := range []dto.TaskEvent{
+		{Type: domain.EventTaskCreated, TaskID: 1, Task: &dto.TaskResp{ProjectID: &other}},
+		{Type: domain.EventTaskCreated, TaskID: 2},
+		{Type: domain.EventTaskDeleted, TaskID: 1},
+		{Type: domain.EventTaskCreated, TaskID: 3, Task: &dto.TaskResp{ProjectID: &own}},
+	} {
+		_, err := svc.Publish(ctx, 7, event)
+		require.NoError(t, err)
+	}
+
+	event := receive(t, events)
+	assert.Equal(t, uint(3), event.TaskID)
+	assert.Equal(t, uint64(4), event.ID)
+}
+
+func TestEvents_MembersSeeEverything(t *testing.T) {
+	svc := setupEventTest(t, 10)
+	svc.OrgRepo.(*mockRepo.MockOrgRepo).On("GetMembership", mock.Anything, uint(7), uint(2)).
+		Return(domain.Membership{Role: enum.MemberViewer}, nil)
+
+	visible, err := svc.Visibility(context.Background(), 7, 2)
+
+	assert.NoError(t, err)
+	assert.Nil(t, visible)
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/mailer"
+	"net/url"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const defaultInvitationTTL = 7 * 24 * time.Hour
+
+type InvitationService struct {
+	InvitationRepo repository.InvitationRepo
+	OrgRepo        repository.OrgRepo
+	ProjectRepo    repository.ProjectRepo
+	UserRepo       repository.UserRepo
+	Tx             repository.Transactor
+	AuthSrv        *AuthService
+	Mailer         mailer.Mailer
+	cfg            cfg.InvitationCfg
+}
+
+func NewInvitationService(
+	invitationRepo repository.InvitationRepo,
+	orgRepo repository.OrgRepo,
+	projectRepo repository.ProjectRepo,
+	userRepo repository.UserRepo,
+	tx repository.Transactor,
+	authSrv *AuthService,
+	mail mailer.Mailer,
+	invitationCfg cfg.InvitationCfg,
+) *InvitationService {
+	if invitationCfg.TTL <= 0 {
+		invitationCfg.TTL = defaultInvitationTTL
+	}
+	return &InvitationService{
+		InvitationRepo: invitationRepo,
+		OrgRepo:        orgRepo,
+		ProjectRepo:    projectRepo,
+		UserRepo:       userRepo,
+		Tx:             tx,
+		AuthSrv:        authSrv,
+		Mailer:         mail,
+		cfg:            invitationCfg,
+	}
+}
+
+// Invite emails an invitation to join orgID, or one of its projects. Only owners of the
+// organization or of the project may invite.
+func (s *InvitationService) Invite(ctx context.Context, orgID uint, req dto.InviteReq, userID uint) (*dto.InvitationResp, error) {
+	// The organization invited to need not be the active one.
+	ctx = tenant.WithOrg(ctx, orgID)
+	role, err := memberRole(ctx, s.OrgRepo, s.ProjectRepo, orgID, userID, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if role < enum.MemberOwner {
+		return nil, api_error.ErrForbidden
+	}
+	org, err := s.OrgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, api_error.ErrOrgNotFound
+	}
+
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	invitation := &domain.Invitation{
+		OrganizationID: orgID,
+		ProjectID:      req.ProjectID,
+		Email:          req.Email,
+		Role:           req.Role,
+		TokenHash:      hashInvitationToken(token),
+		InvitedByID:    userID,
+		ExpiresAt:      time.Now().Add(s.cfg.TTL),
+	}
+	// Sending inside the transaction drops the invitation again when the mail cannot go out.
+	err = s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.InvitationRepo.Create(ctx, invitation); err != nil {
+			return err
+		}
+		return s.Mailer.Send(ctx, mailer.Message{
+			To:      req.Email,
+			Subject: fmt.Sprintf("You have been invited to %s", org.Name),
+			Body: fmt.Sprintf("You have been invited to join %s as %s.\n\nAccept or decline the invitation here:\n%s\n\nThe link expires on %s.\n",
+				org.Name, req.Role, s.acceptLink(token), invitation.ExpiresAt.Format(time.RFC1123)),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invitation.Organization = &org
+	return invitationToResp(invitation), nil
+}
+
+// ListInvitations returns the pending invitations sent to the user's email address.
+func (s *InvitationService) ListInvitations(ctx context.Context, userID uint) ([]dto.InvitationResp, error) {
+	user, err := s.UserRepo.GetByID(tenant.Unscoped(ctx), userID)
+	if err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+	if user.Email == "" {
+		return []dto.InvitationResp{}, nil
+	}
+
+	invitations, err := s.InvitationRepo.ListPendingByEmail(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]dto.InvitationResp, len(invitations))
+	for i := range invitations {
+		resps[i] = *invitationToResp(&invitations[i])
+	}
+	return resps, nil
+}
+
+// Accept makes userID a member as described by the invitation. The token is the proof of
+// invitation, so it may be accepted from an account with a different email address.
+func (s *InvitationService) Accept(ctx context.Context, token string, userID uint) (*dto.OrgResp, error) {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	var membership domain.Membership
+	err = s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		membership, err = s.join(ctx, &invitation, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.OrgRepo.GetByID(ctx, invitation.OrganizationID)
+	if err != nil {
+		return nil, api_error.ErrOrgNotFound
+	}
+	return &dto.OrgResp{
+		ID:       org.ID,
+		Name:     org.Name,
+		Personal: org.Personal,
+		Role:     membership.Role.String(),
+	}, nil
+}
+
+// AcceptWithSignup creates an account for the invited email address, accepts the invitation
+// with it and starts a session in the organization.
+func (s *InvitationService) AcceptWithSignup(ctx context.Context, token string, req dto.InvitationSignupReq) (*dto.JWTResp, error) {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.UserRepo.GetByField(ctx, "username", req.Username); !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, api_error.UsernameExists(req.Username)
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := domain.User{
+		Username: req.Username,
+		Email:    invitation.Email,
+		// The signup link was mailed to this address.
+		EmailVerified: true,
+		Password:      string(hashed),
+	}
+	err = s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.UserRepo.Create(ctx, &user); err != nil {
+			return err
+		}
+		_, err := s.join(ctx, &invitation, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.AuthSrv.StartOrgSession(ctx, user, invitation.OrganizationID)
+}
+
+// Decline answers the invitation without joining.
+func (s *InvitationService) Decline(ctx context.Context, token string) error {
+	invitation, err := s.pending(ctx, token)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	invitation.DeclinedAt = &now
+	return s.InvitationRepo.UpdateByID(ctx, &invitation, []string{"declined_at"})
+}
+
+func (s *InvitationService) pending(ctx context.Context, token string) (domain.Invitation, error) {
+	invitation, err := s.InvitationRepo.GetByTokenHash(ctx, hashInvitationToken(token))
+	if err != nil || !invitation.Pending() {
+		return domain.Invitation{}, api_error.ErrInvitationNotFound
+	}
+	return invitation, nil
+}
+
+// join adds userID to the invitation's organization and marks it accepted. Organization
+// invitations never lower an existing role and turn guests into full members. Project
+// invitations make the user a guest of the organization if they were not a member yet and
+// grant the role on the project only.
+func (s *InvitationService) join(ctx context.Context, invitation *domain.Invitation, userID uint) (domain.Membership, error) {
+	orgRole := invitation.Role
+	if invitation.ProjectID != nil {
+		orgRole = enum.MemberViewer
+	}
+
+	membership, err := s.OrgRepo.GetMembership(ctx, invitation.OrganizationID, userID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		membership = domain.Membership{
+			OrganizationID: invitation.OrganizationID,
+			UserID:         userID,
+			Role:           orgRole,
+			Guest:          invitation.ProjectID != nil,
+		}
+		if err := s.OrgRepo.AddMember(ctx, &membership); err != nil {
+			return membership, err
+		}
+	case err != nil:
+		return membership, err
+	case membership.Role < orgRole, membership.Guest && invitation.ProjectID == nil:
+		membership.Role = max(membership.Role, orgRole)
+		membership.Guest = false
+		if err := s.OrgRepo.UpdateMembership(ctx, &membership, []string{"role", "guest"}); err != nil {
+			return membership, err
+		}
+	}
+
+	if invitation.ProjectID != nil {
+		if _, err := s.ProjectRepo.GetMember(ctx, *invitation.ProjectID, userID); errors.Is(err, gorm.ErrRecordNotFound) {
+			err = s.ProjectRepo.AddMember(ctx, &domain.ProjectMember{
+				ProjectID: *invitation.ProjectID,
+				UserID:    userID,
+				Role:      invitation.Role,
+			})
+			if err != nil {
+				return membership, err
+			}
+		} else if err != nil {
+			return membership, err
+		}
+	}
+
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	return membership, s.InvitationRepo.UpdateByID(ctx, invitation, []string{"accepted_at"})
+}
+
+func (s *InvitationService) acceptLink(token string) string {
+	return s.cfg.AcceptURL + "?token=" + url.QueryEscape(token)
+}
+
+func hashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func invitationToResp(invitation *domain.Invitation) *dto.InvitationResp {
+	resp := &dto.InvitationResp{
+		ID:             invitation.ID,
+		OrganizationID: invitation.OrganizationID,
+		ProjectID:      invitation.ProjectID,
+		Email:          invitation.Email,
+		Role:           invitation.Role.String(),
+		ExpiresAt:      invitation.ExpiresAt,
+	}
+	if invitation.Organization != nil {
+		resp.OrganizationName = invitation.Organization.Name
+	}
+	return resp
+}
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/pkg/mailer"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type recordingMailer struct {
+	sent []mailer.Message
+	err  error
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mailer.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+type invitationMocks struct {
+	invitationRepo *mockRepo.MockInvitationRepo
+	orgRepo        *mockRepo.MockOrgRepo
+	projectRepo    *mockRepo.MockProjectRepo
+	userRepo       *mockRepo.MockUserRepo
+	sessionRepo    *mockRepo.MockSessionRepo
+	mailer         *recordingMailer
+}
+
+func setupInvitationTest(t *testing.T) (*InvitationService, invitationMocks) {
+	t.Helper()
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
+	t.Cleanup(mr.Close)
+	m := invitationMocks{
+		invitationRepo: new(mockRepo.MockInvitationRepo),
+		orgRepo:        new(mockRepo.MockOrgRepo),
+		projectRepo:    new(mockRepo.MockProjectRepo),
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		mailer:         &recordingMailer{},
+	}
+	svc := NewInvitationService(m.invitationRepo, m.orgRepo, m.projectRepo, m.userRepo, mockRepo.NoopTransactor{}, authSrv, m.mailer,
+		cfg.InvitationCfg{AcceptURL: "http://app/invitations"})
+	return svc, m
+}
+
+func pendingInvitation(projectID *uint, role enum.MemberRole) domain.Invitation {
+	invitation := domain.Invitation{
+		OrganizationID: 7,
+		ProjectID:      projectID,
+		Email:          "jane@example.com",
+		Role:           role,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+	invitation.ID = 4
+	return invitation
+}
+
+func TestInvite_SendsTokenByMail(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	org := domain.Organization{Name: "Acme"}
+	org.ID = 7
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberOwner}, nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(org, nil)
+	var stored *domain.Invitation
+	m.invitationRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Invitation")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.Invitation)
+	}).Return(uint(4), nil)
+
+	resp, err := svc.Invite(context.Background(), 7, dto.InviteReq{Email: "jane@example.com", Role: enum.MemberEditor}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Editor", resp.Role)
+	assert.Equal(t, "Acme", resp.OrganizationName)
+	assert.Len(t, m.mailer.sent, 1)
+	assert.Equal(t, "jane@example.com", m.mailer.sent[0].To)
+
+	// The mail carries the token, the database only its hash.
+	link := m.mailer.sent[0].Body[strings.Index(m.mailer.sent[0].Body, "http://app/invitations"):]
+	u, err := url.Parse(strings.Fields(link)[0])
+	assert.NoError(t, err)
+	token := u.Query().Get("token")
+	assert.NotEmpty(t, token)
+	assert.Equal(t, hashInvitationToken(token), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, token)
+}
+
+func TestInvite_RequiresOwner(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+
+	_, err := svc.Invite(context.Background(), 7, dto.InviteReq{Email: "jane@example.com"}, 1)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	m.invitationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestInvite_ProjectOwnerMayInvite(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	projectID := uint(3)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	m.projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{OwnerID: 1}, nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+	m.invitationRepo.On("Create", mock.Anything, mock.Anything).Return(uint(4), nil)
+
+	resp, err := svc.Invite(context.Background(), 7, dto.InviteReq{Email: "jane@example.com", ProjectID: &projectID}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &projectID, resp.ProjectID)
+}
+
+func TestInvite_MailFailure(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+	m.mailer.err = errors.New("relay down")
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberOwner}, nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+	m.invitationRepo.On("Create", mock.Anything, mock.Anything).Return(uint(4), nil)
+
+	_, err := svc.Invite(context.Background(), 7, dto.InviteReq{Email: "jane@example.com"}, 1)
+
+	assert.EqualError(t, err, "relay down")
+}
+
+func TestAcceptInvitation_JoinsOrg(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, hashInvitationToken("tok")).Return(pendingInvitation(nil, enum.MemberEditor), nil)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+	m.orgRepo.On("AddMember", mock.Anything, &domain.Membership{OrganizationID: 7, UserID: 2, Role: enum.MemberEditor}).Return(nil)
+	m.invitationRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(i *domain.Invitation) bool {
+		return i.AcceptedAt != nil
+	}), []string{"accepted_at"}).Return(nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+
+	resp, err := svc.Accept(context.Background(), "tok", 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Editor", resp.Role)
+	m.orgRepo.AssertExpectations(t)
+	m.invitationRepo.AssertExpectations(t)
+}
+
+func TestAcceptInvitation_ProjectRoleKeepsOrgRole(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	projectID := uint(3)
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(pendingInvitation(&projectID, enum.MemberOwner), nil)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	m.projectRepo.On("GetMember", mock.Anything, projectID, uint(2)).Return(domain.ProjectMember{}, gorm.ErrRecordNotFound)
+	m.projectRepo.On("AddMember", mock.Anything, &domain.ProjectMember{ProjectID: projectID, UserID: 2, Role: enum.MemberOwner}).Return(nil)
+	m.invitationRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"accepted_at"}).Return(nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+
+	resp, err := svc.Accept(context.Background(), "tok", 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Editor", resp.Role)
+	m.orgRepo.AssertNotCalled(t, "UpdateMembership", mock.Anything, mock.Anything, mock.Anything)
+	m.projectRepo.AssertExpectations(t)
+}
+
+func TestAcceptInvitation_ProjectMakesGuest(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	projectID := uint(3)
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(pendingInvitation(&projectID, enum.MemberEditor), nil)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+	m.orgRepo.On("AddMember", mock.Anything, &domain.Membership{OrganizationID: 7, UserID: 2, Role: enum.MemberViewer, Guest: true}).Return(nil)
+	m.projectRepo.On("GetMember", mock.Anything, projectID, uint(2)).Return(domain.ProjectMember{}, gorm.ErrRecordNotFound)
+	m.projectRepo.On("AddMember", mock.Anything, &domain.ProjectMember{ProjectID: projectID, UserID: 2, Role: enum.MemberEditor}).Return(nil)
+	m.invitationRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"accepted_at"}).Return(nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+
+	_, err := svc.Accept(context.Background(), "tok", 2)
+
+	assert.NoError(t, err)
+	m.orgRepo.AssertExpectations(t)
+}
+
+func TestAcceptInvitation_OrgInvitationPromotesGuest(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(pendingInvitation(nil, enum.MemberViewer), nil)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{UserID: 2, Role: enum.MemberViewer, Guest: true}, nil)
+	m.orgRepo.On("UpdateMembership", mock.Anything, mock.MatchedBy(func(m *domain.Membership) bool {
+		return !m.Guest && m.Role == enum.MemberViewer
+	}), []string{"role", "guest"}).Return(nil)
+	m.invitationRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"accepted_at"}).Return(nil)
+	m.orgRepo.On("GetByID", mock.Anything, uint(7)).Return(domain.Organization{Name: "Acme"}, nil)
+
+	_, err := svc.Accept(context.Background(), "tok", 2)
+
+	assert.NoError(t, err)
+	m.orgRepo.AssertExpectations(t)
+}
+
+func TestAcceptInvitation_Expired(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	invitation := pendingInvitation(nil, enum.MemberViewer)
+	invitation.ExpiresAt = time.Now().Add(-time.Minute)
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(invitation, nil)
+
+	_, err := svc.Accept(context.Background(), "tok", 2)
+
+	assert.Equal(t, api_error.ErrInvitationNotFound, err)
+}
+
+func TestAcceptWithSignup_CreatesAccount(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(pendingInvitation(nil, enum.MemberViewer), nil)
+	m.userRepo.On("GetByField", mock.Anything, "username", "jane").Return(domain.User{}, gorm.ErrRecordNotFound)
+	m.userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Email == "jane@example.com" && u.EmailVerified && u.Password != "secret1"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = 9
+	}).Return(uint(9), nil)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(9)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+	m.orgRepo.On("AddMember", mock.Anything, mock.Anything).Return(nil)
+	m.invitationRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"accepted_at"}).Return(nil)
+	m.sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil)
+
+	resp, err := svc.AcceptWithSignup(context.Background(), "tok", dto.InvitationSignupReq{Username: "jane", Password: "secret1"})
+
+	assert.NoError(t, err)
+	claims, err := svc.AuthSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), claims.OrgID)
+	assert.Equal(t, "9", claims.Subject)
+}
+
+func TestDeclineInvitation(t *testing.T) {
+	svc, m := setupInvitationTest(t)
+
+	m.invitationRepo.On("GetByTokenHash", mock.Anything, mock.Anything).Return(pendingInvitation(nil, enum.MemberViewer), nil)
+	m.invitationRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(i *domain.Invitation) bool {
+		return i.DeclinedAt != nil
+	}), []string{"declined_at"}).Return(nil)
+
+	assert.NoError(t, svc.Decline(context.Background(), "tok"))
+	m.invitationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+const (
+	NotificationAssigned      = "assigned"
+	NotificationStatusChanged = "status_changed"
+	NotificationMentioned     = "mentioned"
+	NotificationTaskUpdated   = "task_updated"
+	NotificationReminder      = "reminder"
+)
+
+// NotificationService runs the users' in-app inboxes.
+type NotificationService struct {
+	NotificationRepo repository.NotificationRepo
+	TaskRepo         repository.TaskRepo
+	UserRepo         repository.UserRepo
+	OrgRepo          repository.OrgRepo
+	ProjectRepo      repository.ProjectRepo
+}
+
+func NewNotificationService(
+	notificationRepo repository.NotificationRepo,
+	taskRepo repository.TaskRepo,
+	userRepo repository.UserRepo,
+	orgRepo repository.OrgRepo,
+	projectRepo repository.ProjectRepo,
+) *NotificationService {
+	return &NotificationService{
+		NotificationRepo: notificationRepo,
+		TaskRepo:         taskRepo,
+		UserRepo:         userRepo,
+		OrgRepo:          orgRepo,
+		ProjectRepo:      projectRepo,
+	}
+}
+
+// HandleEvent tells users about the task changes relayed from the outbox that concern them:
+// mentioned users about the mention, assignees about their assignment, the creator and
+// assignees about status changes, and watchers about any other update. Nobody hears about
+// one change twice.
+func (s *NotificationService) HandleEvent(ctx context.Context, event *domain.Event) error {
+	if event.Type != domain.EventTaskCreated && event.Type != domain.EventTaskUpdated {
+		return nil
+	}
+	var change TaskChange
+	if err := event.Decode(&change); err != nil || change.Task == nil {
+		return err
+	}
+	task := change.Task
+	notification := domain.Notification{
+		OrganizationID: event.OrganizationID,
+		TaskID:         &event.AggregateID,
+		ActorID:        event.ActorID,
+	}
+
+	told, err := s.mentioned(ctx, event.OrganizationID, task, change.Mentions)
+	if err != nil {
+		return err
+	}
+	mention := notification
+	mention.Kind = NotificationMentioned
+	mention.Title = fmt.Sprintf("You were mentioned in %q", task.Name)
+	mention.Body = task.Description
+	if err := s.Notify(ctx, mention, told); err != nil {
+		return err
+	}
+	if event.Type != domain.EventTaskUpdated {
+		return nil
+	}
+
+	switch {
+	case change.AssigneeID != nil:
+		assigned := notification
+		assigned.Kind = NotificationAssigned
+		assigned.Title = fmt.Sprintf("You were assigned to %q", task.Name)
+		assigned.Body = task.Description
+		if err := s.Notify(ctx, assigned, []uint{*change.AssigneeID}); err != nil {
+			return err
+		}
+		told = append(told, *change.AssigneeID)
+	case change.PreviousStatus != "" && change.PreviousStatus != task.StatusName:
+		recipients, err := s.TaskRepo.ListAssigneeIDs(ctx, event.AggregateID)
+		if err != nil {
+			return err
+		}
+		if task.CreatedByID != nil {
+			recipients = append(recipients, *task.CreatedByID)
+		}
+		statusChanged := notification
+		statusChanged.Kind = NotificationStatusChanged
+		statusChanged.Title = fmt.Sprintf("%q is now %s", task.Name, task.StatusName)
+		if err := s.Notify(ctx, statusChanged, recipients); err != nil {
+			return err
+		}
+		told = append(told, recipients...)
+	}
+
+	watchers, err := s.TaskRepo.ListWatcherIDs(ctx, event.AggregateID)
+	if err != nil {
+		return err
+	}
+	updated := notification
+	updated.Kind = NotificationTaskUpdated
+	updated.Title = fmt.Sprintf("%q was updated", task.Name)
+	return s.Notify(ctx, updated, slices.DeleteFunc(watchers, func(id uint) bool {
+		return slices.Contains(told, id)
+	}))
+}
+
+// mentioned resolves usernames to the users of orgID who can see task. Unknown names and
+// users who cannot see the task are left out.
+func (s *NotificationService) mentioned(ctx context.Context, orgID uint, task *dto.TaskResp, usernames []string) ([]uint, error) {
+	var ids []uint
+	for _, name := range usernames {
+		user, err := s.UserRepo.GetByField(ctx, "username", name)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := memberRole(ctx, s.OrgRepo, s.ProjectRepo, orgID, user.ID, task.ProjectID); err != nil {
+			continue
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
+
+// Notify puts a copy of notification into the inbox of every recipient, except the actor
+// and those who turned its kind off.
+func (s *NotificationService) Notify(ctx context.Context, notification domain.Notification, recipients []uint) error {
+	recipients = slices.DeleteFunc(slices.Compact(slices.Sorted(slices.Values(recipients))), func(id uint) bool {
+		return id == 0 || (notification.ActorID != nil && id == *notification.ActorID)
+	})
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	muted, err := s.NotificationRepo.MutedUsers(ctx, notification.Kind, recipients)
+	if err != nil {
+		return err
+	}
+	for _, userID := range recipients {
+		if slices.Contains(muted, userID) {
+			continue
+		}
+		n := notification
+		n.UserID = userID
+		if _, err := s.NotificationRepo.Create(ctx, &n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *NotificationService) ListNotifications(ctx context.Context, userID uint, filter dto.NotificationListFilter, limit, offset int) (*dto.NotificationListResp, error) {
+	notifications, total, err := s.NotificationRepo.ListByUser(ctx, userID, filter.Unread, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.NotificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]dto.NotificationResp, len(notifications))
+	for i, n := range notifications {
+		resps[i] = *notificationToResp(&n)
+	}
+	return &dto.NotificationListResp{
+		Notifications: resps,
+		Total:         total,
+		Unread:        unread,
+		Limit:         limit,
+		Offset:        offset,
+	}, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, notificationID, userID uint) error {
+	found, err := s.NotificationRepo.MarkRead(ctx, notificationID, userID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return api_error.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uint) error {
+	return s.NotificationRepo.MarkAllRead(ctx, userID)
+}
+
+func (s *NotificationService) GetPreferences(ctx context.Context, userID uint) (*dto.NotificationPrefs, error) {
+	prefs, err := s.NotificationRepo.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.NotificationPrefs{Assigned: true, StatusChanged: true, Mentioned: true, TaskUpdated: true}
+	for _, p := range prefs {
+		switch p.Kind {
+		case NotificationAssigned:
+			resp.Assigned = p.Enabled
+		case NotificationStatusChanged:
+			resp.StatusChanged = p.Enabled
+		case NotificationMentioned:
+			resp.Mentioned = p.Enabled
+		case NotificationTaskUpdated:
+			resp.TaskUpdated = p.Enabled
+		}
+	}
+	return resp, nil
+}
+
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID uint, req dto.UpdateNotificationPrefsReq) (*dto.NotificationPrefs, error) {
+	changes := map[string]*bool{
+		NotificationAssigned:      req.Assigned,
+		NotificationStatusChanged: req.StatusChanged,
+		NotificationMentioned:     req.Mentioned,
+		NotificationTaskUpdated:   req.TaskUpdated,
+	}
+	for kind, enabled := range changes {
+		if enabled == nil {
+			continue
+		}
+		err := s.NotificationRepo.SavePreference(ctx, &domain.NotificationPreference{
+			UserID:  userID,
+			Kind:    kind,
+			Enabled: *enabled,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.GetPreferences(ctx, userID)
+}
+
+func notificationToResp(n *domain.Notification) *dto.NotificationResp {
+	return &dto.NotificationResp{
+		ID:             n.ID,
+		Kind:           n.Kind,
+		Title:          n.Title,
+		Body:           n.Body,
+		OrganizationID: n.OrganizationID,
+		TaskID:         n.TaskID,
+		ActorID:        n.ActorID,
+		ReadAt:         n.ReadAt,
+		CreatedAt:      n.CreatedAt,
+	}
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNotify_SkipsActorAndMutedUsers(t *testing.T) {
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, nil, nil, nil, nil)
+
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationStatusChanged, []uint{2, 3}).Return([]uint{3}, nil)
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == 2 && n.Kind == NotificationStatusChanged
+	})).Return(uint(1), nil).Once()
+
+	actor := uint(1)
+	err := svc.Notify(context.Background(), domain.Notification{Kind: NotificationStatusChanged, ActorID: &actor}, []uint{3, 1, 2, 3})
+
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestNotify_NoRecipients(t *testing.T) {
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, nil, nil, nil, nil)
+
+	actor := uint(1)
+	err := svc.Notify(context.Background(), domain.Notification{Kind: NotificationAssigned, ActorID: &actor}, []uint{1})
+
+	assert.NoError(t, err)
+	notificationRepo.AssertNotCalled(t, "MutedUsers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func taskChangeEvent(t *testing.T, change TaskChange) *domain.Event {
+	data, err := json.Marshal(change)
+	require.NoError(t, err)
+	actor := uint(1)
+	return &domain.Event{OrganizationID: 7, Type: domain.EventTaskUpdated, AggregateID: 1, ActorID: &actor, Payload: string(data)}
+}
+
+func TestHandleEvent_StatusChangeNotifies(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, taskRepo, nil, nil, nil)
+
+	creator := uint(2)
+	event := taskChangeEvent(t, TaskChange{
+		Task:           &dto.TaskResp{ID: 1, Name: "Task", Status: "Started", StatusName: "Started", CreatedByID: &creator},
+		PreviousStatus: "Created",
+	})
+	taskRepo.On("ListAssigneeIDs", mock.Anything, uint(1)).Return([]uint{1, 3}, nil)
+	taskRepo.On("ListWatcherIDs", mock.Anything, uint(1)).Return([]uint{}, nil)
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationStatusChanged, []uint{2, 3}).Return([]uint{}, nil)
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Title == `"Task" is now Started` && *n.TaskID == 1 && n.OrganizationID == 7
+	})).Return(uint(1), nil).Twice()
+
+	err := svc.HandleEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestHandleEvent_CustomStatusChangeNotifies(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, taskRepo, nil, nil, nil)
+
+	// Both workflow statuses count as Started.
+	event := taskChangeEvent(t, TaskChange{
+		Task:           &dto.TaskResp{ID: 1, Name: "Task", Status: "Started", StatusName: "QA"},
+		PreviousStatus: "In Review",
+	})
+	taskRepo.On("ListAssigneeIDs", mock.Anything, uint(1)).Return([]uint{3}, nil)
+	taskRepo.On("ListWatcherIDs", mock.Anything, uint(1)).Return([]uint{}, nil)
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationStatusChanged, []uint{3}).Return([]uint{}, nil)
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.Title == `"Task" is now QA`
+	})).Return(uint(1), nil)
+
+	err := svc.HandleEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestHandleEvent_AssignedNotifies(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, taskRepo, nil, nil, nil)
+
+	assignee := uint(4)
+	event := taskChangeEvent(t, TaskChange{Task: &dto.TaskResp{ID: 1, Name: "Task"}, AssigneeID: &assignee})
+	taskRepo.On("ListWatcherIDs", mock.Anything, uint(1)).Return([]uint{4}, nil)
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationAssigned, []uint{4}).Return([]uint{}, nil)
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == 4 && n.Kind == NotificationAssigned && *n.ActorID == 1
+	})).Return(uint(1), nil)
+
+	err := svc.HandleEvent(context.Background(), event)
+
+	assert.NoError(t, err)
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestHandleEvent_IgnoresUnchangedStatus(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, taskRepo, nil, nil, nil)
+
+	event := taskChangeEvent(t, TaskChange{Task: &dto.TaskResp{ID: 1, Name: "Renamed", Status: "Created", StatusName: "Created"}})
+	taskRepo.On("ListWatcherIDs", mock.Anything, uint(1)).Return([]uint{}, nil)
+
+	assert.NoError(t, svc.HandleEvent(context.Background(), event))
+	taskRepo.AssertNotCalled(t, "ListAssigneeIDs", mock.Anything, mock.Anything)
+	notificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestHandleEvent_WatchersHearOfUpdates(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, taskRepo, nil, nil, nil)
+
+	// The creator hears of the status change already; the actor hears of nothing.
+	creator := uint(2)
+	event := taskChangeEvent(t, TaskChange{
+		Task:           &dto.TaskResp{ID: 1, Name: "Task", StatusName: "Done", CreatedByID: &creator},
+		PreviousStatus: "Started",
+	})
+	taskRepo.On("ListAssigneeIDs", mock.Anything, uint(1)).Return([]uint{}, nil)
+	taskRepo.On("ListWatcherIDs", mock.Anything, uint(1)).Return([]uint{1, 2, 5}, nil)
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationStatusChanged, []uint{2}).Return([]uint{}, nil)
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationTaskUpdated, []uint{5}).Return([]uint{}, nil)
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == 2 && n.Kind == NotificationStatusChanged
+	})).Return(uint(1), nil).Once()
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == 5 && n.Kind == NotificationTaskUpdated && n.Title == `"Task" was updated`
+	})).Return(uint(2), nil).Once()
+
+	assert.NoError(t, svc.HandleEvent(context.Background(), event))
+	notificationRepo.AssertExpectations(t)
+}
+
+func TestHandleEvent_MentionsNotifyThoseWhoSeeTheTask(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	userRepo := new(mockRepo.MockUserRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewNotificationService(notificationRepo, taskRepo, userRepo, orgRepo, nil)
+
+	mary := domain.User{Username: "mary"}
+	mary.ID = 3
+	guest := domain.User{Username: "guest"}
+	guest.ID = 4
+	userRepo.On("GetByField", mock.Anything, "username", "mary").Return(mary, nil)
+	userRepo.On("GetByField", mock.Anything, "username", "guest").Return(guest, nil)
+	userRepo.On("GetByField", mock.Anything, "username", "nobody").Return(domain.User{}, gorm.ErrRecordNotFound)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(3)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(4)).Return(domain.Membership{Guest: true}, nil)
+	taskRepo.On("ListWatcherIDs", mock.Anything, uint(1)).Return([]uint{3}, nil)
+	notificationRepo.On("MutedUsers", mock.Anything, NotificationMentioned, []uint{3}).Return([]uint{}, nil)
+	notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == 3 && n.Kind == NotificationMentioned && n.Title == `You were mentioned in "Task"`
+	})).Return(uint(1), nil).Once()
+
+	event := taskChangeEvent(t, TaskChange{
+		Task:     &dto.TaskResp{ID: 1, Name: "Task", Description: "@mary @guest @nobody"},
+		Mentions: []string{"mary", "guest", "nobody"},
+	})
+
+	assert.NoError(t, svc.HandleEvent(context.Background(), event))
+	notificationRepo.AssertExpectations(t)
+	notificationRepo.AssertNotCalled(t, "MutedUsers", mock.Anything, NotificationTaskUpdated, mock.Anything)
+}
+
+func TestListNotifications_UnreadCount(t *testing.T) {
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, nil, nil, nil, nil)
+
+	notificationRepo.On("ListByUser", mock.Anything, uint(1), true, 20, 0).
+		Return([]domain.Notification{{Kind: NotificationAssigned, Title: "t"}}, int64(1), nil)
+	notificationRepo.On("CountUnread", mock.Anything, uint(1)).Return(int64(4), nil)
+
+	resp, err := svc.ListNotifications(context.Background(), 1, dto.NotificationListFilter{Unread: true}, 20, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Notifications, 1)
+	assert.Equal(t, int64(4), resp.Unread)
+}
+
+func TestMarkRead_NotFound(t *testing.T) {
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, nil, nil, nil, nil)
+
+	notificationRepo.On("MarkRead", mock.Anything, uint(9), uint(1)).Return(false, nil)
+
+	err := svc.MarkRead(context.Background(), 9, 1)
+
+	assert.Equal(t, api_error.ErrNotificationNotFound, err)
+}
+
+func TestUpdatePreferences_OnlyGivenKinds(t *testing.T) {
+	notificationRepo := new(mockRepo.MockNotificationRepo)
+	svc := NewNotificationService(notificationRepo, nil, nil, nil, nil)
+
+	notificationRepo.On("SavePreference", mock.Anything, &domain.NotificationPreference{UserID: 1, Kind: NotificationStatusChanged}).Return(nil).Once()
+	notificationRepo.On("ListPreferences", mock.Anything, uint(1)).
+		Return([]domain.NotificationPreference{{UserID: 1, Kind: NotificationStatusChanged}}, nil)
+
+	off := false
+	resp, err := svc.UpdatePreferences(context.Background(), 1, dto.UpdateNotificationPrefsReq{StatusChanged: &off})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Assigned)
+	assert.False(t, resp.StatusChanged)
+	notificationRepo.AssertExpectations(t)
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/pkg/logger"
+	"graph-interview/pkg/oidc"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// OIDCStateCookie binds a login to the browser that started it, so a callback URL
+// issued to someone else cannot sign this browser in.
+const OIDCStateCookie = "oidc_state"
+
+type oidcState struct {
+	Provider string `json:"provider"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+type OIDCService struct {
+	UserRepo     repository.UserRepo
+	IdentityRepo repository.IdentityRepo
+	AuthSrv      *AuthService
+	redis        *redis.Client
+	providerCfgs map[string]cfg.OIDCProviderCfg
+	httpClient   *http.Client
+
+	mu        sync.Mutex
+	providers map[string]*oidc.Provider
+}
+
+func NewOIDCService(
+	userRepo repository.UserRepo,
+	identityRepo repository.IdentityRepo,
+	authSrv *AuthService,
+	redis *redis.Client,
+	providerCfgs map[string]cfg.OIDCProviderCfg,
+	httpClient *http.Client,
+) *OIDCService {
+	return &OIDCService{
+		UserRepo:     userRepo,
+		IdentityRepo: identityRepo,
+		AuthSrv:      authSrv,
+		redis:        redis,
+		providerCfgs: providerCfgs,
+		httpClient:   httpClient,
+		providers:    map[string]*oidc.Provider{},
+	}
+}
+
+// provider lazily discovers the identity provider so the API can start while it is unreachable.
+func (s *OIDCService) provider(ctx context.Context, name string) (*oidc.Provider, error) {
+	c, ok := s.providerCfgs[name]
+	if !ok {
+		return nil, api_error.ErrUnknownProvider
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.providers[name]; ok {
+		return p, nil
+	}
+	p, err := oidc.Discover(ctx, oidc.Config{
+		Issuer:       c.Issuer,
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURL:  c.RedirectURL,
+		Scopes:       c.Scopes,
+	}, s.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	s.providers[name] = p
+	return p, nil
+}
+
+// BeginLogin stores a fresh state/nonce/PKCE verifier and returns the provider authorization URL
+// together with the state, which the caller pins to the browser with SetStateCookie.
+func (s *OIDCService) BeginLogin(ctx context.Context, providerName string) (string, string, error) {
+	p, err := s.provider(ctx, providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	verifier, challenge := oidc.NewPKCE()
+	st := oidcState{Provider: providerName, Nonce: oidc.RandomString(16), Verifier: verifier}
+	raw, err := json.Marshal(st)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := oidc.RandomString(24)
+	if err := s.redis.Set(ctx, cache.OIDCStateKey(state), raw, oidcStateTTL).Err(); err != nil {
+		return "", "", err
+	}
+	return p.AuthCodeURL(state, st.Nonce, challenge), state, nil
+}
+
+func (s *OIDCService) SetStateCookie(c *gin.Context, state string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCStateCookie, state, int(oidcStateTTL.Seconds()), "/", "", true, true)
+}
+
+// CheckStateCookie reports whether the callback state matches the one pinned to this
+// browser and clears the cookie either way, so it cannot be replayed.
+func (s *OIDCService) CheckStateCookie(c *gin.Context, state string) bool {
+	cookie, _ := c.Cookie(OIDCStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(OIDCStateCookie, "", -1, "/", "", true, true)
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) == 1
+}
+
+// CompleteLogin handles the provider callback: it exchanges the code, verifies the ID token,
+// resolves (or provisions) the local user and issues our own token pair.
+func (s *OIDCService) CompleteLogin(ctx context.Context, providerName, state, code string) (*dto.JWTResp, error) {
+	raw, err := s.redis.GetDel(ctx, cache.OIDCStateKey(state)).Bytes()
+	if err != nil {
+		return nil, api_error.ErrInvalidOIDCState
+	}
+	var st oidcState
+	if err := json.Unmarshal(raw, &st); err != nil || st.Provider != providerName {
+		return nil, api_error.ErrInvalidOIDCState
+	}
+
+	p, err := s.provider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := p.Exchange(ctx, code, st.Verifier)
+	if err != nil {
+		logger.Logger.Warn("oidc code exchange failed", "provider", providerName, "err", err)
+		return nil, api_error.ErrOIDCLoginFailed
+	}
+	claims, err := p.VerifyIDToken(ctx, tokens.IDToken, st.Nonce)
+	if err != nil {
+		logger.Logger.Warn("oidc id token rejected", "provider", providerName, "err", err)
+		return nil, api_error.ErrOIDCLoginFailed
+	}
+
+	user, err := s.resolveUser(ctx, providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.AuthSrv.StartSession(ctx, user)
+}
+
+func (s *OIDCService) resolveUser(ctx context.Context, providerName string, claims *oidc.IDTokenClaims) (domain.User, error) {
+	identity, err := s.IdentityRepo.GetByProviderSubject(ctx, providerName, claims.Subject)
+	if err == nil {
+		return s.UserRepo.GetByID(ctx, identity.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.User{}, err
+	}
+
+	// First login with this identity: link it to the account owning the same email, or
+	// provision a new one. Both sides must have verified the address, otherwise whoever
+	// registered it locally first would receive the SSO logins.
+	var user domain.User
+	if claims.Email != "" && claims.EmailVerified {
+		user, err = s.UserRepo.GetByVerifiedEmail(ctx, claims.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.User{}, err
+		}
+	}
+	if user.ID == 0 {
+		if user, err = s.provisionUser(ctx, claims); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	if _, err := s.IdentityRepo.Create(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: providerName,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (s *OIDCService) provisionUser(ctx context.Context, claims *oidc.IDTokenClaims) (domain.User, error) {
+	username, err := s.availableUsername(ctx, claims)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	// Externally managed accounts get an unusable random password.
+	hashed, err := bcrypt.GenerateFromPassword([]byte(oidc.RandomString(32)), bcrypt.DefaultCost)
+	if err != nil {
+		return domain.User{}, err
+	}
+	user := domain.User{
+		Username:      username,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Password:      string(hashed),
+		Avatar:        claims.Picture,
+	}
+	if _, err := s.UserRepo.Create(ctx, &user); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+func (s *OIDCService) availableUsername(ctx context.Context, claims *oidc.IDTokenClaims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	if base == "" {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 1; i <= 100; i++ {
+		_, err := s.UserRepo.GetByField(ctx, "username", candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", fmt.Errorf("no available username for %s", base)
+}
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type pendingCode struct {
+	challenge string
+	nonce     string
+}
+
+// mockIdP is a minimal OpenID provider: discovery, JWKS and a token endpoint checking PKCE.
+type mockIdP struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	subject string
+	email   string
+
+	mu    sync.Mutex
+	codes map[string]pendingCode
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, subject: "ext-123", email: "jane@example.com", codes: map[string]pendingCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		idp.mu.Lock()
+		pending, ok := idp.codes[r.PostForm.Get("code")]
+		idp.mu.Unlock()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != pending.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		tok := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            idp.server.URL,
+			"aud":            "todoapp",
+			"sub":            idp.subject,
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"iat":            time.Now().Unix(),
+			"nonce":          pending.nonce,
+			"email":          idp.email,
+			"email_verified": true,
+		})
+		tok.Header["kid"] = "test-key"
+		signed, _ := tok.SignedString(key)
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "at", "token_type": "Bearer", "id_token": signed})
+	})
+	idp.server = httptest.NewServer(mux)
+	return idp
+}
+
+// authorize plays the user agent: it follows the authorization URL and returns the issued code and state.
+func (idp *mockIdP) authorize(t *testing.T, authURL string, challengeOverride string) (code, state string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+
+	challenge := q.Get("code_challenge")
+	if challengeOverride != "" {
+		challenge = challengeOverride
+	}
+	idp.mu.Lock()
+	idp.codes["code-"+q.Get("state")] = pendingCode{challenge: challenge, nonce: q.Get("nonce")}
+	idp.mu.Unlock()
+	return "code-" + q.Get("state"), q.Get("state")
+}
+
+func setupOIDCTest(t *testing.T) (*OIDCService, *mockRepo.MockUserRepo, *mockRepo.MockIdentityRepo, *mockIdP) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	idp := newMockIdP(t)
+	t.Cleanup(idp.server.Close)
+
+	userRepo := new(mockRepo.MockUserRepo)
+	identityRepo := new(mockRepo.MockIdentityRepo)
+	sessionRepo := new(mockRepo.MockSessionRepo)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil)
+	authSrv := NewAuthService(userRepo, sessionRepo, memberOrgRepo(1), mockRepo.NoopTransactor{}, rdb, "test-secret")
+	srv := NewOIDCService(userRepo, identityRepo, authSrv, rdb, map[string]cfg.OIDCProviderCfg{
+		"company": {
+			Issuer:      idp.server.URL,
+			ClientID:    "todoapp",
+			RedirectURL: "http://localhost/v1/auth/oidc/company/callback",
+			Scopes:      []string{"profile", "email"},
+		},
+	}, idp.server.Client())
+	return srv, userRepo, identityRepo, idp
+}
+
+func TestOIDCLogin_ProvisionsUser(t *testing.T) {
+	srv, userRepo, identityRepo, idp := setupOIDCTest(t)
+	ctx := context.Background()
+
+	identityRepo.On("GetByProviderSubject", mock.Anything, "company", "ext-123").
+		Return(domain.UserIdentity{}, gorm.ErrRecordNotFound)
+	userRepo.On("GetByVerifiedEmail", mock.Anything, "jane@example.com").
+		Return(domain.User{}, gorm.ErrRecordNotFound)
+	userRepo.On("GetByField", mock.Anything, "username", "jane").
+		Return(domain.User{}, gorm.ErrRecordNotFound)
+	userRepo.On("Create", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.EmailVerified
+	})).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*domain.User).ID = 7
+		}).
+		Return(uint(7), nil)
+	identityRepo.On("Create", mock.Anything, mock.MatchedBy(func(i *domain.UserIdentity) bool {
+		return i.UserID == 7 && i.Provider == "company" && i.Subject == "ext-123"
+	})).Return(uint(1), nil)
+
+	authURL, _, err := srv.BeginLogin(ctx, "company")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL, "")
+
+	resp, err := srv.CompleteLogin(ctx, "company", state, code)
+
+	assert.NoError(t, err)
+	require.NotNil(t, resp)
+	assert.NotEmpty(t, resp.Access)
+	claims, err := srv.AuthSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", claims.Subject)
+	userRepo.AssertExpectations(t)
+	identityRepo.AssertExpectations(t)
+}
+
+func TestOIDCLogin_LinksVerifiedLocalAccount(t *testing.T) {
+	srv, userRepo, identityRepo, idp := setupOIDCTest(t)
+	ctx := context.Background()
+
+	identityRepo.On("GetByProviderSubject", mock.Anything, "company", "ext-123").
+		Return(domain.UserIdentity{}, gorm.ErrRecordNotFound)
+	local := domain.User{Username: "jane", Email: "jane@example.com", EmailVerified: true}
+	local.ID = 4
+	userRepo.On("GetByVerifiedEmail", mock.Anything, "jane@example.com").Return(local, nil)
+	identityRepo.On("Create", mock.Anything, mock.MatchedBy(func(i *domain.UserIdentity) bool {
+		return i.UserID == 4
+	})).Return(uint(1), nil)
+
+	authURL, _, err := srv.BeginLogin(ctx, "company")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL, "")
+
+	resp, err := srv.CompleteLogin(ctx, "company", state, code)
+
+	require.NoError(t, err)
+	claims, err := srv.AuthSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, "4", claims.Subject)
+	userRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	identityRepo.AssertExpectations(t)
+}
+
+func TestOIDCLogin_ExistingIdentity(t *testing.T) {
+	srv, userRepo, identityRepo, idp := setupOIDCTest(t)
+	ctx := context.Background()
+
+	identityRepo.On("GetByProviderSubject", mock.Anything, "company", "ext-123").
+		Return(domain.UserIdentity{UserID: 3}, nil)
+	user := domain.User{Username: "jane"}
+	user.ID = 3
+	userRepo.On("GetByID", mock.Anything, uint(3)).Return(user, nil)
+
+	authURL, _, err := srv.BeginLogin(ctx, "company")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL, "")
+
+	resp, err := srv.CompleteLogin(ctx, "company", state, code)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	identityRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	userRepo.AssertExpectations(t)
+}
+
+func TestOIDCLogin_StateIsSingleUse(t *testing.T) {
+	srv, userRepo, identityRepo, idp := setupOIDCTest(t)
+	ctx := context.Background()
+
+	identityRepo.On("GetByProviderSubject", mock.Anything, "company", "ext-123").
+		Return(domain.UserIdentity{UserID: 3}, nil)
+	userRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.User{}, nil)
+
+	authURL, _, err := srv.BeginLogin(ctx, "company")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL, "")
+
+	_, err = srv.CompleteLogin(ctx, "company", state, code)
+	assert.NoError(t, err)
+
+	_, err = srv.CompleteLogin(ctx, "company", state, code)
+	assert.Equal(t, api_error.ErrInvalidOIDCState, err)
+}
+
+func TestOIDCLogin_PKCEMismatch(t *testing.T) {
+	srv, _, _, idp := setupOIDCTest(t)
+	ctx := context.Background()
+
+	authURL, _, err := srv.BeginLogin(ctx, "company")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL, "not-the-challenge")
+
+	resp, err := srv.CompleteLogin(ctx, "company", state, code)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, api_error.ErrOIDCLoginFailed, err)
+}
+
+func TestOIDCLogin_UnknownProvider(t *testing.T) {
+	srv, _, _, _ := setupOIDCTest(t)
+
+	_, _, err := srv.BeginLogin(context.Background(), "nope")
+
+	assert.Equal(t, api_error.ErrUnknownProvider, err)
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+)
+
+type OrgService struct {
+	OrgRepo  repository.OrgRepo
+	UserRepo repository.UserRepo
+	Tx       repository.Transactor
+	AuthSrv  *AuthService
+}
+
+func NewOrgService(orgRepo repository.OrgRepo, userRepo repository.UserRepo, tx repository.Transactor, authSrv *AuthService) *OrgService {
+	return &OrgService{
+		OrgRepo:  orgRepo,
+		UserRepo: userRepo,
+		Tx:       tx,
+		AuthSrv:  authSrv,
+	}
+}
+
+// CreateOrg creates an organization with userID as its owner.
+func (s *OrgService) CreateOrg(ctx context.Context, req dto.CreateOrgReq, userID uint) (*dto.OrgResp, error) {
+	org := &domain.Organization{Name: req.Name}
+	err := s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.OrgRepo.Create(ctx, org); err != nil {
+			return err
+		}
+		return s.OrgRepo.AddMember(ctx, &domain.Membership{
+			OrganizationID: org.ID,
+			UserID:         userID,
+			Role:           enum.MemberOwner,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.OrgResp{
+		ID:       org.ID,
+		Name:     org.Name,
+		Personal: org.Personal,
+		Role:     enum.MemberOwner.String(),
+	}, nil
+}
+
+// ListOrgs returns the organizations userID belongs to, flagging activeOrgID.
+func (s *OrgService) ListOrgs(ctx context.Context, userID, activeOrgID uint) ([]dto.OrgResp, error) {
+	memberships, err := s.OrgRepo.ListMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]dto.OrgResp, 0, len(memberships))
+	for _, m := range memberships {
+		if m.Organization == nil {
+			continue
+		}
+		resps = append(resps, dto.OrgResp{
+			ID:       m.OrganizationID,
+			Name:     m.Organization.Name,
+			Personal: m.Organization.Personal,
+			Role:     m.Role.String(),
+			Active:   m.OrganizationID == activeOrgID,
+		})
+	}
+	return resps, nil
+}
+
+// ListMembers lists the members of orgID. Only members may see them; to anyone else the
+// organization does not exist.
+func (s *OrgService) ListMembers(ctx context.Context, orgID, userID uint) ([]dto.MemberResp, error) {
+	if _, err := s.OrgRepo.GetMembership(ctx, orgID, userID); err != nil {
+		return nil, api_error.ErrOrgNotFound
+	}
+
+	// The organization listed need not be the active one, so users must not be limited to it.
+	memberships, err := s.OrgRepo.ListMembers(tenant.Unscoped(ctx), orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]dto.MemberResp, 0, len(memberships))
+	for _, m := range memberships {
+		if m.User == nil {
+			continue
+		}
+		resps = append(resps, dto.MemberResp{
+			UserID:   m.UserID,
+			Username: m.User.Username,
+			Role:     m.Role.String(),
+			Guest:    m.Guest,
+			JoinedAt: m.CreatedAt,
+		})
+	}
+	return resps, nil
+}
+
+// UpdateMemberRole changes the role of memberID in orgID, which makes a guest a full member.
+// Only owners may change roles, and not their own, so an organization cannot lose its last
+// owner this way.
+func (s *OrgService) UpdateMemberRole(ctx context.Context, orgID, memberID uint, role enum.MemberRole, userID uint) (*dto.MemberResp, error) {
+	caller, err := s.OrgRepo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, api_error.ErrOrgNotFound
+	}
+	if caller.Role < enum.MemberOwner || memberID == userID {
+		return nil, api_error.ErrForbidden
+	}
+
+	member, err := s.OrgRepo.GetMembership(ctx, orgID, memberID)
+	if err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+	member.Role = role
+	member.Guest = false
+	if err := s.OrgRepo.UpdateMembership(ctx, &member, []string{"role", "guest"}); err != nil {
+		return nil, err
+	}
+	return &dto.MemberResp{
+		UserID:   member.UserID,
+		Role:     member.Role.String(),
+		JoinedAt: member.CreatedAt,
+	}, nil
+}
+
+// SwitchOrg starts a new session scoped to orgID, which userID must be a member of.
+func (s *OrgService) SwitchOrg(ctx context.Context, orgID, userID uint) (*dto.JWTResp, error) {
+	if _, err := s.OrgRepo.GetMembership(ctx, orgID, userID); err != nil {
+		return nil, api_error.ErrOrgNotFound
+	}
+	user, err := s.UserRepo.GetByID(tenant.Unscoped(ctx), userID)
+	if err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+	return s.AuthSrv.StartOrgSession(ctx, user, orgID)
+}
+
+// memberRole resolves the role of userID in orgID. For work inside a project, owning the
+// project or a project membership takes precedence over the organization role. Users
+// outside the organization get ErrOrgNotFound, and so do guests outside their projects.
+func memberRole(ctx context.Context, orgRepo repository.OrgRepo, projectRepo repository.ProjectRepo, orgID, userID uint, projectID *uint) (enum.MemberRole, error) {
+	membership, err := orgRepo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return 0, api_error.ErrOrgNotFound
+	}
+	if projectID == nil {
+		if membership.Guest {
+			return 0, api_error.ErrOrgNotFound
+		}
+		return membership.Role, nil
+	}
+
+	project, err := projectRepo.GetByID(ctx, *projectID)
+	if err != nil {
+		return 0, api_error.ErrProjectNotFound
+	}
+	if project.OwnerID == userID {
+		return enum.MemberOwner, nil
+	}
+	if member, err := projectRepo.GetMember(ctx, *projectID, userID); err == nil {
+		return member.Role, nil
+	}
+	if membership.Guest {
+		return 0, api_error.ErrOrgNotFound
+	}
+	return membership.Role, nil
+}
+
+// requireRole checks that userID holds at least role need for work in the active
+// organization, inside projectID when set. Without an active organization there is nothing
+// to check: the storage layer refuses to touch organization data at all.
+func requireRole(ctx context.Context, orgRepo repository.OrgRepo, projectRepo repository.ProjectRepo, userID uint, projectID *uint, need enum.MemberRole) error {
+	orgID, ok := tenant.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	role, err := memberRole(ctx, orgRepo, projectRepo, orgID, userID, projectID)
+	if err != nil {
+		if errors.Is(err, api_error.ErrOrgNotFound) {
+			return api_error.ErrForbidden
+		}
+		return err
+	}
+	if role < need {
+		return api_error.ErrForbidden
+	}
+	return nil
+}
+
+// requireProjectRole loads projectID and checks that userID holds at least role need in it.
+// The project's owner always does; outside an organization nobody else may change it.
+func requireProjectRole(ctx context.Context, orgRepo repository.OrgRepo, projectRepo repository.ProjectRepo, userID, projectID uint, need enum.MemberRole) (domain.Project, error) {
+	project, err := projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return domain.Project{}, api_error.ErrProjectNotFound
+	}
+	if project.OwnerID == userID {
+		return project, nil
+	}
+	if _, ok := tenant.FromContext(ctx); !ok && need > enum.MemberViewer {
+		return domain.Project{}, api_error.ErrForbidden
+	}
+	if err := requireRole(ctx, orgRepo, projectRepo, userID, &projectID, need); err != nil {
+		return domain.Project{}, err
+	}
+	return project, nil
+}
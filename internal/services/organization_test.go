@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupOrgTest(t *testing.T) (*OrgService, *mockRepo.MockOrgRepo, *mockRepo.MockUserRepo, *mockRepo.MockSessionRepo) {
+	t.Helper()
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
+	t.Cleanup(mr.Close)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	authSrv.OrgRepo = orgRepo
+	return NewOrgService(orgRepo, userRepo, mockRepo.NoopTransactor{}, authSrv), orgRepo, userRepo, sessionRepo
+}
+
+func TestCreateOrg_AddsOwner(t *testing.T) {
+	svc, orgRepo, _, _ := setupOrgTest(t)
+
+	orgRepo.On("Create", mock.Anything, mock.MatchedBy(func(o *domain.Organization) bool {
+		return o.Name == "Acme" && !o.Personal
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Organization).ID = 7
+	}).Return(uint(7), nil)
+	orgRepo.On("AddMember", mock.Anything, &domain.Membership{OrganizationID: 7, UserID: 1, Role: enum.MemberOwner}).Return(nil)
+
+	resp, err := svc.CreateOrg(context.Background(), dto.CreateOrgReq{Name: "Acme"}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), resp.ID)
+	assert.Equal(t, "Owner", resp.Role)
+	orgRepo.AssertExpectations(t)
+}
+
+func TestListOrgs_MarksActive(t *testing.T) {
+	svc, orgRepo, _, _ := setupOrgTest(t)
+
+	orgRepo.On("ListMemberships", mock.Anything, uint(1)).Return([]domain.Membership{
+		{OrganizationID: 2, Organization: &domain.Organization{Name: "john", Personal: true}, Role: enum.MemberOwner},
+		{OrganizationID: 7, Organization: &domain.Organization{Name: "Acme"}, Role: enum.MemberViewer},
+	}, nil)
+
+	resp, err := svc.ListOrgs(context.Background(), 1, 7)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.False(t, resp[0].Active)
+	assert.True(t, resp[1].Active)
+	assert.Equal(t, "Viewer", resp[1].Role)
+}
+
+func TestListMembers_NotMember(t *testing.T) {
+	svc, orgRepo, _, _ := setupOrgTest(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.ListMembers(context.Background(), 7, 1)
+
+	assert.Equal(t, api_error.ErrOrgNotFound, err)
+	orgRepo.AssertNotCalled(t, "ListMembers", mock.Anything, mock.Anything)
+}
+
+func TestListMembers_Success(t *testing.T) {
+	svc, orgRepo, _, _ := setupOrgTest(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+	orgRepo.On("ListMembers", mock.Anything, uint(7)).Return([]domain.Membership{
+		{UserID: 1, User: &domain.User{Username: "john"}, Role: enum.MemberViewer},
+		{UserID: 2, User: &domain.User{Username: "jane"}, Role: enum.MemberOwner},
+	}, nil)
+
+	resp, err := svc.ListMembers(context.Background(), 7, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.Equal(t, "jane", resp[1].Username)
+	assert.Equal(t, "Owner", resp[1].Role)
+}
+
+func TestSwitchOrg_IssuesScopedTokens(t *testing.T) {
+	svc, orgRepo, userRepo, sessionRepo := setupOrgTest(t)
+
+	user := domain.User{Username: "john"}
+	user.ID = 1
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	userRepo.On("GetByID", mock.Anything, uint(1)).Return(user, nil)
+	sessionRepo.On("Create", mock.Anything, mock.Anything).Return(uint(1), nil)
+
+	resp, err := svc.SwitchOrg(context.Background(), 7, 1)
+
+	assert.NoError(t, err)
+	claims, err := svc.AuthSrv.ParseToken(resp.Access)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(7), claims.OrgID)
+}
+
+func TestSwitchOrg_NotMember(t *testing.T) {
+	svc, orgRepo, userRepo, _ := setupOrgTest(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.SwitchOrg(context.Background(), 7, 1)
+
+	assert.Equal(t, api_error.ErrOrgNotFound, err)
+	userRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestUpdateMemberRole_Success(t *testing.T) {
+	svc, orgRepo, _, _ := setupOrgTest(t)
+
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberOwner}, nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{UserID: 2, Role: enum.MemberViewer}, nil)
+	orgRepo.On("UpdateMembership", mock.Anything, mock.MatchedBy(func(m *domain.Membership) bool {
+		return m.UserID == 2 && m.Role == enum.MemberEditor
+	}), []string{"role", "guest"}).Return(nil)
+
+	resp, err := svc.UpdateMemberRole(context.Background(), 7, 2, enum.MemberEditor, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Editor", resp.Role)
+	orgRepo.AssertExpectations(t)
+}
+
+func TestUpdateMemberRole_NotOwner(t *testing.T) {
+	svc, orgRepo, _, _ := setupOrgTest(t)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+
+	_, err := svc.UpdateMemberRole(context.Background(), 7, 2, enum.MemberOwner, 1)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	orgRepo.AssertNotCalled(t, "UpdateMembership", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMemberRole_GuestOnlyInOwnProjects(t *testing.T) {
+	orgRepo := new(mockRepo.MockOrgRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer, Guest: true}, nil)
+	own, other := uint(3), uint(4)
+	projectRepo.On("GetByID", mock.Anything, own).Return(domain.Project{OwnerID: 1}, nil)
+	projectRepo.On("GetByID", mock.Anything, other).Return(domain.Project{OwnerID: 1}, nil)
+	projectRepo.On("GetMember", mock.Anything, own, uint(2)).Return(domain.ProjectMember{Role: enum.MemberEditor}, nil)
+	projectRepo.On("GetMember", mock.Anything, other, uint(2)).Return(domain.ProjectMember{}, gorm.ErrRecordNotFound)
+	ctx := context.Background()
+
+	_, orgErr := memberRole(ctx, orgRepo, projectRepo, 7, 2, nil)
+	role, ownErr := memberRole(ctx, orgRepo, projectRepo, 7, 2, &own)
+	_, otherErr := memberRole(ctx, orgRepo, projectRepo, 7, 2, &other)
+
+	assert.Equal(t, api_error.ErrOrgNotFound, orgErr)
+	assert.NoError(t, ownErr)
+	assert.Equal(t, enum.MemberEditor, role)
+	assert.Equal(t, api_error.ErrOrgNotFound, otherErr)
+}
@@ -0,0 +1,370 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/tenant"
+	"slices"
+	"time"
+)
+
+const (
+	DeletionModeAnonymize = "anonymize"
+	DeletionModeDelete    = "delete"
+
+	TaskPolicyOrphan   = "orphan"
+	TaskPolicyReassign = "reassign"
+	TaskPolicyDelete   = "delete"
+)
+
+// exportPageSize bounds each task query made while building an export.
+const exportPageSize = 200
+
+type PrivacyService struct {
+	UserRepo         repository.UserRepo
+	SessionRepo      repository.SessionRepo
+	IdentityRepo     repository.IdentityRepo
+	TaskRepo         repository.TaskRepo
+	ProjectRepo      repository.ProjectRepo
+	OrgRepo          repository.OrgRepo
+	ReminderRepo     repository.ReminderRepo
+	NotificationRepo repository.NotificationRepo
+	ViewRepo         repository.ViewRepo
+	WebhookRepo      repository.WebhookRepo
+	InvitationRepo   repository.InvitationRepo
+	Tx               repository.Transactor
+	AuthSrv          *AuthService
+	cfg              cfg.PrivacyCfg
+}
+
+func NewPrivacyService(
+	userRepo repository.UserRepo,
+	sessionRepo repository.SessionRepo,
+	identityRepo repository.IdentityRepo,
+	taskRepo repository.TaskRepo,
+	projectRepo repository.ProjectRepo,
+	orgRepo repository.OrgRepo,
+	reminderRepo repository.ReminderRepo,
+	notificationRepo repository.NotificationRepo,
+	viewRepo repository.ViewRepo,
+	webhookRepo repository.WebhookRepo,
+	invitationRepo repository.InvitationRepo,
+	tx repository.Transactor,
+	authSrv *AuthService,
+	privacyCfg cfg.PrivacyCfg,
+) *PrivacyService {
+	if privacyCfg.DeletionMode == "" {
+		privacyCfg.DeletionMode = DeletionModeAnonymize
+	}
+	if privacyCfg.TaskPolicy == "" {
+		privacyCfg.TaskPolicy = TaskPolicyOrphan
+	}
+	return &PrivacyService{
+		UserRepo:         userRepo,
+		SessionRepo:      sessionRepo,
+		IdentityRepo:     identityRepo,
+		TaskRepo:         taskRepo,
+		ProjectRepo:      projectRepo,
+		OrgRepo:          orgRepo,
+		ReminderRepo:     reminderRepo,
+		NotificationRepo: notificationRepo,
+		ViewRepo:         viewRepo,
+		WebhookRepo:      webhookRepo,
+		InvitationRepo:   invitationRepo,
+		Tx:               tx,
+		AuthSrv:          authSrv,
+		cfg:              privacyCfg,
+	}
+}
+
+// ExportUser collects all personal data stored for the user across every organization.
+func (s *PrivacyService) ExportUser(ctx context.Context, userID uint) (*dto.UserExport, error) {
+	ctx = tenant.Unscoped(ctx)
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, api_error.ErrUserNotFound
+	}
+
+	created, err := s.collectTasks(ctx, dto.TaskListFilter{CreatedBy: userID})
+	if err != nil {
+		return nil, err
+	}
+	assigned, err := s.collectTasks(ctx, dto.TaskListFilter{Assignee: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	projects, _, err := s.ProjectRepo.ListByFilter(ctx, dto.ProjectListFilter{Owner: userID}, -1, -1)
+	if err != nil {
+		return nil, err
+	}
+	projectResps := make([]dto.ProjectResp, len(projects))
+	for i, p := range projects {
+		projectResps[i] = *projectToResp(&p)
+	}
+
+	sessions, err := s.SessionRepo.ListByUser(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	sessionResps := make([]dto.SessionResp, len(sessions))
+	for i, ss := range sessions {
+		sessionResps[i] = *sessionToResp(&ss)
+	}
+
+	identities, err := s.IdentityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identityResps := make([]dto.IdentityResp, len(identities))
+	for i, id := range identities {
+		identityResps[i] = dto.IdentityResp{
+			Provider:  id.Provider,
+			Subject:   id.Subject,
+			Email:     id.Email,
+			CreatedAt: id.CreatedAt,
+		}
+	}
+
+	notifications, _, err := s.NotificationRepo.ListByUser(ctx, userID, false, -1, -1)
+	if err != nil {
+		return nil, err
+	}
+	notificationResps := make([]dto.NotificationResp, len(notifications))
+	for i, n := range notifications {
+		notificationResps[i] = *notificationToResp(&n)
+	}
+
+	reminders, err := s.ReminderRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	reminderResps := make([]dto.ReminderResp, len(reminders))
+	for i, r := range reminders {
+		reminderResps[i] = *reminderToResp(&r)
+	}
+
+	views, err := s.ViewRepo.ListByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := s.ViewRepo.ListPins(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	viewResps := make([]dto.ViewResp, len(views))
+	for i, v := range views {
+		viewResps[i] = *viewToResp(&v, "", slices.Contains(pins, viewRef(&v, "")))
+	}
+
+	return &dto.UserExport{
+		ExportedAt: time.Now(),
+		Profile: dto.UserProfileResp{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Avatar:   user.Avatar,
+		},
+		Projects:      projectResps,
+		TasksCreated:  created,
+		Assignments:   assigned,
+		Sessions:      sessionResps,
+		Identities:    identityResps,
+		Notifications: notificationResps,
+		Reminders:     reminderResps,
+		Views:         viewResps,
+	}, nil
+}
+
+// ExportUserArchive returns the export as a ZIP archive with one JSON document per section.
+func (s *PrivacyService) ExportUserArchive(ctx context.Context, userID uint) ([]byte, error) {
+	export, err := s.ExportUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"projects.json", export.Projects},
+		{"tasks_created.json", export.TasksCreated},
+		{"assignments.json", export.Assignments},
+		{"sessions.json", export.Sessions},
+		{"identities.json", export.Identities},
+		{"notifications.json", export.Notifications},
+		{"reminders.json", export.Reminders},
+		{"views.json", export.Views},
+	}
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: export.ExportedAt})
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *PrivacyService) collectTasks(ctx context.Context, filter dto.TaskListFilter) ([]dto.TaskResp, error) {
+	resps := []dto.TaskResp{}
+	for offset := 0; ; offset += exportPageSize {
+		tasks, _, err := s.TaskRepo.ListByFilter(ctx, filter, exportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for i := range tasks {
+			resps = append(resps, *taskToResp(&tasks[i]))
+		}
+		if len(tasks) < exportPageSize {
+			return resps, nil
+		}
+	}
+}
+
+// DeleteAccount removes the user's personal data according to the configured deletion mode
+// and hands the tasks, projects, shared views and webhooks they created over according to the
+// task policy. Their other views, view pins and unanswered invitations are removed. Everything
+// runs in a single transaction; the user's tokens are revoked first so no session outlives the account.
+// Data in every organization the user belongs to is affected.
+func (s *PrivacyService) DeleteAccount(ctx context.Context, userID uint) error {
+	ctx = tenant.Unscoped(ctx)
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return api_error.ErrUserNotFound
+	}
+
+	return s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.AuthSrv.RevokeUserSessions(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.SessionRepo.DeleteByUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.IdentityRepo.DeleteByUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.TaskRepo.RemoveAssignee(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.TaskRepo.RemoveWatcher(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.OrgRepo.DeleteMembershipsByUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.ProjectRepo.DeleteMembersByUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.ReminderRepo.DeleteByUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.NotificationRepo.DeleteByUser(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.InvitationRepo.DeletePendingByUser(ctx, userID, user.Email); err != nil {
+			return err
+		}
+		if err := s.applyTaskPolicy(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.applyProjectPolicy(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.applyViewPolicy(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.applyWebhookPolicy(ctx, userID); err != nil {
+			return err
+		}
+
+		if s.cfg.DeletionMode == DeletionModeDelete {
+			if err := s.TaskRepo.ClearUpdater(ctx, userID); err != nil {
+				return err
+			}
+			return s.UserRepo.DeleteByID(ctx, userID)
+		}
+
+		now := time.Now()
+		user.Username = fmt.Sprintf("deleted-user-%d", user.ID)
+		user.Email = ""
+		user.EmailVerified = false
+		user.Avatar = ""
+		user.Password = ""
+		user.DeactivatedAt = &now
+		return s.UserRepo.UpdateByID(ctx, &user, []string{"username", "email", "email_verified", "avatar", "password", "deactivated_at"})
+	})
+}
+
+// applyTaskPolicy deals with the tasks created by userID. Reassigning falls back to
+// orphaning when no other target user is configured.
+func (s *PrivacyService) applyTaskPolicy(ctx context.Context, userID uint) error {
+	switch s.cfg.TaskPolicy {
+	case TaskPolicyDelete:
+		return s.TaskRepo.DeleteByCreator(ctx, userID)
+	case TaskPolicyReassign:
+		if to := s.cfg.ReassignTo; to != 0 && to != userID {
+			return s.TaskRepo.ReassignCreator(ctx, userID, &to)
+		}
+	}
+	return s.TaskRepo.ReassignCreator(ctx, userID, nil)
+}
+
+// applyProjectPolicy deals with the projects owned by userID. They follow the tasks when
+// those are reassigned; otherwise they stay with an anonymized owner or are deleted
+// together with the account.
+func (s *PrivacyService) applyProjectPolicy(ctx context.Context, userID uint) error {
+	if to := s.reassignTo(userID); to != 0 {
+		return s.ProjectRepo.ReassignOwner(ctx, userID, to)
+	}
+	if s.cfg.DeletionMode == DeletionModeDelete {
+		return s.ProjectRepo.DeleteByOwner(ctx, userID)
+	}
+	return nil
+}
+
+// applyViewPolicy deletes the views saved by userID and their pins. Shared views others
+// rely on follow the tasks when those are reassigned.
+func (s *PrivacyService) applyViewPolicy(ctx context.Context, userID uint) error {
+	if to := s.reassignTo(userID); to != 0 {
+		if err := s.ViewRepo.ReassignShared(ctx, userID, to); err != nil {
+			return err
+		}
+	}
+	return s.ViewRepo.DeleteByOwner(ctx, userID)
+}
+
+// applyWebhookPolicy deals with the webhooks userID created. They follow the tasks when
+// those are reassigned and are deleted otherwise.
+func (s *PrivacyService) applyWebhookPolicy(ctx context.Context, userID uint) error {
+	if to := s.reassignTo(userID); to != 0 {
+		return s.WebhookRepo.ReassignCreator(ctx, userID, to)
+	}
+	return s.WebhookRepo.DeleteByCreator(ctx, userID)
+}
+
+// reassignTo returns the user the data of userID is handed over to, or 0 when the task
+// policy does not reassign.
+func (s *PrivacyService) reassignTo(userID uint) uint {
+	if to := s.cfg.ReassignTo; s.cfg.TaskPolicy == TaskPolicyReassign && to != userID {
+		return to
+	}
+	return 0
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type privacyMocks struct {
+	userRepo     *mockRepo.MockUserRepo
+	sessionRepo  *mockRepo.MockSessionRepo
+	identityRepo *mockRepo.MockIdentityRepo
+	taskRepo     *mockRepo.MockTaskRepo
+	projectRepo  *mockRepo.MockProjectRepo
+	orgRepo      *mockRepo.MockOrgRepo
+	reminderRepo *mockRepo.MockReminderRepo
+	notifyRepo   *mockRepo.MockNotificationRepo
+	viewRepo     *mockRepo.MockViewRepo
+	webhookRepo  *mockRepo.MockWebhookRepo
+	inviteRepo   *mockRepo.MockInvitationRepo
+}
+
+func setupPrivacyTest(t *testing.T, privacyCfg cfg.PrivacyCfg) (*PrivacyService, privacyMocks) {
+	t.Helper()
+	authSrv, userRepo, sessionRepo, mr := setupAuthTest(t)
+	t.Cleanup(mr.Close)
+	m := privacyMocks{
+		userRepo:     userRepo,
+		sessionRepo:  sessionRepo,
+		identityRepo: new(mockRepo.MockIdentityRepo),
+		taskRepo:     new(mockRepo.MockTaskRepo),
+		projectRepo:  new(mockRepo.MockProjectRepo),
+		orgRepo:      new(mockRepo.MockOrgRepo),
+		reminderRepo: new(mockRepo.MockReminderRepo),
+		notifyRepo:   new(mockRepo.MockNotificationRepo),
+		viewRepo:     new(mockRepo.MockViewRepo),
+		webhookRepo:  new(mockRepo.MockWebhookRepo),
+		inviteRepo:   new(mockRepo.MockInvitationRepo),
+	}
+	svc := NewPrivacyService(m.userRepo, m.sessionRepo, m.identityRepo, m.taskRepo, m.projectRepo, m.orgRepo, m.reminderRepo, m.notifyRepo, m.viewRepo, m.webhookRepo, m.inviteRepo, mockRepo.NoopTransactor{}, authSrv, privacyCfg)
+	return svc, m
+}
+
+func privacyUser() domain.User {
+	user := domain.User{Username: "john", Email: "john@example.com", Avatar: "a.png"}
+	user.ID = 5
+	return user
+}
+
+func TestExportUser_Success(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{})
+
+	creator := uint(5)
+	m.userRepo.On("GetByID", mock.Anything, uint(5)).Return(privacyUser(), nil)
+	m.taskRepo.On("ListByFilter", mock.Anything, dto.TaskListFilter{CreatedBy: 5}, exportPageSize, 0).
+		Return([]domain.Task{{Name: "mine", CreatedByUserID: &creator}}, int64(1), nil)
+	m.taskRepo.On("ListByFilter", mock.Anything, dto.TaskListFilter{Assignee: 5}, exportPageSize, 0).
+		Return([]domain.Task{}, int64(0), nil)
+	m.projectRepo.On("ListByFilter", mock.Anything, dto.ProjectListFilter{Owner: 5}, -1, -1).
+		Return([]domain.Project{{Name: "Home", OwnerID: 5}}, int64(1), nil)
+	m.sessionRepo.On("ListByUser", mock.Anything, uint(5), false).
+		Return([]domain.UserSession{{UserID: 5, UserAgent: "curl"}}, nil)
+	m.identityRepo.On("ListByUser", mock.Anything, uint(5)).
+		Return([]domain.UserIdentity{{UserID: 5, Provider: "company", Subject: "sub"}}, nil)
+	m.notifyRepo.On("ListByUser", mock.Anything, uint(5), false, -1, -1).
+		Return([]domain.Notification{{UserID: 5, Title: "Assigned"}}, int64(1), nil)
+	m.reminderRepo.On("ListByUser", mock.Anything, uint(5)).
+		Return([]domain.Reminder{{UserID: 5, Channel: "email"}}, nil)
+	view := domain.View{OwnerID: 5, Name: "Mine"}
+	view.ID = 8
+	m.viewRepo.On("ListByOwner", mock.Anything, uint(5)).Return([]domain.View{view}, nil)
+	m.viewRepo.On("ListPins", mock.Anything, uint(5)).Return([]string{"8"}, nil)
+
+	export, err := svc.ExportUser(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", export.Profile.Email)
+	assert.Len(t, export.TasksCreated, 1)
+	assert.Equal(t, "Home", export.Projects[0].Name)
+	assert.Empty(t, export.Assignments)
+	assert.Equal(t, "curl", export.Sessions[0].UserAgent)
+	assert.Equal(t, "company", export.Identities[0].Provider)
+	assert.Equal(t, "Assigned", export.Notifications[0].Title)
+	assert.Equal(t, "email", export.Reminders[0].Channel)
+	assert.Equal(t, "Mine", export.Views[0].Name)
+	assert.True(t, export.Views[0].Pinned)
+}
+
+func TestExportUser_NotFound(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{})
+	m.userRepo.On("GetByID", mock.Anything, uint(5)).Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.ExportUser(context.Background(), 5)
+
+	assert.ErrorIs(t, err, api_error.ErrUserNotFound)
+}
+
+func TestExportUserArchive_ContainsSections(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{})
+
+	m.userRepo.On("GetByID", mock.Anything, uint(5)).Return(privacyUser(), nil)
+	m.taskRepo.On("ListByFilter", mock.Anything, mock.Anything, exportPageSize, 0).Return([]domain.Task{}, int64(0), nil)
+	m.projectRepo.On("ListByFilter", mock.Anything, mock.Anything, -1, -1).Return([]domain.Project{}, int64(0), nil)
+	m.sessionRepo.On("ListByUser", mock.Anything, uint(5), false).Return([]domain.UserSession{}, nil)
+	m.identityRepo.On("ListByUser", mock.Anything, uint(5)).Return([]domain.UserIdentity{}, nil)
+	m.notifyRepo.On("ListByUser", mock.Anything, uint(5), false, -1, -1).Return([]domain.Notification{}, int64(0), nil)
+	m.reminderRepo.On("ListByUser", mock.Anything, uint(5)).Return([]domain.Reminder{}, nil)
+	m.viewRepo.On("ListByOwner", mock.Anything, uint(5)).Return([]domain.View{}, nil)
+	m.viewRepo.On("ListPins", mock.Anything, uint(5)).Return([]string{}, nil)
+
+	data, err := svc.ExportUserArchive(context.Background(), 5)
+	assert.NoError(t, err)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"profile.json", "projects.json", "tasks_created.json", "assignments.json", "sessions.json", "identities.json", "notifications.json", "reminders.json", "views.json"}, names)
+}
+
+func expectAccountCleanup(m privacyMocks) {
+	m.userRepo.On("GetByID", mock.Anything, uint(5)).Return(privacyUser(), nil)
+	m.sessionRepo.On("ListByUser", mock.Anything, uint(5), true).Return([]domain.UserSession{}, nil)
+	m.sessionRepo.On("DeleteByUser", mock.Anything, uint(5)).Return(nil)
+	m.identityRepo.On("DeleteByUser", mock.Anything, uint(5)).Return(nil)
+	m.taskRepo.On("RemoveAssignee", mock.Anything, uint(5)).Return(nil)
+	m.taskRepo.On("RemoveWatcher", mock.Anything, uint(5)).Return(nil)
+	m.orgRepo.On("DeleteMembershipsByUser", mock.Anything, uint(5)).Return(nil)
+	m.projectRepo.On("DeleteMembersByUser", mock.Anything, uint(5)).Return(nil)
+	m.reminderRepo.On("DeleteByUser", mock.Anything, uint(5)).Return(nil)
+	m.notifyRepo.On("DeleteByUser", mock.Anything, uint(5)).Return(nil)
+	m.inviteRepo.On("DeletePendingByUser", mock.Anything, uint(5), "john@example.com").Return(nil)
+	m.viewRepo.On("DeleteByOwner", mock.Anything, uint(5)).Return(nil)
+}
+
+func TestDeleteAccount_AnonymizeAndOrphan(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{})
+	expectAccountCleanup(m)
+	m.webhookRepo.On("DeleteByCreator", mock.Anything, uint(5)).Return(nil)
+	m.taskRepo.On("ReassignCreator", mock.Anything, uint(5), (*uint)(nil)).Return(nil)
+	m.userRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(u *domain.User) bool {
+		return u.Username == "deleted-user-5" && u.Email == "" && u.Password == "" && !u.Active()
+	}), []string{"username", "email", "email_verified", "avatar", "password", "deactivated_at"}).Return(nil)
+
+	err := svc.DeleteAccount(context.Background(), 5)
+
+	assert.NoError(t, err)
+	m.userRepo.AssertExpectations(t)
+	m.taskRepo.AssertExpectations(t)
+	m.viewRepo.AssertExpectations(t)
+	m.webhookRepo.AssertExpectations(t)
+	m.inviteRepo.AssertExpectations(t)
+	m.userRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestDeleteAccount_HardDeleteAndReassign(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{DeletionMode: DeletionModeDelete, TaskPolicy: TaskPolicyReassign, ReassignTo: 1})
+	expectAccountCleanup(m)
+	m.taskRepo.On("ReassignCreator", mock.Anything, uint(5), mock.MatchedBy(func(to *uint) bool {
+		return to != nil && *to == 1
+	})).Return(nil)
+	m.projectRepo.On("ReassignOwner", mock.Anything, uint(5), uint(1)).Return(nil)
+	m.viewRepo.On("ReassignShared", mock.Anything, uint(5), uint(1)).Return(nil)
+	m.webhookRepo.On("ReassignCreator", mock.Anything, uint(5), uint(1)).Return(nil)
+	m.taskRepo.On("ClearUpdater", mock.Anything, uint(5)).Return(nil)
+	m.userRepo.On("DeleteByID", mock.Anything, uint(5)).Return(nil)
+
+	err := svc.DeleteAccount(context.Background(), 5)
+
+	assert.NoError(t, err)
+	m.viewRepo.AssertExpectations(t)
+	m.webhookRepo.AssertExpectations(t)
+	m.webhookRepo.AssertNotCalled(t, "DeleteByCreator", mock.Anything, mock.Anything)
+	m.projectRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.taskRepo.AssertExpectations(t)
+	m.sessionRepo.AssertExpectations(t)
+	m.identityRepo.AssertExpectations(t)
+	m.orgRepo.AssertExpectations(t)
+}
+
+func TestDeleteAccount_DeleteTasks(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{DeletionMode: DeletionModeDelete, TaskPolicy: TaskPolicyDelete})
+	expectAccountCleanup(m)
+	m.webhookRepo.On("DeleteByCreator", mock.Anything, uint(5)).Return(nil)
+	m.taskRepo.On("DeleteByCreator", mock.Anything, uint(5)).Return(nil)
+	m.projectRepo.On("DeleteByOwner", mock.Anything, uint(5)).Return(nil)
+	m.taskRepo.On("ClearUpdater", mock.Anything, uint(5)).Return(nil)
+	m.userRepo.On("DeleteByID", mock.Anything, uint(5)).Return(nil)
+
+	err := svc.DeleteAccount(context.Background(), 5)
+
+	assert.NoError(t, err)
+	m.taskRepo.AssertExpectations(t)
+	m.taskRepo.AssertNotCalled(t, "ReassignCreator", mock.Anything, mock.Anything, mock.Anything)
+	m.projectRepo.AssertExpectations(t)
+}
+
+func TestDeleteAccount_ReassignToSelfOrphans(t *testing.T) {
+	svc, m := setupPrivacyTest(t, cfg.PrivacyCfg{TaskPolicy: TaskPolicyReassign, ReassignTo: 5})
+	expectAccountCleanup(m)
+	m.webhookRepo.On("DeleteByCreator", mock.Anything, uint(5)).Return(nil)
+	m.taskRepo.On("ReassignCreator", mock.Anything, uint(5), (*uint)(nil)).Return(nil)
+	m.userRepo.On("UpdateByID", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	assert.NoError(t, svc.DeleteAccount(context.Background(), 5))
+	m.taskRepo.AssertExpectations(t)
+}
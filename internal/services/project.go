@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+)
+
+type ProjectService struct {
+	ProjectRepo     repository.ProjectRepo
+	TaskRepo        repository.TaskRepo
+	WorkflowRepo    repository.WorkflowRepo
+	CustomFieldRepo repository.CustomFieldRepo
+	Tx              repository.Transactor
+}
+
+func NewProjectService(projectRepo repository.ProjectRepo, taskRepo repository.TaskRepo, workflowRepo repository.WorkflowRepo, customFieldRepo repository.CustomFieldRepo, tx repository.Transactor) *ProjectService {
+	return &ProjectService{
+		ProjectRepo:     projectRepo,
+		TaskRepo:        taskRepo,
+		WorkflowRepo:    workflowRepo,
+		CustomFieldRepo: customFieldRepo,
+		Tx:              tx,
+	}
+}
+
+func (s *ProjectService) CreateProject(ctx context.Context, req dto.CreateProjectReq, userID uint) (*dto.ProjectResp, error) {
+	project := &domain.Project{
+		Name:        req.Name,
+		Description: req.Description,
+		OwnerID:     userID,
+	}
+	if _, err := s.ProjectRepo.Create(ctx, project); err != nil {
+		return nil, err
+	}
+	return projectToResp(project), nil
+}
+
+func (s *ProjectService) GetProject(ctx context.Context, projectID uint) (*dto.ProjectResp, error) {
+	project, err := s.ProjectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return nil, api_error.ErrProjectNotFound
+	}
+	return projectToResp(&project), nil
+}
+
+func (s *ProjectService) ListProjects(ctx context.Context, filter dto.ProjectListFilter, limit, offset int) (*dto.ProjectListResp, error) {
+	projects, total, err := s.ProjectRepo.ListByFilter(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	projectResps := make([]dto.ProjectResp, len(projects))
+	for i, p := range projects {
+		projectResps[i] = *projectToResp(&p)
+	}
+
+	return &dto.ProjectListResp{
+		Projects: projectResps,
+		Total:    total,
+		Limit:    limit,
+		Offset:   offset,
+	}, nil
+}
+
+// UpdateProject changes a project's details or archived flag. Only the owner may do so.
+func (s *ProjectService) UpdateProject(ctx context.Context, projectID uint, req dto.UpdateProjectReq, userID uint) (*dto.ProjectResp, error) {
+	project, err := s.ownedProject(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	if req.Name != nil {
+		project.Name = *req.Name
+		fields = append(fields, "name")
+	}
+	if req.Description != nil {
+		project.Description = *req.Description
+		fields = append(fields, "description")
+	}
+	if req.Archived != nil {
+		project.Archived = *req.Archived
+		fields = append(fields, "archived")
+	}
+	if len(fields) == 0 {
+		return projectToResp(&project), nil
+	}
+
+	if err := s.ProjectRepo.UpdateByID(ctx, &project, fields); err != nil {
+		return nil, err
+	}
+	return projectToResp(&project), nil
+}
+
+// DeleteProject removes a project owned by userID along with its workflow and custom fields.
+// Its tasks are kept without a project.
+func (s *ProjectService) DeleteProject(ctx context.Context, projectID uint, userID uint) error {
+	if _, err := s.ownedProject(ctx, projectID, userID); err != nil {
+		return err
+	}
+	return s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.TaskRepo.ClearProject(ctx, projectID); err != nil {
+			return err
+		}
+		if err := s.WorkflowRepo.DeleteByProject(ctx, projectID); err != nil {
+			return err
+		}
+		if err := s.CustomFieldRepo.DeleteByProject(ctx, projectID); err != nil {
+			return err
+		}
+		return s.ProjectRepo.DeleteByID(ctx, projectID)
+	})
+}
+
+// ListProjectTasks lists the tasks of a project, narrowed down by the usual task filters.
+func (s *ProjectService) ListProjectTasks(ctx context.Context, projectID uint, filter dto.TaskListFilter, limit, offset int) (*dto.TaskListResp, error) {
+	if _, err := s.ProjectRepo.GetByID(ctx, projectID); err != nil {
+		return nil, api_error.ErrProjectNotFound
+	}
+
+	filter.ProjectID = projectID
+	tasks, total, err := s.TaskRepo.ListByFilter(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	taskResps := make([]dto.TaskResp, len(tasks))
+	for i, t := range tasks {
+		taskResps[i] = *taskToResp(&t)
+	}
+
+	return &dto.TaskListResp{
+		Tasks:  taskResps,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+func (s *ProjectService) ownedProject(ctx context.Context, projectID uint, userID uint) (domain.Project, error) {
+	project, err := s.ProjectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return domain.Project{}, api_error.ErrProjectNotFound
+	}
+	if project.OwnerID != userID {
+		return domain.Project{}, api_error.ErrForbidden
+	}
+	return project, nil
+}
+
+func projectToResp(project *domain.Project) *dto.ProjectResp {
+	return &dto.ProjectResp{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		OwnerID:     project.OwnerID,
+		Archived:    project.Archived,
+		CreatedAt:   project.CreatedAt,
+		UpdatedAt:   project.UpdatedAt,
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupProjectTest() (*ProjectService, *mockRepo.MockProjectRepo, *mockRepo.MockTaskRepo) {
+	projectRepo := new(mockRepo.MockProjectRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewProjectService(projectRepo, taskRepo, new(mockRepo.MockWorkflowRepo), new(mockRepo.MockCustomFieldRepo), mockRepo.NoopTransactor{})
+	return svc, projectRepo, taskRepo
+}
+
+func ownedProject(id, owner uint) domain.Project {
+	project := domain.Project{Name: "Home", OwnerID: owner}
+	project.ID = id
+	return project
+}
+
+func TestCreateProject_Success(t *testing.T) {
+	svc, projectRepo, _ := setupProjectTest()
+
+	projectRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Project) bool {
+		return p.Name == "Home" && p.OwnerID == 1
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Project).ID = 3
+	}).Return(uint(3), nil)
+
+	resp, err := svc.CreateProject(context.Background(), dto.CreateProjectReq{Name: "Home"}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), resp.ID)
+	assert.Equal(t, uint(1), resp.OwnerID)
+	assert.False(t, resp.Archived)
+}
+
+func TestGetProject_NotFound(t *testing.T) {
+	svc, projectRepo, _ := setupProjectTest()
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Project{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.GetProject(context.Background(), 3)
+
+	assert.Equal(t, api_error.ErrProjectNotFound, err)
+}
+
+func TestUpdateProject_Archive(t *testing.T) {
+	svc, projectRepo, _ := setupProjectTest()
+
+	archived := true
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(ownedProject(3, 1), nil)
+	projectRepo.On("UpdateByID", mock.Anything, mock.Anything, []string{"archived"}).Return(nil)
+
+	resp, err := svc.UpdateProject(context.Background(), 3, dto.UpdateProjectReq{Archived: &archived}, 1)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Archived)
+	projectRepo.AssertExpectations(t)
+}
+
+func TestUpdateProject_NotOwner(t *testing.T) {
+	svc, projectRepo, _ := setupProjectTest()
+
+	name := "Work"
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(ownedProject(3, 2), nil)
+
+	_, err := svc.UpdateProject(context.Background(), 3, dto.UpdateProjectReq{Name: &name}, 1)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	projectRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteProject_DetachesTasks(t *testing.T) {
+	svc, projectRepo, taskRepo := setupProjectTest()
+	workflowRepo := svc.WorkflowRepo.(*mockRepo.MockWorkflowRepo)
+	customFieldRepo := svc.CustomFieldRepo.(*mockRepo.MockCustomFieldRepo)
+
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(ownedProject(3, 1), nil)
+	taskRepo.On("ClearProject", mock.Anything, uint(3)).Return(nil)
+	workflowRepo.On("DeleteByProject", mock.Anything, uint(3)).Return(nil)
+	customFieldRepo.On("DeleteByProject", mock.Anything, uint(3)).Return(nil)
+	projectRepo.On("DeleteByID", mock.Anything, uint(3)).Return(nil)
+
+	err := svc.DeleteProject(context.Background(), 3, 1)
+
+	assert.NoError(t, err)
+	projectRepo.AssertExpectations(t)
+	taskRepo.AssertExpectations(t)
+	workflowRepo.AssertExpectations(t)
+	customFieldRepo.AssertExpectations(t)
+}
+
+func TestListProjectTasks_ScopesFilter(t *testing.T) {
+	svc, projectRepo, taskRepo := setupProjectTest()
+
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(ownedProject(3, 1), nil)
+	taskRepo.On("ListByFilter", mock.Anything, dto.TaskListFilter{Assignee: 2, ProjectID: 3}, 20, 0).
+		Return([]domain.Task{{Name: "t"}}, int64(1), nil)
+
+	resp, err := svc.ListProjectTasks(context.Background(), 3, dto.TaskListFilter{Assignee: 2}, 20, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Total)
+	taskRepo.AssertExpectations(t)
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/logger"
+	redis_pkg "graph-interview/pkg/redis"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultReminderInterval    = 30 * time.Second
+	defaultReminderLockTTL     = time.Minute
+	defaultReminderBatchSize   = 100
+	defaultReminderMaxAttempts = 5
+)
+
+// ReminderService manages the reminders users set on tasks and fires them once they are due.
+// Reminders are personal: users see and delete only their own, and any member who can see
+// a task may set one.
+type ReminderService struct {
+	ReminderRepo repository.ReminderRepo
+	TaskRepo     repository.TaskRepo
+	ProjectRepo  repository.ProjectRepo
+	OrgRepo      repository.OrgRepo
+	Channels     map[string]ReminderChannel
+	lock         *redis_pkg.Lock
+	cfg          cfg.ReminderCfg
+}
+
+func NewReminderService(
+	reminderRepo repository.ReminderRepo,
+	taskRepo repository.TaskRepo,
+	projectRepo repository.ProjectRepo,
+	orgRepo repository.OrgRepo,
+	channels map[string]ReminderChannel,
+	rdb *redis.Client,
+	reminderCfg cfg.ReminderCfg,
+) *ReminderService {
+	if reminderCfg.Interval <= 0 {
+		reminderCfg.Interval = defaultReminderInterval
+	}
+	if reminderCfg.LockTTL <= 0 {
+		reminderCfg.LockTTL = defaultReminderLockTTL
+	}
+	if reminderCfg.BatchSize <= 0 {
+		reminderCfg.BatchSize = defaultReminderBatchSize
+	}
+	if reminderCfg.MaxAttempts <= 0 {
+		reminderCfg.MaxAttempts = defaultReminderMaxAttempts
+	}
+	return &ReminderService{
+		ReminderRepo: reminderRepo,
+		TaskRepo:     taskRepo,
+		ProjectRepo:  projectRepo,
+		OrgRepo:      orgRepo,
+		Channels:     channels,
+		lock:         redis_pkg.NewLock(rdb, cache.ReminderLockKey, reminderCfg.LockTTL),
+		cfg:          reminderCfg,
+	}
+}
+
+func (s *ReminderService) CreateReminder(ctx context.Context, taskID uint, req dto.CreateReminderReq, userID uint) (*dto.ReminderResp, error) {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	if err := requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, task.ProjectID, enum.MemberViewer); err != nil {
+		return nil, err
+	}
+	if req.RemindAt != nil && !req.RemindAt.After(time.Now()) {
+		return nil, api_error.ErrRemindAtInPast
+	}
+	if req.BeforeDueMinutes != nil && task.DueDate == nil {
+		return nil, api_error.ErrReminderNoDue
+	}
+	if req.Channel == ReminderChannelWebhook {
+		if err := checkTargetURL(req.Target); err != nil {
+			return nil, err
+		}
+	}
+
+	reminder := &domain.Reminder{
+		TaskID:           taskID,
+		UserID:           userID,
+		RemindAt:         req.RemindAt,
+		BeforeDueMinutes: req.BeforeDueMinutes,
+		Channel:          req.Channel,
+		Target:           req.Target,
+	}
+	if _, err := s.ReminderRepo.Create(ctx, reminder); err != nil {
+		return nil, err
+	}
+	reminder.Task = &task
+	return reminderToResp(reminder), nil
+}
+
+// ListReminders returns the user's own reminders on a task.
+func (s *ReminderService) ListReminders(ctx context.Context, taskID, userID uint) ([]dto.ReminderResp, error) {
+	if _, err := s.TaskRepo.GetByID(ctx, taskID); err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	reminders, err := s.ReminderRepo.ListByTask(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]dto.ReminderResp, len(reminders))
+	for i := range reminders {
+		resps[i] = *reminderToResp(&reminders[i])
+	}
+	return resps, nil
+}
+
+func (s *ReminderService) DeleteReminder(ctx context.Context, reminderID, userID uint) error {
+	reminder, err := s.ReminderRepo.GetByID(ctx, reminderID)
+	if err != nil || reminder.UserID != userID {
+		return api_error.ErrReminderNotFound
+	}
+	return s.ReminderRepo.DeleteByID(ctx, reminderID)
+}
+
+// Run fires due reminders every interval until ctx is done. API instances share a Redis
+// lock, so only one of them works through due reminders at a time and each reminder fires
+// once. A batch stops well before the lock expires; what is left waits for the next tick.
+func (s *ReminderService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.tick(ctx); err != nil {
+				logger.Logger.Warn("reminder scheduler failed", "err", err)
+			}
+		}
+	}
+}
+
+func (s *ReminderService) tick(ctx context.Context) error {
+	ok, err := s.lock.Acquire(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	defer func() {
+		_ = s.lock.Release(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.LockTTL/2)
+	defer cancel()
+	_, err = s.FireDue(ctx, time.Now())
+	return err
+}
+
+// FireDue delivers the reminders due at now and returns how many were sent. A reminder is
+// marked sent only once its channel accepted it; failed deliveries are retried on later
+// calls until MaxAttempts is reached.
+func (s *ReminderService) FireDue(ctx context.Context, now time.Time) (int, error) {
+	ctx = tenant.Unscoped(ctx)
+	reminders, err := s.ReminderRepo.ListDue(ctx, now, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range reminders {
+		if ctx.Err() != nil {
+			break
+		}
+		reminder := &reminders[i]
+
+		err := s.deliver(ctx, reminder)
+		fields := []string{"attempts", "last_error"}
+		reminder.Attempts++
+		if err == nil {
+			reminder.LastError = ""
+			reminder.SentAt = &now
+			fields = append(fields, "sent_at")
+			sent++
+		} else {
+			reminder.LastError = err.Error()
+			if reminder.Attempts >= s.cfg.MaxAttempts {
+				reminder.FailedAt = &now
+				fields = append(fields, "failed_at")
+			}
+			logger.Logger.Warn("reminder delivery failed", "reminder", reminder.ID, "channel", reminder.Channel, "err", err)
+		}
+		// Record the outcome even when the batch ran out of time during the delivery.
+		if err := s.ReminderRepo.UpdateByID(context.WithoutCancel(ctx), reminder, fields); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+func (s *ReminderService) deliver(ctx context.Context, reminder *domain.Reminder) error {
+	channel, ok := s.Channels[reminder.Channel]
+	if !ok {
+		return fmt.Errorf("unknown reminder channel %q", reminder.Channel)
+	}
+	return channel.Deliver(ctx, reminder)
+}
+
+func reminderToResp(reminder *domain.Reminder) *dto.ReminderResp {
+	return &dto.ReminderResp{
+		ID:               reminder.ID,
+		TaskID:           reminder.TaskID,
+		RemindAt:         reminder.RemindAt,
+		BeforeDueMinutes: reminder.BeforeDueMinutes,
+		FiresAt:          reminder.FiresAt(),
+		Channel:          reminder.Channel,
+		Target:           reminder.Target,
+		SentAt:           reminder.SentAt,
+		FailedAt:         reminder.FailedAt,
+		CreatedAt:        reminder.CreatedAt,
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/pkg/mailer"
+	"net/http"
+	"time"
+)
+
+const (
+	ReminderChannelEmail   = "email"
+	ReminderChannelWebhook = "webhook"
+	ReminderChannelInApp   = "in_app"
+)
+
+// ReminderChannel delivers a fired reminder. The reminder comes with its task and user loaded.
+type ReminderChannel interface {
+	Deliver(ctx context.Context, reminder *domain.Reminder) error
+}
+
+// EmailChannel mails reminders to the user's address.
+type EmailChannel struct {
+	Mailer mailer.Mailer
+}
+
+func (ch EmailChannel) Deliver(ctx context.Context, reminder *domain.Reminder) error {
+	if reminder.User == nil || reminder.User.Email == "" {
+		return errors.New("user has no email address")
+	}
+	return ch.Mailer.Send(ctx, mailer.Message{
+		To:      reminder.User.Email,
+		Subject: "Reminder: " + reminder.Task.Name,
+		Body:    reminderText(reminder.Task) + "\n",
+	})
+}
+
+// WebhookChannel posts reminders as JSON to the reminder's target URL. Targets are user
+// supplied, so Client should come from safehttp.NewClient.
+type WebhookChannel struct {
+	Client *http.Client
+}
+
+type reminderPayload struct {
+	Event      string     `json:"event"`
+	ReminderID uint       `json:"reminder_id"`
+	TaskID     uint       `json:"task_id"`
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	DueDate    *time.Time `json:"due_date,omitempty"`
+	FiredAt    time.Time  `json:"fired_at"`
+}
+
+func (ch WebhookChannel) Deliver(ctx context.Context, reminder *domain.Reminder) error {
+	body, err := json.Marshal(reminderPayload{
+		Event:      "task.reminder",
+		ReminderID: reminder.ID,
+		TaskID:     reminder.TaskID,
+		Name:       reminder.Task.Name,
+		Status:     reminder.Task.Status.String(),
+		DueDate:    reminder.Task.DueDate,
+		FiredAt:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reminder.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ch.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// InAppChannel puts reminders into the user's notification inbox.
+type InAppChannel struct {
+	NotificationRepo repository.NotificationRepo
+}
+
+func (ch InAppChannel) Deliver(ctx context.Context, reminder *domain.Reminder) error {
+	_, err := ch.NotificationRepo.Create(ctx, &domain.Notification{
+		UserID:         reminder.UserID,
+		OrganizationID: reminder.OrganizationID,
+		TaskID:         &reminder.TaskID,
+		Kind:           NotificationReminder,
+		Title:          "Reminder: " + reminder.Task.Name,
+		Body:           reminderText(reminder.Task),
+	})
+	return err
+}
+
+func reminderText(task *domain.Task) string {
+	if task.DueDate == nil {
+		return fmt.Sprintf("Task %q is currently %s.", task.Name, task.StatusName())
+	}
+	return fmt.Sprintf("Task %q is due on %s and currently %s.", task.Name, task.DueDate.Format(time.RFC1123), task.StatusName())
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/pkg/safehttp"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type reminderMocks struct {
+	reminderRepo     *mockRepo.MockReminderRepo
+	taskRepo         *mockRepo.MockTaskRepo
+	notificationRepo *mockRepo.MockNotificationRepo
+	mailer           *recordingMailer
+}
+
+func setupReminderTest(rdb *redis.Client) (*ReminderService, reminderMocks) {
+	m := reminderMocks{
+		reminderRepo:     new(mockRepo.MockReminderRepo),
+		taskRepo:         new(mockRepo.MockTaskRepo),
+		notificationRepo: new(mockRepo.MockNotificationRepo),
+		mailer:           &recordingMailer{},
+	}
+	channels := map[string]ReminderChannel{
+		ReminderChannelEmail: EmailChannel{Mailer: m.mailer},
+		ReminderChannelInApp: InAppChannel{NotificationRepo: m.notificationRepo},
+	}
+	svc := NewReminderService(m.reminderRepo, m.taskRepo, nil, nil, channels, rdb, cfg.ReminderCfg{MaxAttempts: 2})
+	return svc, m
+}
+
+func dueReminder(channel string) domain.Reminder {
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	task := &domain.Task{Name: "Weekly report", Status: enum.Started, DueDate: &due}
+	task.ID = 3
+	user := &domain.User{Username: "john", Email: "john@example.com"}
+	user.ID = 1
+	reminder := domain.Reminder{TaskID: 3, Task: task, UserID: 1, User: user, Channel: channel}
+	reminder.ID = 8
+	return reminder
+}
+
+func TestCreateReminder_BeforeDue(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+
+	due := time.Now().Add(48 * time.Hour)
+	minutes := 60
+	m.taskRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Task{DueDate: &due}, nil)
+	m.reminderRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Reminder) bool {
+		return r.TaskID == 3 && r.UserID == 1 && *r.BeforeDueMinutes == 60
+	})).Return(uint(8), nil)
+
+	resp, err := svc.CreateReminder(context.Background(), 3, dto.CreateReminderReq{BeforeDueMinutes: &minutes, Channel: ReminderChannelEmail}, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, due.Add(-time.Hour), *resp.FiresAt)
+	m.reminderRepo.AssertExpectations(t)
+}
+
+func TestCreateReminder_BeforeDueWithoutDueDate(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+
+	minutes := 60
+	m.taskRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Task{}, nil)
+
+	_, err := svc.CreateReminder(context.Background(), 3, dto.CreateReminderReq{BeforeDueMinutes: &minutes, Channel: ReminderChannelEmail}, 1)
+
+	assert.Equal(t, api_error.ErrReminderNoDue, err)
+	m.reminderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateReminder_InPast(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+
+	at := time.Now().Add(-time.Minute)
+	m.taskRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Task{}, nil)
+
+	_, err := svc.CreateReminder(context.Background(), 3, dto.CreateReminderReq{RemindAt: &at, Channel: ReminderChannelInApp}, 1)
+
+	assert.Equal(t, api_error.ErrRemindAtInPast, err)
+}
+
+func TestCreateReminder_PrivateTarget(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+
+	at := time.Now().Add(time.Hour)
+	m.taskRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Task{}, nil)
+
+	for _, target := range []string{"http://127.0.0.1:6379/", "http://169.254.169.254/latest/meta-data", "http://192.168.0.10/hook"} {
+		_, err := svc.CreateReminder(context.Background(), 3, dto.CreateReminderReq{RemindAt: &at, Channel: ReminderChannelWebhook, Target: target}, 1)
+		assert.ErrorIs(t, err, api_error.ErrForbiddenURL, target)
+	}
+	m.reminderRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestDeleteReminder_OtherUser(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+
+	m.reminderRepo.On("GetByID", mock.Anything, uint(8)).Return(domain.Reminder{UserID: 2}, nil)
+
+	err := svc.DeleteReminder(context.Background(), 8, 1)
+
+	assert.Equal(t, api_error.ErrReminderNotFound, err)
+	m.reminderRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestFireDue_Delivers(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+
+	now := time.Now()
+	m.reminderRepo.On("ListDue", mock.Anything, now, defaultReminderBatchSize).
+		Return([]domain.Reminder{dueReminder(ReminderChannelEmail), dueReminder(ReminderChannelInApp)}, nil)
+	m.notificationRepo.On("Create", mock.Anything, mock.MatchedBy(func(n *domain.Notification) bool {
+		return n.UserID == 1 && *n.TaskID == 3 && n.Kind == "reminder"
+	})).Return(uint(1), nil)
+	m.reminderRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(r *domain.Reminder) bool {
+		return r.SentAt != nil && r.Attempts == 1
+	}), []string{"attempts", "last_error", "sent_at"}).Return(nil).Twice()
+
+	sent, err := svc.FireDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Len(t, m.mailer.sent, 1)
+	assert.Equal(t, "john@example.com", m.mailer.sent[0].To)
+	m.reminderRepo.AssertExpectations(t)
+}
+
+func TestFireDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	svc, m := setupReminderTest(nil)
+	m.mailer.err = errors.New("relay down")
+
+	now := time.Now()
+	reminder := dueReminder(ReminderChannelEmail)
+	reminder.Attempts = 1
+	m.reminderRepo.On("ListDue", mock.Anything, now, defaultReminderBatchSize).Return([]domain.Reminder{reminder}, nil)
+	m.reminderRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(r *domain.Reminder) bool {
+		return r.SentAt == nil && r.FailedAt != nil && r.LastError == "relay down"
+	}), []string{"attempts", "last_error", "failed_at"}).Return(nil)
+
+	sent, err := svc.FireDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, sent)
+	m.reminderRepo.AssertExpectations(t)
+}
+
+func TestTick_SkipsWhileAnotherInstanceHoldsTheLock(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	svc, m := setupReminderTest(rdb)
+
+	assert.NoError(t, mr.Set(cache.ReminderLockKey, "other-instance"))
+	assert.NoError(t, svc.tick(context.Background()))
+	m.reminderRepo.AssertNotCalled(t, "ListDue", mock.Anything, mock.Anything, mock.Anything)
+
+	mr.Del(cache.ReminderLockKey)
+	m.reminderRepo.On("ListDue", mock.Anything, mock.Anything, defaultReminderBatchSize).Return([]domain.Reminder{}, nil)
+	assert.NoError(t, svc.tick(context.Background()))
+	m.reminderRepo.AssertExpectations(t)
+	assert.False(t, mr.Exists(cache.ReminderLockKey))
+}
+
+func TestWebhookChannel_PostsPayload(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	reminder := dueReminder(ReminderChannelWebhook)
+	reminder.Target = srv.URL
+	err := WebhookChannel{Client: srv.Client()}.Deliver(context.Background(), &reminder)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "task.reminder", got["event"])
+	assert.Equal(t, "Weekly report", got["name"])
+}
+
+func TestWebhookChannel_RejectedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	reminder := dueReminder(ReminderChannelWebhook)
+	reminder.Target = srv.URL
+	err := WebhookChannel{Client: srv.Client()}.Deliver(context.Background(), &reminder)
+
+	assert.Error(t, err)
+}
+
+func TestWebhookChannel_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	reminder := dueReminder(ReminderChannelWebhook)
+	reminder.Target = srv.URL
+	err := WebhookChannel{Client: safehttp.NewClient(time.Second)}.Deliver(context.Background(), &reminder)
+
+	assert.ErrorIs(t, err, safehttp.ErrForbiddenAddress)
+	assert.False(t, called)
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"strings"
+	"time"
+)
+
+// ShareService manages public read-only links to tasks and projects. Creating, listing and
+// revoking links requires the editor role on what is shared.
+type ShareService struct {
+	ShareRepo   repository.ShareRepo
+	TaskRepo    repository.TaskRepo
+	ProjectRepo repository.ProjectRepo
+	OrgRepo     repository.OrgRepo
+	secret      []byte
+}
+
+func NewShareService(shareRepo repository.ShareRepo, taskRepo repository.TaskRepo, projectRepo repository.ProjectRepo, orgRepo repository.OrgRepo, secret string) *ShareService {
+	return &ShareService{
+		ShareRepo:   shareRepo,
+		TaskRepo:    taskRepo,
+		ProjectRepo: projectRepo,
+		OrgRepo:     orgRepo,
+		secret:      []byte(secret),
+	}
+}
+
+func (s *ShareService) CreateShare(ctx context.Context, req dto.CreateShareReq, userID uint) (*dto.ShareResp, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, api_error.ErrExpiryInPast
+	}
+	if err := s.authorize(ctx, req.TaskID, req.ProjectID, userID); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	link := &domain.ShareLink{
+		Nonce:       base64.RawURLEncoding.EncodeToString(b),
+		TaskID:      req.TaskID,
+		ProjectID:   req.ProjectID,
+		CreatedByID: userID,
+		ExpiresAt:   req.ExpiresAt,
+	}
+	if _, err := s.ShareRepo.Create(ctx, link); err != nil {
+		return nil, err
+	}
+	return s.shareToResp(link), nil
+}
+
+// ListShares lists the active links with their working tokens. It requires the editor role
+// on the task or project the filter names, or in the organization when it names neither.
+func (s *ShareService) ListShares(ctx context.Context, filter dto.ShareListFilter, userID uint) ([]dto.ShareResp, error) {
+	var err error
+	switch {
+	case filter.TaskID != 0:
+		err = s.authorize(ctx, &filter.TaskID, nil, userID)
+	case filter.ProjectID != 0:
+		err = s.authorize(ctx, nil, &filter.ProjectID, userID)
+	default:
+		err = requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, nil, enum.MemberEditor)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	links, err := s.ShareRepo.ListActive(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]dto.ShareResp, len(links))
+	for i := range links {
+		resps[i] = *s.shareToResp(&links[i])
+	}
+	return resps, nil
+}
+
+// RevokeShare disables a link for good.
+func (s *ShareService) RevokeShare(ctx context.Context, shareID, userID uint) error {
+	link, err := s.ShareRepo.GetByID(ctx, shareID)
+	if err != nil || link.RevokedAt != nil {
+		return api_error.ErrShareNotFound
+	}
+	if err := s.authorize(ctx, link.TaskID, link.ProjectID, userID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	link.RevokedAt = &now
+	return s.ShareRepo.UpdateByID(ctx, &link, []string{"revoked_at"})
+}
+
+// Resolve serves the shared task or project for a token. It runs without a user, so the
+// organization is taken from the link itself.
+func (s *ShareService) Resolve(ctx context.Context, token string, limit, offset int) (*dto.SharedResp, error) {
+	nonce, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.signature(nonce))) {
+		return nil, api_error.ErrShareNotFound
+	}
+	link, err := s.ShareRepo.GetByNonce(tenant.Unscoped(ctx), nonce)
+	if err != nil || !link.Active() {
+		return nil, api_error.ErrShareNotFound
+	}
+
+	ctx = tenant.WithOrg(ctx, link.OrganizationID)
+	resp := &dto.SharedResp{ExpiresAt: link.ExpiresAt}
+	if link.TaskID != nil {
+		task, err := s.TaskRepo.GetByID(ctx, *link.TaskID)
+		if err != nil {
+			return nil, api_error.ErrShareNotFound
+		}
+		resp.Task = sharedTask(&task)
+		return resp, nil
+	}
+
+	project, err := s.ProjectRepo.GetByID(ctx, *link.ProjectID)
+	if err != nil {
+		return nil, api_error.ErrShareNotFound
+	}
+	tasks, total, err := s.TaskRepo.ListByFilter(ctx, dto.TaskListFilter{ProjectID: project.ID}, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	shared := make([]dto.SharedTaskResp, len(tasks))
+	for i := range tasks {
+		shared[i] = *sharedTask(&tasks[i])
+	}
+	resp.Project = &dto.SharedProjectResp{
+		Name:        project.Name,
+		Description: project.Description,
+		Tasks:       shared,
+		Total:       total,
+		Limit:       limit,
+		Offset:      offset,
+	}
+	return resp, nil
+}
+
+// authorize requires the editor role on the shared task's project, or on the shared project.
+func (s *ShareService) authorize(ctx context.Context, taskID, projectID *uint, userID uint) error {
+	if taskID != nil {
+		task, err := s.TaskRepo.GetByID(ctx, *taskID)
+		if err != nil {
+			return api_error.ErrTaskNotFound
+		}
+		projectID = task.ProjectID
+	} else if _, err := s.ProjectRepo.GetByID(ctx, *projectID); err != nil {
+		return api_error.ErrProjectNotFound
+	}
+	return requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberEditor)
+}
+
+func (s *ShareService) signature(nonce string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("share:" + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *ShareService) shareToResp(link *domain.ShareLink) *dto.ShareResp {
+	return &dto.ShareResp{
+		ID:        link.ID,
+		Token:     link.Nonce + "." + s.signature(link.Nonce),
+		TaskID:    link.TaskID,
+		ProjectID: link.ProjectID,
+		ExpiresAt: link.ExpiresAt,
+		CreatedAt: link.CreatedAt,
+	}
+}
+
+func sharedTask(task *domain.Task) *dto.SharedTaskResp {
+	return &dto.SharedTaskResp{
+		Name:        task.Name,
+		Description: task.Description,
+		Status:      task.StatusName(),
+		CreatedAt:   task.CreatedAt,
+		UpdatedAt:   task.UpdatedAt,
+	}
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type shareMocks struct {
+	shareRepo   *mockRepo.MockShareRepo
+	taskRepo    *mockRepo.MockTaskRepo
+	projectRepo *mockRepo.MockProjectRepo
+	orgRepo     *mockRepo.MockOrgRepo
+}
+
+func setupShareTest() (*ShareService, shareMocks) {
+	m := shareMocks{
+		shareRepo:   new(mockRepo.MockShareRepo),
+		taskRepo:    new(mockRepo.MockTaskRepo),
+		projectRepo: new(mockRepo.MockProjectRepo),
+		orgRepo:     new(mockRepo.MockOrgRepo),
+	}
+	return NewShareService(m.shareRepo, m.taskRepo, m.projectRepo, m.orgRepo, "test-secret"), m
+}
+
+func TestCreateShare_TokenResolves(t *testing.T) {
+	svc, m := setupShareTest()
+
+	taskID := uint(1)
+	var stored *domain.ShareLink
+	m.taskRepo.On("GetByID", mock.Anything, taskID).Return(domain.Task{Name: "t", Description: "d", Status: enum.Started}, nil)
+	m.shareRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.ShareLink")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.ShareLink)
+		stored.OrganizationID = 7
+	}).Return(uint(2), nil)
+
+	resp, err := svc.CreateShare(context.Background(), dto.CreateShareReq{TaskID: &taskID}, 1)
+	assert.NoError(t, err)
+	assert.NotContains(t, stored.Nonce, ".")
+
+	m.shareRepo.On("GetByNonce", mock.Anything, stored.Nonce).Return(*stored, nil)
+	shared, err := svc.Resolve(context.Background(), resp.Token, 20, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Started", shared.Task.Status)
+	assert.Nil(t, shared.Project)
+}
+
+func TestCreateShare_ViewerForbidden(t *testing.T) {
+	svc, m := setupShareTest()
+
+	projectID := uint(3)
+	m.projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{OwnerID: 1}, nil)
+	m.projectRepo.On("GetMember", mock.Anything, projectID, uint(2)).Return(domain.ProjectMember{}, gorm.ErrRecordNotFound)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+
+	_, err := svc.CreateShare(tenant.WithOrg(context.Background(), 7), dto.CreateShareReq{ProjectID: &projectID}, 2)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	m.shareRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateShare_ExpiryInPast(t *testing.T) {
+	svc, _ := setupShareTest()
+
+	taskID := uint(1)
+	past := time.Now().Add(-time.Hour)
+	_, err := svc.CreateShare(context.Background(), dto.CreateShareReq{TaskID: &taskID, ExpiresAt: &past}, 1)
+
+	assert.Equal(t, api_error.ErrExpiryInPast, err)
+}
+
+func TestResolve_ForgedToken(t *testing.T) {
+	svc, m := setupShareTest()
+
+	_, err := svc.Resolve(context.Background(), "nonce.forged", 20, 0)
+
+	assert.Equal(t, api_error.ErrShareNotFound, err)
+	m.shareRepo.AssertNotCalled(t, "GetByNonce", mock.Anything, mock.Anything)
+}
+
+func TestResolve_RevokedOrExpired(t *testing.T) {
+	svc, m := setupShareTest()
+
+	now := time.Now()
+	past := now.Add(-time.Minute)
+	m.shareRepo.On("GetByNonce", mock.Anything, "revoked").Return(domain.ShareLink{RevokedAt: &now}, nil)
+	m.shareRepo.On("GetByNonce", mock.Anything, "expired").Return(domain.ShareLink{ExpiresAt: &past}, nil)
+
+	for _, nonce := range []string{"revoked", "expired"} {
+		_, err := svc.Resolve(context.Background(), nonce+"."+svc.signature(nonce), 20, 0)
+		assert.Equal(t, api_error.ErrShareNotFound, err, nonce)
+	}
+}
+
+func TestResolve_ProjectScopedToLinkOrg(t *testing.T) {
+	svc, m := setupShareTest()
+
+	projectID := uint(3)
+	project := domain.Project{Name: "Client work"}
+	project.ID = projectID
+	m.shareRepo.On("GetByNonce", mock.MatchedBy(tenant.IsUnscoped), "n").
+		Return(domain.ShareLink{OrganizationID: 7, ProjectID: &projectID}, nil)
+	inLinkOrg := mock.MatchedBy(func(ctx context.Context) bool {
+		orgID, ok := tenant.FromContext(ctx)
+		return ok && orgID == 7
+	})
+	m.projectRepo.On("GetByID", inLinkOrg, projectID).Return(project, nil)
+	m.taskRepo.On("ListByFilter", inLinkOrg, dto.TaskListFilter{ProjectID: projectID}, 20, 0).
+		Return([]domain.Task{{Name: "a"}, {Name: "b"}}, int64(2), nil)
+
+	shared, err := svc.Resolve(context.Background(), "n."+svc.signature("n"), 20, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Client work", shared.Project.Name)
+	assert.Len(t, shared.Project.Tasks, 2)
+}
+
+func TestRevokeShare(t *testing.T) {
+	svc, m := setupShareTest()
+
+	taskID := uint(1)
+	link := domain.ShareLink{TaskID: &taskID}
+	link.ID = 2
+	m.shareRepo.On("GetByID", mock.Anything, uint(2)).Return(link, nil)
+	m.taskRepo.On("GetByID", mock.Anything, taskID).Return(domain.Task{}, nil)
+	m.shareRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(l *domain.ShareLink) bool {
+		return l.RevokedAt != nil
+	}), []string{"revoked_at"}).Return(nil)
+
+	assert.NoError(t, svc.RevokeShare(context.Background(), 2, 1))
+	m.shareRepo.AssertExpectations(t)
+}
+
+func TestListShares_ViewerForbidden(t *testing.T) {
+	svc, m := setupShareTest()
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+
+	_, err := svc.ListShares(tenant.WithOrg(context.Background(), 7), dto.ShareListFilter{}, 2)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	m.shareRepo.AssertNotCalled(t, "ListActive", mock.Anything, mock.Anything)
+}
+
+func TestListShares_ProjectEditor(t *testing.T) {
+	svc, m := setupShareTest()
+
+	projectID := uint(3)
+	m.projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{OwnerID: 1}, nil)
+	m.projectRepo.On("GetMember", mock.Anything, projectID, uint(2)).Return(domain.ProjectMember{Role: enum.MemberEditor}, nil)
+	m.orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+	filter := dto.ShareListFilter{ProjectID: projectID}
+	m.shareRepo.On("ListActive", mock.Anything, filter).Return([]domain.ShareLink{{Nonce: "n", ProjectID: &projectID}}, nil)
+
+	resps, err := svc.ListShares(tenant.WithOrg(context.Background(), 7), filter, 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, resps, 1)
+	assert.NotEmpty(t, resps[0].Token)
+}
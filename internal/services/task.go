@@ -2,45 +2,127 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"graph-interview/internal/api/handlers/dto"
 	api_error "graph-interview/internal/api/handlers/errors"
 	"graph-interview/internal/domain"
 	"graph-interview/internal/repository"
 	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/rrule"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
 )
 
+// TaskService manages tasks. Changing tasks requires at least the editor role in the
+// organization, or in the task's project when the user has a role there.
 type TaskService struct {
-	TaskRepo repository.TaskRepo
+	TaskRepo    repository.TaskRepo
+	ProjectRepo repository.ProjectRepo
+	OrgRepo     repository.OrgRepo
+	Tx          repository.Transactor
+	// Bus, when set, records task changes as domain events along with them.
+	Bus *EventBus
+	// WorkflowRepo makes tasks in projects follow their project's workflow. Without it
+	// every task follows the default one.
+	WorkflowRepo repository.WorkflowRepo
+	// CustomFieldRepo lets tasks in projects hold values for the project's custom fields.
+	// Without it tasks have none.
+	CustomFieldRepo repository.CustomFieldRepo
 }
 
-func NewTaskService(taskrepo repository.TaskRepo) *TaskService {
+func NewTaskService(
+	taskrepo repository.TaskRepo,
+	projectRepo repository.ProjectRepo,
+	orgRepo repository.OrgRepo,
+	tx repository.Transactor,
+	bus *EventBus,
+	workflowRepo repository.WorkflowRepo,
+	customFieldRepo repository.CustomFieldRepo,
+) *TaskService {
 	return &TaskService{
-		TaskRepo: taskrepo,
+		TaskRepo:        taskrepo,
+		ProjectRepo:     projectRepo,
+		OrgRepo:         orgRepo,
+		Tx:              tx,
+		Bus:             bus,
+		WorkflowRepo:    workflowRepo,
+		CustomFieldRepo: customFieldRepo,
 	}
 }
 
 func (s *TaskService) CreateTask(ctx context.Context, req dto.CreateTaskReq, userID uint) (*dto.TaskResp, error) {
+	if err := s.authorize(ctx, userID, req.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if req.ProjectID != nil {
+		if err := s.checkProject(ctx, *req.ProjectID); err != nil {
+			return nil, err
+		}
+	}
+
+	recurrence, err := parseRecurrence(req.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence != "" && req.DueDate == nil {
+		return nil, api_error.ErrRecurrenceNoDue
+	}
+
+	workflow, err := s.workflow(ctx, req.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	initial, ok := workflow.Initial()
+	if !ok {
+		return nil, api_error.ErrUnknownStatus
+	}
+	customFields, err := s.customFields(ctx, req.ProjectID, nil, req.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &domain.Task{
 		Name:            req.Name,
 		Description:     req.Description,
 		Status:          enum.Created,
-		CreatedByUserID: userID,
-		UpdatedByUserID: userID,
+		ProjectID:       req.ProjectID,
+		DueDate:         req.DueDate,
+		Recurrence:      recurrence,
+		Labels:          normalizeLabels(req.Labels),
+		CustomFields:    customFields,
+		CreatedByUserID: &userID,
+		UpdatedByUserID: &userID,
+		Version:         1,
+	}
+	if recurrence != "" {
+		task.Occurrence = 1
 	}
+	applyStatus(task, initial)
 
-	id, err := s.TaskRepo.Create(ctx, task)
+	var resp *dto.TaskResp
+	err = s.withEvents(ctx, func(ctx context.Context) error {
+		position, err := s.endOfColumn(ctx, task.Column())
+		if err != nil {
+			return err
+		}
+		task.Position = position
+		id, err := s.TaskRepo.Create(ctx, task)
+		if err != nil {
+			return err
+		}
+		task.ID = id
+		resp = taskToResp(task)
+		return s.emit(ctx, domain.EventTaskCreated, task.ID, userID, TaskChange{Task: resp, Mentions: newMentions("", task.Description)})
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	return &dto.TaskResp{
-		ID:          id,
-		Name:        task.Name,
-		Description: task.Description,
-		Status:      task.Status.String(),
-		CreatedByID: task.CreatedByUserID,
-		UpdatedByID: task.UpdatedByUserID,
-	}, nil
+	return resp, nil
 }
 
 func (s *TaskService) GetTask(ctx context.Context, taskID uint) (*dto.TaskResp, error) {
@@ -70,42 +152,200 @@ func (s *TaskService) ListTasks(ctx context.Context, filter dto.TaskListFilter,
 	}, nil
 }
 
-func (s *TaskService) UpdateTask(ctx context.Context, taskID uint, req dto.UpdateTaskReq, userID uint) (*dto.TaskResp, error) {
+// TaskPatch computes the new editable state of a task from its current one.
+type TaskPatch func(current dto.UpdateTaskReq) (dto.UpdateTaskReq, error)
+
+// UpdateTask replaces the editable state of a task with req. Marking a recurring task done
+// creates its next occurrence in the same transaction. A non-zero version must be the
+// task's current one.
+func (s *TaskService) UpdateTask(ctx context.Context, taskID uint, req dto.UpdateTaskReq, userID uint, version int) (*dto.TaskResp, error) {
+	return s.PatchTask(ctx, taskID, func(dto.UpdateTaskReq) (dto.UpdateTaskReq, error) {
+		return req, nil
+	}, userID, version)
+}
+
+// PatchTask updates a task like UpdateTask, to the state patch derives from the current one.
+func (s *TaskService) PatchTask(ctx context.Context, taskID uint, patch TaskPatch, userID uint, version int) (*dto.TaskResp, error) {
 	task, err := s.TaskRepo.GetByID(ctx, taskID)
 	if err != nil {
 		return nil, api_error.ErrTaskNotFound
 	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if version != 0 && version != task.Version {
+		return nil, api_error.ErrTaskModified
+	}
+	req, err := patch(taskToDoc(&task))
+	if err != nil {
+		return nil, err
+	}
+	oldStatus, previous := task.Status, task.StatusName()
+	description := task.Description
 
 	var fields []string
-	task.UpdatedByUserID = userID
+	task.UpdatedByUserID = &userID
 	fields = append(fields, "updated_by_user_id")
 
-	if req.Name != nil {
-		task.Name = *req.Name
+	if req.Name != task.Name {
+		task.Name = req.Name
 		fields = append(fields, "name")
 	}
-	if req.Description != nil {
-		task.Description = *req.Description
+	if req.Description != task.Description {
+		task.Description = req.Description
 		fields = append(fields, "description")
 	}
-	if req.Status != nil {
-		task.Status = *req.Status
-		fields = append(fields, "status")
+	statusName := req.StatusName
+	if strings.EqualFold(statusName, task.StatusName()) {
+		statusName = ""
+	}
+	statusFields, err := s.setStatus(ctx, &task, req.Status, statusName)
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, statusFields...)
+	if !sameTime(req.DueDate, task.DueDate) {
+		task.DueDate = req.DueDate
+		fields = append(fields, "due_date")
+	}
+	recurrence, err := parseRecurrence(req.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	if recurrence != task.Recurrence {
+		task.Recurrence = recurrence
+		fields = append(fields, "recurrence")
+		if recurrence != "" && task.Occurrence == 0 {
+			task.Occurrence = 1
+			fields = append(fields, "occurrence")
+		}
+	}
+	if labels := normalizeLabels(req.Labels); !slices.Equal(labels, task.Labels) {
+		task.Labels = labels
+		fields = append(fields, "labels")
+	}
+	customFields, err := s.customFields(ctx, task.ProjectID, task.CustomFields, req.CustomFields, false)
+	if err != nil {
+		return nil, err
+	}
+	if !sameCustomFields(customFields, task.CustomFields) {
+		task.CustomFields = customFields
+		fields = append(fields, "custom_fields")
+	}
+	if task.Recurrence != "" && task.DueDate == nil {
+		return nil, api_error.ErrRecurrenceNoDue
 	}
 
-	if err := s.TaskRepo.UpdateByID(ctx, &task, fields); err != nil {
+	var next *domain.Task
+	if task.Status == enum.Done && oldStatus != enum.Done && task.Recurrence != "" {
+		if next, err = nextOccurrence(&task, userID); err != nil {
+			return nil, err
+		}
+	}
+	change := statusChange(&task, previous)
+	change.Mentions = newMentions(description, task.Description)
+	return s.commit(ctx, &task, fields, next, change, userID)
+}
+
+// commit saves the updated fields of task, and next along with it, and records the update
+// as change, which statusChange starts.
+func (s *TaskService) commit(ctx context.Context, task *domain.Task, fields []string, next *domain.Task, change TaskChange, userID uint) (*dto.TaskResp, error) {
+	var resp *dto.TaskResp
+	err := s.withEvents(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = s.record(ctx, task, fields, next, change, userID)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	return resp, nil
+}
 
-	return taskToResp(&task), nil
+// record does the writes of commit, for callers that run them in a transaction of their own.
+func (s *TaskService) record(ctx context.Context, task *domain.Task, fields []string, next *domain.Task, change TaskChange, userID uint) (*dto.TaskResp, error) {
+	if err := s.save(ctx, task, fields, next); err != nil {
+		return nil, err
+	}
+	change.Task = taskToResp(task)
+	if err := s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, change); err != nil {
+		return nil, err
+	}
+	if next != nil {
+		if err := s.emit(ctx, domain.EventTaskCreated, next.ID, userID, TaskChange{Task: taskToResp(next)}); err != nil {
+			return nil, err
+		}
+	}
+	return change.Task, nil
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, taskID uint) error {
-	_, err := s.TaskRepo.GetByID(ctx, taskID)
+// save writes the updated fields of task, and creates next, the task's following
+// occurrence, along with it when there is one.
+func (s *TaskService) save(ctx context.Context, task *domain.Task, fields []string, next *domain.Task) error {
+	if next == nil {
+		return s.update(ctx, task, fields)
+	}
+
+	// The rule moves on to the new occurrence, so reopening and completing this one again
+	// does not create a second successor.
+	task.Recurrence = ""
+	task.SeriesID = next.SeriesID
+	fields = append(fields, "series_id")
+	if !slices.Contains(fields, "recurrence") {
+		fields = append(fields, "recurrence")
+	}
+	return s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.update(ctx, task, fields); err != nil {
+			return err
+		}
+		workflow, err := s.workflow(ctx, next.ProjectID)
+		if err != nil {
+			return err
+		}
+		if initial, ok := workflow.Initial(); ok {
+			applyStatus(next, initial)
+		}
+		position, err := s.endOfColumn(ctx, next.Column())
+		if err != nil {
+			return err
+		}
+		next.Position = position
+		_, err = s.TaskRepo.Create(ctx, next)
+		return err
+	})
+}
+
+// update writes fields of task, failing with ErrTaskModified when someone else changed
+// the task since it was read.
+func (s *TaskService) update(ctx context.Context, task *domain.Task, fields []string) error {
+	ok, err := s.TaskRepo.UpdateByID(ctx, task, fields)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return api_error.ErrTaskModified
+	}
+	return nil
+}
+
+// DeleteTask deletes a task. A non-zero version must be the task's current one.
+func (s *TaskService) DeleteTask(ctx context.Context, taskID uint, userID uint, version int) error {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
 	if err != nil {
 		return api_error.ErrTaskNotFound
 	}
-	return s.TaskRepo.DeleteByID(ctx, taskID)
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return err
+	}
+	if version != 0 && version != task.Version {
+		return api_error.ErrTaskModified
+	}
+	return s.withEvents(ctx, func(ctx context.Context) error {
+		if err := s.TaskRepo.DeleteByID(ctx, taskID); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventTaskDeleted, taskID, userID, TaskChange{})
+	})
 }
 
 func (s *TaskService) ArchiveTask(ctx context.Context, taskID uint, userID uint) (*dto.TaskResp, error) {
@@ -113,16 +353,310 @@ func (s *TaskService) ArchiveTask(ctx context.Context, taskID uint, userID uint)
 	if err != nil {
 		return nil, api_error.ErrTaskNotFound
 	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
 
-	task.Status = enum.Canceled
-	task.UpdatedByUserID = userID
-	fields := []string{"status", "updated_by_user_id"}
+	previous := task.StatusName()
+	fields, err := s.setStatus(ctx, &task, enum.Canceled, "")
+	if err != nil {
+		return nil, err
+	}
+	task.UpdatedByUserID = &userID
+	fields = append(fields, "updated_by_user_id")
 
-	if err := s.TaskRepo.UpdateByID(ctx, &task, fields); err != nil {
+	change := statusChange(&task, previous)
+	err = s.withEvents(ctx, func(ctx context.Context) error {
+		if err := s.update(ctx, &task, fields); err != nil {
+			return err
+		}
+		change.Task = taskToResp(&task)
+		return s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, change)
+	})
+	if err != nil {
 		return nil, err
 	}
+	return change.Task, nil
+}
 
-	return taskToResp(&task), nil
+// MoveTask puts a task into another project, or takes it out of its project when projectID is nil.
+func (s *TaskService) MoveTask(ctx context.Context, taskID uint, projectID *uint, userID uint) (*dto.TaskResp, error) {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, userID, projectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if projectID != nil {
+		if err := s.checkProject(ctx, *projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The task keeps its status if the new workflow has it, or else moves to the first one
+	// counting as the same built-in status.
+	workflow, err := s.workflow(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	fields := []string{"project_id", "updated_by_user_id"}
+	if _, ok := workflow.Find(task.StatusName()); !ok {
+		status, ok := workflow.ForStatus(task.Status)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", api_error.ErrUnknownStatus, task.StatusName())
+		}
+		fields = append(fields, applyStatus(&task, status)...)
+	}
+	customFields, err := s.carryCustomFields(ctx, projectID, task.CustomFields)
+	if err != nil {
+		return nil, err
+	}
+	if !sameCustomFields(customFields, task.CustomFields) {
+		task.CustomFields = customFields
+		fields = append(fields, "custom_fields")
+	}
+
+	task.ProjectID = projectID
+	task.UpdatedByUserID = &userID
+	var resp *dto.TaskResp
+	err = s.withEvents(ctx, func(ctx context.Context) error {
+		if err := s.update(ctx, &task, fields); err != nil {
+			return err
+		}
+		resp = taskToResp(&task)
+		return s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, TaskChange{Task: resp})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// AssignTask adds assigneeID, who must belong to the organization, to the task's assignees
+// and lets them know.
+func (s *TaskService) AssignTask(ctx context.Context, taskID, assigneeID, userID uint) (*dto.TaskResp, error) {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if orgID, ok := tenant.FromContext(ctx); ok {
+		if _, err := s.OrgRepo.GetMembership(ctx, orgID, assigneeID); err != nil {
+			return nil, api_error.ErrUserNotFound
+		}
+	}
+
+	resp := taskToResp(&task)
+	err = s.withEvents(ctx, func(ctx context.Context) error {
+		if err := s.TaskRepo.AssignUser(ctx, taskID, assigneeID); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, TaskChange{Task: resp, AssigneeID: &assigneeID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *TaskService) UnassignTask(ctx context.Context, taskID, assigneeID, userID uint) error {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return api_error.ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return err
+	}
+	return s.withEvents(ctx, func(ctx context.Context) error {
+		if err := s.TaskRepo.UnassignUser(ctx, taskID, assigneeID); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, TaskChange{Task: taskToResp(&task)})
+	})
+}
+
+// WatchTask subscribes userID to the changes of a task they can see.
+func (s *TaskService) WatchTask(ctx context.Context, taskID, userID uint) error {
+	if _, err := s.TaskRepo.GetByID(ctx, taskID); err != nil {
+		return api_error.ErrTaskNotFound
+	}
+	return s.TaskRepo.Watch(ctx, taskID, userID)
+}
+
+func (s *TaskService) UnwatchTask(ctx context.Context, taskID, userID uint) error {
+	if _, err := s.TaskRepo.GetByID(ctx, taskID); err != nil {
+		return api_error.ErrTaskNotFound
+	}
+	return s.TaskRepo.Unwatch(ctx, taskID, userID)
+}
+
+// withEvents runs write in one transaction with the events it emits.
+func (s *TaskService) withEvents(ctx context.Context, write func(ctx context.Context) error) error {
+	return withEvents(ctx, s.Tx, s.Bus, write)
+}
+
+func (s *TaskService) emit(ctx context.Context, eventType string, taskID, userID uint, change TaskChange) error {
+	return s.Bus.Emit(ctx, eventType, taskID, &userID, change)
+}
+
+// workflow returns the workflow of tasks in projectID.
+func (s *TaskService) workflow(ctx context.Context, projectID *uint) (*domain.Workflow, error) {
+	return projectWorkflow(ctx, s.WorkflowRepo, projectID)
+}
+
+// setStatus moves task to the status of its workflow called name or, without a name, to the
+// first one counting as status unless the task already counts as that. It returns the
+// fields that changed, and fails when the workflow does not allow the move.
+func (s *TaskService) setStatus(ctx context.Context, task *domain.Task, status enum.TaskStatus, name string) ([]string, error) {
+	if name == "" && status == task.Status {
+		return nil, nil
+	}
+	workflow, err := s.workflow(ctx, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	var target domain.WorkflowStatus
+	var ok bool
+	if name != "" {
+		target, ok = workflow.Find(name)
+	} else {
+		target, ok = workflow.ForStatus(status)
+		name = status.String()
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", api_error.ErrUnknownStatus, name)
+	}
+	if !workflow.Allows(task.StatusName(), target.Name) {
+		return nil, fmt.Errorf("%w: %s -> %s", api_error.ErrTransitionDenied, task.StatusName(), target.Name)
+	}
+	return applyStatus(task, target), nil
+}
+
+// statusChange starts the change record of task, noting previous when the task left that
+// workflow status. Moves between custom statuses of one category count too.
+func statusChange(task *domain.Task, previous string) TaskChange {
+	var change TaskChange
+	if task.StatusName() != previous {
+		change.PreviousStatus = previous
+	}
+	return change
+}
+
+// mentionPattern matches @username mentions. The @ must not follow a word character, so
+// email addresses are not taken for mentions.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([\w.-]+)`)
+
+// newMentions returns the usernames mentioned in after but not in before, in order of
+// appearance.
+func newMentions(before, after string) []string {
+	known := mentions(before)
+	var names []string
+	for _, name := range mentions(after) {
+		if !slices.Contains(known, name) && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// mentions returns the usernames mentioned in text. A trailing period ends the sentence
+// rather than the name.
+func mentions(text string) []string {
+	var names []string
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		if name := strings.TrimRight(m[1], "."); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (s *TaskService) authorize(ctx context.Context, userID uint, projectID *uint, need enum.MemberRole) error {
+	return requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, need)
+}
+
+// checkProject makes sure tasks can be added to the project.
+func (s *TaskService) checkProject(ctx context.Context, projectID uint) error {
+	project, err := s.ProjectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return api_error.ErrProjectNotFound
+	}
+	if project.Archived {
+		return api_error.ErrProjectArchived
+	}
+	return nil
+}
+
+// parseRecurrence validates an RRULE and returns it in canonical form. An empty rule is
+// returned as is.
+func parseRecurrence(rule string) (string, error) {
+	if rule == "" {
+		return "", nil
+	}
+	r, err := rrule.Parse(rule)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", api_error.ErrInvalidRecurrence, err)
+	}
+	return r.String(), nil
+}
+
+// nextOccurrence builds the task following a completed occurrence of a recurring task, or
+// returns nil when the rule has run out.
+func nextOccurrence(task *domain.Task, userID uint) (*domain.Task, error) {
+	rule, err := rrule.Parse(task.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", api_error.ErrInvalidRecurrence, err)
+	}
+	due, err := rule.Next(*task.DueDate, task.Occurrence)
+	if errors.Is(err, rrule.ErrNoOccurrence) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seriesID := task.ID
+	if task.SeriesID != nil {
+		seriesID = *task.SeriesID
+	}
+	return &domain.Task{
+		Name:            task.Name,
+		Description:     task.Description,
+		Status:          enum.Created,
+		ProjectID:       task.ProjectID,
+		DueDate:         &due,
+		Recurrence:      task.Recurrence,
+		Labels:          task.Labels,
+		CustomFields:    task.CustomFields,
+		Checklist:       uncheckedChecklist(task.Checklist),
+		ChecklistNextID: task.ChecklistNextID,
+		SeriesID:        &seriesID,
+		Occurrence:      task.Occurrence + 1,
+		CreatedByUserID: task.CreatedByUserID,
+		UpdatedByUserID: &userID,
+		Version:         1,
+	}, nil
+}
+
+// taskToDoc returns the editable state of task.
+func taskToDoc(task *domain.Task) dto.UpdateTaskReq {
+	return dto.UpdateTaskReq{
+		Name:        task.Name,
+		Description: task.Description,
+		Status:      task.Status,
+		StatusName:  task.StatusName(),
+		DueDate:     task.DueDate,
+		Recurrence:  task.Recurrence,
+		Labels:      labelsOrEmpty(task.Labels),
+		// An empty map rather than null, so JSON Patch can add fields to it.
+		CustomFields: customFieldsOrEmpty(task.CustomFields),
+	}
 }
 
 func taskToResp(task *domain.Task) *dto.TaskResp {
@@ -131,9 +665,56 @@ func taskToResp(task *domain.Task) *dto.TaskResp {
 		Name:        task.Name,
 		Description: task.Description,
 		Status:      task.Status.String(),
+		StatusName:  task.StatusName(),
+		Category:    task.Status.Category().String(),
+		ProjectID:   task.ProjectID,
+		DueDate:     task.DueDate,
+		Recurrence:  task.Recurrence,
+		SeriesID:    task.SeriesID,
+		Occurrence:  task.Occurrence,
+		Labels:      labelsOrEmpty(task.Labels),
+		Position:    task.Position,
 		CreatedByID: task.CreatedByUserID,
 		UpdatedByID: task.UpdatedByUserID,
+		Version:     task.Version,
 		CreatedAt:   task.CreatedAt,
 		UpdatedAt:   task.UpdatedAt,
+		// Set fields only, left out of the JSON when there are none.
+		CustomFields:      task.CustomFields,
+		ChecklistProgress: checklistProgress(task.Checklist),
+	}
+}
+
+// normalizeLabels trims labels and drops empty and duplicate ones. The result is sorted.
+func normalizeLabels(labels []string) []string {
+	out := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label = strings.TrimSpace(label); label != "" {
+			out = append(out, label)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func labelsOrEmpty(labels []string) []string {
+	if labels == nil {
+		return []string{}
+	}
+	return labels
+}
+
+// customFieldsOrEmpty returns a copy of values, so edits to it leave the task alone.
+func customFieldsOrEmpty(values map[string]any) map[string]any {
+	if values == nil {
+		return map[string]any{}
 	}
+	return maps.Clone(values)
 }
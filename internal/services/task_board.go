@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/pkg/lexorank"
+)
+
+// Board lists the tasks matching f by status column, each in board order and cut off after
+// limit tasks. The columns are the statuses of the workflow of f's project, or the
+// built-in ones without a project. A status in f narrows the board to the columns counting
+// as it; its sort is ignored.
+func (s *TaskService) Board(ctx context.Context, f dto.TaskListFilter, limit int) (*dto.BoardResp, error) {
+	only := f.Status
+	if only != nil && only.String() == "" {
+		return nil, api_error.ErrInvalidStatus
+	}
+	var projectID *uint
+	if f.ProjectID != 0 {
+		projectID = &f.ProjectID
+	}
+	workflow, err := s.workflow(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	f.Orders = []filter.Order{{Field: filter.Position}}
+
+	parsed := f.Parsed
+	resp := &dto.BoardResp{Columns: make([]dto.BoardColumn, 0, len(workflow.Statuses)), Limit: limit}
+	for _, status := range workflow.Statuses {
+		if only != nil && status.Status != *only {
+			continue
+		}
+		f.Status = &status.Status
+		if workflow.ProjectID != 0 {
+			// Several statuses of a workflow can count as the same built-in one.
+			f.Parsed = withStatus(parsed, status)
+		}
+		tasks, total, err := s.TaskRepo.ListByFilter(ctx, f, limit, 0)
+		if err != nil {
+			return nil, err
+		}
+		column := dto.BoardColumn{Status: status.Status.String(), StatusName: status.Name, Tasks: make([]dto.TaskResp, len(tasks)), Total: total}
+		for i, t := range tasks {
+			column.Tasks[i] = *taskToResp(&t)
+		}
+		resp.Columns = append(resp.Columns, column)
+	}
+	return resp, nil
+}
+
+// withStatus narrows e to the tasks in the workflow status.
+func withStatus(e filter.Expr, status domain.WorkflowStatus) filter.Expr {
+	cmp := filter.Cmp{
+		Field:  filter.Fields["status"],
+		Op:     filter.OpEq,
+		Values: []any{filter.WorkflowStatus{Name: status.Name, Status: status.Status}},
+	}
+	if e == nil {
+		return cmp
+	}
+	return filter.And{Left: e, Right: cmp}
+}
+
+// RepositionTask moves a task on the board, setting its status and its position among the
+// tasks of that column in one update. The column is a status of the task's workflow when
+// req names one, and otherwise the first one counting as req's status. Columns hold the
+// tasks of the task's project only. Marking a recurring task done creates its next
+// occurrence like UpdateTask does. A non-zero version must be the task's current one.
+func (s *TaskService) RepositionTask(ctx context.Context, taskID uint, req dto.RepositionTaskReq, userID uint, version int) (*dto.TaskResp, error) {
+	if req.StatusName == "" && req.Status.String() == "" {
+		return nil, api_error.ErrInvalidStatus
+	}
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if version != 0 && version != task.Version {
+		return nil, api_error.ErrTaskModified
+	}
+	column, err := s.boardColumn(ctx, &task, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// A rebalance renumbers the column ahead of the move, so both go in one transaction
+	// and a failed move leaves the column as it was.
+	var resp *dto.TaskResp
+	err = s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		position, err := s.boardPosition(ctx, &task, column, req)
+		if err != nil {
+			return err
+		}
+
+		oldStatus, previous := task.Status, task.StatusName()
+		statusFields, err := s.setStatus(ctx, &task, column.Status, column.Name)
+		if err != nil {
+			return err
+		}
+		fields := append([]string{"updated_by_user_id"}, statusFields...)
+		task.UpdatedByUserID = &userID
+		if position != task.Position {
+			task.Position = position
+			fields = append(fields, "position")
+		}
+
+		var next *domain.Task
+		if task.Status == enum.Done && oldStatus != enum.Done && task.Recurrence != "" {
+			if next, err = nextOccurrence(&task, userID); err != nil {
+				return err
+			}
+		}
+		resp, err = s.record(ctx, &task, fields, next, statusChange(&task, previous), userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.Bus != nil {
+		s.Bus.Kick()
+	}
+	return resp, nil
+}
+
+// boardColumn returns the status of the task's workflow req moves task to: the one it
+// names, or else the task's own status when it already counts as req's status, or the
+// first one that does.
+func (s *TaskService) boardColumn(ctx context.Context, task *domain.Task, req dto.RepositionTaskReq) (domain.WorkflowStatus, error) {
+	if req.StatusName == "" && req.Status == task.Status {
+		return domain.WorkflowStatus{Name: task.StatusName(), Status: task.Status}, nil
+	}
+	workflow, err := s.workflow(ctx, task.ProjectID)
+	if err != nil {
+		return domain.WorkflowStatus{}, err
+	}
+	var column domain.WorkflowStatus
+	var ok bool
+	name := req.StatusName
+	if name != "" {
+		column, ok = workflow.Find(name)
+	} else {
+		column, ok = workflow.ForStatus(req.Status)
+		name = req.Status.String()
+	}
+	if !ok {
+		return domain.WorkflowStatus{}, fmt.Errorf("%w: %s", api_error.ErrUnknownStatus, name)
+	}
+	return column, nil
+}
+
+// columnOf returns the board column of the tasks of projectID in the workflow status.
+func columnOf(projectID *uint, status domain.WorkflowStatus) domain.Column {
+	task := domain.Task{ProjectID: projectID}
+	applyStatus(&task, status)
+	return task.Column()
+}
+
+// boardPosition returns the position req asks for task in the workflow status's column,
+// rebalancing the column when there is no room at the slot.
+func (s *TaskService) boardPosition(ctx context.Context, task *domain.Task, status domain.WorkflowStatus, req dto.RepositionTaskReq) (string, error) {
+	column := columnOf(task.ProjectID, status)
+	before, err := s.boardNeighbor(ctx, task, column, req.BeforeID)
+	if err != nil {
+		return "", err
+	}
+	after, err := s.boardNeighbor(ctx, task, column, req.AfterID)
+	if err != nil {
+		return "", err
+	}
+	position, err := s.boardSlot(ctx, task, column, before, after)
+	if errors.Is(err, lexorank.ErrInvalidKey) || (err == nil && position == "") {
+		// A neighbor has no position yet, or the keys around the slot collide; number the
+		// column afresh and look again.
+		if err = s.rebalance(ctx, column, task, before, after); err == nil {
+			position, err = s.boardSlot(ctx, task, column, before, after)
+		}
+		if errors.Is(err, lexorank.ErrInvalidKey) {
+			return "", api_error.ErrBoardConflict
+		}
+	}
+	return position, err
+}
+
+// boardNeighbor loads the task id names as a neighbor of task in the column, which it has
+// to be in.
+func (s *TaskService) boardNeighbor(ctx context.Context, task *domain.Task, column domain.Column, id *uint) (*domain.Task, error) {
+	if id == nil {
+		return nil, nil
+	}
+	if *id == task.ID {
+		return nil, api_error.ErrBoardConflict
+	}
+	neighbor, err := s.TaskRepo.GetByID(ctx, *id)
+	if err != nil || neighbor.Column() != column {
+		return nil, api_error.ErrBoardConflict
+	}
+	return &neighbor, nil
+}
+
+// boardSlot returns the position for task between after and before in the column.
+// Without before it goes right after after, without after right before before, and with
+// neither at the end. It returns "" when a neighbor has no position to go by.
+func (s *TaskService) boardSlot(ctx context.Context, task *domain.Task, column domain.Column, before, after *domain.Task) (string, error) {
+	if (before != nil && before.Position == "") || (after != nil && after.Position == "") {
+		return "", nil
+	}
+	var lo, hi string
+	var err error
+	switch {
+	case before != nil && after != nil:
+		lo, hi = after.Position, before.Position
+	case after != nil:
+		lo = after.Position
+		hi, err = s.TaskRepo.PositionAfter(ctx, column, lo, task.ID)
+	case before != nil:
+		hi = before.Position
+		lo, err = s.TaskRepo.PositionBefore(ctx, column, hi, task.ID)
+	default:
+		if lo, err = s.TaskRepo.LastPosition(ctx, column); err == nil && task.Column() == column && lo == task.Position && lo != "" {
+			// Already last in the column.
+			return lo, nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	return lexorank.Between(lo, hi)
+}
+
+// rebalance spreads the positions of the tasks in the column evenly, keeping their order,
+// and updates the positions and versions of the loaded tasks among them to match. It runs
+// in the caller's transaction.
+func (s *TaskService) rebalance(ctx context.Context, column domain.Column, loaded ...*domain.Task) error {
+	ids, err := s.TaskRepo.ListColumnIDs(ctx, column)
+	if err != nil {
+		return err
+	}
+	positions := lexorank.Spread(len(ids))
+	for i, id := range ids {
+		if err := s.TaskRepo.SetPosition(ctx, id, positions[i]); err != nil {
+			return err
+		}
+		for _, task := range loaded {
+			if task != nil && task.ID == id {
+				task.Position = positions[i]
+				task.Version++
+			}
+		}
+	}
+	return nil
+}
+
+// endOfColumn returns a position after every task in the column.
+func (s *TaskService) endOfColumn(ctx context.Context, column domain.Column) (string, error) {
+	last, err := s.TaskRepo.LastPosition(ctx, column)
+	if err != nil {
+		return "", err
+	}
+	return lexorank.Between(last, "")
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func boardTask(id uint, status enum.TaskStatus, position string) domain.Task {
+	task := domain.Task{Name: "Task", Status: status, Position: position, Version: 1}
+	task.ID = id
+	return task
+}
+
+func setupBoardTest(tasks ...domain.Task) (*TaskService, *mockRepo.MockTaskRepo) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	for _, task := range tasks {
+		taskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	return NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil), taskRepo
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestRepositionTask_BetweenNeighbors(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Created, "c"), boardTask(5, enum.Started, "k"), boardTask(6, enum.Started, "m"))
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == enum.Started && task.Position == "l"
+	}), []string{"updated_by_user_id", "status", "position"}).Return(true, nil)
+
+	resp, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, AfterID: ptr(uint(5)), BeforeID: ptr(uint(6))}, 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Started", resp.Status)
+	assert.Equal(t, "l", resp.Position)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestRepositionTask_OneNeighbor(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Started, "c"), boardTask(5, enum.Started, "k"))
+	taskRepo.On("PositionBefore", mock.Anything, domain.Column{Status: enum.Started}, "k", uint(4)).Return("c", nil)
+	taskRepo.On("PositionAfter", mock.Anything, domain.Column{Status: enum.Started}, "k", uint(4)).Return("", nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+	// Before k, with c, the task itself, left out of the way, lands between c and k.
+	resp, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, BeforeID: ptr(uint(5))}, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "g", resp.Position)
+
+	resp, err = svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, AfterID: ptr(uint(5))}, 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "s", resp.Position)
+}
+
+func TestRepositionTask_ToEnd(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Created, "c"))
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Done}).Return("z", nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Position == "zi"
+	}), []string{"updated_by_user_id", "status", "position"}).Return(true, nil)
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Done}, 1, 0)
+
+	require.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestRepositionTask_RebalancesUnpositionedColumn(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Created, "c"), boardTask(5, enum.Started, ""))
+	taskRepo.On("ListColumnIDs", mock.Anything, domain.Column{Status: enum.Started}).Return([]uint{7, 5}, nil)
+	taskRepo.On("SetPosition", mock.Anything, uint(7), "c").Return(nil)
+	taskRepo.On("SetPosition", mock.Anything, uint(5), "o").Return(nil)
+	taskRepo.On("PositionAfter", mock.Anything, domain.Column{Status: enum.Started}, "o", uint(4)).Return("", nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Position == "u"
+	}), mock.Anything).Return(true, nil)
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, AfterID: ptr(uint(5))}, 1, 0)
+
+	require.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestRepositionTask_RebalanceBumpsMovedTaskVersion(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Started, ""), boardTask(5, enum.Started, ""))
+	taskRepo.On("ListColumnIDs", mock.Anything, domain.Column{Status: enum.Started}).Return([]uint{4, 5}, nil)
+	taskRepo.On("SetPosition", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	taskRepo.On("PositionAfter", mock.Anything, domain.Column{Status: enum.Started}, "o", uint(4)).Return("", nil)
+	// The rebalance bumped the stored version, so the move has to go by the new one.
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ID == 4 && task.Version == 2
+	}), mock.Anything).Return(true, nil)
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, AfterID: ptr(uint(5))}, 1, 1)
+
+	require.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+}
+
+// txTracker is a Transactor that marks the context of each outermost transaction with its
+// number, so tests can tell which writes went together.
+type txTracker struct{ began int }
+
+type txKey struct{}
+
+func (tr *txTracker) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) == nil {
+		tr.began++
+		ctx = context.WithValue(ctx, txKey{}, tr.began)
+	}
+	return fn(ctx)
+}
+
+func TestRepositionTask_RebalancesInMoveTransaction(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Created, "c"), boardTask(5, enum.Started, ""))
+	tx := &txTracker{}
+	svc.Tx = tx
+	inTx := mock.MatchedBy(func(ctx context.Context) bool { return ctx.Value(txKey{}) == 1 })
+	taskRepo.On("ListColumnIDs", mock.Anything, domain.Column{Status: enum.Started}).Return([]uint{5}, nil)
+	taskRepo.On("SetPosition", inTx, uint(5), mock.Anything).Return(nil)
+	taskRepo.On("PositionAfter", mock.Anything, domain.Column{Status: enum.Started}, mock.Anything, uint(4)).Return("", nil)
+	taskRepo.On("UpdateByID", inTx, mock.Anything, mock.Anything).Return(false, nil)
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, AfterID: ptr(uint(5))}, 1, 0)
+
+	assert.ErrorIs(t, err, api_error.ErrTaskModified)
+	assert.Equal(t, 1, tx.began)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestRepositionTask_Conflicts(t *testing.T) {
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Created, "c"), boardTask(5, enum.Started, "k"), boardTask(6, enum.Started, "m"))
+	taskRepo.On("ListColumnIDs", mock.Anything, domain.Column{Status: enum.Started}).Return([]uint{5, 6}, nil)
+	taskRepo.On("SetPosition", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	tests := []dto.RepositionTaskReq{
+		{Status: enum.Done, AfterID: ptr(uint(5))},
+		{Status: enum.Created, AfterID: ptr(uint(4))},
+		{Status: enum.Started, AfterID: ptr(uint(6)), BeforeID: ptr(uint(5))},
+	}
+	for _, req := range tests {
+		_, err := svc.RepositionTask(context.Background(), 4, req, 1, 0)
+		assert.Equal(t, api_error.ErrBoardConflict, err)
+	}
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.TaskStatus(9)}, 1, 0)
+	assert.Equal(t, api_error.ErrInvalidStatus, err)
+	_, err = svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started}, 1, 3)
+	assert.Equal(t, api_error.ErrTaskModified, err)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRepositionTask_ColumnsArePerProject(t *testing.T) {
+	other := boardTask(5, enum.Started, "k")
+	other.ProjectID = ptr(uint(8))
+	svc, taskRepo := setupBoardTest(boardTask(4, enum.Created, "c"), other)
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Started, AfterID: ptr(uint(5))}, 1, 0)
+
+	assert.Equal(t, api_error.ErrBoardConflict, err)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRepositionTask_DoneCreatesNextOccurrence(t *testing.T) {
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	task := boardTask(4, enum.Started, "c")
+	task.DueDate, task.Recurrence, task.Occurrence = &due, "FREQ=DAILY", 1
+	svc, taskRepo := setupBoardTest(task)
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Done}).Return("", nil)
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Created}).Return("r", nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+	taskRepo.On("Create", mock.Anything, mock.MatchedBy(func(next *domain.Task) bool {
+		return next.Status == enum.Created && next.Position == "w" && next.DueDate.Equal(due.AddDate(0, 0, 1))
+	})).Return(uint(5), nil)
+
+	_, err := svc.RepositionTask(context.Background(), 4, dto.RepositionTaskReq{Status: enum.Done}, 1, 0)
+
+	require.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestBoard(t *testing.T) {
+	svc, taskRepo := setupBoardTest()
+	onColumn := func(status enum.TaskStatus) any {
+		return mock.MatchedBy(func(f dto.TaskListFilter) bool {
+			return *f.Status == status && f.ProjectID == 3 && len(f.Orders) == 1 && f.Orders[0].Field == filter.Position
+		})
+	}
+	taskRepo.On("ListByFilter", mock.Anything, onColumn(enum.Started), 10, 0).Return([]domain.Task{boardTask(1, enum.Started, "i")}, int64(1), nil)
+	for _, status := range []enum.TaskStatus{enum.Created, enum.Delayed, enum.Done, enum.Failed, enum.Canceled} {
+		taskRepo.On("ListByFilter", mock.Anything, onColumn(status), 10, 0).Return([]domain.Task{}, int64(0), nil)
+	}
+
+	resp, err := svc.Board(context.Background(), dto.TaskListFilter{ProjectID: 3, Sort: "-updated"}, 10)
+
+	require.NoError(t, err)
+	var statuses []string
+	for _, column := range resp.Columns {
+		statuses = append(statuses, column.Status)
+	}
+	assert.Equal(t, []string{"Created", "Started", "Delayed", "Done", "Failed", "Canceled"}, statuses)
+	assert.Equal(t, int64(1), resp.Columns[1].Total)
+	assert.Equal(t, "i", resp.Columns[1].Tasks[0].Position)
+	assert.Empty(t, resp.Columns[0].Tasks)
+}
+
+func TestBoard_OneColumn(t *testing.T) {
+	svc, taskRepo := setupBoardTest()
+	taskRepo.On("ListByFilter", mock.Anything, mock.AnythingOfType("dto.TaskListFilter"), 20, 0).Return([]domain.Task{}, int64(0), nil)
+
+	resp, err := svc.Board(context.Background(), dto.TaskListFilter{Status: ptr(enum.Done)}, 20)
+	require.NoError(t, err)
+	require.Len(t, resp.Columns, 1)
+	assert.Equal(t, "Done", resp.Columns[0].Status)
+
+	_, err = svc.Board(context.Background(), dto.TaskListFilter{Status: ptr(enum.TaskStatus(9))}, 20)
+	assert.Equal(t, api_error.ErrInvalidStatus, err)
+}
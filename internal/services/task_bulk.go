@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"slices"
+	"strconv"
+)
+
+// Operations of a bulk task request.
+const (
+	BulkSetStatus    = "set_status"
+	BulkAssign       = "assign"
+	BulkUnassign     = "unassign"
+	BulkAddLabels    = "add_labels"
+	BulkRemoveLabels = "remove_labels"
+	BulkArchive      = "archive"
+	BulkDelete       = "delete"
+)
+
+// MaxBulkTasks caps how many tasks one bulk request may change.
+const MaxBulkTasks = 500
+
+// bulkPlan is what the operations of a bulk request do to one task.
+type bulkPlan struct {
+	task     domain.Task
+	fields   []string
+	previous enum.TaskStatus
+	// previousName is the workflow status the task had before.
+	previousName string
+	next         *domain.Task
+	assign       []uint
+	unassign     []uint
+	delete       bool
+	changes      []string
+}
+
+// BulkTasks applies the operations of req to every target task in one transaction. Each
+// task is checked first and gets its own result; the changes are only written when all of
+// them pass, and never in a dry run.
+func (s *TaskService) BulkTasks(ctx context.Context, req dto.BulkTaskReq, userID uint) (*dto.BulkTaskResp, error) {
+	if err := validateBulkOps(req.Operations); err != nil {
+		return nil, err
+	}
+	tasks, results, err := s.bulkTargets(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]*bulkPlan, len(results))
+	failed := false
+	for i := range results {
+		if results[i].Error != "" {
+			failed = true
+			continue
+		}
+		plan, err := s.planBulk(ctx, tasks[i], req.Operations, userID)
+		if err != nil {
+			results[i].Error = err.Error()
+			failed = true
+			continue
+		}
+		plans[i] = plan
+		results[i].OK = true
+		results[i].Changes = plan.changes
+		if !plan.delete {
+			results[i].Task = taskToResp(&plan.task)
+		}
+	}
+
+	resp := &dto.BulkTaskResp{DryRun: req.DryRun, Results: results}
+	if req.DryRun || failed {
+		return resp, nil
+	}
+
+	err = s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		for i, plan := range plans {
+			if err := s.applyBulk(ctx, plan, userID); err != nil {
+				results[i].OK = false
+				results[i].Error = err.Error()
+				return err
+			}
+			if !plan.delete {
+				results[i].Task = taskToResp(&plan.task)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Everything was rolled back; the failing task's result says why.
+		for i := range results {
+			results[i].OK = false
+		}
+		return resp, nil
+	}
+	if s.Bus != nil {
+		s.Bus.Kick()
+	}
+	resp.Applied = true
+	return resp, nil
+}
+
+func validateBulkOps(ops []dto.BulkTaskOp) error {
+	for i, op := range ops {
+		var missing string
+		switch op.Op {
+		case BulkSetStatus:
+			if op.Status == nil {
+				missing = "status"
+			}
+		case BulkAssign, BulkUnassign:
+			if op.UserID == 0 {
+				missing = "user_id"
+			}
+		case BulkAddLabels, BulkRemoveLabels:
+			if len(op.Labels) == 0 {
+				missing = "labels"
+			}
+		case BulkArchive, BulkDelete:
+		default:
+			return fmt.Errorf("%w: operation %d: unknown op %q", api_error.ErrInvalidBulkOp, i, op.Op)
+		}
+		if missing != "" {
+			return fmt.Errorf("%w: operation %d: %s needs %s", api_error.ErrInvalidBulkOp, i, op.Op, missing)
+		}
+		if op.Op == BulkDelete && i != len(ops)-1 {
+			return fmt.Errorf("%w: delete must be the last operation", api_error.ErrInvalidBulkOp)
+		}
+	}
+	return nil
+}
+
+// bulkTargets loads the tasks req applies to, with a result for each. Tasks that could not
+// be found have their result's Error set.
+func (s *TaskService) bulkTargets(ctx context.Context, req dto.BulkTaskReq) ([]domain.Task, []dto.BulkTaskResult, error) {
+	if (len(req.TaskIDs) == 0) == (req.Filter == nil) {
+		return nil, nil, api_error.ErrBulkTargets
+	}
+
+	if req.Filter != nil {
+		tasks, _, err := s.TaskRepo.ListByFilter(ctx, *req.Filter, MaxBulkTasks+1, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(tasks) > MaxBulkTasks {
+			return nil, nil, api_error.ErrBulkTooMany
+		}
+		results := make([]dto.BulkTaskResult, len(tasks))
+		for i, task := range tasks {
+			results[i] = dto.BulkTaskResult{TaskID: task.ID, Changes: []string{}}
+		}
+		return tasks, results, nil
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(req.TaskIDs)))
+	if len(ids) > MaxBulkTasks {
+		return nil, nil, api_error.ErrBulkTooMany
+	}
+	tasks := make([]domain.Task, len(ids))
+	results := make([]dto.BulkTaskResult, len(ids))
+	for i, id := range ids {
+		results[i] = dto.BulkTaskResult{TaskID: id, Changes: []string{}}
+		task, err := s.TaskRepo.GetByID(ctx, id)
+		if err != nil {
+			results[i].Error = api_error.ErrTaskNotFound.Error()
+			continue
+		}
+		tasks[i] = task
+	}
+	return tasks, results, nil
+}
+
+// planBulk works out what ops do to task, checking that userID may do it.
+func (s *TaskService) planBulk(ctx context.Context, task domain.Task, ops []dto.BulkTaskOp, userID uint) (*bulkPlan, error) {
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+
+	plan := &bulkPlan{task: task, previous: task.Status, previousName: task.StatusName()}
+	setField := func(field string) {
+		if !slices.Contains(plan.fields, field) {
+			plan.fields = append(plan.fields, field)
+		}
+	}
+	setStatus := func(status enum.TaskStatus) error {
+		from := plan.task.StatusName()
+		fields, err := s.setStatus(ctx, &plan.task, status, "")
+		if err != nil {
+			return err
+		}
+		if len(fields) > 0 {
+			plan.changes = append(plan.changes, fmt.Sprintf("status: %s -> %s", from, plan.task.StatusName()))
+		}
+		for _, field := range fields {
+			setField(field)
+		}
+		return nil
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case BulkSetStatus:
+			if err := setStatus(*op.Status); err != nil {
+				return nil, err
+			}
+		case BulkArchive:
+			if err := setStatus(enum.Canceled); err != nil {
+				return nil, err
+			}
+		case BulkAssign:
+			if orgID, ok := tenant.FromContext(ctx); ok {
+				if _, err := s.OrgRepo.GetMembership(ctx, orgID, op.UserID); err != nil {
+					return nil, api_error.ErrUserNotFound
+				}
+			}
+			plan.assign = append(plan.assign, op.UserID)
+			plan.changes = append(plan.changes, "assign: "+strconv.FormatUint(uint64(op.UserID), 10))
+		case BulkUnassign:
+			plan.unassign = append(plan.unassign, op.UserID)
+			plan.changes = append(plan.changes, "unassign: "+strconv.FormatUint(uint64(op.UserID), 10))
+		case BulkAddLabels:
+			for _, label := range normalizeLabels(op.Labels) {
+				if !slices.Contains(plan.task.Labels, label) {
+					plan.task.Labels = append(slices.Clone(plan.task.Labels), label)
+					plan.changes = append(plan.changes, "label: +"+label)
+					setField("labels")
+				}
+			}
+			plan.task.Labels = normalizeLabels(plan.task.Labels)
+		case BulkRemoveLabels:
+			for _, label := range normalizeLabels(op.Labels) {
+				if i := slices.Index(plan.task.Labels, label); i >= 0 {
+					plan.task.Labels = slices.Delete(slices.Clone(plan.task.Labels), i, i+1)
+					plan.changes = append(plan.changes, "label: -"+label)
+					setField("labels")
+				}
+			}
+		case BulkDelete:
+			plan.delete = true
+			plan.changes = append(plan.changes, "delete")
+		}
+	}
+	if plan.delete || len(plan.fields) == 0 {
+		return plan, nil
+	}
+
+	plan.task.UpdatedByUserID = &userID
+	setField("updated_by_user_id")
+	if plan.task.Status == enum.Done && plan.previous != enum.Done && plan.task.Recurrence != "" {
+		next, err := nextOccurrence(&plan.task, userID)
+		if err != nil {
+			return nil, err
+		}
+		plan.next = next
+		if next != nil {
+			plan.changes = append(plan.changes, "create next occurrence")
+		}
+	}
+	return plan, nil
+}
+
+// applyBulk writes plan and emits the matching events.
+func (s *TaskService) applyBulk(ctx context.Context, plan *bulkPlan, userID uint) error {
+	task := &plan.task
+	if plan.delete {
+		if err := s.TaskRepo.DeleteByID(ctx, task.ID); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventTaskDeleted, task.ID, userID, TaskChange{})
+	}
+
+	if len(plan.fields) > 0 {
+		if err := s.save(ctx, task, plan.fields, plan.next); err != nil {
+			return err
+		}
+	}
+	for _, assigneeID := range plan.unassign {
+		if err := s.TaskRepo.UnassignUser(ctx, task.ID, assigneeID); err != nil {
+			return err
+		}
+	}
+	if len(plan.fields) > 0 || len(plan.unassign) > 0 {
+		change := statusChange(task, plan.previousName)
+		change.Task = taskToResp(task)
+		if err := s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, change); err != nil {
+			return err
+		}
+	}
+	if plan.next != nil {
+		if err := s.emit(ctx, domain.EventTaskCreated, plan.next.ID, userID, TaskChange{Task: taskToResp(plan.next)}); err != nil {
+			return err
+		}
+	}
+	for _, assigneeID := range plan.assign {
+		if err := s.TaskRepo.AssignUser(ctx, task.ID, assigneeID); err != nil {
+			return err
+		}
+		if err := s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, TaskChange{Task: taskToResp(task), AssigneeID: &assigneeID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
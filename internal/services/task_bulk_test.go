@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func bulkTask(id uint, status enum.TaskStatus, labels ...string) domain.Task {
+	task := domain.Task{Name: "Task", Status: status, Labels: labels, Version: 1}
+	task.ID = id
+	return task
+}
+
+func TestBulkTasks_Applies(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(bulkTask(1, enum.Created), nil)
+	taskRepo.On("GetByID", mock.Anything, uint(2)).Return(bulkTask(2, enum.Done, "bug"), nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ID == 1 && task.Status == enum.Done && assert.ObjectsAreEqual([]string{"bug"}, task.Labels)
+	}), []string{"status", "labels", "updated_by_user_id"}).Return(true, nil).Once()
+
+	done := enum.Done
+	resp, err := svc.BulkTasks(context.Background(), dto.BulkTaskReq{
+		TaskIDs: []uint{2, 1, 2},
+		Operations: []dto.BulkTaskOp{
+			{Op: BulkSetStatus, Status: &done},
+			{Op: BulkAddLabels, Labels: []string{" bug "}},
+		},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.True(t, resp.Applied)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, []string{"status: Created -> Done", "label: +bug"}, resp.Results[0].Changes)
+	assert.True(t, resp.Results[1].OK)
+	assert.Empty(t, resp.Results[1].Changes)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestBulkTasks_DryRun(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(bulkTask(1, enum.Created, "bug", "ui"), nil)
+
+	resp, err := svc.BulkTasks(context.Background(), dto.BulkTaskReq{
+		TaskIDs:    []uint{1},
+		Operations: []dto.BulkTaskOp{{Op: BulkRemoveLabels, Labels: []string{"bug"}}, {Op: BulkArchive}},
+		DryRun:     true,
+	}, 1)
+
+	require.NoError(t, err)
+	assert.False(t, resp.Applied)
+	assert.Equal(t, []string{"label: -bug", "status: Created -> Canceled"}, resp.Results[0].Changes)
+	assert.Equal(t, []string{"ui"}, resp.Results[0].Task.Labels)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkTasks_FailedTaskWritesNothing(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(bulkTask(1, enum.Created), nil)
+	taskRepo.On("GetByID", mock.Anything, uint(9)).Return(domain.Task{}, gorm.ErrRecordNotFound)
+
+	resp, err := svc.BulkTasks(context.Background(), dto.BulkTaskReq{
+		TaskIDs:    []uint{1, 9},
+		Operations: []dto.BulkTaskOp{{Op: BulkDelete}},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.False(t, resp.Applied)
+	assert.True(t, resp.Results[0].OK)
+	assert.Equal(t, api_error.ErrTaskNotFound.Error(), resp.Results[1].Error)
+	taskRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything)
+}
+
+func TestBulkTasks_RollsBackOnConflict(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	taskRepo.On("ListByFilter", mock.Anything, dto.TaskListFilter{Label: "sprint-1"}, MaxBulkTasks+1, 0).
+		Return([]domain.Task{bulkTask(1, enum.Created), bulkTask(2, enum.Created)}, int64(2), nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool { return task.ID == 1 }), mock.Anything).Return(true, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool { return task.ID == 2 }), mock.Anything).Return(false, nil)
+
+	resp, err := svc.BulkTasks(context.Background(), dto.BulkTaskReq{
+		Filter:     &dto.TaskListFilter{Label: "sprint-1"},
+		Operations: []dto.BulkTaskOp{{Op: BulkArchive}},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.False(t, resp.Applied)
+	assert.False(t, resp.Results[0].OK)
+	assert.Equal(t, api_error.ErrTaskModified.Error(), resp.Results[1].Error)
+}
+
+func TestBulkTasks_AssignEmitsPerAssignee(t *testing.T) {
+	bus, _, emitted := setupBusTest(t)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, bus, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(bulkTask(1, enum.Created), nil)
+	taskRepo.On("AssignUser", mock.Anything, uint(1), uint(4)).Return(nil)
+	taskRepo.On("AssignUser", mock.Anything, uint(1), uint(5)).Return(nil)
+
+	resp, err := svc.BulkTasks(context.Background(), dto.BulkTaskReq{
+		TaskIDs:    []uint{1},
+		Operations: []dto.BulkTaskOp{{Op: BulkAssign, UserID: 4}, {Op: BulkAssign, UserID: 5}},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.True(t, resp.Applied)
+	require.Len(t, *emitted, 2)
+	var change TaskChange
+	require.NoError(t, (*emitted)[1].Decode(&change))
+	assert.Equal(t, uint(5), *change.AssigneeID)
+}
+
+func TestBulkTasks_InvalidRequest(t *testing.T) {
+	svc := NewTaskService(new(mockRepo.MockTaskRepo), nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+	archive := []dto.BulkTaskOp{{Op: BulkArchive}}
+
+	_, err := svc.BulkTasks(context.Background(), dto.BulkTaskReq{Operations: archive}, 1)
+	assert.ErrorIs(t, err, api_error.ErrBulkTargets)
+
+	_, err = svc.BulkTasks(context.Background(), dto.BulkTaskReq{TaskIDs: []uint{1}, Filter: &dto.TaskListFilter{}, Operations: archive}, 1)
+	assert.ErrorIs(t, err, api_error.ErrBulkTargets)
+
+	_, err = svc.BulkTasks(context.Background(), dto.BulkTaskReq{TaskIDs: []uint{1}, Operations: []dto.BulkTaskOp{{Op: BulkSetStatus}}}, 1)
+	assert.ErrorIs(t, err, api_error.ErrInvalidBulkOp)
+
+	_, err = svc.BulkTasks(context.Background(), dto.BulkTaskReq{TaskIDs: []uint{1}, Operations: []dto.BulkTaskOp{{Op: BulkDelete}, {Op: BulkArchive}}}, 1)
+	assert.ErrorIs(t, err, api_error.ErrInvalidBulkOp)
+}
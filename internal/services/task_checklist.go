@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxChecklistItems bounds how many items a task's checklist can hold.
+const MaxChecklistItems = 100
+
+func (s *TaskService) GetChecklist(ctx context.Context, taskID uint) (*dto.ChecklistResp, error) {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	return checklistToResp(&task), nil
+}
+
+// AddChecklistItem adds an unchecked item to the end of the task's checklist. A non-zero
+// version must be the task's current one, as for the other checklist changes.
+func (s *TaskService) AddChecklistItem(ctx context.Context, taskID uint, req dto.ChecklistItemReq, userID uint, version int) (*dto.ChecklistResp, error) {
+	text, err := checklistText(req.Text)
+	if err != nil {
+		return nil, err
+	}
+	return s.editChecklist(ctx, taskID, userID, version, func(task *domain.Task, items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		if len(items) >= MaxChecklistItems {
+			return nil, fmt.Errorf("%w: a checklist can hold at most %d items", api_error.ErrInvalidChecklist, MaxChecklistItems)
+		}
+		// Tasks from before the counter was kept start it after their highest item.
+		id := max(task.ChecklistNextID, 1)
+		for _, item := range items {
+			id = max(id, item.ID+1)
+		}
+		task.ChecklistNextID = id + 1
+		return append(items, domain.ChecklistItem{ID: id, Text: text}), nil
+	})
+}
+
+// UpdateChecklistItem rewords an item or checks it off, recording who checked it and when.
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, taskID, itemID uint, req dto.UpdateChecklistItemReq, userID uint, version int) (*dto.ChecklistResp, error) {
+	var text string
+	if req.Text != nil {
+		var err error
+		if text, err = checklistText(*req.Text); err != nil {
+			return nil, err
+		}
+	}
+	return s.editChecklist(ctx, taskID, userID, version, func(_ *domain.Task, items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		i, err := checklistIndex(items, itemID)
+		if err != nil {
+			return nil, err
+		}
+		item := &items[i]
+		if req.Text != nil {
+			item.Text = text
+		}
+		if req.Checked != nil && *req.Checked != item.Checked {
+			item.Checked = *req.Checked
+			item.CheckedBy, item.CheckedAt = nil, nil
+			if item.Checked {
+				now := time.Now()
+				item.CheckedBy, item.CheckedAt = &userID, &now
+			}
+		}
+		return items, nil
+	})
+}
+
+// MoveChecklistItem moves an item to another position in the checklist.
+func (s *TaskService) MoveChecklistItem(ctx context.Context, taskID, itemID uint, req dto.MoveChecklistItemReq, userID uint, version int) (*dto.ChecklistResp, error) {
+	return s.editChecklist(ctx, taskID, userID, version, func(_ *domain.Task, items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		i, err := checklistIndex(items, itemID)
+		if err != nil {
+			return nil, err
+		}
+		item := items[i]
+		items = slices.Delete(items, i, i+1)
+		return slices.Insert(items, min(req.Position, len(items)), item), nil
+	})
+}
+
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, taskID, itemID, userID uint, version int) (*dto.ChecklistResp, error) {
+	return s.editChecklist(ctx, taskID, userID, version, func(_ *domain.Task, items []domain.ChecklistItem) ([]domain.ChecklistItem, error) {
+		i, err := checklistIndex(items, itemID)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Delete(items, i, i+1), nil
+	})
+}
+
+// editChecklist applies edit to a copy of the task's checklist and saves the result as an
+// update of the task, which fails if the task changed in the meantime. edit may also move
+// the task's checklist ID counter on.
+func (s *TaskService) editChecklist(ctx context.Context, taskID, userID uint, version int, edit func(*domain.Task, []domain.ChecklistItem) ([]domain.ChecklistItem, error)) (*dto.ChecklistResp, error) {
+	task, err := s.TaskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, api_error.ErrTaskNotFound
+	}
+	if err := s.authorize(ctx, userID, task.ProjectID, enum.MemberEditor); err != nil {
+		return nil, err
+	}
+	if version != 0 && version != task.Version {
+		return nil, api_error.ErrTaskModified
+	}
+	nextID := task.ChecklistNextID
+	items, err := edit(&task, slices.Clone(task.Checklist))
+	if err != nil {
+		return nil, err
+	}
+
+	task.Checklist = items
+	task.UpdatedByUserID = &userID
+	fields := []string{"checklist", "updated_by_user_id"}
+	if task.ChecklistNextID != nextID {
+		fields = append(fields, "checklist_next_id")
+	}
+	err = s.withEvents(ctx, func(ctx context.Context) error {
+		if err := s.update(ctx, &task, fields); err != nil {
+			return err
+		}
+		return s.emit(ctx, domain.EventTaskUpdated, task.ID, userID, TaskChange{Task: taskToResp(&task)})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return checklistToResp(&task), nil
+}
+
+// checklistText trims the text of an item, which cannot be blank.
+func checklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return "", fmt.Errorf("%w: text cannot be blank", api_error.ErrInvalidChecklist)
+	}
+	return text, nil
+}
+
+func checklistIndex(items []domain.ChecklistItem, itemID uint) (int, error) {
+	i := slices.IndexFunc(items, func(item domain.ChecklistItem) bool { return item.ID == itemID })
+	if i < 0 {
+		return 0, api_error.ErrChecklistNotFound
+	}
+	return i, nil
+}
+
+// uncheckedChecklist returns a copy of items with every item unchecked, for a new
+// occurrence of a recurring task.
+func uncheckedChecklist(items []domain.ChecklistItem) []domain.ChecklistItem {
+	if len(items) == 0 {
+		return nil
+	}
+	unchecked := make([]domain.ChecklistItem, len(items))
+	for i, item := range items {
+		unchecked[i] = domain.ChecklistItem{ID: item.ID, Text: item.Text}
+	}
+	return unchecked
+}
+
+func checklistProgress(items []domain.ChecklistItem) dto.ChecklistProgress {
+	progress := dto.ChecklistProgress{Total: len(items)}
+	for _, item := range items {
+		if item.Checked {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+func checklistToResp(task *domain.Task) *dto.ChecklistResp {
+	items := make([]dto.ChecklistItemResp, len(task.Checklist))
+	for i, item := range task.Checklist {
+		items[i] = dto.ChecklistItemResp{
+			ID:        item.ID,
+			Text:      item.Text,
+			Checked:   item.Checked,
+			CheckedBy: item.CheckedBy,
+			CheckedAt: item.CheckedAt,
+		}
+	}
+	return &dto.ChecklistResp{
+		TaskID:   task.ID,
+		Version:  task.Version,
+		Items:    items,
+		Progress: checklistProgress(task.Checklist),
+	}
+}
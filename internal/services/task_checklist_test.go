@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	mockRepo "graph-interview/internal/repository/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// checklistTask is task 4 with the items Draft (checked), Review and Ship, in that order.
+func checklistTask() domain.Task {
+	checkedAt := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	task := domain.Task{Name: "Release", Version: 2, Checklist: []domain.ChecklistItem{
+		{ID: 1, Text: "Draft", Checked: true, CheckedBy: ptr(uint(2)), CheckedAt: &checkedAt},
+		{ID: 2, Text: "Review"},
+		{ID: 4, Text: "Ship"},
+	}}
+	task.ID = 4
+	return task
+}
+
+func setupChecklistTest() (*TaskService, *mockRepo.MockTaskRepo) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	taskRepo.On("GetByID", mock.Anything, uint(4)).Return(checklistTask(), nil)
+	return NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil), taskRepo
+}
+
+func checklistTexts(resp *dto.ChecklistResp) []string {
+	texts := make([]string, len(resp.Items))
+	for i, item := range resp.Items {
+		texts[i] = item.Text
+	}
+	return texts
+}
+
+func TestGetChecklist(t *testing.T) {
+	svc, _ := setupChecklistTest()
+
+	resp, err := svc.GetChecklist(context.Background(), 4)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Draft", "Review", "Ship"}, checklistTexts(resp))
+	assert.Equal(t, dto.ChecklistProgress{Done: 1, Total: 3}, resp.Progress)
+	assert.Equal(t, 2, resp.Version)
+}
+
+func TestAddChecklistItem(t *testing.T) {
+	svc, taskRepo := setupChecklistTest()
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ChecklistNextID == 6
+	}), []string{"checklist", "updated_by_user_id", "checklist_next_id"}).Return(true, nil)
+
+	resp, err := svc.AddChecklistItem(context.Background(), 4, dto.ChecklistItemReq{Text: "  Announce "}, 1, 2)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Items, 4)
+	assert.Equal(t, dto.ChecklistItemResp{ID: 5, Text: "Announce"}, resp.Items[3])
+	assert.Equal(t, dto.ChecklistProgress{Done: 1, Total: 4}, resp.Progress)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestAddChecklistItem_DoesNotReuseDeletedIDs(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	task := checklistTask()
+	// Items 5 to 7 were added and deleted again.
+	task.ChecklistNextID = 8
+	taskRepo.On("GetByID", mock.Anything, uint(4)).Return(task, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ChecklistNextID == 9
+	}), mock.Anything).Return(true, nil)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	resp, err := svc.AddChecklistItem(context.Background(), 4, dto.ChecklistItemReq{Text: "Announce"}, 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(8), resp.Items[3].ID)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestAddChecklistItem_Invalid(t *testing.T) {
+	svc, taskRepo := setupChecklistTest()
+
+	_, err := svc.AddChecklistItem(context.Background(), 4, dto.ChecklistItemReq{Text: "   "}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrInvalidChecklist)
+
+	_, err = svc.AddChecklistItem(context.Background(), 4, dto.ChecklistItemReq{Text: "Announce"}, 1, 1)
+	assert.ErrorIs(t, err, api_error.ErrTaskModified)
+
+	full := checklistTask()
+	full.ID = 5
+	for i := len(full.Checklist); i < MaxChecklistItems; i++ {
+		full.Checklist = append(full.Checklist, domain.ChecklistItem{ID: uint(i + 5), Text: strings.Repeat("x", i)})
+	}
+	taskRepo.On("GetByID", mock.Anything, uint(5)).Return(full, nil)
+	_, err = svc.AddChecklistItem(context.Background(), 5, dto.ChecklistItemReq{Text: "Announce"}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrInvalidChecklist)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateChecklistItem(t *testing.T) {
+	svc, taskRepo := setupChecklistTest()
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+	resp, err := svc.UpdateChecklistItem(context.Background(), 4, 2, dto.UpdateChecklistItemReq{Checked: ptr(true)}, 3, 0)
+	require.NoError(t, err)
+	item := resp.Items[1]
+	assert.True(t, item.Checked)
+	assert.Equal(t, ptr(uint(3)), item.CheckedBy)
+	assert.NotNil(t, item.CheckedAt)
+	assert.Equal(t, dto.ChecklistProgress{Done: 2, Total: 3}, resp.Progress)
+
+	// Unchecking forgets who checked the item; rewording leaves it checked or not.
+	resp, err = svc.UpdateChecklistItem(context.Background(), 4, 1, dto.UpdateChecklistItemReq{Checked: ptr(false)}, 3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, dto.ChecklistItemResp{ID: 1, Text: "Draft"}, resp.Items[0])
+
+	resp, err = svc.UpdateChecklistItem(context.Background(), 4, 1, dto.UpdateChecklistItemReq{Text: ptr("Write draft")}, 3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Write draft", resp.Items[0].Text)
+	assert.True(t, resp.Items[0].Checked)
+	assert.Equal(t, ptr(uint(2)), resp.Items[0].CheckedBy)
+
+	_, err = svc.UpdateChecklistItem(context.Background(), 4, 3, dto.UpdateChecklistItemReq{Checked: ptr(true)}, 3, 0)
+	assert.ErrorIs(t, err, api_error.ErrChecklistNotFound)
+}
+
+func TestMoveChecklistItem(t *testing.T) {
+	tests := []struct {
+		itemID   uint
+		position int
+		want     []string
+	}{
+		{4, 0, []string{"Ship", "Draft", "Review"}},
+		{1, 1, []string{"Review", "Draft", "Ship"}},
+		{1, 2, []string{"Review", "Ship", "Draft"}},
+		{2, 10, []string{"Draft", "Ship", "Review"}},
+		{2, 1, []string{"Draft", "Review", "Ship"}},
+	}
+	for _, tt := range tests {
+		svc, taskRepo := setupChecklistTest()
+		taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+		resp, err := svc.MoveChecklistItem(context.Background(), 4, tt.itemID, dto.MoveChecklistItemReq{Position: tt.position}, 1, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, checklistTexts(resp), "item %d to %d", tt.itemID, tt.position)
+	}
+}
+
+func TestDeleteChecklistItem(t *testing.T) {
+	svc, taskRepo := setupChecklistTest()
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return len(task.Checklist) == 2
+	}), mock.Anything).Return(true, nil)
+
+	resp, err := svc.DeleteChecklistItem(context.Background(), 4, 1, 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Review", "Ship"}, checklistTexts(resp))
+	assert.Equal(t, dto.ChecklistProgress{Done: 0, Total: 2}, resp.Progress)
+	taskRepo.AssertExpectations(t)
+
+	_, err = svc.DeleteChecklistItem(context.Background(), 4, 1, 1, 0)
+	require.NoError(t, err, "edits work on a copy of the loaded checklist")
+}
+
+func TestNextOccurrence_UnchecksChecklist(t *testing.T) {
+	task := checklistTask()
+	due := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	task.DueDate = &due
+	task.Recurrence = "FREQ=WEEKLY"
+	task.ChecklistNextID = 5
+
+	next, err := nextOccurrence(&task, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []domain.ChecklistItem{{ID: 1, Text: "Draft"}, {ID: 2, Text: "Review"}, {ID: 4, Text: "Ship"}}, next.Checklist)
+	assert.Equal(t, uint(5), next.ChecklistNextID)
+	assert.True(t, task.Checklist[0].Checked)
+	assert.Equal(t, dto.ChecklistProgress{Done: 0, Total: 3}, taskToResp(next).ChecklistProgress)
+}
@@ -2,23 +2,30 @@ package services
 
 import (
 	"context"
+	"errors"
 	"graph-interview/internal/api/handlers/dto"
 	api_error "graph-interview/internal/api/handlers/errors"
 	"graph-interview/internal/domain"
 	"graph-interview/internal/repository/enum"
 	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
 func TestCreateTask_Success(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
-	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Created}).Return("i", nil)
+	taskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Position == "r"
+	})).
 		Run(func(args mock.Arguments) {
 			task := args.Get(1).(*domain.Task)
 			task.ID = 1
@@ -42,7 +49,7 @@ func TestCreateTask_Success(t *testing.T) {
 
 func TestGetTask_Success(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	taskRepo.On("GetByID", mock.Anything, uint(1)).
 		Return(domain.Task{
@@ -61,7 +68,7 @@ func TestGetTask_Success(t *testing.T) {
 
 func TestGetTask_NotFound(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	taskRepo.On("GetByID", mock.Anything, uint(999)).
 		Return(domain.Task{}, gorm.ErrRecordNotFound)
@@ -76,7 +83,7 @@ func TestGetTask_NotFound(t *testing.T) {
 
 func TestListTasks_Success(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	tasks := []domain.Task{
 		{Name: "Task 1", Status: enum.Created},
@@ -98,7 +105,7 @@ func TestListTasks_Success(t *testing.T) {
 
 func TestUpdateTask_Success(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	existingTask := domain.Task{
 		Name:        "Old Name",
@@ -110,12 +117,11 @@ func TestUpdateTask_Success(t *testing.T) {
 	taskRepo.On("GetByID", mock.Anything, uint(1)).
 		Return(existingTask, nil)
 	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).
-		Return(nil)
+		Return(true, nil)
 
-	newName := "New Name"
-	req := dto.UpdateTaskReq{Name: &newName}
+	req := dto.UpdateTaskReq{Name: "New Name", Description: "Old Desc"}
 
-	resp, err := svc.UpdateTask(context.Background(), 1, req, 1)
+	resp, err := svc.UpdateTask(context.Background(), 1, req, 1, 0)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
@@ -125,7 +131,7 @@ func TestUpdateTask_Success(t *testing.T) {
 
 func TestUpdateTask_StatusChange(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	existingTask := domain.Task{
 		Name:   "Task",
@@ -136,12 +142,11 @@ func TestUpdateTask_StatusChange(t *testing.T) {
 	taskRepo.On("GetByID", mock.Anything, uint(1)).
 		Return(existingTask, nil)
 	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).
-		Return(nil)
+		Return(true, nil)
 
-	newStatus := enum.Started
-	req := dto.UpdateTaskReq{Status: &newStatus}
+	req := dto.UpdateTaskReq{Name: "Task", Status: enum.Started}
 
-	resp, err := svc.UpdateTask(context.Background(), 1, req, 1)
+	resp, err := svc.UpdateTask(context.Background(), 1, req, 1, 0)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
@@ -149,16 +154,97 @@ func TestUpdateTask_StatusChange(t *testing.T) {
 	taskRepo.AssertExpectations(t)
 }
 
+func TestUpdateTask_VersionMismatch(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "Task", Version: 3}, nil)
+
+	_, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Renamed"}, 1, 2)
+
+	assert.Equal(t, api_error.ErrTaskModified, err)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_LostRace(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "Task", Version: 3}, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Version == 3
+	}), mock.Anything).Return(false, nil)
+
+	_, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Renamed"}, 1, 3)
+
+	assert.Equal(t, api_error.ErrTaskModified, err)
+}
+
+func TestUpdateTask_ClearsOmittedFields(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	existing := domain.Task{Name: "Task", Description: "Desc", DueDate: &due, Labels: []string{"bug"}}
+	existing.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existing, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Description == "" && task.DueDate == nil && len(task.Labels) == 0
+	}), []string{"updated_by_user_id", "description", "due_date", "labels"}).Return(true, nil)
+
+	resp, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task"}, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Nil(t, resp.DueDate)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestPatchTask_SeesCurrentState(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	existing := domain.Task{Name: "Task", Description: "Desc", Status: enum.Started, Labels: []string{"bug"}}
+	existing.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existing, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), []string{"updated_by_user_id", "name"}).Return(true, nil)
+
+	resp, err := svc.PatchTask(context.Background(), 1, func(current dto.UpdateTaskReq) (dto.UpdateTaskReq, error) {
+		assert.Equal(t, dto.UpdateTaskReq{Name: "Task", Description: "Desc", Status: enum.Started, StatusName: "Started", Labels: []string{"bug"}, CustomFields: map[string]any{}}, current)
+		current.Name = "Renamed"
+		return current, nil
+	}, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", resp.Name)
+	assert.Equal(t, "Desc", resp.Description)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestPatchTask_PatchError(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "Task"}, nil)
+	patchErr := errors.New("bad patch")
+
+	_, err := svc.PatchTask(context.Background(), 1, func(current dto.UpdateTaskReq) (dto.UpdateTaskReq, error) {
+		return current, patchErr
+	}, 1, 0)
+
+	assert.Equal(t, patchErr, err)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestDeleteTask_Success(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	taskRepo.On("GetByID", mock.Anything, uint(1)).
 		Return(domain.Task{}, nil)
 	taskRepo.On("DeleteByID", mock.Anything, uint(1)).
 		Return(nil)
 
-	err := svc.DeleteTask(context.Background(), 1)
+	err := svc.DeleteTask(context.Background(), 1, 1, 0)
 
 	assert.NoError(t, err)
 	taskRepo.AssertExpectations(t)
@@ -166,12 +252,12 @@ func TestDeleteTask_Success(t *testing.T) {
 
 func TestDeleteTask_NotFound(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	taskRepo.On("GetByID", mock.Anything, uint(999)).
 		Return(domain.Task{}, gorm.ErrRecordNotFound)
 
-	err := svc.DeleteTask(context.Background(), 999)
+	err := svc.DeleteTask(context.Background(), 999, 1, 0)
 
 	assert.Error(t, err)
 	assert.Equal(t, api_error.ErrTaskNotFound, err)
@@ -180,7 +266,7 @@ func TestDeleteTask_NotFound(t *testing.T) {
 
 func TestArchiveTask_Success(t *testing.T) {
 	taskRepo := new(mockRepo.MockTaskRepo)
-	svc := NewTaskService(taskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
 
 	existingTask := domain.Task{
 		Name:   "Task",
@@ -191,7 +277,7 @@ func TestArchiveTask_Success(t *testing.T) {
 	taskRepo.On("GetByID", mock.Anything, uint(1)).
 		Return(existingTask, nil)
 	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), []string{"status", "updated_by_user_id"}).
-		Return(nil)
+		Return(true, nil)
 
 	resp, err := svc.ArchiveTask(context.Background(), 1, 1)
 
@@ -200,3 +286,267 @@ func TestArchiveTask_Success(t *testing.T) {
 	assert.Equal(t, "Canceled", resp.Status)
 	taskRepo.AssertExpectations(t)
 }
+
+func TestCreateTask_InArchivedProject(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	svc := NewTaskService(taskRepo, projectRepo, nil, nil, nil, nil, nil)
+
+	projectID := uint(3)
+	projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{Archived: true}, nil)
+
+	_, err := svc.CreateTask(context.Background(), dto.CreateTaskReq{Name: "t", ProjectID: &projectID}, 1)
+
+	assert.Equal(t, api_error.ErrProjectArchived, err)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestMoveTask_Success(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	svc := NewTaskService(taskRepo, projectRepo, nil, nil, nil, nil, nil)
+
+	projectID := uint(3)
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "t"}, nil)
+	projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{Name: "Home"}, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.ProjectID != nil && *task.ProjectID == projectID
+	}), []string{"project_id", "updated_by_user_id"}).Return(true, nil)
+
+	resp, err := svc.MoveTask(context.Background(), 1, &projectID, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, projectID, *resp.ProjectID)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestMoveTask_ProjectNotFound(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	svc := NewTaskService(taskRepo, projectRepo, nil, nil, nil, nil, nil)
+
+	projectID := uint(3)
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "t"}, nil)
+	projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.MoveTask(context.Background(), 1, &projectID, 1)
+
+	assert.Equal(t, api_error.ErrProjectNotFound, err)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_ViewerForbidden(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewTaskService(taskRepo, nil, orgRepo, nil, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "t"}, nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+
+	_, err := svc.UpdateTask(tenant.WithOrg(context.Background(), 7), 1, dto.UpdateTaskReq{Name: "new"}, 2, 0)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteTask_ProjectRoleOverridesOrgRole(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewTaskService(taskRepo, projectRepo, orgRepo, nil, nil, nil, nil)
+
+	projectID := uint(3)
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{ProjectID: &projectID}, nil)
+	taskRepo.On("DeleteByID", mock.Anything, uint(1)).Return(nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+	projectRepo.On("GetByID", mock.Anything, projectID).Return(domain.Project{OwnerID: 1}, nil)
+	projectRepo.On("GetMember", mock.Anything, projectID, uint(2)).Return(domain.ProjectMember{Role: enum.MemberEditor}, nil)
+
+	err := svc.DeleteTask(tenant.WithOrg(context.Background(), 7), 1, 2, 0)
+
+	assert.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestCreateTask_NotOrgMember(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewTaskService(taskRepo, nil, orgRepo, nil, nil, nil, nil)
+
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.CreateTask(tenant.WithOrg(context.Background(), 7), dto.CreateTaskReq{Name: "t"}, 2)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateTask_RecurrenceNeedsDueDate(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	_, err := svc.CreateTask(context.Background(), dto.CreateTaskReq{Name: "t", Recurrence: "FREQ=WEEKLY"}, 1)
+
+	assert.Equal(t, api_error.ErrRecurrenceNoDue, err)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateTask_InvalidRecurrence(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	_, err := svc.CreateTask(context.Background(), dto.CreateTaskReq{Name: "t", DueDate: &due, Recurrence: "FREQ=YEARLY"}, 1)
+
+	assert.ErrorIs(t, err, api_error.ErrInvalidRecurrence)
+}
+
+func TestUpdateTask_DoneCreatesNextOccurrence(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	due := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	existing := domain.Task{Name: "Weekly report", Status: enum.Started, DueDate: &due, Recurrence: "FREQ=WEEKLY;BYDAY=MO", Occurrence: 1}
+	existing.ID = 4
+
+	taskRepo.On("GetByID", mock.Anything, uint(4)).Return(existing, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Recurrence == "" && *task.SeriesID == 4
+	}), []string{"updated_by_user_id", "status", "series_id", "recurrence"}).Return(true, nil)
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{Status: enum.Created}).Return("", nil)
+	taskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Name == "Weekly report" &&
+			task.Position == "i" &&
+			task.Status == enum.Created &&
+			task.DueDate.Equal(due.AddDate(0, 0, 7)) &&
+			task.Recurrence == "FREQ=WEEKLY;BYDAY=MO" &&
+			*task.SeriesID == 4 &&
+			task.Occurrence == 2
+	})).Return(uint(5), nil)
+
+	req := dto.UpdateTaskReq{Name: existing.Name, Status: enum.Done, DueDate: &due, Recurrence: existing.Recurrence}
+	resp, err := svc.UpdateTask(context.Background(), 4, req, 1, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), *resp.SeriesID)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_DoneSeriesFinished(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, nil, nil, nil)
+
+	seriesID := uint(4)
+	due := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+	existing := domain.Task{Name: "Invoice", Status: enum.Created, DueDate: &due, Recurrence: "FREQ=MONTHLY;COUNT=3", SeriesID: &seriesID, Occurrence: 3}
+	existing.ID = 9
+
+	taskRepo.On("GetByID", mock.Anything, uint(9)).Return(existing, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), []string{"updated_by_user_id", "status"}).Return(true, nil)
+
+	req := dto.UpdateTaskReq{Name: existing.Name, Status: enum.Done, DueDate: &due, Recurrence: existing.Recurrence}
+	_, err := svc.UpdateTask(context.Background(), 9, req, 1, 0)
+
+	assert.NoError(t, err)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateTask_EmitsStatusChange(t *testing.T) {
+	bus, _, emitted := setupBusTest(t)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, bus, nil, nil)
+
+	existing := domain.Task{Name: "Task", Status: enum.Created}
+	existing.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existing, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+	_, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", Status: enum.Started}, 1, 0)
+
+	assert.NoError(t, err)
+	require.Len(t, *emitted, 1)
+	event := (*emitted)[0]
+	assert.Equal(t, domain.EventTaskUpdated, event.Type)
+	var change TaskChange
+	require.NoError(t, event.Decode(&change))
+	assert.Equal(t, enum.Created.String(), change.PreviousStatus)
+	assert.Equal(t, enum.Started.String(), change.Task.Status)
+}
+
+func TestUpdateTask_EmitsNewMentions(t *testing.T) {
+	bus, _, emitted := setupBusTest(t)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, mockRepo.NoopTransactor{}, bus, nil, nil)
+
+	existing := domain.Task{Name: "Task", Description: "cc @mary", Status: enum.Created}
+	existing.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existing, nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(true, nil)
+
+	_, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", Description: "cc @mary @bob", Status: enum.Created}, 1, 0)
+
+	assert.NoError(t, err)
+	require.Len(t, *emitted, 1)
+	var change TaskChange
+	require.NoError(t, (*emitted)[0].Decode(&change))
+	assert.Equal(t, []string{"bob"}, change.Mentions)
+}
+
+func TestWatchTask_HiddenTask(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	svc := NewTaskService(taskRepo, nil, nil, nil, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{}, gorm.ErrRecordNotFound)
+
+	err := svc.WatchTask(context.Background(), 1, 2)
+
+	assert.ErrorIs(t, err, api_error.ErrTaskNotFound)
+	taskRepo.AssertNotCalled(t, "Watch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAssignTask_EmitsAssignment(t *testing.T) {
+	bus, _, emitted := setupBusTest(t)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewTaskService(taskRepo, nil, orgRepo, mockRepo.NoopTransactor{}, bus, nil, nil)
+
+	existing := domain.Task{Name: "Task"}
+	existing.ID = 1
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(existing, nil)
+	taskRepo.On("AssignUser", mock.Anything, uint(1), uint(4)).Return(nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(4)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+
+	_, err := svc.AssignTask(tenant.WithOrg(context.Background(), 7), 1, 4, 1)
+
+	assert.NoError(t, err)
+	taskRepo.AssertExpectations(t)
+	require.Len(t, *emitted, 1)
+	event := (*emitted)[0]
+	assert.Equal(t, uint(7), event.OrganizationID)
+	assert.Equal(t, uint(1), *event.ActorID)
+	var change TaskChange
+	require.NoError(t, event.Decode(&change))
+	assert.Equal(t, uint(4), *change.AssigneeID)
+}
+
+func TestAssignTask_NotOrgMember(t *testing.T) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewTaskService(taskRepo, nil, orgRepo, nil, nil, nil, nil)
+
+	taskRepo.On("GetByID", mock.Anything, uint(1)).Return(domain.Task{Name: "Task"}, nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(4)).Return(domain.Membership{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.AssignTask(tenant.WithOrg(context.Background(), 7), 1, 4, 1)
+
+	assert.Equal(t, api_error.ErrUserNotFound, err)
+	taskRepo.AssertNotCalled(t, "AssignUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewMentions(t *testing.T) {
+	assert.Equal(t, []string{"mary", "j.doe"}, newMentions("", "Ask @mary and @j.doe. Not john@example.com, nor @mary again."))
+	assert.Equal(t, []string{"bob"}, newMentions("cc @mary", "cc @mary, @bob"))
+	assert.Empty(t, newMentions("@mary", "@mary"))
+}
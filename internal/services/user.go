@@ -7,6 +7,7 @@ import (
 	api_error "graph-interview/internal/api/handlers/errors"
 	"graph-interview/internal/domain"
 	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
@@ -14,11 +15,16 @@ import (
 
 type UserService struct {
 	UserRepo repository.UserRepo
+	Tx       repository.Transactor
+	// Bus, when set, records new users as domain events along with them.
+	Bus *EventBus
 }
 
-func NewUserService(userRepo repository.UserRepo) *UserService {
+func NewUserService(userRepo repository.UserRepo, tx repository.Transactor, bus *EventBus) *UserService {
 	return &UserService{
 		UserRepo: userRepo,
+		Tx:       tx,
+		Bus:      bus,
 	}
 }
 
@@ -34,11 +40,18 @@ func (s *UserService) CreateUser(ctx context.Context, req dto.CreateUserReq) (*d
 		return nil, api_error.UsernameExists(req.Username)
 	}
 
-	ID, err := s.UserRepo.Create(ctx, &domain.User{
+	user := &domain.User{
 		Username: req.Username,
 		Email:    req.Email,
 		Password: req.Password,
 		Avatar:   "",
+	}
+	var ID uint
+	err = withEvents(ctx, s.Tx, s.Bus, func(ctx context.Context) error {
+		if ID, err = s.UserRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.Bus.Emit(ctx, domain.EventUserCreated, ID, &ID, UserChange{Username: user.Username})
 	})
 	if err != nil {
 		return nil, err
@@ -60,3 +73,32 @@ func (s *UserService) GetProfile(ctx context.Context, userID uint) (*dto.UserPro
 		Avatar:   user.Avatar,
 	}, nil
 }
+
+func (s *UserService) IsAdmin(ctx context.Context, userID uint) (bool, error) {
+	user, err := s.UserRepo.GetByID(ctx, userID)
+	if err != nil {
+		return false, api_error.ErrUserNotFound
+	}
+	return user.Role == enum.RoleAdmin && user.Active(), nil
+}
+
+// PromoteAdmins grants the admin role to the given usernames, skipping unknown ones.
+func (s *UserService) PromoteAdmins(ctx context.Context, usernames []string) error {
+	for _, username := range usernames {
+		user, err := s.UserRepo.GetByField(ctx, "username", username)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if user.Role == enum.RoleAdmin {
+			continue
+		}
+		user.Role = enum.RoleAdmin
+		if err := s.UserRepo.UpdateByID(ctx, &user, []string{"role"}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -14,7 +14,7 @@ import (
 
 func TestCreateUser_Success(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	svc := NewUserService(userRepo)
+	svc := NewUserService(userRepo, nil, nil)
 
 	userRepo.On("GetByField", mock.Anything, "username", "testuser").
 		Return(domain.User{}, gorm.ErrRecordNotFound)
@@ -35,9 +35,27 @@ func TestCreateUser_Success(t *testing.T) {
 	userRepo.AssertExpectations(t)
 }
 
+func TestCreateUser_EmitsEvent(t *testing.T) {
+	bus, _, emitted := setupBusTest(t)
+	userRepo := new(mockRepo.MockUserRepo)
+	svc := NewUserService(userRepo, mockRepo.NoopTransactor{}, bus)
+
+	userRepo.On("GetByField", mock.Anything, "username", "testuser").
+		Return(domain.User{}, gorm.ErrRecordNotFound)
+	userRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.User")).
+		Return(uint(5), nil)
+
+	_, err := svc.CreateUser(context.Background(), dto.CreateUserReq{Username: "testuser", Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.Len(t, *emitted, 1)
+	assert.Equal(t, domain.EventUserCreated, (*emitted)[0].Type)
+	assert.Equal(t, uint(5), (*emitted)[0].AggregateID)
+}
+
 func TestCreateUser_DuplicateUsername(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	svc := NewUserService(userRepo)
+	svc := NewUserService(userRepo, nil, nil)
 
 	userRepo.On("GetByField", mock.Anything, "username", "existinguser").
 		Return(domain.User{Username: "existinguser"}, nil)
@@ -58,7 +76,7 @@ func TestCreateUser_DuplicateUsername(t *testing.T) {
 
 func TestGetProfile_Success(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	svc := NewUserService(userRepo)
+	svc := NewUserService(userRepo, nil, nil)
 
 	userRepo.On("GetByID", mock.Anything, uint(1)).
 		Return(domain.User{
@@ -78,7 +96,7 @@ func TestGetProfile_Success(t *testing.T) {
 
 func TestGetProfile_NotFound(t *testing.T) {
 	userRepo := new(mockRepo.MockUserRepo)
-	svc := NewUserService(userRepo)
+	svc := NewUserService(userRepo, nil, nil)
 
 	userRepo.On("GetByID", mock.Anything, uint(999)).
 		Return(domain.User{}, gorm.ErrRecordNotFound)
@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Keys of the built-in views.
+const (
+	ViewMyOpenTasks     = "my-open-tasks"
+	ViewOverdue         = "overdue"
+	ViewRecentlyUpdated = "recently-updated"
+)
+
+// builtinViews are offered to everyone alongside the saved views.
+var builtinViews = []struct {
+	key  string
+	view domain.View
+}{
+	{ViewMyOpenTasks, domain.View{Name: "My open tasks", Filter: "assignee = me and status in (Created, Started, Delayed)", Sort: "due,-updated"}},
+	{ViewOverdue, domain.View{Name: "Overdue", Filter: "due < now and status in (Created, Started, Delayed)", Sort: "due"}},
+	{ViewRecentlyUpdated, domain.View{Name: "Recently updated", Filter: "updated >= -7d", Sort: "-updated"}},
+}
+
+// ViewService manages saved views and runs them. A view is referred to by its ID, or by its
+// key for built-in views. Views a user cannot see are reported as not found.
+type ViewService struct {
+	ViewRepo    repository.ViewRepo
+	TaskRepo    repository.TaskRepo
+	ProjectRepo repository.ProjectRepo
+	OrgRepo     repository.OrgRepo
+}
+
+func NewViewService(viewRepo repository.ViewRepo, taskRepo repository.TaskRepo, projectRepo repository.ProjectRepo, orgRepo repository.OrgRepo) *ViewService {
+	return &ViewService{
+		ViewRepo:    viewRepo,
+		TaskRepo:    taskRepo,
+		ProjectRepo: projectRepo,
+		OrgRepo:     orgRepo,
+	}
+}
+
+func (s *ViewService) CreateView(ctx context.Context, req dto.ViewReq, userID uint) (*dto.ViewResp, error) {
+	if err := s.check(ctx, req, userID); err != nil {
+		return nil, err
+	}
+	view := &domain.View{
+		OwnerID:   userID,
+		Name:      req.Name,
+		Filter:    req.Filter,
+		Sort:      req.Sort,
+		Shared:    req.Shared,
+		ProjectID: req.ProjectID,
+	}
+	if _, err := s.ViewRepo.Create(ctx, view); err != nil {
+		return nil, err
+	}
+	return viewToResp(view, "", false), nil
+}
+
+// ListViews returns the built-in views, the user's own views and the views shared with
+// them. Pinned views come first, in the order they were pinned.
+func (s *ViewService) ListViews(ctx context.Context, userID uint) ([]dto.ViewResp, error) {
+	if err := requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, nil, enum.MemberViewer); err != nil {
+		return nil, err
+	}
+	pins, err := s.ViewRepo.ListPins(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.ViewRepo.ListVisible(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resps := make([]dto.ViewResp, 0, len(builtinViews)+len(saved))
+	for _, builtin := range builtinViews {
+		resps = append(resps, *viewToResp(&builtin.view, builtin.key, slices.Contains(pins, builtin.key)))
+	}
+	for i := range saved {
+		view := &saved[i]
+		if view.OwnerID != userID && view.ProjectID != nil &&
+			requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, view.ProjectID, enum.MemberViewer) != nil {
+			continue
+		}
+		resps = append(resps, *viewToResp(view, "", slices.Contains(pins, viewRef(view, ""))))
+	}
+
+	pinIndex := func(resp dto.ViewResp) int {
+		ref := resp.Key
+		if ref == "" {
+			ref = strconv.FormatUint(uint64(resp.ID), 10)
+		}
+		if i := slices.Index(pins, ref); i >= 0 {
+			return i
+		}
+		return len(pins)
+	}
+	slices.SortStableFunc(resps, func(a, b dto.ViewResp) int {
+		return pinIndex(a) - pinIndex(b)
+	})
+	return resps, nil
+}
+
+func (s *ViewService) GetView(ctx context.Context, ref string, userID uint) (*dto.ViewResp, error) {
+	view, key, err := s.resolve(ctx, ref, userID)
+	if err != nil {
+		return nil, err
+	}
+	pins, err := s.ViewRepo.ListPins(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return viewToResp(&view, key, slices.Contains(pins, viewRef(&view, key))), nil
+}
+
+// UpdateView replaces a saved view. Only its owner, or an owner of the project or
+// organization it is shared with, may change it.
+func (s *ViewService) UpdateView(ctx context.Context, ref string, req dto.ViewReq, userID uint) (*dto.ViewResp, error) {
+	view, err := s.editable(ctx, ref, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.check(ctx, req, userID); err != nil {
+		return nil, err
+	}
+	view.Name = req.Name
+	view.Filter = req.Filter
+	view.Sort = req.Sort
+	view.Shared = req.Shared
+	view.ProjectID = req.ProjectID
+	if err := s.ViewRepo.UpdateByID(ctx, &view, []string{"name", "filter", "sort", "shared", "project_id"}); err != nil {
+		return nil, err
+	}
+	pins, err := s.ViewRepo.ListPins(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return viewToResp(&view, "", slices.Contains(pins, viewRef(&view, ""))), nil
+}
+
+// DeleteView removes a saved view along with everyone's pins of it. The same users as for
+// UpdateView may delete it.
+func (s *ViewService) DeleteView(ctx context.Context, ref string, userID uint) error {
+	view, err := s.editable(ctx, ref, userID)
+	if err != nil {
+		return err
+	}
+	return s.ViewRepo.DeleteByID(ctx, view.ID)
+}
+
+func (s *ViewService) PinView(ctx context.Context, ref string, userID uint) error {
+	view, key, err := s.resolve(ctx, ref, userID)
+	if err != nil {
+		return err
+	}
+	return s.ViewRepo.Pin(ctx, userID, viewRef(&view, key))
+}
+
+func (s *ViewService) UnpinView(ctx context.Context, ref string, userID uint) error {
+	view, key, err := s.resolve(ctx, ref, userID)
+	if err != nil {
+		return err
+	}
+	return s.ViewRepo.Unpin(ctx, userID, viewRef(&view, key))
+}
+
+// ViewTasks runs a view: it lists the tasks matching its filter right now, in its order.
+// "me" and relative dates in the filter resolve for the user running it.
+func (s *ViewService) ViewTasks(ctx context.Context, ref string, userID uint, limit, offset int) (*dto.TaskListResp, error) {
+	view, _, err := s.resolve(ctx, ref, userID)
+	if err != nil {
+		return nil, err
+	}
+	taskFilter := dto.TaskListFilter{Query: view.Filter, Sort: view.Sort}
+	if view.ProjectID != nil {
+		taskFilter.ProjectID = *view.ProjectID
+	}
+	if taskFilter.Orders, err = filter.ParseSort(view.Sort); err != nil {
+		return nil, err
+	}
+	if view.Filter != "" {
+		if taskFilter.Parsed, err = filter.Parse(view.Filter, filter.Env{UserID: userID, Now: time.Now()}); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks, total, err := s.TaskRepo.ListByFilter(ctx, taskFilter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	taskResps := make([]dto.TaskResp, len(tasks))
+	for i := range tasks {
+		taskResps[i] = *taskToResp(&tasks[i])
+	}
+	return &dto.TaskListResp{
+		Tasks:  taskResps,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// resolve finds the view ref refers to, along with its key when it is a built-in one.
+func (s *ViewService) resolve(ctx context.Context, ref string, userID uint) (domain.View, string, error) {
+	if err := requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, nil, enum.MemberViewer); err != nil {
+		return domain.View{}, "", err
+	}
+	for _, builtin := range builtinViews {
+		if builtin.key == ref {
+			return builtin.view, builtin.key, nil
+		}
+	}
+
+	id, err := strconv.ParseUint(ref, 10, 64)
+	if err != nil {
+		return domain.View{}, "", api_error.ErrViewNotFound
+	}
+	view, err := s.ViewRepo.GetByID(ctx, uint(id))
+	if err != nil {
+		return domain.View{}, "", api_error.ErrViewNotFound
+	}
+	if view.OwnerID == userID {
+		return view, "", nil
+	}
+	if !view.Shared || requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, view.ProjectID, enum.MemberViewer) != nil {
+		return domain.View{}, "", api_error.ErrViewNotFound
+	}
+	return view, "", nil
+}
+
+// editable finds a saved view userID may change.
+func (s *ViewService) editable(ctx context.Context, ref string, userID uint) (domain.View, error) {
+	view, key, err := s.resolve(ctx, ref, userID)
+	if err != nil {
+		return domain.View{}, err
+	}
+	if key != "" {
+		return domain.View{}, api_error.ErrBuiltinView
+	}
+	if view.OwnerID != userID {
+		if err := requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, view.ProjectID, enum.MemberOwner); err != nil {
+			return domain.View{}, err
+		}
+	}
+	return view, nil
+}
+
+// check validates the filter and sort of req, and that userID may save it: sharing a view
+// takes an editor of the project or organization it is shared with.
+func (s *ViewService) check(ctx context.Context, req dto.ViewReq, userID uint) error {
+	if req.Filter != "" {
+		expr, err := filter.Parse(req.Filter, filter.Env{UserID: userID, Now: time.Now()})
+		if err != nil {
+			return err
+		}
+		// Only a project's view can use the statuses of a workflow, checked as it is listed.
+		if req.ProjectID == nil {
+			if _, err := filter.BindStatuses(expr, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := filter.ParseSort(req.Sort); err != nil {
+		return err
+	}
+	need := enum.MemberViewer
+	if req.Shared {
+		need = enum.MemberEditor
+	}
+	return requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, req.ProjectID, need)
+}
+
+// viewRef is how pins refer to a view: by key for built-in views, by ID otherwise.
+func viewRef(view *domain.View, key string) string {
+	if key != "" {
+		return key
+	}
+	return strconv.FormatUint(uint64(view.ID), 10)
+}
+
+func viewToResp(view *domain.View, key string, pinned bool) *dto.ViewResp {
+	resp := &dto.ViewResp{
+		Key:       key,
+		Name:      view.Name,
+		Filter:    view.Filter,
+		Sort:      view.Sort,
+		Shared:    view.Shared || key != "",
+		ProjectID: view.ProjectID,
+		Builtin:   key != "",
+		Pinned:    pinned,
+	}
+	if key == "" {
+		resp.ID = view.ID
+		resp.OwnerID = view.OwnerID
+		resp.CreatedAt = &view.CreatedAt
+	}
+	return resp
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupViewTest() (*ViewService, *mockRepo.MockViewRepo, *mockRepo.MockTaskRepo, *mockRepo.MockOrgRepo) {
+	viewRepo := new(mockRepo.MockViewRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	return NewViewService(viewRepo, taskRepo, nil, orgRepo), viewRepo, taskRepo, orgRepo
+}
+
+func savedView(id, ownerID uint, shared bool) domain.View {
+	view := domain.View{OwnerID: ownerID, Name: "Mine", Filter: "assignee = me", Sort: "-updated", Shared: shared}
+	view.ID = id
+	return view
+}
+
+func TestCreateView(t *testing.T) {
+	svc, viewRepo, _, _ := setupViewTest()
+	viewRepo.On("Create", mock.Anything, mock.MatchedBy(func(view *domain.View) bool {
+		return view.OwnerID == 1 && view.Filter == "status = Started" && view.Sort == "due"
+	})).Return(uint(4), nil)
+
+	resp, err := svc.CreateView(context.Background(), dto.ViewReq{Name: "Started", Filter: "status = Started", Sort: "due"}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Started", resp.Name)
+	assert.False(t, resp.Builtin)
+	viewRepo.AssertExpectations(t)
+}
+
+func TestCreateView_InvalidFilter(t *testing.T) {
+	svc, viewRepo, _, _ := setupViewTest()
+
+	_, err := svc.CreateView(context.Background(), dto.ViewReq{Name: "Bad", Filter: "status = Begun"}, 1)
+	var filterErr *filter.Error
+	assert.ErrorAs(t, err, &filterErr)
+
+	_, err = svc.CreateView(context.Background(), dto.ViewReq{Name: "Bad", Sort: "assignee"}, 1)
+	assert.ErrorAs(t, err, &filterErr)
+	viewRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateView_SharingNeedsEditor(t *testing.T) {
+	svc, viewRepo, _, orgRepo := setupViewTest()
+	ctx := tenant.WithOrg(context.Background(), 7)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(2)).Return(domain.Membership{Role: enum.MemberViewer}, nil)
+	viewRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.View")).Return(uint(4), nil)
+
+	_, err := svc.CreateView(ctx, dto.ViewReq{Name: "Team", Shared: true}, 2)
+	assert.Equal(t, api_error.ErrForbidden, err)
+
+	_, err = svc.CreateView(ctx, dto.ViewReq{Name: "Private"}, 2)
+	assert.NoError(t, err)
+	viewRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestListViews_PinnedFirst(t *testing.T) {
+	svc, viewRepo, _, _ := setupViewTest()
+	viewRepo.On("ListPins", mock.Anything, uint(1)).Return([]string{"5", ViewOverdue}, nil)
+	viewRepo.On("ListVisible", mock.Anything, uint(1)).Return([]domain.View{savedView(4, 1, false), savedView(5, 2, true)}, nil)
+
+	resps, err := svc.ListViews(context.Background(), 1)
+
+	require.NoError(t, err)
+	var refs []string
+	for _, resp := range resps {
+		refs = append(refs, viewRef(&domain.View{Model: gorm.Model{ID: resp.ID}}, resp.Key))
+	}
+	assert.Equal(t, []string{"5", ViewOverdue, ViewMyOpenTasks, ViewRecentlyUpdated, "4"}, refs)
+	assert.True(t, resps[0].Pinned)
+	assert.True(t, resps[1].Builtin)
+	assert.False(t, resps[4].Pinned)
+}
+
+func TestGetView_HidesOthersPrivateViews(t *testing.T) {
+	svc, viewRepo, _, _ := setupViewTest()
+	viewRepo.On("GetByID", mock.Anything, uint(4)).Return(savedView(4, 2, false), nil)
+
+	_, err := svc.GetView(context.Background(), "4", 1)
+	assert.Equal(t, api_error.ErrViewNotFound, err)
+
+	_, err = svc.GetView(context.Background(), "no-such-view", 1)
+	assert.Equal(t, api_error.ErrViewNotFound, err)
+}
+
+func TestUpdateView_OnlyOwner(t *testing.T) {
+	svc, viewRepo, _, orgRepo := setupViewTest()
+	ctx := tenant.WithOrg(context.Background(), 7)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+	viewRepo.On("GetByID", mock.Anything, uint(5)).Return(savedView(5, 2, true), nil)
+
+	_, err := svc.UpdateView(ctx, "5", dto.ViewReq{Name: "Renamed"}, 1)
+	assert.Equal(t, api_error.ErrForbidden, err)
+
+	_, err = svc.UpdateView(ctx, ViewOverdue, dto.ViewReq{Name: "Renamed"}, 1)
+	assert.Equal(t, api_error.ErrBuiltinView, err)
+	viewRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeleteView_OrgOwnerMayDeleteShared(t *testing.T) {
+	svc, viewRepo, _, orgRepo := setupViewTest()
+	ctx := tenant.WithOrg(context.Background(), 7)
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberOwner}, nil)
+	viewRepo.On("GetByID", mock.Anything, uint(5)).Return(savedView(5, 2, true), nil)
+	viewRepo.On("DeleteByID", mock.Anything, uint(5)).Return(nil)
+
+	assert.NoError(t, svc.DeleteView(ctx, "5", 1))
+	viewRepo.AssertExpectations(t)
+}
+
+func TestPinView(t *testing.T) {
+	svc, viewRepo, _, _ := setupViewTest()
+	viewRepo.On("GetByID", mock.Anything, uint(5)).Return(savedView(5, 2, true), nil)
+	viewRepo.On("Pin", mock.Anything, uint(1), "5").Return(nil)
+	viewRepo.On("Pin", mock.Anything, uint(1), ViewMyOpenTasks).Return(nil)
+
+	assert.NoError(t, svc.PinView(context.Background(), "05", 1))
+	assert.NoError(t, svc.PinView(context.Background(), ViewMyOpenTasks, 1))
+	viewRepo.AssertExpectations(t)
+}
+
+func TestViewTasks_RunsForRequestingUser(t *testing.T) {
+	svc, viewRepo, taskRepo, _ := setupViewTest()
+	view := savedView(5, 2, true)
+	projectID := uint(3)
+	view.ProjectID = &projectID
+	viewRepo.On("GetByID", mock.Anything, uint(5)).Return(view, nil)
+	taskRepo.On("ListByFilter", mock.Anything, mock.MatchedBy(func(f dto.TaskListFilter) bool {
+		return f.ProjectID == 3 &&
+			f.Parsed.String() == "assignee = 1" &&
+			len(f.Orders) == 1 && f.Orders[0].String() == "-updated"
+	}), 20, 0).Return([]domain.Task{{Name: "Task"}}, int64(1), nil)
+
+	resp, err := svc.ViewTasks(context.Background(), "5", 1, 20, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Total)
+	assert.Equal(t, "Task", resp.Tasks[0].Name)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestBuiltinViewsParse(t *testing.T) {
+	for _, builtin := range builtinViews {
+		_, err := filter.Parse(builtin.view.Filter, filter.Env{UserID: 1})
+		assert.NoError(t, err, builtin.key)
+		_, err = filter.ParseSort(builtin.view.Sort)
+		assert.NoError(t, err, builtin.key)
+	}
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/cache"
+	"graph-interview/internal/repository/enum"
+	"graph-interview/internal/repository/tenant"
+	"graph-interview/pkg/logger"
+	redis_pkg "graph-interview/pkg/redis"
+	"graph-interview/pkg/safehttp"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const EventWebhookTest = "webhook.test"
+
+const (
+	defaultWebhookInterval     = 10 * time.Second
+	defaultWebhookLockTTL      = time.Minute
+	defaultWebhookBatchSize    = 100
+	defaultWebhookMaxAttempts  = 8
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookRetryBackoff = 30 * time.Second
+	defaultWebhookMaxBackoff   = 6 * time.Hour
+)
+
+// Headers sent with every webhook delivery. The signature is the hex HMAC-SHA256 of the
+// timestamp, a dot and the body, keyed with the webhook's secret.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// WebhookService manages the organization's webhooks and delivers task events to them.
+// Only organization owners may manage webhooks. Events are queued in the database and sent
+// by a background worker, retrying failures with exponential backoff. Webhooks can only
+// point at public addresses; Client refuses others and does not follow redirects.
+type WebhookService struct {
+	WebhookRepo repository.WebhookRepo
+	ProjectRepo repository.ProjectRepo
+	OrgRepo     repository.OrgRepo
+	Tx          repository.Transactor
+	Client      *http.Client
+	lock        *redis_pkg.Lock
+	cfg         cfg.WebhookCfg
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepo,
+	projectRepo repository.ProjectRepo,
+	orgRepo repository.OrgRepo,
+	tx repository.Transactor,
+	rdb *redis.Client,
+	webhookCfg cfg.WebhookCfg,
+) *WebhookService {
+	if webhookCfg.Interval <= 0 {
+		webhookCfg.Interval = defaultWebhookInterval
+	}
+	if webhookCfg.LockTTL <= 0 {
+		webhookCfg.LockTTL = defaultWebhookLockTTL
+	}
+	if webhookCfg.BatchSize <= 0 {
+		webhookCfg.BatchSize = defaultWebhookBatchSize
+	}
+	if webhookCfg.MaxAttempts <= 0 {
+		webhookCfg.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if webhookCfg.Timeout <= 0 {
+		webhookCfg.Timeout = defaultWebhookTimeout
+	}
+	if webhookCfg.RetryBackoff <= 0 {
+		webhookCfg.RetryBackoff = defaultWebhookRetryBackoff
+	}
+	if webhookCfg.MaxBackoff <= 0 {
+		webhookCfg.MaxBackoff = defaultWebhookMaxBackoff
+	}
+	return &WebhookService{
+		WebhookRepo: webhookRepo,
+		ProjectRepo: projectRepo,
+		OrgRepo:     orgRepo,
+		Tx:          tx,
+		Client:      safehttp.NewClient(webhookCfg.Timeout),
+		lock:        redis_pkg.NewLock(rdb, cache.WebhookLockKey, webhookCfg.LockTTL),
+		cfg:         webhookCfg,
+	}
+}
+
+func (s *WebhookService) CreateWebhook(ctx context.Context, req dto.CreateWebhookReq, userID uint) (*dto.WebhookResp, error) {
+	if err := s.authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := checkTargetURL(req.URL); err != nil {
+		return nil, err
+	}
+	secret := req.Secret
+	if secret == "" {
+		b := make([]byte, 32)
+		_, _ = rand.Read(b)
+		secret = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	webhook := &domain.Webhook{
+		CreatedByID: userID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      req.Events,
+	}
+	if _, err := s.WebhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	resp := webhookToResp(webhook)
+	resp.Secret = secret
+	return resp, nil
+}
+
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID uint) ([]dto.WebhookResp, error) {
+	if err := s.authorize(ctx, userID); err != nil {
+		return nil, err
+	}
+	webhooks, err := s.WebhookRepo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]dto.WebhookResp, len(webhooks))
+	for i := range webhooks {
+		resps[i] = *webhookToResp(&webhooks[i])
+	}
+	return resps, nil
+}
+
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhookID uint, req dto.UpdateWebhookReq, userID uint) (*dto.WebhookResp, error) {
+	webhook, err := s.get(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []string
+	if req.URL != nil {
+		if err := checkTargetURL(*req.URL); err != nil {
+			return nil, err
+		}
+		webhook.URL = *req.URL
+		fields = append(fields, "url")
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+		fields = append(fields, "secret")
+	}
+	if req.Events != nil {
+		webhook.Events = req.Events
+		fields = append(fields, "events")
+	}
+	if len(fields) > 0 {
+		if err := s.WebhookRepo.UpdateByID(ctx, &webhook, fields); err != nil {
+			return nil, err
+		}
+	}
+	return webhookToResp(&webhook), nil
+}
+
+// DeleteWebhook removes a webhook together with its delivery log and pending deliveries.
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID, userID uint) error {
+	if _, err := s.get(ctx, webhookID, userID); err != nil {
+		return err
+	}
+	return s.Tx.WithinTx(ctx, func(ctx context.Context) error {
+		return s.WebhookRepo.DeleteByID(ctx, webhookID)
+	})
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID, userID uint, limit, offset int) (*dto.WebhookDeliveryListResp, error) {
+	if _, err := s.get(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+	deliveries, total, err := s.WebhookRepo.ListDeliveries(ctx, webhookID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	resps := make([]dto.WebhookDeliveryResp, len(deliveries))
+	for i := range deliveries {
+		resps[i] = *deliveryToResp(&deliveries[i])
+	}
+	return &dto.WebhookDeliveryListResp{
+		Deliveries: resps,
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+	}, nil
+}
+
+// SendTest sends a webhook.test event to the webhook right away and returns the outcome.
+// A failed test is retried like any other delivery.
+func (s *WebhookService) SendTest(ctx context.Context, webhookID, userID uint) (*dto.WebhookDeliveryResp, error) {
+	webhook, err := s.get(ctx, webhookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.enqueue(ctx, &webhook, EventWebhookTest, map[string]string{"message": "This is a test event."})
+	if err != nil {
+		return nil, err
+	}
+	delivery.Webhook = &webhook
+	if err := s.attempt(ctx, delivery, time.Now()); err != nil {
+		return nil, err
+	}
+	return deliveryToResp(delivery), nil
+}
+
+// HandleEvent queues task events relayed from the outbox for the organization's webhooks.
+func (s *WebhookService) HandleEvent(ctx context.Context, event *domain.Event) error {
+	taskEvent, ok, err := taskEvent(event)
+	if !ok || err != nil {
+		return err
+	}
+	return s.Enqueue(ctx, taskEvent)
+}
+
+// Enqueue queues a task event for every webhook of the active organization subscribed to it.
+func (s *WebhookService) Enqueue(ctx context.Context, event dto.TaskEvent) error {
+	webhooks, err := s.WebhookRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event.Type) {
+			continue
+		}
+		if _, err := s.enqueue(ctx, &webhooks[i], event.Type, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) enqueue(ctx context.Context, webhook *domain.Webhook, eventType string, data any) (*domain.WebhookDelivery, error) {
+	now := time.Now()
+	eventID := uuid.NewString()
+	payload, err := json.Marshal(dto.WebhookPayload{
+		ID:             eventID,
+		Type:           eventType,
+		OrganizationID: webhook.OrganizationID,
+		CreatedAt:      now,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &domain.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     eventType,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+	}
+	if _, err := s.WebhookRepo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Run sends due deliveries every interval until ctx is done. API instances share a Redis
+// lock, so each delivery is attempted by one of them at a time.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.tick(ctx); err != nil {
+				logger.Logger.Warn("webhook worker failed", "err", err)
+			}
+		}
+	}
+}
+
+func (s *WebhookService) tick(ctx context.Context) error {
+	ok, err := s.lock.Acquire(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	defer func() {
+		_ = s.lock.Release(context.Background())
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.LockTTL/2)
+	defer cancel()
+	_, err = s.DeliverDue(ctx, time.Now())
+	return err
+}
+
+// DeliverDue attempts the deliveries due at now and returns how many succeeded.
+func (s *WebhookService) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	ctx = tenant.Unscoped(ctx)
+	deliveries, err := s.WebhookRepo.ListDueDeliveries(ctx, now, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := s.attempt(ctx, &deliveries[i], now); err != nil {
+			return delivered, err
+		}
+		if deliveries[i].DeliveredAt != nil {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// attempt posts a delivery once and records the outcome. Failures are scheduled for a retry
+// after an exponentially growing backoff, until MaxAttempts is reached.
+func (s *WebhookService) attempt(ctx context.Context, delivery *domain.WebhookDelivery, now time.Time) error {
+	status, err := s.post(ctx, delivery, now)
+	delivery.Attempts++
+	delivery.ResponseStatus = status
+	fields := []string{"attempts", "response_status", "last_error"}
+	if err == nil {
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		fields = append(fields, "delivered_at")
+	} else {
+		delivery.LastError = err.Error()
+		if delivery.Attempts >= s.cfg.MaxAttempts {
+			delivery.FailedAt = &now
+			fields = append(fields, "failed_at")
+		} else {
+			delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+			fields = append(fields, "next_attempt_at")
+		}
+		logger.Logger.Warn("webhook delivery failed", "delivery", delivery.ID, "webhook", delivery.WebhookID, "err", err)
+	}
+	// Record the outcome even when the batch ran out of time during the request.
+	return s.WebhookRepo.UpdateDelivery(context.WithoutCancel(ctx), delivery, fields)
+}
+
+// post sends a delivery to its webhook and returns the response status. Only the status is
+// kept of the response, so the delivery log cannot be used to read what a URL returns.
+func (s *WebhookService) post(ctx context.Context, delivery *domain.WebhookDelivery, now time.Time) (int, error) {
+	if delivery.Webhook == nil {
+		return 0, fmt.Errorf("webhook %d no longer exists", delivery.WebhookID)
+	}
+	body := []byte(delivery.Payload)
+	timestamp := now.Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookIDHeader, delivery.EventID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(delivery.Webhook.Secret, timestamp, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait before the retry following attempt number attempts.
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	wait := s.cfg.RetryBackoff
+	for i := 1; i < attempts && wait < s.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, s.cfg.MaxBackoff)
+}
+
+func (s *WebhookService) get(ctx context.Context, webhookID, userID uint) (domain.Webhook, error) {
+	if err := s.authorize(ctx, userID); err != nil {
+		return domain.Webhook{}, err
+	}
+	webhook, err := s.WebhookRepo.GetByID(ctx, webhookID)
+	if err != nil {
+		return domain.Webhook{}, api_error.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (s *WebhookService) authorize(ctx context.Context, userID uint) error {
+	return requireRole(ctx, s.OrgRepo, s.ProjectRepo, userID, nil, enum.MemberOwner)
+}
+
+// checkTargetURL rejects URLs outbound requests must not go to, such as loopback or
+// private addresses.
+func checkTargetURL(rawURL string) error {
+	if err := safehttp.CheckURL(rawURL); err != nil {
+		return fmt.Errorf("%w: %v", api_error.ErrForbiddenURL, err)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 signature receivers use to verify a delivery.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func webhookToResp(webhook *domain.Webhook) *dto.WebhookResp {
+	return &dto.WebhookResp{
+		ID:        webhook.ID,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func deliveryToResp(delivery *domain.WebhookDelivery) *dto.WebhookDeliveryResp {
+	resp := &dto.WebhookDeliveryResp{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		FailedAt:       delivery.FailedAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.DeliveredAt == nil && delivery.FailedAt == nil {
+		resp.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return resp
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/cfg"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"graph-interview/internal/repository/tenant"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupWebhookTest() (*WebhookService, *mockRepo.MockWebhookRepo, *mockRepo.MockOrgRepo) {
+	webhookRepo := new(mockRepo.MockWebhookRepo)
+	orgRepo := new(mockRepo.MockOrgRepo)
+	svc := NewWebhookService(webhookRepo, nil, orgRepo, mockRepo.NoopTransactor{}, nil, cfg.WebhookCfg{
+		MaxAttempts:  3,
+		RetryBackoff: time.Minute,
+		MaxBackoff:   3 * time.Minute,
+	})
+	return svc, webhookRepo, orgRepo
+}
+
+func pendingDelivery(url string) domain.WebhookDelivery {
+	webhook := &domain.Webhook{URL: url, Secret: "0123456789abcdef", Events: []string{domain.EventTaskCreated}}
+	webhook.ID = 2
+	delivery := domain.WebhookDelivery{
+		Webhook:   webhook,
+		WebhookID: 2,
+		EventID:   "evt-1",
+		EventType: domain.EventTaskCreated,
+		Payload:   `{"id":"evt-1","type":"task.created"}`,
+	}
+	delivery.ID = 5
+	return delivery
+}
+
+func TestDeliverDue_SignedDelivery(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	svc, webhookRepo, _ := setupWebhookTest()
+	svc.Client = receiver.Client()
+	now := time.Now()
+	webhookRepo.On("ListDueDeliveries", mock.Anything, now, 100).Return([]domain.WebhookDelivery{pendingDelivery(receiver.URL)}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.DeliveredAt != nil && d.Attempts == 1 && d.ResponseStatus == http.StatusNoContent
+	}), []string{"attempts", "response_status", "last_error", "delivered_at"}).Return(nil)
+
+	delivered, err := svc.DeliverDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, `{"id":"evt-1","type":"task.created"}`, string(body))
+	assert.Equal(t, domain.EventTaskCreated, got.Header.Get(WebhookEventHeader))
+	assert.Equal(t, "evt-1", got.Header.Get(WebhookIDHeader))
+	timestamp, _ := strconv.ParseInt(got.Header.Get(WebhookTimestampHeader), 10, 64)
+	assert.Equal(t, "sha256="+SignWebhook("0123456789abcdef", timestamp, body), got.Header.Get(WebhookSignatureHeader))
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_RetriesWithBackoff(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	svc, webhookRepo, _ := setupWebhookTest()
+	svc.Client = receiver.Client()
+	now := time.Now()
+	delivery := pendingDelivery(receiver.URL)
+	delivery.Attempts = 1
+	webhookRepo.On("ListDueDeliveries", mock.Anything, now, 100).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.Attempts == 2 && d.ResponseStatus == http.StatusServiceUnavailable &&
+			d.NextAttemptAt.Equal(now.Add(2*time.Minute)) && d.FailedAt == nil
+	}), []string{"attempts", "response_status", "last_error", "next_attempt_at"}).Return(nil)
+
+	delivered, err := svc.DeliverDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_GivesUpAfterMaxAttempts(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	svc, webhookRepo, _ := setupWebhookTest()
+	svc.Client = receiver.Client()
+	now := time.Now()
+	delivery := pendingDelivery(receiver.URL)
+	delivery.Attempts = 2
+	webhookRepo.On("ListDueDeliveries", mock.Anything, now, 100).Return([]domain.WebhookDelivery{delivery}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.Attempts == 3 && d.FailedAt != nil
+	}), []string{"attempts", "response_status", "last_error", "failed_at"}).Return(nil)
+
+	_, err := svc.DeliverDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_KeepsOnlyStatus(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "secret internal page", http.StatusForbidden)
+	}))
+	defer receiver.Close()
+
+	svc, webhookRepo, _ := setupWebhookTest()
+	svc.Client = receiver.Client()
+	now := time.Now()
+	webhookRepo.On("ListDueDeliveries", mock.Anything, now, 100).Return([]domain.WebhookDelivery{pendingDelivery(receiver.URL)}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.ResponseStatus == http.StatusForbidden && d.LastError == "webhook responded 403 Forbidden"
+	}), mock.Anything).Return(nil)
+
+	_, err := svc.DeliverDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestDeliverDue_RefusesPrivateAddresses(t *testing.T) {
+	called := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer receiver.Close()
+
+	svc, webhookRepo, _ := setupWebhookTest()
+	now := time.Now()
+	webhookRepo.On("ListDueDeliveries", mock.Anything, now, 100).Return([]domain.WebhookDelivery{pendingDelivery(receiver.URL)}, nil)
+	webhookRepo.On("UpdateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		return d.DeliveredAt == nil && d.ResponseStatus == 0 && strings.Contains(d.LastError, "not allowed")
+	}), mock.Anything).Return(nil)
+
+	delivered, err := svc.DeliverDue(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, delivered)
+	assert.False(t, called)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestCreateWebhook_PrivateURL(t *testing.T) {
+	svc, webhookRepo, _ := setupWebhookTest()
+
+	_, err := svc.CreateWebhook(context.Background(), dto.CreateWebhookReq{URL: "http://10.0.0.5/hook", Events: []string{domain.EventTaskCreated}}, 1)
+	assert.ErrorIs(t, err, api_error.ErrForbiddenURL)
+
+	webhook := domain.Webhook{URL: "https://hooks.example.com/task"}
+	webhook.ID = 2
+	webhookRepo.On("GetByID", mock.Anything, uint(2)).Return(webhook, nil)
+	url := "http://[::1]:9000/hook"
+	_, err = svc.UpdateWebhook(context.Background(), 2, dto.UpdateWebhookReq{URL: &url}, 1)
+	assert.ErrorIs(t, err, api_error.ErrForbiddenURL)
+	webhookRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	webhookRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	svc, _, _ := setupWebhookTest()
+
+	assert.Equal(t, time.Minute, svc.backoff(1))
+	assert.Equal(t, 2*time.Minute, svc.backoff(2))
+	assert.Equal(t, 3*time.Minute, svc.backoff(3))
+	assert.Equal(t, 3*time.Minute, svc.backoff(10))
+}
+
+func TestEnqueue_SubscribedWebhooksOnly(t *testing.T) {
+	svc, webhookRepo, _ := setupWebhookTest()
+
+	created := domain.Webhook{Events: []string{domain.EventTaskCreated}}
+	created.ID = 1
+	deleted := domain.Webhook{Events: []string{domain.EventTaskDeleted}}
+	deleted.ID = 2
+	webhookRepo.On("List", mock.Anything).Return([]domain.Webhook{created, deleted}, nil)
+	webhookRepo.On("CreateDelivery", mock.Anything, mock.MatchedBy(func(d *domain.WebhookDelivery) bool {
+		var payload struct {
+			Type string        `json:"type"`
+			Data dto.TaskEvent `json:"data"`
+		}
+		_ = json.Unmarshal([]byte(d.Payload), &payload)
+		return d.WebhookID == 1 && payload.Type == domain.EventTaskCreated && payload.Data.TaskID == 9
+	})).Return(uint(1), nil).Once()
+
+	err := svc.Enqueue(context.Background(), dto.TaskEvent{Type: domain.EventTaskCreated, TaskID: 9})
+
+	assert.NoError(t, err)
+	webhookRepo.AssertExpectations(t)
+}
+
+func TestCreateWebhook_OwnersOnly(t *testing.T) {
+	svc, webhookRepo, orgRepo := setupWebhookTest()
+	orgRepo.On("GetMembership", mock.Anything, uint(7), uint(1)).Return(domain.Membership{Role: enum.MemberEditor}, nil)
+
+	req := dto.CreateWebhookReq{URL: "https://example.com/hook", Events: []string{domain.EventTaskCreated}}
+	_, err := svc.CreateWebhook(tenant.WithOrg(context.Background(), 7), req, 1)
+
+	assert.Equal(t, api_error.ErrForbidden, err)
+	webhookRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateWebhook_GeneratesSecret(t *testing.T) {
+	svc, webhookRepo, _ := setupWebhookTest()
+	webhookRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Webhook")).Return(uint(1), nil)
+
+	req := dto.CreateWebhookReq{URL: "https://example.com/hook", Events: []string{domain.EventTaskCreated}}
+	resp, err := svc.CreateWebhook(context.Background(), req, 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Secret, 43)
+}
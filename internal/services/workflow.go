@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/repository"
+	"graph-interview/internal/repository/enum"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// WorkflowService manages the workflows of projects. Anyone who can see a project can read
+// its workflow; changing it takes the project's owner or an owner role.
+type WorkflowService struct {
+	WorkflowRepo repository.WorkflowRepo
+	TaskRepo     repository.TaskRepo
+	ProjectRepo  repository.ProjectRepo
+	OrgRepo      repository.OrgRepo
+}
+
+func NewWorkflowService(
+	workflowRepo repository.WorkflowRepo,
+	taskRepo repository.TaskRepo,
+	projectRepo repository.ProjectRepo,
+	orgRepo repository.OrgRepo,
+) *WorkflowService {
+	return &WorkflowService{
+		WorkflowRepo: workflowRepo,
+		TaskRepo:     taskRepo,
+		ProjectRepo:  projectRepo,
+		OrgRepo:      orgRepo,
+	}
+}
+
+func (s *WorkflowService) GetWorkflow(ctx context.Context, projectID, userID uint) (*dto.WorkflowResp, error) {
+	if _, err := requireProjectRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberViewer); err != nil {
+		return nil, err
+	}
+	workflow, err := projectWorkflow(ctx, s.WorkflowRepo, &projectID)
+	if err != nil {
+		return nil, err
+	}
+	return workflowToResp(workflow, projectID), nil
+}
+
+// UpdateWorkflow replaces the project's workflow. Statuses that tasks are still in must be
+// kept, under the same name and built-in status.
+func (s *WorkflowService) UpdateWorkflow(ctx context.Context, projectID uint, req dto.WorkflowReq, userID uint) (*dto.WorkflowResp, error) {
+	if _, err := requireProjectRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberOwner); err != nil {
+		return nil, err
+	}
+	workflow, err := buildWorkflow(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkInUse(ctx, projectID, workflow); err != nil {
+		return nil, err
+	}
+
+	workflow.ProjectID = projectID
+	if err := s.WorkflowRepo.Save(ctx, workflow); err != nil {
+		return nil, err
+	}
+	return workflowToResp(workflow, projectID), nil
+}
+
+// ResetWorkflow puts the project back on the default workflow.
+func (s *WorkflowService) ResetWorkflow(ctx context.Context, projectID, userID uint) (*dto.WorkflowResp, error) {
+	if _, err := requireProjectRole(ctx, s.OrgRepo, s.ProjectRepo, userID, projectID, enum.MemberOwner); err != nil {
+		return nil, err
+	}
+	workflow := domain.DefaultWorkflow()
+	if err := s.checkInUse(ctx, projectID, workflow); err != nil {
+		return nil, err
+	}
+	if err := s.WorkflowRepo.DeleteByProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	return workflowToResp(workflow, projectID), nil
+}
+
+// checkInUse makes sure workflow keeps every status the project's tasks are in.
+func (s *WorkflowService) checkInUse(ctx context.Context, projectID uint, workflow *domain.Workflow) error {
+	used, err := s.TaskRepo.ListStatusesInUse(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	var dropped []string
+	for _, status := range used {
+		if kept, ok := workflow.Find(status.Name); !ok || kept.Status != status.Status {
+			dropped = append(dropped, status.Name)
+		}
+	}
+	if len(dropped) > 0 {
+		return fmt.Errorf("%w: %s", api_error.ErrWorkflowStatusInUse, strings.Join(dropped, ", "))
+	}
+	return nil
+}
+
+// buildWorkflow validates req and turns it into a workflow. Status names must be unique
+// regardless of case, there must be a todo status for new tasks to start in, and
+// transitions may only name statuses of the workflow.
+func buildWorkflow(req dto.WorkflowReq) (*domain.Workflow, error) {
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: "+format, append([]any{api_error.ErrInvalidWorkflow}, args...)...)
+	}
+
+	workflow := &domain.Workflow{}
+	for _, s := range req.Statuses {
+		name := strings.TrimSpace(s.Name)
+		if name == "" {
+			return nil, invalid("status names cannot be blank")
+		}
+		if _, ok := workflow.Find(name); ok {
+			return nil, invalid("status %q is listed twice", name)
+		}
+		category, ok := enum.ParseStatusCategory(s.Category)
+		if !ok {
+			return nil, invalid("unknown category %q", s.Category)
+		}
+		status := categoryStatus[category]
+		if s.CountsAs != nil {
+			status = *s.CountsAs
+		}
+		if status.String() == "" || status.Category() != category {
+			return nil, invalid("status %q cannot count as %d, which is not a %s status", name, status, category)
+		}
+		workflow.Statuses = append(workflow.Statuses, domain.WorkflowStatus{Name: name, Category: category, Status: status})
+	}
+	if _, ok := workflow.Initial(); !ok {
+		return nil, invalid("a todo status is needed for new tasks")
+	}
+
+	for _, t := range req.Transitions {
+		from, ok := workflow.Find(strings.TrimSpace(t.From))
+		if !ok {
+			return nil, invalid("transition from unknown status %q", t.From)
+		}
+		to, ok := workflow.Find(strings.TrimSpace(t.To))
+		if !ok {
+			return nil, invalid("transition to unknown status %q", t.To)
+		}
+		workflow.Transitions = append(workflow.Transitions, domain.WorkflowTransition{From: from.Name, To: to.Name})
+	}
+	return workflow, nil
+}
+
+// categoryStatus is the built-in status a custom status counts as unless it says otherwise.
+var categoryStatus = map[enum.StatusCategory]enum.TaskStatus{
+	enum.CategoryTodo:       enum.Created,
+	enum.CategoryInProgress: enum.Started,
+	enum.CategoryDone:       enum.Done,
+}
+
+// projectWorkflow returns the workflow tasks in the project follow: its own, or the default
+// one when it has none, when projectID is nil or when workflows are not stored.
+func projectWorkflow(ctx context.Context, repo repository.WorkflowRepo, projectID *uint) (*domain.Workflow, error) {
+	if repo == nil || projectID == nil {
+		return domain.DefaultWorkflow(), nil
+	}
+	workflow, err := repo.GetByProject(ctx, *projectID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.DefaultWorkflow(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// applyStatus puts task in the workflow status and returns the fields that changed.
+func applyStatus(task *domain.Task, status domain.WorkflowStatus) []string {
+	var fields []string
+	if status.Status != task.Status {
+		task.Status = status.Status
+		fields = append(fields, "status")
+	}
+	custom := status.Name
+	if custom == status.Status.String() {
+		custom = ""
+	}
+	if custom != task.CustomStatus {
+		task.CustomStatus = custom
+		fields = append(fields, "custom_status")
+	}
+	return fields
+}
+
+func workflowToResp(workflow *domain.Workflow, projectID uint) *dto.WorkflowResp {
+	resp := &dto.WorkflowResp{
+		ProjectID:   projectID,
+		Default:     workflow.ProjectID == 0,
+		Statuses:    make([]dto.WorkflowStatusResp, len(workflow.Statuses)),
+		Transitions: make([]dto.WorkflowTransitionResp, len(workflow.Transitions)),
+	}
+	for i, s := range workflow.Statuses {
+		resp.Statuses[i] = dto.WorkflowStatusResp{Name: s.Name, Category: s.Category.String(), CountsAs: s.Status.String()}
+	}
+	for i, t := range workflow.Transitions {
+		resp.Transitions[i] = dto.WorkflowTransitionResp{From: t.From, To: t.To}
+	}
+	return resp
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"graph-interview/internal/api/handlers/dto"
+	api_error "graph-interview/internal/api/handlers/errors"
+	"graph-interview/internal/domain"
+	"graph-interview/internal/filter"
+	"graph-interview/internal/repository/enum"
+	mockRepo "graph-interview/internal/repository/mock"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupWorkflowTest() (*WorkflowService, *mockRepo.MockWorkflowRepo, *mockRepo.MockTaskRepo, *mockRepo.MockProjectRepo) {
+	workflowRepo := new(mockRepo.MockWorkflowRepo)
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	project := domain.Project{Name: "Project", OwnerID: 1}
+	project.ID = 3
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(project, nil)
+	return NewWorkflowService(workflowRepo, taskRepo, projectRepo, nil), workflowRepo, taskRepo, projectRepo
+}
+
+// reviewWorkflow is Backlog -> In Review -> Shipped, with Backlog counting as Created, In
+// Review as Started and Shipped as Done.
+func reviewWorkflow() domain.Workflow {
+	return domain.Workflow{
+		ProjectID: 3,
+		Statuses: []domain.WorkflowStatus{
+			{Name: "Backlog", Category: enum.CategoryTodo, Status: enum.Created},
+			{Name: "In Review", Category: enum.CategoryInProgress, Status: enum.Started},
+			{Name: "Shipped", Category: enum.CategoryDone, Status: enum.Done},
+			{Name: "Canceled", Category: enum.CategoryDone, Status: enum.Canceled},
+		},
+		Transitions: []domain.WorkflowTransition{
+			{From: "Backlog", To: "In Review"},
+			{From: "In Review", To: "Shipped"},
+			{From: "Backlog", To: "Canceled"},
+			{From: "In Review", To: "Canceled"},
+		},
+	}
+}
+
+func TestGetWorkflow_Default(t *testing.T) {
+	svc, workflowRepo, _, _ := setupWorkflowTest()
+	workflowRepo.On("GetByProject", mock.Anything, uint(3)).Return(domain.Workflow{}, gorm.ErrRecordNotFound)
+
+	resp, err := svc.GetWorkflow(context.Background(), 3, 2)
+
+	require.NoError(t, err)
+	assert.True(t, resp.Default)
+	require.Len(t, resp.Statuses, 6)
+	assert.Equal(t, dto.WorkflowStatusResp{Name: "Delayed", Category: "in_progress", CountsAs: "Delayed"}, resp.Statuses[2])
+	assert.Empty(t, resp.Transitions)
+}
+
+func TestUpdateWorkflow(t *testing.T) {
+	svc, workflowRepo, taskRepo, _ := setupWorkflowTest()
+	taskRepo.On("ListStatusesInUse", mock.Anything, uint(3)).Return([]domain.WorkflowStatus{{Name: "Created", Status: enum.Created}}, nil)
+	workflowRepo.On("Save", mock.Anything, mock.MatchedBy(func(w *domain.Workflow) bool {
+		return w.ProjectID == 3 && len(w.Statuses) == 3 && w.Statuses[1].Status == enum.Delayed
+	})).Return(nil)
+
+	resp, err := svc.UpdateWorkflow(context.Background(), 3, dto.WorkflowReq{
+		Statuses: []dto.WorkflowStatusReq{
+			{Name: "created", Category: "todo"},
+			{Name: "Blocked", Category: "in_progress", CountsAs: ptr(enum.Delayed)},
+			{Name: "Shipped", Category: "done"},
+		},
+		Transitions: []dto.WorkflowTransitionReq{{From: "CREATED", To: "blocked"}},
+	}, 1)
+
+	require.NoError(t, err)
+	assert.False(t, resp.Default)
+	assert.Equal(t, "Done", resp.Statuses[2].CountsAs)
+	assert.Equal(t, []dto.WorkflowTransitionResp{{From: "created", To: "Blocked"}}, resp.Transitions)
+	workflowRepo.AssertExpectations(t)
+}
+
+func TestUpdateWorkflow_Invalid(t *testing.T) {
+	svc, workflowRepo, _, _ := setupWorkflowTest()
+
+	tests := map[string]dto.WorkflowReq{
+		"duplicate":       {Statuses: []dto.WorkflowStatusReq{{Name: "Open", Category: "todo"}, {Name: "open", Category: "done"}}},
+		"blank":           {Statuses: []dto.WorkflowStatusReq{{Name: " ", Category: "todo"}}},
+		"no todo":         {Statuses: []dto.WorkflowStatusReq{{Name: "Doing", Category: "in_progress"}}},
+		"wrong counts as": {Statuses: []dto.WorkflowStatusReq{{Name: "Open", Category: "todo", CountsAs: ptr(enum.Done)}}},
+		"unknown target": {
+			Statuses:    []dto.WorkflowStatusReq{{Name: "Open", Category: "todo"}},
+			Transitions: []dto.WorkflowTransitionReq{{From: "Open", To: "Closed"}},
+		},
+	}
+	for name, req := range tests {
+		_, err := svc.UpdateWorkflow(context.Background(), 3, req, 1)
+		assert.ErrorIs(t, err, api_error.ErrInvalidWorkflow, name)
+	}
+	workflowRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestUpdateWorkflow_StatusInUse(t *testing.T) {
+	svc, workflowRepo, taskRepo, _ := setupWorkflowTest()
+	taskRepo.On("ListStatusesInUse", mock.Anything, uint(3)).Return([]domain.WorkflowStatus{
+		{Name: "Created", Status: enum.Created},
+		{Name: "Started", Status: enum.Started},
+	}, nil)
+
+	// Started is dropped; Created is kept but no longer counts as Created.
+	_, err := svc.UpdateWorkflow(context.Background(), 3, dto.WorkflowReq{Statuses: []dto.WorkflowStatusReq{
+		{Name: "Open", Category: "todo"},
+		{Name: "Created", Category: "in_progress"},
+	}}, 1)
+
+	assert.ErrorIs(t, err, api_error.ErrWorkflowStatusInUse)
+	assert.ErrorContains(t, err, "Created, Started")
+	workflowRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestResetWorkflow(t *testing.T) {
+	svc, workflowRepo, taskRepo, _ := setupWorkflowTest()
+	taskRepo.On("ListStatusesInUse", mock.Anything, uint(3)).Return([]domain.WorkflowStatus{{Name: "In Review", Status: enum.Started}}, nil).Once()
+
+	_, err := svc.ResetWorkflow(context.Background(), 3, 1)
+	assert.ErrorIs(t, err, api_error.ErrWorkflowStatusInUse)
+	workflowRepo.AssertNotCalled(t, "DeleteByProject", mock.Anything, mock.Anything)
+
+	taskRepo.On("ListStatusesInUse", mock.Anything, uint(3)).Return([]domain.WorkflowStatus{{Name: "Started", Status: enum.Started}}, nil)
+	workflowRepo.On("DeleteByProject", mock.Anything, uint(3)).Return(nil)
+	resp, err := svc.ResetWorkflow(context.Background(), 3, 1)
+	require.NoError(t, err)
+	assert.True(t, resp.Default)
+	workflowRepo.AssertExpectations(t)
+}
+
+func TestUpdateWorkflow_ProjectNotFound(t *testing.T) {
+	svc, _, _, projectRepo := setupWorkflowTest()
+	projectRepo.On("GetByID", mock.Anything, uint(9)).Return(domain.Project{}, gorm.ErrRecordNotFound)
+
+	_, err := svc.UpdateWorkflow(context.Background(), 9, dto.WorkflowReq{}, 1)
+	assert.Equal(t, api_error.ErrProjectNotFound, err)
+}
+
+func setupWorkflowTaskTest(task domain.Task) (*TaskService, *mockRepo.MockTaskRepo) {
+	taskRepo := new(mockRepo.MockTaskRepo)
+	projectRepo := new(mockRepo.MockProjectRepo)
+	workflowRepo := new(mockRepo.MockWorkflowRepo)
+	projectRepo.On("GetByID", mock.Anything, uint(3)).Return(domain.Project{Name: "Project"}, nil)
+	workflowRepo.On("GetByProject", mock.Anything, uint(3)).Return(reviewWorkflow(), nil)
+	workflowRepo.On("GetByProject", mock.Anything, uint(4)).Return(domain.Workflow{}, gorm.ErrRecordNotFound)
+	if task.ID != 0 {
+		taskRepo.On("GetByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	return NewTaskService(taskRepo, projectRepo, nil, mockRepo.NoopTransactor{}, nil, workflowRepo, nil), taskRepo
+}
+
+func reviewTask(custom string, status enum.TaskStatus) domain.Task {
+	task := domain.Task{Name: "Task", Status: status, CustomStatus: custom, ProjectID: ptr(uint(3)), Version: 1}
+	task.ID = 1
+	return task
+}
+
+func TestCreateTask_StartsInWorkflowInitialStatus(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(domain.Task{})
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{ProjectID: 3, Status: enum.Created, CustomStatus: "Backlog"}).Return("", nil)
+	taskRepo.On("Create", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == enum.Created && task.CustomStatus == "Backlog"
+	})).Return(uint(1), nil)
+
+	resp, err := svc.CreateTask(context.Background(), dto.CreateTaskReq{Name: "Task", ProjectID: ptr(uint(3))}, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Created", resp.Status)
+	assert.Equal(t, "Backlog", resp.StatusName)
+	assert.Equal(t, "todo", resp.Category)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_ByStatusName(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("Backlog", enum.Created))
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == enum.Started && task.CustomStatus == "In Review"
+	}), []string{"updated_by_user_id", "status", "custom_status"}).Return(true, nil)
+
+	resp, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", Status: enum.Created, StatusName: "in review"}, 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "In Review", resp.StatusName)
+	assert.Equal(t, "in_progress", resp.Category)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_ByBuiltinStatus(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("In Review", enum.Started))
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == enum.Done && task.CustomStatus == "Shipped"
+	}), []string{"updated_by_user_id", "status", "custom_status"}).Return(true, nil)
+
+	// The current name alongside a new built-in status moves to the first status counting as it.
+	resp, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", Status: enum.Done, StatusName: "In Review"}, 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Shipped", resp.StatusName)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestUpdateTask_WorkflowRejects(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("Backlog", enum.Created))
+
+	_, err := svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", StatusName: "Shipped"}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrTransitionDenied)
+
+	_, err = svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", StatusName: "Done"}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrUnknownStatus)
+
+	_, err = svc.UpdateTask(context.Background(), 1, dto.UpdateTaskReq{Name: "Task", Status: enum.Failed}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrUnknownStatus)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestArchiveTask_UsesWorkflowStatus(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("Shipped", enum.Done))
+
+	_, err := svc.ArchiveTask(context.Background(), 1, 1)
+	assert.ErrorIs(t, err, api_error.ErrTransitionDenied)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMoveTask_RemapsStatus(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("In Review", enum.Started))
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return *task.ProjectID == 4 && task.Status == enum.Started && task.CustomStatus == ""
+	}), []string{"project_id", "updated_by_user_id", "custom_status"}).Return(true, nil)
+	svc.ProjectRepo.(*mockRepo.MockProjectRepo).On("GetByID", mock.Anything, uint(4)).Return(domain.Project{Name: "Other"}, nil)
+
+	resp, err := svc.MoveTask(context.Background(), 1, ptr(uint(4)), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Started", resp.StatusName)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestBoard_WorkflowColumns(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(domain.Task{})
+	onColumn := func(name string, status enum.TaskStatus) any {
+		return mock.MatchedBy(func(f dto.TaskListFilter) bool {
+			cmp, ok := f.Parsed.(filter.Cmp)
+			return ok && *f.Status == status && assert.ObjectsAreEqual([]any{filter.WorkflowStatus{Name: name, Status: status}}, cmp.Values)
+		})
+	}
+	task := reviewTask("In Review", enum.Started)
+	taskRepo.On("ListByFilter", mock.Anything, onColumn("In Review", enum.Started), 10, 0).Return([]domain.Task{task}, int64(1), nil)
+	for _, status := range []domain.WorkflowStatus{reviewWorkflow().Statuses[0], reviewWorkflow().Statuses[2], reviewWorkflow().Statuses[3]} {
+		taskRepo.On("ListByFilter", mock.Anything, onColumn(status.Name, status.Status), 10, 0).Return([]domain.Task{}, int64(0), nil)
+	}
+
+	resp, err := svc.Board(context.Background(), dto.TaskListFilter{ProjectID: 3}, 10)
+
+	require.NoError(t, err)
+	var names []string
+	for _, column := range resp.Columns {
+		names = append(names, column.StatusName)
+	}
+	assert.Equal(t, []string{"Backlog", "In Review", "Shipped", "Canceled"}, names)
+	assert.Equal(t, "Started", resp.Columns[1].Status)
+	assert.Equal(t, int64(1), resp.Columns[1].Total)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestBoard_WorkflowNarrowedByStatus(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(domain.Task{})
+	taskRepo.On("ListByFilter", mock.Anything, mock.AnythingOfType("dto.TaskListFilter"), 20, 0).Return([]domain.Task{}, int64(0), nil)
+
+	resp, err := svc.Board(context.Background(), dto.TaskListFilter{ProjectID: 3, Status: ptr(enum.Done)}, 20)
+
+	require.NoError(t, err)
+	require.Len(t, resp.Columns, 1)
+	assert.Equal(t, "Shipped", resp.Columns[0].StatusName)
+}
+
+func TestRepositionTask_ByStatusName(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("Backlog", enum.Created))
+	neighbor := reviewTask("In Review", enum.Started)
+	neighbor.ID, neighbor.Position = 2, "i"
+	taskRepo.On("GetByID", mock.Anything, uint(2)).Return(neighbor, nil)
+	taskRepo.On("PositionAfter", mock.Anything, domain.Column{ProjectID: 3, Status: enum.Started, CustomStatus: "In Review"}, "i", uint(1)).Return("", nil)
+	taskRepo.On("UpdateByID", mock.Anything, mock.MatchedBy(func(task *domain.Task) bool {
+		return task.Status == enum.Started && task.CustomStatus == "In Review" && task.Position > "i"
+	}), []string{"updated_by_user_id", "status", "custom_status", "position"}).Return(true, nil)
+
+	resp, err := svc.RepositionTask(context.Background(), 1, dto.RepositionTaskReq{StatusName: "in review", AfterID: ptr(uint(2))}, 1, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, "In Review", resp.StatusName)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestRepositionTask_ByStatusNameRejects(t *testing.T) {
+	svc, taskRepo := setupWorkflowTaskTest(reviewTask("Backlog", enum.Created))
+	other := reviewTask("Backlog", enum.Created)
+	other.ID = 2
+	taskRepo.On("GetByID", mock.Anything, uint(2)).Return(other, nil)
+	taskRepo.On("LastPosition", mock.Anything, domain.Column{ProjectID: 3, Status: enum.Done, CustomStatus: "Shipped"}).Return("", nil)
+
+	_, err := svc.RepositionTask(context.Background(), 1, dto.RepositionTaskReq{StatusName: "Shipped"}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrTransitionDenied)
+
+	_, err = svc.RepositionTask(context.Background(), 1, dto.RepositionTaskReq{StatusName: "Review"}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrUnknownStatus)
+
+	// The neighbor has to be in the named column.
+	_, err = svc.RepositionTask(context.Background(), 1, dto.RepositionTaskReq{StatusName: "In Review", AfterID: ptr(uint(2))}, 1, 0)
+	assert.ErrorIs(t, err, api_error.ErrBoardConflict)
+	taskRepo.AssertNotCalled(t, "UpdateByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatusChange_BetweenCustomStatuses(t *testing.T) {
+	task := reviewTask("QA", enum.Started)
+
+	assert.Equal(t, "In Review", statusChange(&task, "In Review").PreviousStatus)
+	assert.Empty(t, statusChange(&task, "QA").PreviousStatus)
+}
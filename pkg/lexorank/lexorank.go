@@ -0,0 +1,110 @@
+// Package lexorank generates string keys for manually ordered lists. Keys compare byte by
+// byte, so a database can sort by them directly (with the "C" collation), and a key can
+// always be found between any two others without renumbering the rest of the list.
+//
+// A key is read as a base-36 fraction between 0 and 1: "h" is about one half and "8zk"
+// comes between "8z" and "9". Keys never end in '0', which would make two spellings of the
+// same value.
+package lexorank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+var ErrInvalidKey = errors.New("lexorank: invalid key")
+
+// Between returns a key ordered after a and before b. An empty a stands for the start of
+// the list and an empty b for its end, so Between("", "") gives the first key of an empty
+// list and Between(last, "") appends to one.
+func Between(a, b string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+	if err := validate(b); err != nil {
+		return "", err
+	}
+	if b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q is not before %q", ErrInvalidKey, a, b)
+	}
+	return midpoint(a, b), nil
+}
+
+// Spread returns n keys spaced evenly over the whole range, for renumbering a list whose
+// keys have grown long or were never set.
+func Spread(n int) []string {
+	width, room := 1, len(digits)
+	for room <= n {
+		width++
+		room *= len(digits)
+	}
+	keys := make([]string, n)
+	for i := range keys {
+		v := (i + 1) * room / (n + 1)
+		key := make([]byte, width)
+		for j := width - 1; j >= 0; j-- {
+			key[j] = digits[v%len(digits)]
+			v /= len(digits)
+		}
+		keys[i] = strings.TrimRight(string(key), "0")
+	}
+	return keys
+}
+
+func validate(key string) error {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+		}
+	}
+	if strings.HasSuffix(key, "0") {
+		return fmt.Errorf("%w: %q ends in 0", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// midpoint returns a key between a and b, where a < b and an empty b stands for 1.
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the prefix the two share; a is padded with zeros where it is shorter.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	lo := strings.IndexByte(digits, digitAt(a, 0))
+	hi := len(digits)
+	if b != "" {
+		hi = strings.IndexByte(digits, b[0])
+	}
+	if hi-lo > 1 {
+		return string(digits[(lo+hi+1)/2])
+	}
+	// The first digits are adjacent. A longer b lets its first digit alone fit between;
+	// otherwise keep a's first digit and find room after the rest of a.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(digits[lo]) + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return '0'
+}
+
+func suffix(key string, i int) string {
+	if i < len(key) {
+		return key[i:]
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package lexorank
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "i"},
+		{"i", "", "r"},
+		{"z", "", "zi"},
+		{"", "1", "0i"},
+		{"", "01", "00i"},
+		{"a", "c", "b"},
+		{"a", "b", "ai"},
+		{"a", "b5", "b"},
+		{"az", "b", "azi"},
+		{"a1", "a2", "a1i"},
+	}
+	for _, tt := range tests {
+		got, err := Between(tt.a, tt.b)
+		require.NoError(t, err, "%q %q", tt.a, tt.b)
+		assert.Equal(t, tt.want, got, "%q %q", tt.a, tt.b)
+	}
+}
+
+func TestBetween_Errors(t *testing.T) {
+	for _, keys := range [][2]string{
+		{"b", "a"},
+		{"a", "a"},
+		{"a0", ""},
+		{"", "A"},
+		{"a-", "b"},
+	} {
+		_, err := Between(keys[0], keys[1])
+		assert.ErrorIs(t, err, ErrInvalidKey, "%q %q", keys[0], keys[1])
+	}
+}
+
+func TestBetween_KeepsOrder(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	keys := []string{}
+	for range 2000 {
+		i := rnd.Intn(len(keys) + 1)
+		var a, b string
+		if i > 0 {
+			a = keys[i-1]
+		}
+		if i < len(keys) {
+			b = keys[i]
+		}
+		key, err := Between(a, b)
+		require.NoError(t, err)
+		require.True(t, a < key && (b == "" || key < b), "%q not between %q and %q", key, a, b)
+		keys = append(keys[:i], append([]string{key}, keys[i:]...)...)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	assert.Equal(t, []string{"c", "o"}, Spread(2))
+	assert.Empty(t, Spread(0))
+
+	keys := Spread(100)
+	assert.Len(t, keys[0], 2)
+	for i := 1; i < len(keys); i++ {
+		assert.Less(t, keys[i-1], keys[i])
+		assert.NoError(t, validate(keys[i]))
+	}
+}
@@ -0,0 +1,74 @@
+// Package mailer delivers transactional email.
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends a single plain-text message.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP delivers mail through an SMTP relay, authenticating with PLAIN when a username is set.
+type SMTP struct {
+	cfg Config
+}
+
+func NewSMTP(cfg Config) *SMTP {
+	return &SMTP{cfg: cfg}
+}
+
+func (m *SMTP) Send(_ context.Context, msg Message) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	return smtp.SendMail(addr, auth, m.cfg.From, []string{msg.To}, []byte(b.String()))
+}
+
+// Log records messages on a logger instead of sending them. It is meant for development
+// setups without a mail relay. Bodies carry invitation and password reset tokens, so only
+// the recipient and subject are logged.
+type Log struct {
+	logger *slog.Logger
+}
+
+func NewLog(logger *slog.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (m *Log) Send(_ context.Context, msg Message) error {
+	m.logger.Info("mail not sent, no smtp relay configured", "to", msg.To, "subject", msg.Subject)
+	return nil
+}
@@ -0,0 +1,50 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock key only while it still holds our token, so a lease that
+// expired and was taken over by another process is left alone.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock is a lease on a key shared by every process using the same Redis instance. The
+// lease ends on Release or after its TTL, whichever comes first.
+type Lock struct {
+	client *redis.Client
+	key    string
+	token  string
+	ttl    time.Duration
+}
+
+func NewLock(client *redis.Client, key string, ttl time.Duration) *Lock {
+	return &Lock{
+		client: client,
+		key:    key,
+		token:  uuid.NewString(),
+		ttl:    ttl,
+	}
+}
+
+// Acquire takes the lease and reports whether it got it. It does not wait for a holder.
+func (l *Lock) Acquire(ctx context.Context) (bool, error) {
+	err := l.client.SetArgs(ctx, l.key, l.token, redis.SetArgs{Mode: "NX", TTL: l.ttl}).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *Lock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err()
+}
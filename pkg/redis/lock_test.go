@@ -0,0 +1,54 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLock_Exclusive(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+
+	a := NewLock(client, "lock:test", time.Minute)
+	b := NewLock(client, "lock:test", time.Minute)
+
+	ok, err := a.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = b.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Releasing a lease held by someone else does nothing.
+	assert.NoError(t, b.Release(ctx))
+	assert.True(t, mr.Exists("lock:test"))
+
+	assert.NoError(t, a.Release(ctx))
+	ok, err = b.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestLock_Expires(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	ctx := context.Background()
+
+	a := NewLock(client, "lock:test", time.Second)
+	b := NewLock(client, "lock:test", time.Second)
+
+	ok, _ := a.Acquire(ctx)
+	assert.True(t, ok)
+
+	mr.FastForward(2 * time.Second)
+	ok, err := b.Acquire(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,220 @@
+// Package rrule implements the subset of RFC 5545 recurrence rules used for recurring
+// tasks: FREQ=DAILY, WEEKLY or MONTHLY with INTERVAL, BYDAY, UNTIL and COUNT.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// maxSteps bounds the search for the next occurrence so a rule that can never match
+// cannot loop forever.
+const maxSteps = 1000
+
+var ErrNoOccurrence = errors.New("rrule: no further occurrence")
+
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Until    *time.Time
+	// Count is the total number of occurrences in the series, zero for unbounded.
+	Count int
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE". An optional "RRULE:"
+// prefix is accepted. BYDAY is only supported with DAILY and WEEKLY.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("rrule: empty rule")
+	}
+
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("rrule: malformed part %q", part)
+		}
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			switch f := Frequency(strings.ToUpper(value)); f {
+			case Daily, Weekly, Monthly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("rrule: unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rrule: invalid INTERVAL %q", value)
+			}
+			r.Interval = n
+		case "BYDAY":
+			for _, code := range strings.Split(strings.ToUpper(value), ",") {
+				day, ok := weekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("rrule: unsupported BYDAY %q", code)
+				}
+				if !slices.Contains(r.ByDay, day) {
+					r.ByDay = append(r.ByDay, day)
+				}
+			}
+			slices.SortFunc(r.ByDay, func(a, b time.Weekday) int { return mondayIndex(a) - mondayIndex(b) })
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("rrule: invalid COUNT %q", value)
+			}
+			r.Count = n
+		default:
+			return nil, fmt.Errorf("rrule: unsupported part %q", key)
+		}
+	}
+
+	switch {
+	case r.Freq == "":
+		return nil, errors.New("rrule: FREQ is required")
+	case r.Until != nil && r.Count != 0:
+		return nil, errors.New("rrule: UNTIL and COUNT are mutually exclusive")
+	case r.Freq == Monthly && len(r.ByDay) > 0:
+		return nil, errors.New("rrule: BYDAY is not supported with FREQ=MONTHLY")
+	}
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("20060102", value); err == nil {
+		// A date-only UNTIL includes the whole day.
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("rrule: invalid UNTIL %q", value)
+}
+
+// String formats the rule in canonical form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = weekdayCodes[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following prev, which is occurrence number n of the series
+// (starting at 1). Times keep prev's clock time and location. ErrNoOccurrence is returned
+// once COUNT or UNTIL ends the series.
+func (r *Rule) Next(prev time.Time, n int) (time.Time, error) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, ErrNoOccurrence
+	}
+
+	var next time.Time
+	var err error
+	switch r.Freq {
+	case Daily:
+		next, err = r.nextDaily(prev)
+	case Weekly:
+		next, err = r.nextWeekly(prev)
+	case Monthly:
+		next, err = r.nextMonthly(prev)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, ErrNoOccurrence
+	}
+	return next, nil
+}
+
+func (r *Rule) nextDaily(prev time.Time) (time.Time, error) {
+	for step := 1; step <= maxSteps; step++ {
+		next := prev.AddDate(0, 0, step*r.Interval)
+		if r.matchesDay(next) {
+			return next, nil
+		}
+	}
+	return time.Time{}, ErrNoOccurrence
+}
+
+func (r *Rule) nextWeekly(prev time.Time) (time.Time, error) {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval), nil
+	}
+	// Later days of the same week come first, then the first listed day of the next
+	// active week. Weeks start on Monday.
+	for _, day := range r.ByDay {
+		if offset := mondayIndex(day) - mondayIndex(prev.Weekday()); offset > 0 {
+			return prev.AddDate(0, 0, offset), nil
+		}
+	}
+	weekStart := prev.AddDate(0, 0, -mondayIndex(prev.Weekday()))
+	return weekStart.AddDate(0, 0, 7*r.Interval+mondayIndex(r.ByDay[0])), nil
+}
+
+// nextMonthly keeps the day of the month, skipping months that do not have it.
+func (r *Rule) nextMonthly(prev time.Time) (time.Time, error) {
+	year, month, day := prev.Date()
+	for step := 1; step <= maxSteps; step++ {
+		next := time.Date(year, month+time.Month(step*r.Interval), day,
+			prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+		if next.Day() == day {
+			return next, nil
+		}
+	}
+	return time.Time{}, ErrNoOccurrence
+}
+
+func (r *Rule) matchesDay(t time.Time) bool {
+	return len(r.ByDay) == 0 || slices.Contains(r.ByDay, t.Weekday())
+}
+
+func mondayIndex(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
@@ -0,0 +1,107 @@
+package rrule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 9, 30, 0, 0, time.UTC)
+}
+
+func TestParse_Canonical(t *testing.T) {
+	r, err := Parse("RRULE:freq=weekly;byday=we,mo;interval=2;count=5")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=5", r.String())
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;BYDAY=XX",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260101",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=DAILY;BYMONTH=1",
+		"FREQ",
+	} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestNext_Daily(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY;INTERVAL=3")
+
+	next, err := r.Next(date(2026, 1, 30), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 2, 2), next)
+}
+
+func TestNext_DailyWeekdays(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR")
+
+	// Friday 2026-01-09 is followed by Monday 2026-01-12.
+	next, err := r.Next(date(2026, 1, 9), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 1, 12), next)
+}
+
+func TestNext_WeeklyByDay(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE")
+
+	// Monday -> Wednesday of the same week -> Monday two weeks later.
+	next, err := r.Next(date(2026, 1, 5), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 1, 7), next)
+
+	next, err = r.Next(next, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 1, 19), next)
+}
+
+func TestNext_WeeklyPlain(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY")
+
+	next, err := r.Next(date(2026, 1, 8), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 1, 15), next)
+}
+
+func TestNext_MonthlySkipsShortMonths(t *testing.T) {
+	r, _ := Parse("FREQ=MONTHLY")
+
+	next, err := r.Next(date(2026, 1, 31), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 3, 31), next)
+}
+
+func TestNext_Count(t *testing.T) {
+	r, _ := Parse("FREQ=DAILY;COUNT=2")
+
+	_, err := r.Next(date(2026, 1, 1), 1)
+	assert.NoError(t, err)
+
+	_, err = r.Next(date(2026, 1, 2), 2)
+	assert.ErrorIs(t, err, ErrNoOccurrence)
+}
+
+func TestNext_Until(t *testing.T) {
+	r, _ := Parse("FREQ=WEEKLY;UNTIL=20260110")
+
+	next, err := r.Next(date(2026, 1, 1), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, date(2026, 1, 8), next)
+
+	_, err = r.Next(next, 2)
+	assert.ErrorIs(t, err, ErrNoOccurrence)
+}
@@ -0,0 +1,71 @@
+package safehttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowed(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":       true,
+		"2606:2800:220:1::1":  true,
+		"127.0.0.1":           false,
+		"::1":                 false,
+		"10.1.2.3":            false,
+		"172.16.0.1":          false,
+		"192.168.1.1":         false,
+		"169.254.169.254":     false,
+		"fe80::1":             false,
+		"fd00::1":             false,
+		"0.0.0.0":             false,
+		"::":                  false,
+		"100.100.100.200":     false,
+		"224.0.0.1":           false,
+		"::ffff:127.0.0.1":    false,
+		"::ffff:93.184.216.3": true,
+	} {
+		assert.Equal(t, want, Allowed(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	assert.NoError(t, CheckURL("https://hooks.example.com/task"))
+	assert.NoError(t, CheckURL("http://93.184.216.34:8080/hook"))
+
+	assert.ErrorIs(t, CheckURL("ftp://example.com/hook"), ErrInvalidURL)
+	assert.ErrorIs(t, CheckURL("https:///hook"), ErrInvalidURL)
+	assert.ErrorIs(t, CheckURL("http://localhost:8080/hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://api.LOCALHOST./hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://127.0.0.1/hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://[::1]/hook"), ErrForbiddenAddress)
+	assert.ErrorIs(t, CheckURL("http://169.254.169.254/latest/meta-data"), ErrForbiddenAddress)
+}
+
+func TestNewClient_RefusesLoopback(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, srv.URL, nil)
+	require.NoError(t, err)
+	_, err = NewClient(time.Second).Do(req)
+
+	assert.ErrorIs(t, err, ErrForbiddenAddress)
+	assert.False(t, called)
+}
+
+func TestNewClient_DoesNotFollowRedirects(t *testing.T) {
+	client := NewClient(time.Second)
+	req := httptest.NewRequest(http.MethodPost, "https://hooks.example.com/moved", nil)
+
+	assert.ErrorIs(t, client.CheckRedirect(req, nil), http.ErrUseLastResponse)
+}